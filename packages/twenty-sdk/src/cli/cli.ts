@@ -1,6 +1,8 @@
 #!/usr/bin/env node
+import { reportRetryStats } from "./utilities/api/services/retry-stats";
 import { loadCliEnvironment } from "./utilities/config/services/environment.service";
 import { formatError, toExitCode } from "./utilities/errors/error-handler";
+import { logLines } from "./utilities/shared/logger";
 import { maybeHandleInlineHelp } from "./help";
 import { buildProgram } from "./program";
 
@@ -15,12 +17,10 @@ export async function main(argv: string[] = process.argv): Promise<void> {
 
     await program.parseAsync(argv);
   } catch (error) {
-    const messages = formatError(error);
-    for (const line of messages) {
-      // eslint-disable-next-line no-console
-      console.error(line);
-    }
     process.exitCode = toExitCode(error);
+    logLines("error", formatError(error), { exitCode: process.exitCode });
+  } finally {
+    reportRetryStats();
   }
 }
 
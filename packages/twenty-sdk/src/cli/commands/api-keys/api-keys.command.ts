@@ -3,6 +3,7 @@ import { requireGraphqlField, type GraphQLResponse } from "../../utilities/api/g
 import { CliError } from "../../utilities/errors/cli-error";
 import { applyGlobalOptions } from "../../utilities/shared/global-options";
 import { createCommandContext } from "../../utilities/shared/context";
+import { printStatus } from "../../utilities/output/services/status-printer";
 
 interface ApiKeyOptions {
   name?: string;
@@ -161,7 +162,7 @@ export function registerApiKeysCommand(program: Command): void {
     .argument("[id]", "API key ID");
   applyGlobalOptions(revokeCmd);
   revokeCmd.action(async (id: string | undefined, _options: unknown, command: Command) => {
-    const { services } = createCommandContext(command);
+    const { globalOptions, services } = createCommandContext(command);
     if (!id) throw new CliError("Missing API key ID.", "INVALID_ARGUMENTS");
     const response = await services.api.post<
       GraphQLResponse<{ revokeApiKey?: { id: string } | null }>
@@ -175,8 +176,16 @@ export function registerApiKeysCommand(program: Command): void {
       `Failed to revoke API key ${id}.`,
     );
     if (!revoked) throw new CliError(`Failed to revoke API key ${id}.`, "API_ERROR");
-    // eslint-disable-next-line no-console
-    console.log(`API key ${id} revoked.`);
+    printStatus(
+      globalOptions,
+      {
+        action: "revoked",
+        object: "api-keys",
+        id,
+        message: `API key ${id} revoked.`,
+      },
+      services.writer,
+    );
   });
 
   const assignRoleCmd = cmd
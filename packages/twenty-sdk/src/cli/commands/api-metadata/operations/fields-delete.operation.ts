@@ -1,4 +1,5 @@
 import { CliError } from "../../../utilities/errors/cli-error";
+import { printStatus } from "../../../utilities/output/services/status-printer";
 import { ApiMetadataContext } from "./types";
 
 export async function runFieldsDelete(ctx: ApiMetadataContext): Promise<void> {
@@ -7,5 +8,14 @@ export async function runFieldsDelete(ctx: ApiMetadataContext): Promise<void> {
     throw new CliError("Missing field ID.", "INVALID_ARGUMENTS");
   }
   await ctx.services.metadata.deleteField(id);
-  console.log(`Field ${id} deleted.`);
+  printStatus(
+    ctx.globalOptions,
+    {
+      action: "deleted",
+      object: ctx.type,
+      id,
+      message: `Field ${id} deleted.`,
+    },
+    ctx.services.writer,
+  );
 }
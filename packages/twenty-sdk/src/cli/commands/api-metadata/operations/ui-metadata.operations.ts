@@ -1,5 +1,6 @@
 import { CliError } from "../../../utilities/errors/cli-error";
 import { parseBody } from "../../../utilities/shared/body";
+import { printStatus } from "../../../utilities/output/services/status-printer";
 import { ApiMetadataContext } from "./types";
 
 export const runCommandMenuItemsList = createListOperation((ctx) =>
@@ -352,7 +353,16 @@ function createDeleteOperation(
     if (!deleted) {
       throw new CliError(`${noun} ${id} was not deleted.`, "API_ERROR");
     }
-    console.log(`${noun} ${id} deleted.`);
+    printStatus(
+      ctx.globalOptions,
+      {
+        action: "deleted",
+        object: ctx.type,
+        id,
+        message: `${noun} ${id} deleted.`,
+      },
+      ctx.services.writer,
+    );
   };
 }
 
@@ -18,6 +18,7 @@ import { runExportOperation } from "./operations/export.operation";
 import { runGroupByOperation } from "./operations/group-by.operation";
 import { runFindDuplicatesOperation } from "./operations/find-duplicates.operation";
 import { runMergeOperation } from "./operations/merge.operation";
+import { runOpenOperation } from "./operations/open.operation";
 
 function applyApiOptions(command: Command): void {
   command
@@ -26,18 +27,52 @@ function applyApiOptions(command: Command): void {
     .option("--filter <expression>", "Filter expression")
     .option("--include <relations>", "Include related records")
     .option("--cursor <cursor>", "Pagination cursor")
+    .option("--page-size <n>", "Records per request while paginating (max 200)")
     .option("--sort <field>", "Sort field")
     .option("--order <direction>", "Sort order (asc or desc)")
-    .option("--param <key=value>", "Additional query params", collect)
+    .option("--sort-local <field[:desc]>", "Sort fetched records client-side (list/export)")
+    .option("--fields <fields>", "Comma-separated top-level fields to return (list/export)")
+    .option("--with-page-info", "Wrap JSON as {records, pageInfo, totalCount} (list)")
+    .option("--param <key=value>", "Extra query parameter sent as-is (repeatable)", collect)
     .option("-d, --data <json>", "JSON payload")
     .option("-f, --file <path>", "JSON/CSV file payload (use - for stdin)")
     .option("--set <key=value>", "Set a field value", collect)
+    .option("--clear <field>", "Set a field to null (update)", collect)
+    .option("--add-to <field=value>", "Append to an array field (update)", collect)
+    .option("--remove-from <field=value>", "Remove from an array field (update)", collect)
+    .option("--idempotency-key <key>", "Idempotency-Key header for create/batch-create")
+    .option("--if-not-exists <field>", "Skip create when a record has the payload's field value")
+    .option("--if-exists", "Treat an already-deleted record as success (delete)")
+    .option("--on-missing <action>", "On a missing ID: error (default) or create (update)")
+    .option("--show-diff", "Print the changed fields as old and new values (update)")
     .option("--ids <ids>", "Comma-separated IDs")
-    .option("--format <format>", "Export format (json or csv)")
-    .option("--output-file <path>", "Output file path")
-    .option("--batch-size <number>", "Batch size (import)")
+    .option("--id-file <path>", "Run get/update/delete for each ID in a file, one per line")
+    .option("--format <format>", "Export format (json, csv, or parquet)")
+    .option("--output-file <path>", "Output file path (export, or list with --output html)")
+    .option("--open", "Open the --output-file page in the default browser (list)")
+    .option("--split-size <records>", "Rotate export files every N records (export)")
+    .option("--split-bytes <size>", "Rotate export files before they exceed a size, e.g. 50MB")
+    .option("--manifest <path>", "Write a JSON provenance manifest after the export (export)")
+    .option("--checkpoint <path>", "Save the export cursor to this file after each page (export)")
+    .option("--resume <path>", "Continue an export from a saved checkpoint (export)")
+    .option("--array", "Write JSON as one buffered array (list/export, default)")
+    .option("--stream", "Write JSON as NDJSON, one record per line (list/export)")
+    .option("--stream-header", 'Start --stream output with a {"totalCount":N} line')
+    .option("--flatten", "Flatten nested fields into dotted CSV columns")
+    .option("--flatten-depth <levels>", "Nesting levels to flatten (default: all)")
+    .option("--flatten-arrays <mode>", "Array handling when flattening (json, join, index)")
+    .option("--expand <relations>", "Inline related record names as <relation>Name (export)")
+    .option("--computed <name=template>", "Append a templated CSV/table column (list)", collect)
+    .option("--batch-size <number>", "Batch size (import, batch-create --stdin-jsonl)")
+    .option("--stdin-jsonl", "Create NDJSON records from stdin as they arrive (batch-create)")
+    .option("--concurrency <n>", "Batches in flight with --stdin-jsonl (default 4)")
+    .option("--template-file <path>", "Render each row through a JSON body template (import)")
     .option("--dry-run", "Preview without executing")
     .option("--continue-on-error", "Continue on batch errors")
+    .option("--continue-from <index>", "Start at this 1-based record index (import resume)")
+    .option("--only-errors", "Print only failed records and totals (batch ops, import, --id-file)")
+    .option("--fail-fast", "Stop at the first failure and exit non-zero (--only-errors, import)")
+    .option("--max-errors <n>", "List at most N failures with --only-errors; totals count all")
     .option("--field <field>", "Group-by field")
     .option("--source <id>", "Source record ID (merge)")
     .option("--target <id>", "Target record ID (merge)")
@@ -181,17 +216,18 @@ export function registerApiCommand(program: Command): void {
 
   registerCommand(api, "import", "Import records from a file", (command) => {
     command.argument("<object>", "Object name (plural)");
-    command.argument("[filePath]", "Import file path");
+    command.argument("[files...]", "Import files, or a file-name pattern such as 'exports/*.csv'");
     applyApiOptions(command);
     applyGlobalOptions(command);
     command.action(
       async (
         object: string,
-        filePath: string | undefined,
+        files: string[],
         _options: unknown,
         actionCommand: Command,
       ) => {
-        await runImportOperation(createApiOperationContext(actionCommand, object, filePath));
+        const context = createApiOperationContext(actionCommand, object, files[0]);
+        await runImportOperation(files.length > 0 ? { ...context, args: files } : context);
       },
     );
   });
@@ -231,6 +267,18 @@ export function registerApiCommand(program: Command): void {
       await runMergeOperation(createApiOperationContext(actionCommand, object));
     });
   });
+
+  registerCommand(api, "open", "Open a record in the web app", (command) => {
+    command.argument("<object>", "Object name (plural)");
+    command.argument("<id>", "Record ID");
+    command.option("--print-url", "Print the record URL instead of opening a browser");
+    applyGlobalOptions(command);
+    command.action(
+      async (object: string, id: string, _options: unknown, actionCommand: Command) => {
+        await runOpenOperation(createApiOperationContext(actionCommand, object, id));
+      },
+    );
+  });
 }
 
 function collect(value: string, previous: string[] = []): string[] {
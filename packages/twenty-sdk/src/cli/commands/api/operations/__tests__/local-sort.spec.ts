@@ -0,0 +1,51 @@
+import { describe, expect, it } from "vitest";
+import { LocalSort, parseLocalSort, sortRecordsLocally } from "../local-sort";
+
+function sortedIds(records: Array<{ id: string }>, sort: LocalSort): string[] {
+  return sortRecordsLocally(records, sort).map((record) => record.id);
+}
+
+describe("local sort", () => {
+  it("parses a dotted field with an optional direction", () => {
+    expect(parseLocalSort("name.firstName")).toEqual({
+      path: ["name", "firstName"],
+      descending: false,
+    });
+    expect(parseLocalSort("createdAt:DESC")).toEqual({ path: ["createdAt"], descending: true });
+    expect(parseLocalSort(undefined)).toBeUndefined();
+    expect(() => parseLocalSort("city:sideways")).toThrow(
+      'Invalid --sort-local value "city:sideways".',
+    );
+  });
+
+  it("sorts numbers numerically and keeps missing values last when descending", () => {
+    const records = [{ id: "a", n: 2 }, { id: "b" }, { id: "c", n: 10 }, { id: "d", n: null }];
+
+    expect(sortedIds(records, { path: ["n"], descending: true })).toEqual(["c", "a", "b", "d"]);
+  });
+
+  it("compares ISO times as instants rather than strings", () => {
+    const records = [
+      { id: "11:00Z", at: "2024-05-01T11:00:00Z" },
+      { id: "10:00Z", at: "2024-05-01T12:00:00+02:00" },
+    ];
+
+    expect(sortedIds(records, { path: ["at"], descending: false })).toEqual(["10:00Z", "11:00Z"]);
+  });
+
+  it("is stable for equal keys and treats empty strings as missing", () => {
+    const records = [
+      { id: "1", name: { firstName: "Bo" } },
+      { id: "2", name: { firstName: "" } },
+      { id: "3", name: { firstName: "Al" } },
+      { id: "4", name: { firstName: "Bo" } },
+    ];
+
+    expect(sortedIds(records, { path: ["name", "firstName"], descending: false })).toEqual([
+      "3",
+      "1",
+      "4",
+      "2",
+    ]);
+  });
+});
@@ -1,3 +1,6 @@
+import os from "node:os";
+import path from "node:path";
+import fs from "fs-extra";
 import { describe, it, expect, vi, beforeEach, afterEach } from "vitest";
 import { Command } from "commander";
 import { registerApiCommand } from "../../api.command";
@@ -11,11 +14,21 @@ import { runRestoreOperation } from "../restore.operation";
 import { runImportOperation } from "../import.operation";
 import { runExportOperation } from "../export.operation";
 import { runMergeOperation } from "../merge.operation";
+import { runOpenOperation } from "../open.operation";
 import { runBatchCreateOperation } from "../batch-create.operation";
 import { runBatchUpdateOperation } from "../batch-update.operation";
 import { runBatchDeleteOperation } from "../batch-delete.operation";
 import { CliError } from "../../../../utilities/errors/cli-error";
+import { parseBody } from "../../../../utilities/shared/body";
+import { readFileOrStdin, readStdinLines } from "../../../../utilities/shared/io";
+import { openInBrowser } from "../../../../utilities/shared/browser";
 import { ApiOperationContext } from "../types";
+import { CLI_VERSION } from "../../../../version";
+import { ExportService } from "../../../../utilities/file/services/export.service";
+import {
+  BufferedOutputWriter,
+  stdoutWriter,
+} from "../../../../utilities/output/services/output-writer";
 
 const mockCreateCommandContext = vi.hoisted(() => vi.fn());
 
@@ -42,12 +55,18 @@ vi.mock("../../../../utilities/shared/body", () => ({
   }),
 }));
 
+vi.mock("../../../../utilities/shared/browser", () => ({
+  openInBrowser: vi.fn(),
+}));
+
 // Mock the io utility
 vi.mock("../../../../utilities/shared/io", () => ({
   readJsonInput: vi.fn().mockImplementation(async (data: string | undefined) => {
     if (data) return JSON.parse(data);
     return undefined;
   }),
+  readFileOrStdin: vi.fn(),
+  readStdinLines: vi.fn(),
 }));
 
 function createMockContext(overrides: Partial<ApiOperationContext> = {}): ApiOperationContext {
@@ -71,6 +90,7 @@ function createMockContext(overrides: Partial<ApiOperationContext> = {}): ApiOpe
         restoreMany: vi.fn().mockResolvedValue([{ id: "1" }, { id: "2" }]),
         merge: vi.fn().mockResolvedValue({ id: "merged-id" }),
         batchCreate: vi.fn().mockResolvedValue([{ id: "1" }, { id: "2" }]),
+        ensure: vi.fn().mockResolvedValue({ record: { id: "test-id" }, created: true }),
         batchUpdate: vi.fn().mockResolvedValue([{ id: "1" }, { id: "2" }]),
         updateMany: vi.fn().mockResolvedValue([{ id: "1" }, { id: "2" }]),
         batchDelete: vi.fn().mockResolvedValue({ deleted: 2 }),
@@ -87,6 +107,7 @@ function createMockContext(overrides: Partial<ApiOperationContext> = {}): ApiOpe
       exporter: {
         export: vi.fn(),
       } as any,
+      writer: stdoutWriter,
     },
     ...overrides,
   } as ApiOperationContext;
@@ -185,13 +206,115 @@ describe("API Operations", () => {
 
       await runCreateOperation(ctx);
 
-      expect(ctx.services.records.create).toHaveBeenCalledWith("people", { name: "Test Person" });
+      expect(ctx.services.records.create).toHaveBeenCalledWith(
+        "people",
+        { name: "Test Person" },
+        { idempotencyKey: undefined },
+      );
       expect(ctx.services.output.render).toHaveBeenCalledWith(
         { id: "test-id", name: "Test" },
         { format: "json", query: undefined },
       );
     });
 
+    it("skips the create with --if-not-exists when the key already exists", async () => {
+      const ctx = createMockContext({
+        options: {
+          data: '{"emails":{"primaryEmail":"ada@example.com"}}',
+          ifNotExists: "emails.primaryEmail",
+        },
+        globalOptions: { output: "json", outputExplicit: true },
+      });
+      vi.mocked(ctx.services.records.ensure).mockResolvedValue({
+        record: { id: "person-1" },
+        created: false,
+      });
+
+      await runCreateOperation(ctx);
+
+      expect(ctx.services.records.ensure).toHaveBeenCalledWith(
+        "people",
+        "emails.primaryEmail",
+        "ada@example.com",
+        { emails: { primaryEmail: "ada@example.com" } },
+      );
+      expect(ctx.services.records.create).not.toHaveBeenCalled();
+      expect(consoleSpy).toHaveBeenCalledWith(
+        JSON.stringify({
+          status: "ok",
+          action: "skipped-existing",
+          object: "people",
+          id: "person-1",
+          field: "emails.primaryEmail",
+          value: "ada@example.com",
+        }),
+      );
+    });
+
+    it("reports an --if-not-exists upsert that overwrote a concurrent record", async () => {
+      const ctx = createMockContext({
+        options: { data: '{"name":"Acme"}', ifNotExists: "name" },
+        globalOptions: { output: "json", outputExplicit: true },
+      });
+      vi.mocked(ctx.services.records.ensure).mockResolvedValue({
+        record: { id: "person-1" },
+        created: false,
+        updated: true,
+      });
+
+      await runCreateOperation(ctx);
+
+      expect(ctx.services.output.render).not.toHaveBeenCalled();
+      expect(consoleSpy).toHaveBeenCalledWith(
+        JSON.stringify({
+          status: "ok",
+          action: "updated-existing",
+          object: "people",
+          id: "person-1",
+          field: "name",
+          value: "Acme",
+        }),
+      );
+    });
+
+    it("renders the new record when --if-not-exists creates it", async () => {
+      const ctx = createMockContext({
+        options: { data: '{"name":"Acme"}', ifNotExists: "name" },
+      });
+
+      await runCreateOperation(ctx);
+
+      expect(ctx.services.output.render).toHaveBeenCalledWith(
+        { id: "test-id" },
+        { format: "json", query: undefined },
+      );
+    });
+
+    it("rejects --if-not-exists when the payload lacks the field", async () => {
+      const ctx = createMockContext({
+        options: { data: '{"name":"Acme"}', ifNotExists: "domainName" },
+      });
+
+      await expect(runCreateOperation(ctx)).rejects.toThrow(
+        "--if-not-exists domainName needs a domainName value in the payload.",
+      );
+      expect(ctx.services.records.ensure).not.toHaveBeenCalled();
+    });
+
+    it("passes --idempotency-key through to the create request", async () => {
+      const ctx = createMockContext({
+        options: { data: '{"name":"Test Person"}', idempotencyKey: "crm-sync-42" },
+      });
+
+      await runCreateOperation(ctx);
+
+      expect(ctx.services.records.create).toHaveBeenCalledWith(
+        "people",
+        { name: "Test Person" },
+        { idempotencyKey: "crm-sync-42" },
+      );
+    });
+
     it("propagates error when create fails", async () => {
       const ctx = createMockContext({
         options: { data: '{"name":"Test"}' },
@@ -229,6 +352,231 @@ describe("API Operations", () => {
       await expect(runUpdateOperation(ctx)).rejects.toThrow(CliError);
       await expect(runUpdateOperation(ctx)).rejects.toThrow("Missing record ID");
     });
+
+    it("creates the record with its ID on a 404 with --on-missing create", async () => {
+      const ctx = createMockContext({
+        arg: "record-123",
+        options: { data: '{"city":"Paris"}', onMissing: "create" },
+      });
+      vi.mocked(ctx.services.records.update).mockRejectedValueOnce(
+        Object.assign(new Error("Request failed"), { response: { status: 404, data: {} } }),
+      );
+
+      await runUpdateOperation(ctx);
+
+      expect(ctx.services.records.create).toHaveBeenCalledWith("people", {
+        city: "Paris",
+        id: "record-123",
+      });
+      expect(ctx.services.output.render).toHaveBeenCalledWith(
+        { created: true, record: { id: "test-id", name: "Test" } },
+        expect.anything(),
+      );
+    });
+
+    it("reports an update of an existing record with --on-missing create", async () => {
+      const ctx = createMockContext({
+        arg: "record-123",
+        options: { data: '{"city":"Paris"}', onMissing: "create" },
+      });
+
+      await runUpdateOperation(ctx);
+
+      expect(ctx.services.records.create).not.toHaveBeenCalled();
+      expect(ctx.services.output.render).toHaveBeenCalledWith(
+        { created: false, record: { id: "test-id", name: "Updated" } },
+        expect.anything(),
+      );
+    });
+
+    it("still fails on a 404 without --on-missing create", async () => {
+      const ctx = createMockContext({
+        arg: "record-123",
+        options: { data: '{"city":"Paris"}' },
+      });
+      vi.mocked(ctx.services.records.update).mockRejectedValueOnce(
+        Object.assign(new Error("Request failed"), { response: { status: 404, data: {} } }),
+      );
+
+      await expect(runUpdateOperation(ctx)).rejects.toThrow("Request failed");
+      expect(ctx.services.records.create).not.toHaveBeenCalled();
+    });
+
+    it("rejects an unknown --on-missing action", async () => {
+      const ctx = createMockContext({ arg: "record-123", options: { onMissing: "skip" } });
+
+      await expect(runUpdateOperation(ctx)).rejects.toThrow(
+        'Invalid --on-missing value "skip"; expected error or create.',
+      );
+    });
+
+    it("keeps explicit nulls from --data", async () => {
+      const ctx = createMockContext({
+        arg: "record-123",
+        options: { data: '{"jobTitle":null,"city":"Paris"}' },
+      });
+
+      await runUpdateOperation(ctx);
+
+      expect(ctx.services.records.update).toHaveBeenCalledWith("people", "record-123", {
+        jobTitle: null,
+        city: "Paris",
+      });
+    });
+
+    it("sends null for each --clear field, including nested paths", async () => {
+      const ctx = createMockContext({
+        arg: "record-123",
+        options: { set: ["jobTitle=CTO"], clear: ["jobTitle", "linkedinLink.primaryLinkUrl"] },
+      });
+      const actual = await vi.importActual<typeof import("../../../../utilities/shared/body")>(
+        "../../../../utilities/shared/body",
+      );
+      vi.mocked(parseBody).mockImplementationOnce(actual.parseBody);
+
+      await runUpdateOperation(ctx);
+
+      expect(ctx.services.records.update).toHaveBeenCalledWith("people", "record-123", {
+        jobTitle: null,
+        linkedinLink: { primaryLinkUrl: null },
+      });
+    });
+
+    it("merges --add-to and --remove-from into the record's current arrays", async () => {
+      const ctx = createMockContext({
+        arg: "record-123",
+        options: {
+          addTo: ["emails.additionalEmails=b@example.com", "emails.additionalEmails=a@example.com"],
+          removeFrom: ["tags=old", "tags=missing"],
+        },
+      });
+      vi.mocked(ctx.services.records.get).mockResolvedValue({
+        id: "record-123",
+        emails: { primaryEmail: "p@example.com", additionalEmails: ["a@example.com"] },
+        tags: ["old", "vip"],
+      });
+      const actual = await vi.importActual<typeof import("../../../../utilities/shared/body")>(
+        "../../../../utilities/shared/body",
+      );
+      vi.mocked(parseBody).mockImplementationOnce(actual.parseBody);
+
+      await runUpdateOperation(ctx);
+
+      expect(ctx.services.records.get).toHaveBeenCalledWith("people", "record-123");
+      expect(ctx.services.records.update).toHaveBeenCalledWith("people", "record-123", {
+        emails: { additionalEmails: ["a@example.com", "b@example.com"] },
+        tags: ["vip"],
+      });
+    });
+
+    it("rejects --add-to on a field that is not an array", async () => {
+      const ctx = createMockContext({
+        arg: "record-123",
+        options: { addTo: ["jobTitle=CTO"] },
+      });
+      vi.mocked(ctx.services.records.get).mockResolvedValue({ jobTitle: "CEO" });
+
+      await expect(runUpdateOperation(ctx)).rejects.toThrow("Field jobTitle is not an array");
+      expect(ctx.services.records.update).not.toHaveBeenCalled();
+    });
+
+    it("renders changed fields as from/to with --show-diff", async () => {
+      const ctx = createMockContext({
+        arg: "record-123",
+        options: {
+          data: '{"jobTitle":"CTO","city":"Paris","name":{"firstName":"Ada"}}',
+          showDiff: true,
+        },
+      });
+      vi.mocked(ctx.services.records.get).mockResolvedValueOnce({
+        id: "record-123",
+        jobTitle: "CEO",
+        city: "Paris",
+        name: { firstName: "Ada", lastName: "Lovelace" },
+      });
+      vi.mocked(ctx.services.records.update).mockResolvedValueOnce({
+        id: "record-123",
+        jobTitle: "CTO",
+        city: "Paris",
+        name: { firstName: "Ada", lastName: "Lovelace" },
+      });
+
+      await runUpdateOperation(ctx);
+
+      expect(ctx.services.records.get).toHaveBeenCalledWith("people", "record-123");
+      expect(ctx.services.output.render).toHaveBeenCalledWith(
+        { id: "record-123", changes: [{ field: "jobTitle", from: "CEO", to: "CTO" }] },
+        expect.any(Object),
+      );
+    });
+
+    it("renders --show-diff as one row per field in table output", async () => {
+      const ctx = createMockContext({
+        arg: "record-123",
+        options: { data: '{"tags":["a","b"]}', showDiff: true },
+        globalOptions: { output: "table" },
+      });
+      vi.mocked(ctx.services.records.get).mockResolvedValueOnce({ id: "record-123", tags: null });
+      vi.mocked(ctx.services.records.update).mockResolvedValueOnce({
+        id: "record-123",
+        tags: ["a", "b"],
+      });
+
+      await runUpdateOperation(ctx);
+
+      expect(ctx.services.output.render).toHaveBeenCalledWith(
+        [{ field: "tags", from: "null", to: '["a","b"]' }],
+        expect.any(Object),
+      );
+    });
+
+    it("diffs a record created by --on-missing create against nothing", async () => {
+      const ctx = createMockContext({
+        arg: "record-123",
+        options: { data: '{"city":"Paris"}', onMissing: "create", showDiff: true },
+      });
+      const notFound = Object.assign(new Error("Request failed"), {
+        response: { status: 404, data: {} },
+      });
+      vi.mocked(ctx.services.records.get).mockRejectedValueOnce(notFound);
+      vi.mocked(ctx.services.records.update).mockRejectedValueOnce(notFound);
+      vi.mocked(ctx.services.records.create).mockResolvedValueOnce({
+        id: "record-123",
+        city: "Paris",
+      });
+
+      await runUpdateOperation(ctx);
+
+      expect(ctx.services.output.render).toHaveBeenCalledWith(
+        {
+          id: "record-123",
+          created: true,
+          changes: [{ field: "city", from: null, to: "Paris" }],
+        },
+        expect.any(Object),
+      );
+    });
+
+    it("applies the same --file payload to each ID from --id-file", async () => {
+      const ctx = createMockContext({
+        options: { idFile: "ids.txt", file: "patch.json", onlyErrors: true },
+      });
+      vi.mocked(readFileOrStdin)
+        .mockResolvedValueOnce("id-1\nid-2\n")
+        .mockResolvedValueOnce('{"city":"Paris"}');
+
+      await runUpdateOperation(ctx);
+
+      expect(readFileOrStdin).toHaveBeenCalledTimes(2);
+      expect(parseBody).toHaveBeenCalledWith('{"city":"Paris"}', undefined, []);
+      expect(ctx.services.records.update).toHaveBeenCalledWith("people", "id-1", {
+        city: "Paris",
+      });
+      expect(ctx.services.records.update).toHaveBeenCalledWith("people", "id-2", {
+        city: "Paris",
+      });
+      expect(consoleSpy).toHaveBeenCalledWith("Updated 2 people, 0 failed.");
+    });
   });
 
   // ==================== DELETE OPERATION ====================
@@ -245,6 +593,32 @@ describe("API Operations", () => {
       expect(consoleSpy).toHaveBeenCalledWith("Deleted people record-123");
     });
 
+    it("reports an already-deleted record as absent with --if-exists", async () => {
+      const ctx = createMockContext({
+        arg: "record-123",
+        options: { yes: true, ifExists: true },
+      });
+      vi.mocked(ctx.services.records.delete).mockRejectedValue(
+        Object.assign(new Error("Request failed"), { response: { status: 404, data: {} } }),
+      );
+
+      await runDeleteOperation(ctx);
+
+      expect(consoleSpy).toHaveBeenCalledWith("people record-123 is already absent");
+    });
+
+    it("still fails on a missing record without --if-exists", async () => {
+      const ctx = createMockContext({
+        arg: "record-123",
+        options: { yes: true },
+      });
+      vi.mocked(ctx.services.records.delete).mockRejectedValue(
+        Object.assign(new Error("Request failed"), { response: { status: 404, data: {} } }),
+      );
+
+      await expect(runDeleteOperation(ctx)).rejects.toThrow("Request failed");
+    });
+
     it("requires --yes before deleting", async () => {
       const ctx = createMockContext({
         arg: "record-123",
@@ -286,6 +660,55 @@ describe("API Operations", () => {
         expect.any(Object),
       );
     });
+
+    it("requires --yes before reading IDs from --id-file", async () => {
+      const ctx = createMockContext({ options: { idFile: "ids.txt" } });
+
+      await expect(runDeleteOperation(ctx)).rejects.toThrow("Delete requires --yes.");
+      expect(readFileOrStdin).not.toHaveBeenCalled();
+    });
+
+    it("deletes each ID from --id-file and reports failures with --only-errors", async () => {
+      const ctx = createMockContext({
+        options: { idFile: "ids.txt", yes: true, ifExists: true, onlyErrors: true },
+      });
+      vi.mocked(readFileOrStdin).mockResolvedValueOnce("id-1\nid-2\nid-3\n");
+      vi.mocked(ctx.services.records.delete)
+        .mockResolvedValueOnce(undefined)
+        .mockRejectedValueOnce(
+          Object.assign(new Error("Request failed"), { response: { status: 404, data: {} } }),
+        )
+        .mockRejectedValueOnce(new Error("Server error"));
+
+      await runDeleteOperation(ctx);
+
+      expect(ctx.services.records.delete).toHaveBeenCalledTimes(3);
+      expect(ctx.services.output.render).not.toHaveBeenCalled();
+      expect(consoleSpy).toHaveBeenCalledWith("Record 3 (id-3) failed: Server error");
+      expect(consoleSpy).toHaveBeenCalledWith("Deleted 2 people, 1 failed.");
+      expect(process.exitCode).toBeUndefined();
+    });
+
+    it("stops at the first failed ID with --fail-fast", async () => {
+      const ctx = createMockContext({
+        options: { idFile: "ids.txt", yes: true, failFast: true },
+      });
+      vi.mocked(readFileOrStdin).mockResolvedValueOnce("id-1\nid-2\n");
+      vi.mocked(ctx.services.records.delete).mockRejectedValueOnce(new Error("Server error"));
+
+      try {
+        await runDeleteOperation(ctx);
+
+        expect(ctx.services.records.delete).toHaveBeenCalledTimes(1);
+        expect(ctx.services.output.render).toHaveBeenCalledWith(
+          [{ id: "id-1", error: "Server error" }],
+          expect.any(Object),
+        );
+        expect(process.exitCode).toBe(1);
+      } finally {
+        process.exitCode = undefined;
+      }
+    });
   });
 
   // ==================== GET OPERATION ====================
@@ -312,6 +735,51 @@ describe("API Operations", () => {
       await expect(runGetOperation(ctx)).rejects.toThrow(CliError);
       await expect(runGetOperation(ctx)).rejects.toThrow("Missing record ID");
     });
+
+    it("gets each ID from --id-file and aggregates the results", async () => {
+      const ctx = createMockContext({ options: { idFile: "ids.txt" } });
+      vi.mocked(readFileOrStdin).mockResolvedValueOnce("id-1\n\n# skipped\n id-2 \n");
+      vi.mocked(ctx.services.records.get)
+        .mockResolvedValueOnce({ id: "id-1" })
+        .mockRejectedValueOnce(new Error("Not found"));
+
+      try {
+        await runGetOperation(ctx);
+
+        expect(readFileOrStdin).toHaveBeenCalledWith("ids.txt");
+        expect(ctx.services.records.get).toHaveBeenCalledTimes(2);
+        expect(ctx.services.records.get).toHaveBeenLastCalledWith("people", "id-2", {
+          include: undefined,
+        });
+        expect(ctx.services.output.render).toHaveBeenCalledWith(
+          [
+            { id: "id-1", record: { id: "id-1" } },
+            { id: "id-2", error: "Not found" },
+          ],
+          expect.any(Object),
+        );
+        expect(process.exitCode).toBe(1);
+      } finally {
+        process.exitCode = undefined;
+      }
+    });
+
+    it("rejects --id-file together with an ID argument", async () => {
+      const ctx = createMockContext({ arg: "record-123", options: { idFile: "ids.txt" } });
+
+      await expect(runGetOperation(ctx)).rejects.toThrow(
+        "Pass a record ID or --id-file, not both.",
+      );
+      expect(readFileOrStdin).not.toHaveBeenCalledWith("ids.txt");
+    });
+
+    it("rejects an --id-file without IDs", async () => {
+      const ctx = createMockContext({ options: { idFile: "ids.txt" } });
+      vi.mocked(readFileOrStdin).mockResolvedValueOnce("# nothing here\n\n");
+
+      await expect(runGetOperation(ctx)).rejects.toThrow("No IDs found in ids.txt.");
+      expect(ctx.services.records.get).not.toHaveBeenCalled();
+    });
   });
 
   // ==================== LIST OPERATION ====================
@@ -341,57 +809,347 @@ describe("API Operations", () => {
       expect(ctx.services.output.render).toHaveBeenCalled();
     });
 
-    it("uses listAll when --all is provided", async () => {
-      const ctx = createMockContext({
-        options: { all: true },
-      });
+    it("names the list kind for --envelope", async () => {
+      const ctx = createMockContext({ object: "opportunities" });
 
       await runListOperation(ctx);
 
-      expect(ctx.services.records.listAll).toHaveBeenCalled();
-      expect(ctx.services.records.list).not.toHaveBeenCalled();
+      expect(ctx.services.output.render).toHaveBeenCalledWith(
+        expect.anything(),
+        expect.objectContaining({ kind: "OpportunityList" }),
+      );
     });
 
-    it("parses key-value params correctly", async () => {
+    it("wraps records with page info for --with-page-info", async () => {
       const ctx = createMockContext({
-        options: {
-          param: ["depth=2", "include_deleted=true"],
-        },
+        options: { withPageInfo: true, limit: "2" },
+        globalOptions: { output: "json", pruneFields: ["secret"] },
+      });
+      vi.mocked(ctx.services.records.list).mockResolvedValueOnce({
+        data: [
+          { id: "1", secret: "x" },
+          { id: "2", secret: "y" },
+        ],
+        pageInfo: { hasNextPage: true, endCursor: "cursor-2" },
+        totalCount: 5,
       });
 
       await runListOperation(ctx);
 
-      expect(ctx.services.records.list).toHaveBeenCalledWith(
-        "people",
-        expect.objectContaining({
-          params: { depth: ["2"], include_deleted: ["true"] },
-        }),
+      expect(ctx.services.output.render).toHaveBeenCalledWith(
+        {
+          records: [{ id: "1" }, { id: "2" }],
+          pageInfo: { hasNextPage: true, endCursor: "cursor-2" },
+          totalCount: 5,
+        },
+        { format: "json", query: undefined, pruneFields: [], maskFields: [] },
       );
     });
 
-    it("rejects --fields because upstream list routes do not parse it", async () => {
+    it("rejects --with-page-info for non-JSON output", async () => {
       const ctx = createMockContext({
-        options: {
-          fields: "id,name",
-        },
+        options: { withPageInfo: true },
+        globalOptions: { output: "csv" },
       });
 
-      await expect(runListOperation(ctx)).rejects.toThrow(CliError);
       await expect(runListOperation(ctx)).rejects.toThrow(
-        "--fields is not supported for list. Twenty REST find-many only supports depth-based field expansion.",
+        "--with-page-info requires JSON output.",
       );
+      expect(ctx.services.records.list).not.toHaveBeenCalled();
     });
-  });
 
-  // ==================== DESTROY OPERATION ====================
-  describe("runDestroyOperation", () => {
-    it("destroys record when --yes is provided", async () => {
-      const ctx = createMockContext({
-        arg: "record-123",
-        options: { yes: true },
+    it("sorts fetched records client-side with --sort-local", async () => {
+      const ctx = createMockContext({ options: { all: true, sortLocal: "city:desc" } });
+      vi.mocked(ctx.services.records.listAll).mockResolvedValueOnce({
+        data: [
+          { id: "1", city: "Berlin" },
+          { id: "2", city: null },
+          { id: "3", city: "Oslo" },
+        ],
       });
 
-      await runDestroyOperation(ctx);
+      await runListOperation(ctx);
+
+      expect(ctx.services.output.render).toHaveBeenCalledWith(
+        [
+          { id: "3", city: "Oslo" },
+          { id: "1", city: "Berlin" },
+          { id: "2", city: null },
+        ],
+        expect.anything(),
+      );
+    });
+
+    it("uses listAll when --all is provided", async () => {
+      const ctx = createMockContext({
+        options: { all: true },
+      });
+
+      await runListOperation(ctx);
+
+      expect(ctx.services.records.listAll).toHaveBeenCalled();
+      expect(ctx.services.records.list).not.toHaveBeenCalled();
+    });
+
+    it("uses --page-size as the per-request limit for --all", async () => {
+      const ctx = createMockContext({
+        options: { all: true, limit: "10", pageSize: "200" },
+      });
+
+      await runListOperation(ctx);
+
+      expect(ctx.services.records.listAll).toHaveBeenCalledWith(
+        "people",
+        expect.objectContaining({ limit: 200 }),
+      );
+    });
+
+    it("rejects --page-size without --all instead of overriding --limit", async () => {
+      const ctx = createMockContext({
+        options: { limit: "10", pageSize: "50" },
+      });
+
+      await expect(runListOperation(ctx)).rejects.toThrow("--page-size only applies with --all.");
+      expect(ctx.services.records.list).not.toHaveBeenCalled();
+    });
+
+    it("parses key-value params correctly", async () => {
+      const ctx = createMockContext({
+        options: {
+          param: ["depth=2", "include_deleted=true"],
+        },
+      });
+
+      await runListOperation(ctx);
+
+      expect(ctx.services.records.list).toHaveBeenCalledWith(
+        "people",
+        expect.objectContaining({
+          params: { depth: ["2"], include_deleted: ["true"] },
+        }),
+      );
+    });
+
+    it("passes --fields to the records service as a field list", async () => {
+      const ctx = createMockContext({
+        options: {
+          fields: "id, name,,city",
+        },
+      });
+
+      await runListOperation(ctx);
+
+      expect(ctx.services.records.list).toHaveBeenCalledWith(
+        "people",
+        expect.objectContaining({ fields: ["id", "name", "city"] }),
+      );
+    });
+
+    it("passes --computed columns to the renderer in order", async () => {
+      const ctx = createMockContext({
+        options: {
+          computed: ["fullName={{.name.firstName}} {{.name.lastName}}", "city={{.city}}"],
+        },
+      });
+
+      await runListOperation(ctx);
+
+      expect(ctx.services.output.render).toHaveBeenCalledWith(expect.anything(), {
+        format: "json",
+        kind: "PersonList",
+        query: undefined,
+        computed: [
+          { name: "fullName", template: "{{.name.firstName}} {{.name.lastName}}" },
+          { name: "city", template: "{{.city}}" },
+        ],
+      });
+    });
+
+    it("rejects --computed values without a column name", async () => {
+      const ctx = createMockContext({ options: { computed: ["{{.city}}"] } });
+
+      await expect(runListOperation(ctx)).rejects.toThrow(/Invalid --computed value/);
+    });
+
+    it("passes --fields as the output column order", async () => {
+      const ctx = createMockContext({
+        options: { fields: "email,id,name" },
+        globalOptions: { output: "csv" },
+      });
+
+      await runListOperation(ctx);
+
+      expect(ctx.services.output.render).toHaveBeenCalledWith(expect.anything(), {
+        format: "csv",
+        kind: "PersonList",
+        query: undefined,
+        columns: ["email", "id", "name"],
+      });
+    });
+
+    it("writes --output html to --output-file and opens it with --open", async () => {
+      const ctx = createMockContext({
+        options: { outputFile: "report.html", open: true },
+        globalOptions: { output: "html" },
+      });
+
+      await runListOperation(ctx);
+
+      expect(ctx.services.output.render).toHaveBeenCalledWith(expect.anything(), {
+        format: "html",
+        kind: "PersonList",
+        query: undefined,
+        outputFile: "report.html",
+      });
+      expect(openInBrowser).toHaveBeenCalledWith(expect.stringMatching(/report\.html$/));
+    });
+
+    it("rejects --output-file on list unless the output is html", async () => {
+      const ctx = createMockContext({ options: { outputFile: "people.json" } });
+
+      await expect(runListOperation(ctx)).rejects.toThrow(
+        "--output-file on list requires --output html.",
+      );
+      expect(ctx.services.records.list).not.toHaveBeenCalled();
+    });
+
+    it("renders NDJSON with --stream", async () => {
+      const ctx = createMockContext({ options: { stream: true } });
+
+      await runListOperation(ctx);
+
+      expect(ctx.services.output.render).toHaveBeenCalledWith(
+        [{ id: "1" }, { id: "2" }],
+        expect.objectContaining({ format: "jsonl" }),
+      );
+    });
+
+    it("renders each page as it arrives with --all --stream", async () => {
+      const ctx = createMockContext({ options: { all: true, stream: true } });
+      vi.mocked(ctx.services.records.listAll).mockImplementation(async (_object, options) => {
+        await options?.onPage?.([{ id: "1" }]);
+        await options?.onPage?.([]);
+        await options?.onPage?.([{ id: "2" }]);
+        return { data: [{ id: "1" }, { id: "2" }] };
+      });
+
+      await runListOperation(ctx);
+
+      expect(ctx.services.output.render).toHaveBeenCalledTimes(2);
+      expect(ctx.services.output.render).toHaveBeenNthCalledWith(1, [{ id: "1" }], {
+        format: "jsonl",
+      });
+      expect(ctx.services.output.render).toHaveBeenNthCalledWith(2, [{ id: "2" }], {
+        format: "jsonl",
+      });
+    });
+
+    it("starts --stream output with the first page's totalCount", async () => {
+      const ctx = createMockContext({
+        options: { all: true, stream: true, streamHeader: true },
+      });
+      vi.mocked(ctx.services.records.listAll).mockImplementation(async (_object, options) => {
+        await options?.onPage?.([{ id: "1" }], { hasNextPage: true, endCursor: "c1" }, 2);
+        await options?.onPage?.([{ id: "2" }], { hasNextPage: false }, 2);
+        return { data: [], totalCount: 2 };
+      });
+      vi.mocked(ctx.services.output.render).mockImplementation(async () => {
+        expect(consoleSpy).toHaveBeenCalledTimes(1);
+      });
+
+      await runListOperation(ctx);
+
+      expect(consoleSpy).toHaveBeenCalledTimes(1);
+      expect(consoleSpy).toHaveBeenCalledWith('{"totalCount":2}');
+      expect(ctx.services.output.render).toHaveBeenCalledTimes(2);
+    });
+
+    it("requires --stream for --stream-header", async () => {
+      const ctx = createMockContext({ options: { streamHeader: true } });
+
+      await expect(runListOperation(ctx)).rejects.toThrow("--stream-header requires --stream.");
+    });
+
+    it("aggregates --distinct values page by page", async () => {
+      const ctx = createMockContext({ options: { distinct: "address.addressCity" } });
+      vi.mocked(ctx.services.records.listAll).mockImplementation(async (_object, options) => {
+        await options?.onPage?.([
+          { address: { addressCity: "Paris" } },
+          { address: { addressCity: "London" } },
+        ]);
+        await options?.onPage?.([{ address: { addressCity: "Paris" } }, { address: null }]);
+        return { data: [] };
+      });
+
+      await runListOperation(ctx);
+
+      expect(ctx.services.records.listAll).toHaveBeenCalledWith(
+        "people",
+        expect.objectContaining({ fields: ["address"] }),
+      );
+      expect(ctx.services.output.render).toHaveBeenCalledWith(["Paris", "London"], {
+        format: "json",
+        query: undefined,
+      });
+    });
+
+    it("renders value,count rows with --with-counts", async () => {
+      const ctx = createMockContext({
+        options: { distinct: "jobTitle", withCounts: true },
+        globalOptions: { output: "csv" },
+      });
+      vi.mocked(ctx.services.records.listAll).mockImplementation(async (_object, options) => {
+        await options?.onPage?.([{ jobTitle: "CTO" }, { jobTitle: "CEO" }, { jobTitle: "CTO" }]);
+        return { data: [] };
+      });
+
+      await runListOperation(ctx);
+
+      expect(ctx.services.output.render).toHaveBeenCalledWith(
+        [
+          { value: "CTO", count: 2 },
+          { value: "CEO", count: 1 },
+        ],
+        { format: "csv", query: undefined },
+      );
+    });
+
+    it("rejects --with-counts without --distinct", async () => {
+      const ctx = createMockContext({ options: { withCounts: true } });
+
+      await expect(runListOperation(ctx)).rejects.toThrow(
+        "--with-counts requires --distinct <field>.",
+      );
+    });
+
+    it("rejects --array together with --stream", async () => {
+      const ctx = createMockContext({ options: { array: true, stream: true } });
+
+      await expect(runListOperation(ctx)).rejects.toThrow("Use only one of --array or --stream.");
+      expect(ctx.services.records.list).not.toHaveBeenCalled();
+    });
+
+    it("rejects --stream for non-JSON output", async () => {
+      const ctx = createMockContext({
+        options: { stream: true },
+        globalOptions: { output: "csv" },
+      });
+
+      await expect(runListOperation(ctx)).rejects.toThrow(
+        "--stream applies to JSON output, not csv.",
+      );
+    });
+  });
+
+  // ==================== DESTROY OPERATION ====================
+  describe("runDestroyOperation", () => {
+    it("destroys record when --yes is provided", async () => {
+      const ctx = createMockContext({
+        arg: "record-123",
+        options: { yes: true },
+      });
+
+      await runDestroyOperation(ctx);
 
       expect(ctx.services.records.destroy).toHaveBeenCalledWith("people", "record-123");
       expect(consoleSpy).toHaveBeenCalledWith("Destroyed people record-123");
@@ -484,193 +1242,1007 @@ describe("API Operations", () => {
   describe("runImportOperation", () => {
     it("imports records from file and batch creates them", async () => {
       const ctx = createMockContext({
-        arg: "/path/to/data.csv",
-        options: {},
+        arg: "/path/to/data.csv",
+        options: {},
+      });
+
+      await runImportOperation(ctx);
+
+      expect(ctx.services.importer.import).toHaveBeenCalledWith("/path/to/data.csv", {
+        dryRun: undefined,
+      });
+      expect(ctx.services.records.batchCreate).toHaveBeenCalledWith("people", [
+        { name: "Test1" },
+        { name: "Test2" },
+      ]);
+      expect(consoleSpy).toHaveBeenCalledWith("Import complete: 2 imported.");
+    });
+
+    it("throws CliError when file path is missing", async () => {
+      const ctx = createMockContext({
+        arg: undefined,
+      });
+
+      await expect(runImportOperation(ctx)).rejects.toThrow(CliError);
+      await expect(runImportOperation(ctx)).rejects.toThrow("Missing import file path");
+    });
+
+    it("does not create records in dry-run mode", async () => {
+      const ctx = createMockContext({
+        arg: "/path/to/data.csv",
+        options: { dryRun: true },
+      });
+
+      await runImportOperation(ctx);
+
+      expect(ctx.services.importer.import).toHaveBeenCalledWith("/path/to/data.csv", {
+        dryRun: true,
+      });
+      expect(ctx.services.records.batchCreate).not.toHaveBeenCalled();
+    });
+
+    it("renders each row through --template-file before batch creating", async () => {
+      vi.mocked(readFileOrStdin).mockResolvedValueOnce(
+        '{"name":{"firstName":{{.first | json}}},"emails":{"primaryEmail":{{.email | json}},"additionalEmails":{{.others | split ";"}}}}',
+      );
+      const ctx = createMockContext({
+        arg: "/path/to/people.csv",
+        options: { templateFile: "body.tmpl" },
+      });
+      (ctx.services.importer.import as ReturnType<typeof vi.fn>).mockResolvedValue([
+        { first: 'Ann "A"', email: "ann@example.com", others: "a@example.com; b@example.com" },
+      ]);
+
+      await runImportOperation(ctx);
+
+      expect(readFileOrStdin).toHaveBeenCalledWith("body.tmpl");
+      expect(ctx.services.records.batchCreate).toHaveBeenCalledWith("people", [
+        {
+          name: { firstName: 'Ann "A"' },
+          emails: {
+            primaryEmail: "ann@example.com",
+            additionalEmails: ["a@example.com", "b@example.com"],
+          },
+        },
+      ]);
+    });
+
+    it("prints rendered bodies instead of raw rows in dry-run mode", async () => {
+      vi.mocked(readFileOrStdin).mockResolvedValueOnce('{"jobTitle":{{.title | json}}}');
+      const ctx = createMockContext({
+        arg: "/path/to/people.csv",
+        options: { dryRun: true, templateFile: "body.tmpl" },
+      });
+      (ctx.services.importer.import as ReturnType<typeof vi.fn>).mockResolvedValue([
+        { title: "CEO" },
+      ]);
+
+      await runImportOperation(ctx);
+
+      expect(ctx.services.importer.import).toHaveBeenCalledWith("/path/to/people.csv", {
+        dryRun: false,
+      });
+      expect(consoleSpy).toHaveBeenCalledWith("Would import 1 records");
+      expect(consoleSpy).toHaveBeenCalledWith('{"jobTitle":"CEO"}');
+      expect(ctx.services.records.batchCreate).not.toHaveBeenCalled();
+    });
+
+    it("rejects templates that do not render valid JSON", async () => {
+      vi.mocked(readFileOrStdin).mockResolvedValueOnce('{"jobTitle":"{{.title}}"}');
+      const ctx = createMockContext({
+        arg: "/path/to/people.csv",
+        options: { templateFile: "body.tmpl" },
+      });
+      (ctx.services.importer.import as ReturnType<typeof vi.fn>).mockResolvedValue([
+        { title: 'Chief "Everything" Officer' },
+      ]);
+
+      await expect(runImportOperation(ctx)).rejects.toThrow(
+        "Record 1 template did not render valid JSON",
+      );
+      expect(ctx.services.records.batchCreate).not.toHaveBeenCalled();
+    });
+
+    it("handles empty import gracefully", async () => {
+      const ctx = createMockContext({
+        arg: "/path/to/empty.csv",
+      });
+      (ctx.services.importer.import as ReturnType<typeof vi.fn>).mockResolvedValue([]);
+
+      await runImportOperation(ctx);
+
+      expect(consoleSpy).toHaveBeenCalledWith("No records to import.");
+      expect(ctx.services.records.batchCreate).not.toHaveBeenCalled();
+    });
+
+    it("continues on error when --continue-on-error is set", async () => {
+      const ctx = createMockContext({
+        arg: "/path/to/data.csv",
+        options: { continueOnError: true, batchSize: "1" },
+      });
+      (ctx.services.importer.import as ReturnType<typeof vi.fn>).mockResolvedValue([
+        { name: "Test1" },
+        { name: "Test2" },
+      ]);
+      (ctx.services.records.batchCreate as ReturnType<typeof vi.fn>)
+        .mockRejectedValueOnce(new Error("Batch 1 failed"))
+        .mockResolvedValueOnce([{ id: "2" }]);
+
+      await runImportOperation(ctx);
+
+      expect(consoleSpy).toHaveBeenCalledWith("Import complete: 1 imported, 1 failed.");
+    });
+
+    it("prints only failed records and totals with --only-errors", async () => {
+      const ctx = createMockContext({
+        arg: "/path/to/data.csv",
+        options: { onlyErrors: true, batchSize: "1" },
+      });
+      (ctx.services.importer.import as ReturnType<typeof vi.fn>).mockResolvedValue([
+        { name: "Test1" },
+        { name: "Test2" },
+        { name: "Test3" },
+      ]);
+      (ctx.services.records.batchCreate as ReturnType<typeof vi.fn>)
+        .mockResolvedValueOnce([{ id: "1" }])
+        .mockRejectedValueOnce({
+          isAxiosError: true,
+          message: "Request failed with status code 400",
+          response: { status: 400, data: { messages: ["emails must be unique"] } },
+        })
+        .mockResolvedValueOnce([{ id: "3" }]);
+
+      await runImportOperation(ctx);
+
+      expect(ctx.services.records.batchCreate).toHaveBeenCalledTimes(3);
+      expect(consoleSpy.mock.calls).toEqual([
+        ["Record 2 failed: HTTP 400: emails must be unique"],
+        ["Imported 2 people, 1 failed."],
+      ]);
+      expect(process.exitCode).toBeUndefined();
+    });
+
+    it("lists at most --max-errors failures and counts the rest", async () => {
+      const ctx = createMockContext({
+        arg: "/path/to/data.csv",
+        options: { onlyErrors: true, maxErrors: "1", batchSize: "1" },
+      });
+      (ctx.services.importer.import as ReturnType<typeof vi.fn>).mockResolvedValue([
+        { name: "Test1" },
+        { name: "Test2" },
+        { name: "Test3" },
+      ]);
+      (ctx.services.records.batchCreate as ReturnType<typeof vi.fn>).mockRejectedValue(
+        new Error("Rejected"),
+      );
+
+      await runImportOperation(ctx);
+
+      expect(ctx.services.records.batchCreate).toHaveBeenCalledTimes(3);
+      expect(consoleSpy.mock.calls).toEqual([
+        ["Record 1 failed: Rejected"],
+        ["... and 2 more errors"],
+        ["Imported 0 people, 3 failed."],
+      ]);
+      expect(process.exitCode).toBeUndefined();
+    });
+
+    it("rejects an invalid --max-errors before importing", async () => {
+      const ctx = createMockContext({
+        arg: "/path/to/data.csv",
+        options: { onlyErrors: true, maxErrors: "-1" },
+      });
+
+      await expect(runImportOperation(ctx)).rejects.toThrow("Invalid --max-errors value");
+      expect(ctx.services.importer.import).not.toHaveBeenCalled();
+    });
+
+    it("stops at the first failed batch and exits non-zero with --fail-fast", async () => {
+      const ctx = createMockContext({
+        arg: "/path/to/data.csv",
+        options: { onlyErrors: true, failFast: true, batchSize: "1" },
+      });
+      (ctx.services.records.batchCreate as ReturnType<typeof vi.fn>).mockRejectedValueOnce(
+        new Error("Batch 1 failed"),
+      );
+
+      try {
+        await runImportOperation(ctx);
+
+        expect(ctx.services.records.batchCreate).toHaveBeenCalledTimes(1);
+        expect(consoleSpy).toHaveBeenCalledWith("Record 1 failed: Batch 1 failed");
+        expect(consoleSpy).toHaveBeenCalledWith("Imported 0 people, 1 failed.");
+        expect(process.exitCode).toBe(1);
+      } finally {
+        process.exitCode = undefined;
+      }
+    });
+
+    it("starts at --continue-from and keeps failure indices absolute", async () => {
+      const ctx = createMockContext({
+        arg: "/path/to/data.csv",
+        options: { continueFrom: "2", onlyErrors: true },
+      });
+      (ctx.services.importer.import as ReturnType<typeof vi.fn>).mockResolvedValue([
+        { name: "Test1" },
+        { name: "Test2" },
+        { name: "Test3" },
+      ]);
+      (ctx.services.records.batchCreate as ReturnType<typeof vi.fn>).mockRejectedValueOnce(
+        new Error("Rejected"),
+      );
+
+      await runImportOperation(ctx);
+
+      expect(ctx.services.records.batchCreate).toHaveBeenCalledWith("people", [
+        { name: "Test2" },
+        { name: "Test3" },
+      ]);
+      expect(consoleSpy).toHaveBeenCalledWith("Record 2 failed: Rejected");
+      expect(consoleSpy).toHaveBeenCalledWith("Record 3 failed: Rejected");
+    });
+
+    it("rejects an invalid --continue-from", async () => {
+      const ctx = createMockContext({
+        arg: "/path/to/data.csv",
+        options: { continueFrom: "0" },
+      });
+
+      await expect(runImportOperation(ctx)).rejects.toThrow(
+        'Invalid --continue-from value "0"; expected a positive record index.',
+      );
+    });
+
+    it("finishes the in-flight batch on Ctrl-C and prints the resume index", async () => {
+      const errorSpy = vi.spyOn(console, "error").mockImplementation(() => {});
+      const ctx = createMockContext({
+        arg: "/path/to/data.csv",
+        options: { batchSize: "1" },
+      });
+      (ctx.services.importer.import as ReturnType<typeof vi.fn>).mockResolvedValue([
+        { name: "Test1" },
+        { name: "Test2" },
+        { name: "Test3" },
+      ]);
+      (ctx.services.records.batchCreate as ReturnType<typeof vi.fn>).mockImplementationOnce(
+        async () => {
+          const listeners = process.listeners("SIGINT");
+          (listeners[listeners.length - 1] as () => void)();
+          return [{ id: "1" }];
+        },
+      );
+
+      try {
+        await runImportOperation(ctx);
+
+        expect(ctx.services.records.batchCreate).toHaveBeenCalledTimes(1);
+        expect(errorSpy).toHaveBeenCalledWith("Import interrupted. Resume with --continue-from 2");
+        expect(consoleSpy).toHaveBeenCalledWith("Import complete: 1 imported.");
+        expect(process.exitCode).toBe(130);
+      } finally {
+        process.exitCode = undefined;
+        errorSpy.mockRestore();
+      }
+    });
+
+    it("caps batch size at 60", async () => {
+      const ctx = createMockContext({
+        arg: "/path/to/data.csv",
+        options: { batchSize: "100" },
+      });
+      const manyRecords = Array.from({ length: 120 }, (_, i) => ({ name: `Test${i}` }));
+      (ctx.services.importer.import as ReturnType<typeof vi.fn>).mockResolvedValue(manyRecords);
+
+      await runImportOperation(ctx);
+
+      // Should have been called twice with batches of 60
+      expect(ctx.services.records.batchCreate).toHaveBeenCalledTimes(2);
+      expect(
+        (ctx.services.records.batchCreate as ReturnType<typeof vi.fn>).mock.calls[0][1],
+      ).toHaveLength(60);
+      expect(
+        (ctx.services.records.batchCreate as ReturnType<typeof vi.fn>).mock.calls[1][1],
+      ).toHaveLength(60);
+    });
+
+    it("imports several files in order with a combined summary", async () => {
+      const ctx = createMockContext({
+        arg: "/path/to/a.csv",
+        args: ["/path/to/a.csv", "/path/to/b.json"],
+      });
+      (ctx.services.importer.import as ReturnType<typeof vi.fn>)
+        .mockResolvedValueOnce([{ name: "A1" }, { name: "A2" }])
+        .mockResolvedValueOnce([{ name: "B1" }]);
+
+      await runImportOperation(ctx);
+
+      expect(ctx.services.importer.import).toHaveBeenNthCalledWith(1, "/path/to/a.csv", {
+        dryRun: undefined,
+      });
+      expect(ctx.services.importer.import).toHaveBeenNthCalledWith(2, "/path/to/b.json", {
+        dryRun: undefined,
+      });
+      expect(ctx.services.records.batchCreate).toHaveBeenNthCalledWith(1, "people", [
+        { name: "A1" },
+        { name: "A2" },
+      ]);
+      expect(ctx.services.records.batchCreate).toHaveBeenNthCalledWith(2, "people", [
+        { name: "B1" },
+      ]);
+      expect(consoleSpy).toHaveBeenCalledWith("/path/to/a.csv: 2 imported");
+      expect(consoleSpy).toHaveBeenCalledWith("/path/to/b.json: 1 imported");
+      expect(consoleSpy).toHaveBeenCalledWith("Import complete: 3 imported from 2 files.");
+    });
+
+    it("keeps importing the remaining files after one fails", async () => {
+      const ctx = createMockContext({
+        arg: "/path/to/a.csv",
+        args: ["/path/to/a.csv", "/path/to/b.csv"],
+      });
+      (ctx.services.importer.import as ReturnType<typeof vi.fn>)
+        .mockRejectedValueOnce(new Error("Unexpected end of file"))
+        .mockResolvedValueOnce([{ name: "B1" }]);
+
+      try {
+        await runImportOperation(ctx);
+
+        expect(ctx.services.records.batchCreate).toHaveBeenCalledTimes(1);
+        expect(consoleSpy).toHaveBeenCalledWith("/path/to/a.csv: failed: Unexpected end of file");
+        expect(consoleSpy).toHaveBeenCalledWith(
+          "Import complete: 1 imported from 2 files (1 could not be imported).",
+        );
+        expect(process.exitCode).toBe(1);
+      } finally {
+        process.exitCode = undefined;
+      }
+    });
+
+    it("stops at the first failed file with --fail-fast", async () => {
+      const ctx = createMockContext({
+        arg: "/path/to/a.csv",
+        args: ["/path/to/a.csv", "/path/to/b.csv"],
+        options: { failFast: true },
+      });
+      (ctx.services.importer.import as ReturnType<typeof vi.fn>).mockRejectedValueOnce(
+        new Error("Unexpected end of file"),
+      );
+
+      await expect(runImportOperation(ctx)).rejects.toThrow("Unexpected end of file");
+      expect(ctx.services.importer.import).toHaveBeenCalledTimes(1);
+    });
+
+    it("applies --dry-run to every file", async () => {
+      const ctx = createMockContext({
+        arg: "/path/to/a.csv",
+        args: ["/path/to/a.csv", "/path/to/b.csv"],
+        options: { dryRun: true },
+      });
+
+      await runImportOperation(ctx);
+
+      expect(ctx.services.importer.import).toHaveBeenCalledTimes(2);
+      expect(ctx.services.importer.import).toHaveBeenCalledWith("/path/to/b.csv", {
+        dryRun: true,
+      });
+      expect(ctx.services.records.batchCreate).not.toHaveBeenCalled();
+    });
+
+    it("rejects --continue-from with several files", async () => {
+      const ctx = createMockContext({
+        arg: "/path/to/a.csv",
+        args: ["/path/to/a.csv", "/path/to/b.csv"],
+        options: { continueFrom: "3" },
+      });
+
+      await expect(runImportOperation(ctx)).rejects.toThrow(
+        "--continue-from applies to a single import file.",
+      );
+      expect(ctx.services.importer.import).not.toHaveBeenCalled();
+    });
+  });
+
+  describe("runBatchUpdateOperation", () => {
+    it("uses collection update-many when filter and shared data are provided", async () => {
+      const ctx = createMockContext({
+        options: {
+          filter: "status[eq]:TODO",
+          data: '{"status":"DONE"}',
+        },
+      });
+
+      await runBatchUpdateOperation(ctx);
+
+      expect(ctx.services.records.updateMany).toHaveBeenCalledWith(
+        "people",
+        { status: "DONE" },
+        {
+          filter: "status[eq]:TODO",
+        },
+      );
+      expect(ctx.services.output.render).toHaveBeenCalled();
+    });
+  });
+
+  // ==================== EXPORT OPERATION ====================
+  describe("runExportOperation", () => {
+    it("exports records to JSON format", async () => {
+      const ctx = createMockContext({
+        options: { format: "json", outputFile: "/path/to/output.json" },
+      });
+
+      await runExportOperation(ctx);
+
+      expect(ctx.services.records.list).toHaveBeenCalled();
+      expect(ctx.services.exporter.export).toHaveBeenCalledWith([{ id: "1" }, { id: "2" }], {
+        format: "json",
+        output: "/path/to/output.json",
+      });
+    });
+
+    it("sorts JSON record keys with --sort-keys but leaves CSV columns alone", async () => {
+      const records = [{ name: { lastName: "Lovelace", firstName: "Ada" }, id: "1" }];
+      const ctx = createMockContext({
+        options: { format: "json" },
+        globalOptions: { output: "json", sortKeys: true },
+      });
+      vi.mocked(ctx.services.records.list).mockResolvedValue({ data: records });
+
+      await runExportOperation(ctx);
+
+      const [exported] = vi.mocked(ctx.services.exporter.export).mock.calls[0]!;
+      expect(JSON.stringify(exported)).toBe(
+        '[{"id":"1","name":{"firstName":"Ada","lastName":"Lovelace"}}]',
+      );
+
+      const csv = createMockContext({
+        options: { format: "csv" },
+        globalOptions: { output: "json", sortKeys: true },
+      });
+      vi.mocked(csv.services.records.list).mockResolvedValue({ data: records });
+
+      await runExportOperation(csv);
+
+      const [rows] = vi.mocked(csv.services.exporter.export).mock.calls[0]!;
+      expect(Object.keys(rows[0]!)).toEqual(["name", "id"]);
+    });
+
+    it("uses --page-size as the per-request limit when exporting everything", async () => {
+      const ctx = createMockContext({
+        options: { format: "json", all: true, pageSize: "150" },
+      });
+
+      await runExportOperation(ctx);
+
+      expect(ctx.services.records.listAll).toHaveBeenCalledWith(
+        "people",
+        expect.objectContaining({ limit: 150 }),
+      );
+    });
+
+    it("writes partial results and prints a resume cursor when interrupted", async () => {
+      const errorSpy = vi.spyOn(console, "error").mockImplementation(() => {});
+      const ctx = createMockContext({
+        options: { format: "json", all: true },
+      });
+      vi.mocked(ctx.services.records.listAll).mockResolvedValueOnce({
+        data: [{ id: "1" }, { id: "2" }],
+        pageInfo: { hasNextPage: true, endCursor: "cursor-2" },
+        interrupted: true,
+      });
+
+      try {
+        await runExportOperation(ctx);
+
+        expect(ctx.services.records.listAll).toHaveBeenCalledWith(
+          "people",
+          expect.objectContaining({ signal: expect.any(AbortSignal) }),
+        );
+        expect(ctx.services.exporter.export).toHaveBeenCalledWith([{ id: "1" }, { id: "2" }], {
+          format: "json",
+          output: undefined,
+        });
+        expect(errorSpy).toHaveBeenCalledWith(
+          "Export interrupted after 2 records. Resume with --cursor cursor-2",
+        );
+        expect(process.exitCode).toBe(130);
+      } finally {
+        process.exitCode = undefined;
+        errorSpy.mockRestore();
+      }
+    });
+
+    it("asks for a different --output-file when resuming an interrupted file export", async () => {
+      const errorSpy = vi.spyOn(console, "error").mockImplementation(() => {});
+      const ctx = createMockContext({
+        options: { format: "csv", all: true, outputFile: "people.csv" },
+      });
+      vi.mocked(ctx.services.records.listAll).mockResolvedValueOnce({
+        data: [{ id: "1" }],
+        pageInfo: { hasNextPage: true, endCursor: "cursor-1" },
+        interrupted: true,
+      });
+
+      try {
+        await runExportOperation(ctx);
+
+        expect(errorSpy).toHaveBeenCalledWith(
+          "Export interrupted after 1 records, kept in people.csv. Resume with --cursor " +
+            "cursor-1 and a different --output-file; reusing the same one overwrites them.",
+        );
+        expect(process.exitCode).toBe(130);
+      } finally {
+        process.exitCode = undefined;
+        errorSpy.mockRestore();
+      }
+    });
+
+    it("streams pages into split files and lists the files written", async () => {
+      const writer = {
+        write: vi.fn().mockResolvedValue(undefined),
+        close: vi.fn().mockResolvedValue(["people-0001.csv", "people-0002.csv"]),
+        recordCount: 3,
+      };
+      const ctx = createMockContext({
+        options: { format: "csv", all: true, splitSize: "2", splitBytes: "50MB" },
+      });
+      ctx.services.exporter.createSplitWriter = vi.fn().mockReturnValue(writer);
+      vi.mocked(ctx.services.records.listAll).mockImplementationOnce(async (_object, options) => {
+        await options?.onPage?.([{ id: "1" }, { id: "2" }]);
+        await options?.onPage?.([{ id: "3" }]);
+        return { data: [], pageInfo: { hasNextPage: false } };
+      });
+
+      await runExportOperation(ctx);
+
+      expect(ctx.services.exporter.createSplitWriter).toHaveBeenCalledWith({
+        format: "csv",
+        output: "people.csv",
+        maxRecords: 2,
+        maxBytes: 50 * 1024 ** 2,
+      });
+      expect(writer.write.mock.calls).toEqual([[[{ id: "1" }, { id: "2" }]], [[{ id: "3" }]]]);
+      expect(ctx.services.exporter.export).not.toHaveBeenCalled();
+      expect(consoleSpy.mock.calls.map((call) => call[0])).toEqual([
+        "Exported 3 people records to 2 files:",
+        "people-0001.csv",
+        "people-0002.csv",
+      ]);
+    });
+
+    it("writes a manifest without the token after the export completes", async () => {
+      const tempRoot = await fs.mkdtemp(path.join(os.tmpdir(), "twenty-export-manifest-"));
+      const manifestPath = path.join(tempRoot, "people.manifest.json");
+      const ctx = createMockContext({
+        options: {
+          format: "csv",
+          outputFile: "people.csv",
+          filter: "city[eq]:Paris",
+          manifest: manifestPath,
+        },
+        globalOptions: { output: "json", workspace: "staging" },
+      });
+      ctx.services.config = {
+        resolveApiConfig: vi.fn().mockResolvedValue({
+          apiUrl: "https://crm.example.com",
+          apiKey: "secret-token",
+          workspace: "staging",
+        }),
+      } as any;
+
+      try {
+        await runExportOperation(ctx);
+
+        const content = await fs.readFile(manifestPath, "utf-8");
+        expect(content).not.toContain("secret-token");
+        expect(JSON.parse(content)).toEqual({
+          exportedAt: expect.any(String),
+          cliVersion: CLI_VERSION,
+          object: "people",
+          format: "csv",
+          records: 2,
+          files: ["people.csv"],
+          baseUrl: "https://crm.example.com",
+          profile: "staging",
+          filters: { filter: "city[eq]:Paris", limit: 200 },
+        });
+        expect(await fs.readdir(tempRoot)).toEqual(["people.manifest.json"]);
+      } finally {
+        await fs.remove(tempRoot);
+      }
+    });
+
+    it("saves a checkpoint per page and resumes after a failure without duplicates", async () => {
+      const tempRoot = await fs.mkdtemp(path.join(os.tmpdir(), "twenty-export-checkpoint-"));
+      const output = path.join(tempRoot, "people.csv");
+      const checkpointPath = path.join(tempRoot, "people.ckpt");
+      const warnSpy = vi.spyOn(console, "error").mockImplementation(() => {});
+      const firstRun = createMockContext({
+        options: { format: "csv", all: true, outputFile: output, checkpoint: checkpointPath },
+      });
+      firstRun.services.exporter = new ExportService();
+      vi.mocked(firstRun.services.records.listAll).mockImplementationOnce(
+        async (_object, options) => {
+          await options?.onPage?.([{ id: "1" }, { id: "2" }], {
+            hasNextPage: true,
+            endCursor: "cursor-2",
+          });
+          throw new Error("socket hang up");
+        },
+      );
+
+      try {
+        await expect(runExportOperation(firstRun)).rejects.toThrow("socket hang up");
+        expect(JSON.parse(await fs.readFile(checkpointPath, "utf-8"))).toMatchObject({
+          object: "people",
+          format: "csv",
+          output,
+          cursor: "cursor-2",
+          records: 2,
+          columns: ["id"],
+        });
+        expect(warnSpy).toHaveBeenCalledWith(
+          `Progress is saved; resume with --resume ${checkpointPath}`,
+        );
+
+        const resumed = createMockContext({
+          options: { format: "csv", all: true, resume: checkpointPath },
+        });
+        resumed.services.exporter = new ExportService();
+        vi.mocked(resumed.services.records.listAll).mockImplementationOnce(
+          async (_object, options) => {
+            await options?.onPage?.([{ id: "3" }], { hasNextPage: false });
+            return { data: [], pageInfo: { hasNextPage: false } };
+          },
+        );
+
+        await runExportOperation(resumed);
+
+        expect(resumed.services.records.listAll).toHaveBeenCalledWith(
+          "people",
+          expect.objectContaining({ cursor: "cursor-2" }),
+        );
+        expect(await fs.readFile(output, "utf-8")).toBe("id\r\n1\r\n2\r\n3");
+        expect(await fs.pathExists(checkpointPath)).toBe(false);
+      } finally {
+        warnSpy.mockRestore();
+        await fs.remove(tempRoot);
+      }
+    });
+
+    it("requires CSV or streamed JSON for --checkpoint", async () => {
+      const ctx = createMockContext({
+        options: { all: true, outputFile: "people.json", checkpoint: "people.ckpt" },
+      });
+
+      await expect(runExportOperation(ctx)).rejects.toThrow(
+        "--checkpoint needs CSV or JSON with --stream.",
+      );
+      expect(ctx.services.records.listAll).not.toHaveBeenCalled();
+    });
+
+    it("rejects a non-positive --split-size", async () => {
+      const ctx = createMockContext({ options: { format: "csv", splitSize: "0" } });
+
+      await expect(runExportOperation(ctx)).rejects.toThrow(
+        'Invalid --split-size value "0"; expected a positive record count.',
+      );
+    });
+
+    it("rejects --page-size on a single-page export", async () => {
+      const ctx = createMockContext({
+        options: { format: "json", limit: "10", pageSize: "50" },
+      });
+
+      await expect(runExportOperation(ctx)).rejects.toThrow(
+        "--page-size only applies with --all.",
+      );
+    });
+
+    it("rejects --page-size above the server maximum", async () => {
+      const ctx = createMockContext({
+        options: { format: "json", all: true, pageSize: "500" },
+      });
+
+      await expect(runExportOperation(ctx)).rejects.toThrow(
+        'Invalid --page-size value "500"; expected an integer from 1 to 200.',
+      );
+      expect(ctx.services.records.listAll).not.toHaveBeenCalled();
+    });
+
+    it("exports records to CSV format", async () => {
+      const ctx = createMockContext({
+        options: { format: "csv" },
+      });
+
+      await runExportOperation(ctx);
+
+      expect(ctx.services.exporter.export).toHaveBeenCalledWith(expect.any(Array), {
+        format: "csv",
+        output: undefined,
+      });
+    });
+
+    it("throws CliError for unsupported format", async () => {
+      const ctx = createMockContext({
+        options: { format: "xml" },
+      });
+
+      await expect(runExportOperation(ctx)).rejects.toThrow(CliError);
+      await expect(runExportOperation(ctx)).rejects.toThrow("Unsupported export format");
+    });
+
+    it("uses listAll when --all is provided", async () => {
+      const ctx = createMockContext({
+        options: { all: true, format: "json" },
       });
 
-      await runImportOperation(ctx);
+      await runExportOperation(ctx);
 
-      expect(ctx.services.importer.import).toHaveBeenCalledWith("/path/to/data.csv", {
-        dryRun: undefined,
-      });
-      expect(ctx.services.records.batchCreate).toHaveBeenCalledWith("people", [
-        { name: "Test1" },
-        { name: "Test2" },
-      ]);
-      expect(consoleSpy).toHaveBeenCalledWith("Import complete: 2 imported.");
+      expect(ctx.services.records.listAll).toHaveBeenCalled();
+      expect(ctx.services.records.list).not.toHaveBeenCalled();
     });
 
-    it("throws CliError when file path is missing", async () => {
+    it("uses --output as file path when not a format", async () => {
       const ctx = createMockContext({
-        arg: undefined,
+        options: { output: "/path/to/file.json", format: "json" },
       });
 
-      await expect(runImportOperation(ctx)).rejects.toThrow(CliError);
-      await expect(runImportOperation(ctx)).rejects.toThrow("Missing import file path");
+      await runExportOperation(ctx);
+
+      expect(ctx.services.exporter.export).toHaveBeenCalledWith(expect.any(Array), {
+        format: "json",
+        output: "/path/to/file.json",
+      });
     });
 
-    it("does not create records in dry-run mode", async () => {
+    it("passes flatten options through for CSV export", async () => {
       const ctx = createMockContext({
-        arg: "/path/to/data.csv",
-        options: { dryRun: true },
+        options: { format: "csv", flatten: true, flattenDepth: "2", flattenArrays: "join" },
       });
 
-      await runImportOperation(ctx);
+      await runExportOperation(ctx);
 
-      expect(ctx.services.importer.import).toHaveBeenCalledWith("/path/to/data.csv", {
-        dryRun: true,
+      expect(ctx.services.exporter.export).toHaveBeenCalledWith(expect.any(Array), {
+        format: "csv",
+        output: undefined,
+        flatten: { depth: 2, arrays: "join" },
       });
-      expect(ctx.services.records.batchCreate).not.toHaveBeenCalled();
     });
 
-    it("handles empty import gracefully", async () => {
+    it("rejects --flatten for JSON export", async () => {
       const ctx = createMockContext({
-        arg: "/path/to/empty.csv",
+        options: { format: "json", flatten: true },
       });
-      (ctx.services.importer.import as ReturnType<typeof vi.fn>).mockResolvedValue([]);
 
-      await runImportOperation(ctx);
-
-      expect(consoleSpy).toHaveBeenCalledWith("No records to import.");
-      expect(ctx.services.records.batchCreate).not.toHaveBeenCalled();
+      await expect(runExportOperation(ctx)).rejects.toThrow("--flatten requires --format csv.");
     });
 
-    it("continues on error when --continue-on-error is set", async () => {
+    it("rejects invalid --flatten-arrays modes", async () => {
       const ctx = createMockContext({
-        arg: "/path/to/data.csv",
-        options: { continueOnError: true, batchSize: "1" },
+        options: { format: "csv", flattenArrays: "explode" },
       });
-      (ctx.services.importer.import as ReturnType<typeof vi.fn>).mockResolvedValue([
-        { name: "Test1" },
-        { name: "Test2" },
-      ]);
-      (ctx.services.records.batchCreate as ReturnType<typeof vi.fn>)
-        .mockRejectedValueOnce(new Error("Batch 1 failed"))
-        .mockResolvedValueOnce([{ id: "2" }]);
-
-      await runImportOperation(ctx);
 
-      expect(consoleSpy).toHaveBeenCalledWith("Import complete: 1 imported, 1 failed.");
+      await expect(runExportOperation(ctx)).rejects.toThrow(/Invalid --flatten-arrays value/);
     });
 
-    it("caps batch size at 60", async () => {
+    it("inlines related company names with --expand company across --all pages", async () => {
       const ctx = createMockContext({
-        arg: "/path/to/data.csv",
-        options: { batchSize: "100" },
+        options: { format: "csv", all: true, expand: "company" },
       });
-      const manyRecords = Array.from({ length: 120 }, (_, i) => ({ name: `Test${i}` }));
-      (ctx.services.importer.import as ReturnType<typeof vi.fn>).mockResolvedValue(manyRecords);
+      vi.mocked(ctx.services.records.listAll).mockResolvedValue({
+        data: [
+          { id: "1", companyId: "c-1", company: { id: "c-1", name: "Acme" } },
+          { id: "2", companyId: null, company: null },
+        ],
+      } as any);
 
-      await runImportOperation(ctx);
+      await runExportOperation(ctx);
 
-      // Should have been called twice with batches of 60
-      expect(ctx.services.records.batchCreate).toHaveBeenCalledTimes(2);
-      expect(
-        (ctx.services.records.batchCreate as ReturnType<typeof vi.fn>).mock.calls[0][1],
-      ).toHaveLength(60);
-      expect(
-        (ctx.services.records.batchCreate as ReturnType<typeof vi.fn>).mock.calls[1][1],
-      ).toHaveLength(60);
+      expect(ctx.services.records.listAll).toHaveBeenCalledWith(
+        "people",
+        expect.objectContaining({ include: "company" }),
+      );
+      expect(ctx.services.exporter.export).toHaveBeenCalledWith(
+        [
+          { id: "1", companyId: "c-1", companyName: "Acme" },
+          { id: "2", companyId: null, companyName: "" },
+        ],
+        { format: "csv", output: undefined },
+      );
     });
-  });
 
-  describe("runBatchUpdateOperation", () => {
-    it("uses collection update-many when filter and shared data are provided", async () => {
+    it("passes --fields to the records service for export", async () => {
       const ctx = createMockContext({
-        options: {
-          filter: "status[eq]:TODO",
-          data: '{"status":"DONE"}',
-        },
+        options: { format: "json", fields: "id,name" },
       });
 
-      await runBatchUpdateOperation(ctx);
+      await runExportOperation(ctx);
 
-      expect(ctx.services.records.updateMany).toHaveBeenCalledWith(
+      expect(ctx.services.records.list).toHaveBeenCalledWith(
         "people",
-        { status: "DONE" },
-        {
-          filter: "status[eq]:TODO",
-        },
+        expect.objectContaining({ fields: ["id", "name"] }),
       );
-      expect(ctx.services.output.render).toHaveBeenCalled();
     });
-  });
 
-  // ==================== EXPORT OPERATION ====================
-  describe("runExportOperation", () => {
-    it("exports records to JSON format", async () => {
+    it("fixes the CSV column order from --fields", async () => {
+      const ctx = createMockContext({ options: { format: "csv", fields: "name,id" } });
+
+      await runExportOperation(ctx);
+
+      expect(ctx.services.exporter.export).toHaveBeenCalledWith(expect.anything(), {
+        format: "csv",
+        output: undefined,
+        columns: ["name", "id"],
+      });
+    });
+
+    it("passes --stream through to the exporter for a file", async () => {
       const ctx = createMockContext({
-        options: { format: "json", outputFile: "/path/to/output.json" },
+        options: { format: "json", stream: true, outputFile: "people.ndjson" },
       });
 
       await runExportOperation(ctx);
 
-      expect(ctx.services.records.list).toHaveBeenCalled();
       expect(ctx.services.exporter.export).toHaveBeenCalledWith([{ id: "1" }, { id: "2" }], {
         format: "json",
-        output: "/path/to/output.json",
+        output: "people.ndjson",
+        stream: true,
       });
     });
 
-    it("exports records to CSV format", async () => {
-      const ctx = createMockContext({
-        options: { format: "csv" },
+    it("exports each page to stdout as it arrives with --all --stream", async () => {
+      const ctx = createMockContext({ options: { all: true, stream: true } });
+      vi.mocked(ctx.services.records.listAll).mockImplementation(async (_object, options) => {
+        await options?.onPage?.([{ id: "1" }, { id: "2" }]);
+        await options?.onPage?.([{ id: "3" }]);
+        return { data: [{ id: "1" }, { id: "2" }, { id: "3" }] };
       });
 
       await runExportOperation(ctx);
 
-      expect(ctx.services.exporter.export).toHaveBeenCalledWith(expect.any(Array), {
-        format: "csv",
-        output: undefined,
+      expect(ctx.services.exporter.export).toHaveBeenCalledTimes(2);
+      expect(ctx.services.exporter.export).toHaveBeenNthCalledWith(2, [{ id: "3" }], {
+        format: "json",
+        stream: true,
       });
     });
 
-    it("throws CliError for unsupported format", async () => {
-      const ctx = createMockContext({
-        options: { format: "xml" },
-      });
+    it("writes a null totalCount header when the server reports none", async () => {
+      const ctx = createMockContext({ options: { stream: true, streamHeader: true } });
 
-      await expect(runExportOperation(ctx)).rejects.toThrow(CliError);
-      await expect(runExportOperation(ctx)).rejects.toThrow("Unsupported export format");
+      await runExportOperation(ctx);
+
+      expect(consoleSpy).toHaveBeenCalledWith('{"totalCount":null}');
+      expect(ctx.services.exporter.export).toHaveBeenCalledWith(
+        expect.any(Array),
+        expect.objectContaining({ stream: true }),
+      );
     });
 
-    it("uses listAll when --all is provided", async () => {
+    it("rejects --stream-header when exporting to a file", async () => {
       const ctx = createMockContext({
-        options: { all: true, format: "json" },
+        options: { stream: true, streamHeader: true, outputFile: "people.ndjson" },
       });
 
-      await runExportOperation(ctx);
-
-      expect(ctx.services.records.listAll).toHaveBeenCalled();
+      await expect(runExportOperation(ctx)).rejects.toThrow(
+        "--stream-header applies to NDJSON on stdout, not to files.",
+      );
       expect(ctx.services.records.list).not.toHaveBeenCalled();
     });
 
-    it("uses --output as file path when not a format", async () => {
+    it("spools --all pages into a Parquet file named after the object", async () => {
+      const writer = {
+        write: vi.fn().mockResolvedValue(undefined),
+        close: vi.fn().mockResolvedValue(undefined),
+        recordCount: 3,
+      };
       const ctx = createMockContext({
-        options: { output: "/path/to/file.json", format: "json" },
+        options: { format: "parquet", all: true, fields: "id,city" },
+      });
+      ctx.services.exporter.createParquetWriter = vi.fn().mockReturnValue(writer);
+      vi.mocked(ctx.services.records.listAll).mockImplementationOnce(async (_object, options) => {
+        await options?.onPage?.([{ id: "1" }, { id: "2" }]);
+        await options?.onPage?.([{ id: "3" }]);
+        return { data: [], pageInfo: { hasNextPage: false } };
       });
 
       await runExportOperation(ctx);
 
-      expect(ctx.services.exporter.export).toHaveBeenCalledWith(expect.any(Array), {
-        format: "json",
-        output: "/path/to/file.json",
+      expect(ctx.services.exporter.createParquetWriter).toHaveBeenCalledWith({
+        output: "people.parquet",
+        columns: ["id", "city"],
       });
+      expect(writer.write.mock.calls).toEqual([[[{ id: "1" }, { id: "2" }]], [[{ id: "3" }]]]);
+      expect(writer.close).toHaveBeenCalled();
+      expect(ctx.services.exporter.export).not.toHaveBeenCalled();
+      expect(consoleSpy).toHaveBeenCalledWith("Exported 3 people records to people.parquet");
     });
 
-    it("rejects --fields because export uses the same upstream list contract", async () => {
-      const ctx = createMockContext({
-        options: { format: "json", fields: "id,name" },
-      });
+    it("discards the Parquet spool when listing fails midway", async () => {
+      const writer = {
+        write: vi.fn().mockResolvedValue(undefined),
+        close: vi.fn().mockResolvedValue(undefined),
+        abort: vi.fn().mockResolvedValue(undefined),
+        recordCount: 0,
+      };
+      const ctx = createMockContext({ options: { format: "parquet", all: true } });
+      ctx.services.exporter.createParquetWriter = vi.fn().mockReturnValue(writer);
+      vi.mocked(ctx.services.records.listAll).mockImplementationOnce(async (_object, options) => {
+        await options?.onPage?.([{ id: "1" }]);
+        throw new CliError("socket hang up", "NETWORK");
+      });
+
+      await expect(runExportOperation(ctx)).rejects.toThrow("socket hang up");
+      expect(writer.abort).toHaveBeenCalled();
+      expect(writer.close).not.toHaveBeenCalled();
+    });
+
+    it("rejects Parquet with split or checkpointed exports", async () => {
+      const ctx = createMockContext({ options: { format: "parquet", splitSize: "100" } });
+
+      await expect(runExportOperation(ctx)).rejects.toThrow(
+        "--format parquet writes one file and cannot be combined with " +
+          "--split-size, --split-bytes, --checkpoint, or --resume.",
+      );
+      expect(ctx.services.records.list).not.toHaveBeenCalled();
+    });
+
+    it("rejects --stream with CSV export", async () => {
+      const ctx = createMockContext({ options: { format: "csv", stream: true } });
 
-      await expect(runExportOperation(ctx)).rejects.toThrow(CliError);
       await expect(runExportOperation(ctx)).rejects.toThrow(
-        "--fields is not supported for export. Twenty REST find-many only supports depth-based field expansion.",
+        "--stream applies to JSON output, not csv.",
       );
     });
   });
 
   // ==================== MERGE OPERATION ====================
+  describe("runOpenOperation", () => {
+    it("opens the record page on the self-hosted origin", async () => {
+      const ctx = createMockContext({ arg: "person-1" });
+      ctx.services.config = {
+        resolveApiConfig: vi.fn().mockResolvedValue({ apiUrl: "https://crm.example.com/" }),
+      } as any;
+
+      await runOpenOperation(ctx);
+
+      expect(openInBrowser).toHaveBeenCalledWith("https://crm.example.com/object/person/person-1");
+      expect(consoleSpy).toHaveBeenCalledWith(
+        "Opening https://crm.example.com/object/person/person-1",
+      );
+    });
+
+    it("prints the hosted app URL with --print-url instead of opening it", async () => {
+      const ctx = createMockContext({
+        object: "opportunities",
+        arg: "opp-1",
+        options: { printUrl: true },
+      });
+      ctx.services.config = {
+        resolveApiConfig: vi.fn().mockResolvedValue({ apiUrl: "https://api.twenty.com" }),
+      } as any;
+
+      await runOpenOperation(ctx);
+
+      expect(consoleSpy).toHaveBeenCalledWith("https://app.twenty.com/object/opportunity/opp-1");
+      expect(openInBrowser).not.toHaveBeenCalled();
+    });
+
+    it("writes the status line to the services' writer", async () => {
+      const writer = new BufferedOutputWriter();
+      const ctx = createMockContext({ arg: "person-1" });
+      ctx.services.writer = writer;
+      ctx.services.config = {
+        resolveApiConfig: vi.fn().mockResolvedValue({ apiUrl: "https://crm.example.com" }),
+      } as any;
+
+      await runOpenOperation(ctx);
+
+      expect(writer.toString()).toBe("Opening https://crm.example.com/object/person/person-1\n");
+      expect(consoleSpy).not.toHaveBeenCalled();
+    });
+  });
+
   describe("runMergeOperation", () => {
     it("merges records using --source and --target", async () => {
       const ctx = createMockContext({
@@ -763,6 +2335,257 @@ describe("API Operations", () => {
       expect(ctx.services.output.render).toHaveBeenCalled();
     });
 
+    it("creates referenced records in order and substitutes created IDs", async () => {
+      const ctx = createMockContext({
+        options: {
+          data: JSON.stringify([
+            { $name: "acme", $object: "companies", name: "Acme" },
+            { name: { firstName: "Ada" }, companyId: "$ref:acme" },
+          ]),
+        },
+      });
+      vi.mocked(ctx.services.records.create)
+        .mockResolvedValueOnce({ id: "company-1", name: "Acme" })
+        .mockResolvedValueOnce({ id: "person-1", companyId: "company-1" });
+
+      await runBatchCreateOperation(ctx);
+
+      expect(ctx.services.records.batchCreate).not.toHaveBeenCalled();
+      expect(ctx.services.records.create).toHaveBeenNthCalledWith(
+        1,
+        "companies",
+        { name: "Acme" },
+        { idempotencyKey: undefined },
+      );
+      expect(ctx.services.records.create).toHaveBeenNthCalledWith(
+        2,
+        "people",
+        { name: { firstName: "Ada" }, companyId: "company-1" },
+        { idempotencyKey: undefined },
+      );
+      expect(ctx.services.output.render).toHaveBeenCalledWith(
+        [
+          { id: "company-1", name: "Acme" },
+          { id: "person-1", companyId: "company-1" },
+        ],
+        expect.anything(),
+      );
+    });
+
+    describe("--stdin-jsonl", () => {
+      let errorSpy: ReturnType<typeof vi.spyOn>;
+
+      beforeEach(() => {
+        errorSpy = vi.spyOn(console, "error").mockImplementation(() => {});
+      });
+
+      afterEach(() => {
+        errorSpy.mockRestore();
+      });
+
+      function stdinLines(...lines: string[]): void {
+        vi.mocked(readStdinLines).mockReturnValue(
+          (async function* () {
+            yield* lines;
+          })(),
+        );
+      }
+
+      it("creates NDJSON records in batches and reports progress to stderr", async () => {
+        stdinLines('{"name":"A"}', "", '{"name":"B"}', '{"name":"C"}');
+        const ctx = createMockContext({ options: { stdinJsonl: true, batchSize: "2" } });
+
+        await runBatchCreateOperation(ctx);
+
+        expect(ctx.services.records.batchCreate).toHaveBeenNthCalledWith(1, "people", [
+          { name: "A" },
+          { name: "B" },
+        ]);
+        expect(ctx.services.records.batchCreate).toHaveBeenNthCalledWith(2, "people", [
+          { name: "C" },
+        ]);
+        expect(errorSpy).toHaveBeenLastCalledWith("Created 3, failed 0 (4 lines read)");
+        expect(consoleSpy).toHaveBeenCalledWith("Created 3 people, 0 failed.");
+      });
+
+      it("bounds the batches in flight by --concurrency", async () => {
+        stdinLines('{"n":1}', '{"n":2}', '{"n":3}', '{"n":4}');
+        const ctx = createMockContext({
+          options: { stdinJsonl: true, batchSize: "1", concurrency: "2" },
+        });
+        let active = 0;
+        let peak = 0;
+        vi.mocked(ctx.services.records.batchCreate).mockImplementation(async () => {
+          active += 1;
+          peak = Math.max(peak, active);
+          await new Promise((resolve) => setTimeout(resolve, 5));
+          active -= 1;
+          return [];
+        });
+
+        await runBatchCreateOperation(ctx);
+
+        expect(ctx.services.records.batchCreate).toHaveBeenCalledTimes(4);
+        expect(peak).toBe(2);
+      });
+
+      it("reports invalid lines and failed batches by line number", async () => {
+        stdinLines('{"name":"A"}', "not json", "[1]", '{"name":"D"}');
+        const ctx = createMockContext({ options: { stdinJsonl: true, batchSize: "1" } });
+        vi.mocked(ctx.services.records.batchCreate)
+          .mockResolvedValueOnce([])
+          .mockRejectedValueOnce(new Error("Invalid"));
+
+        await runBatchCreateOperation(ctx);
+
+        expect(consoleSpy.mock.calls.map(([line]) => line)).toEqual([
+          expect.stringMatching(/^Record 2 failed: Line 2 is not valid JSON/),
+          "Record 3 failed: Line 3 is not a JSON object.",
+          "Record 4 failed: Invalid",
+          "Created 1 people, 3 failed.",
+        ]);
+      });
+
+      it("rejects --stdin-jsonl together with --data", async () => {
+        const ctx = createMockContext({ options: { stdinJsonl: true, data: "[]" } });
+
+        await expect(runBatchCreateOperation(ctx)).rejects.toThrow(
+          "Use only one of --stdin-jsonl, --data, or --file.",
+        );
+      });
+    });
+
+    it("reports each record of a rejected batch with --only-errors", async () => {
+      const ctx = createMockContext({
+        options: { data: '[{"name":"A"},{"name":"B"}]', onlyErrors: true },
+      });
+      vi.mocked(ctx.services.records.batchCreate).mockRejectedValueOnce(new Error("Invalid"));
+
+      await runBatchCreateOperation(ctx);
+
+      expect(ctx.services.output.render).not.toHaveBeenCalled();
+      expect(consoleSpy.mock.calls).toEqual([
+        ["Record 1 failed: Invalid"],
+        ["Record 2 failed: Invalid"],
+        ["Created 0 people, 2 failed."],
+      ]);
+    });
+
+    it("reports individual referenced-record failures with --only-errors", async () => {
+      const ctx = createMockContext({
+        globalOptions: { output: "json", outputExplicit: true },
+        options: {
+          data: JSON.stringify([
+            { $name: "acme", $object: "companies", name: "Acme" },
+            { name: { firstName: "Ada" }, companyId: "$ref:acme" },
+            { name: { firstName: "Bob" } },
+          ]),
+          onlyErrors: true,
+        },
+      });
+      vi.mocked(ctx.services.records.create)
+        .mockResolvedValueOnce({ id: "company-1" })
+        .mockRejectedValueOnce(new Error("Ada failed"))
+        .mockResolvedValueOnce({ id: "person-2" });
+
+      await runBatchCreateOperation(ctx);
+
+      expect(ctx.services.records.create).toHaveBeenCalledTimes(3);
+      expect(JSON.parse(consoleSpy.mock.calls[0]![0] as string)).toEqual({
+        status: "ok",
+        action: "created",
+        object: "people",
+        succeeded: 2,
+        failed: 1,
+        failures: [{ index: 2, error: "Ada failed" }],
+      });
+    });
+
+    it("skips records whose $ref target failed with --only-errors", async () => {
+      const ctx = createMockContext({
+        globalOptions: { output: "json", outputExplicit: true },
+        options: {
+          data: JSON.stringify([
+            { $name: "acme", $object: "companies", name: "Acme" },
+            { $name: "ada", name: { firstName: "Ada" }, companyId: "$ref:acme" },
+            { name: { firstName: "Bob" }, referrerId: "$ref:ada" },
+            { name: { firstName: "Cy" } },
+          ]),
+          onlyErrors: true,
+        },
+      });
+      vi.mocked(ctx.services.records.create)
+        .mockRejectedValueOnce(new Error("Acme failed"))
+        .mockResolvedValueOnce({ id: "person-4" });
+
+      await runBatchCreateOperation(ctx);
+
+      expect(ctx.services.records.create).toHaveBeenCalledTimes(2);
+      expect(ctx.services.records.create).toHaveBeenLastCalledWith(
+        "people",
+        { name: { firstName: "Cy" } },
+        { idempotencyKey: undefined },
+      );
+      expect(JSON.parse(consoleSpy.mock.calls[0]![0] as string)).toMatchObject({
+        succeeded: 1,
+        failed: 3,
+        failures: [
+          { index: 1, error: "Acme failed" },
+          { index: 2, error: 'Skipped: references "$ref:acme" from record 1, which failed.' },
+          { index: 3, error: 'Skipped: references "$ref:ada" from record 2, which failed.' },
+        ],
+      });
+    });
+
+    it("lists the records already created when a referenced record fails", async () => {
+      const ctx = createMockContext({
+        options: {
+          data: JSON.stringify([
+            { $name: "acme", $object: "companies", name: "Acme" },
+            { name: { firstName: "Ada" }, companyId: "$ref:acme" },
+            { name: { firstName: "Bob" } },
+          ]),
+        },
+      });
+      vi.mocked(ctx.services.records.create)
+        .mockResolvedValueOnce({ id: "company-1" })
+        .mockResolvedValueOnce({ id: "person-1" })
+        .mockRejectedValueOnce(new Error("Bob failed"));
+
+      await expect(runBatchCreateOperation(ctx)).rejects.toMatchObject({
+        message: [
+          "Record 3 failed: Bob failed",
+          "Created before the failure:",
+          "  1. companies company-1 ($name acme)",
+          "  2. people person-1",
+        ].join("\n"),
+        code: "API_ERROR",
+        suggestion:
+          "Remove the created records from the input before re-running, or they will be " +
+          "created again; --only-errors continues past failures instead.",
+      });
+      expect(ctx.services.output.render).not.toHaveBeenCalled();
+    });
+
+    it("rejects forward and unknown references before creating anything", async () => {
+      const forward = createMockContext({
+        options: {
+          data: JSON.stringify([{ companyId: "$ref:acme" }, { $name: "acme", name: "Acme" }]),
+        },
+      });
+      const unknown = createMockContext({
+        options: { data: JSON.stringify([{ companyId: "$ref:globex" }]) },
+      });
+
+      await expect(runBatchCreateOperation(forward)).rejects.toThrow(
+        'Record 1 references "$ref:acme" before it is created.',
+      );
+      await expect(runBatchCreateOperation(unknown)).rejects.toThrow(
+        'Record 1 references unknown "$ref:globex".',
+      );
+      expect(forward.services.records.create).not.toHaveBeenCalled();
+    });
+
     it("batch creates records from CSV file", async () => {
       const ctx = createMockContext({
         options: { file: "/path/to/data.csv" },
@@ -852,6 +2675,22 @@ describe("API Operations", () => {
       expect(ctx.services.records.batchDelete).toHaveBeenCalledWith("people", ["id-a", "id-b"]);
     });
 
+    it("reports failed IDs with --only-errors", async () => {
+      const ctx = createMockContext({
+        options: { ids: "id-1,id-2", yes: true, onlyErrors: true },
+      });
+      vi.mocked(ctx.services.records.batchDelete).mockRejectedValueOnce(new Error("Not found"));
+
+      await runBatchDeleteOperation(ctx);
+
+      expect(ctx.services.output.render).not.toHaveBeenCalled();
+      expect(consoleSpy.mock.calls).toEqual([
+        ["Record 1 (id-1) failed: Not found"],
+        ["Record 2 (id-2) failed: Not found"],
+        ["Deleted 0 people, 2 failed."],
+      ]);
+    });
+
     it("requires --yes before batch deleting", async () => {
       const ctx = createMockContext({
         options: { ids: "id-1,id-2" },
@@ -0,0 +1,70 @@
+import { CliError } from "../../../utilities/errors/cli-error";
+import { parsePrimitive, splitOnce } from "../../../utilities/shared/parse";
+
+export interface ArrayEdit {
+  field: string;
+  op: "add" | "remove";
+  value: unknown;
+}
+
+export function parseArrayEdits(addTo?: string[], removeFrom?: string[]): ArrayEdit[] {
+  return [
+    ...(addTo ?? []).map((expr) => parseArrayEdit("--add-to", "add", expr)),
+    ...(removeFrom ?? []).map((expr) => parseArrayEdit("--remove-from", "remove", expr)),
+  ];
+}
+
+function parseArrayEdit(flag: string, op: ArrayEdit["op"], expr: string): ArrayEdit {
+  const [rawField, rawValue] = splitOnce(expr, "=");
+  const field = rawField.trim();
+  if (!field || !expr.includes("=")) {
+    throw new CliError(
+      `Invalid ${flag} value ${JSON.stringify(expr)}.`,
+      "INVALID_ARGUMENTS",
+      `Use ${flag} <field=value>, e.g. ${flag} emails.additionalEmails=ann@example.com.`,
+    );
+  }
+
+  return { field, op, value: parsePrimitive(rawValue) };
+}
+
+// Applies the edits to the record's current arrays and returns one --set
+// expression per field holding the merged array. Adding an element that is
+// already present, or removing one that is not, leaves the array unchanged.
+export function mergeArrayEdits(record: unknown, edits: ArrayEdit[]): string[] {
+  const merged = new Map<string, unknown[]>();
+  for (const edit of edits) {
+    const current = merged.get(edit.field) ?? currentArray(record, edit.field);
+    const key = JSON.stringify(edit.value);
+    const present = current.some((item) => JSON.stringify(item) === key);
+    if (edit.op === "add") {
+      merged.set(edit.field, present ? current : [...current, edit.value]);
+    } else {
+      merged.set(edit.field, current.filter((item) => JSON.stringify(item) !== key));
+    }
+  }
+
+  return [...merged].map(([field, values]) => `${field}=${JSON.stringify(values)}`);
+}
+
+function currentArray(record: unknown, field: string): unknown[] {
+  let value: unknown = record;
+  for (const part of field.split(".")) {
+    value =
+      value !== null && typeof value === "object"
+        ? (value as Record<string, unknown>)[part]
+        : undefined;
+  }
+  if (value == null) {
+    return [];
+  }
+  if (!Array.isArray(value)) {
+    throw new CliError(
+      `Field ${field} is not an array; --add-to and --remove-from only edit array fields.`,
+      "INVALID_ARGUMENTS",
+      `Use --set ${field}=<value> to replace it.`,
+    );
+  }
+
+  return value;
+}
@@ -1,8 +1,34 @@
 import path from "path";
-import { ApiOperationContext } from "./types";
+import { ApiOperationContext, RecordWriteHooks } from "./types";
 import { parseArrayPayload } from "../../../utilities/shared/body";
+import { CliError, errorWithCause } from "../../../utilities/errors/cli-error";
+import { readStdinLines } from "../../../utilities/shared/io";
+import {
+  hasBatchReferences,
+  planReferencedRecords,
+  resolveReferences,
+  ReferencedRecord,
+} from "./batch-references";
+import {
+  batchFailures,
+  describeFailure,
+  FailureReport,
+  printFailureReport,
+} from "./failure-report";
+import { createFromJsonLines } from "./stream-create";
+
+export async function runBatchCreateOperation(
+  ctx: ApiOperationContext,
+  hooks: RecordWriteHooks = {},
+): Promise<void> {
+  if (ctx.options.stdinJsonl) {
+    if (ctx.options.data || ctx.options.file) {
+      throw new CliError("Use only one of --stdin-jsonl, --data, or --file.", "INVALID_ARGUMENTS");
+    }
+    await createFromJsonLines(ctx, readStdinLines(), hooks);
+    return;
+  }
 
-export async function runBatchCreateOperation(ctx: ApiOperationContext): Promise<void> {
   let records: Record<string, unknown>[] = [];
   if (ctx.options.file) {
     const ext = path.extname(ctx.options.file).toLowerCase();
@@ -17,9 +43,139 @@ export async function runBatchCreateOperation(ctx: ApiOperationContext): Promise
     records = payload as Record<string, unknown>[];
   }
 
-  const response = await ctx.services.records.batchCreate(ctx.object, records);
+  if (hooks.record) {
+    records = records.map(hooks.record);
+  }
+
+  if (ctx.options.onlyErrors) {
+    printFailureReport(ctx, await createReportingFailures(ctx, records));
+    return;
+  }
+
+  const response = hasBatchReferences(records)
+    ? await createReferencedRecords(ctx, planReferencedRecords(records, ctx.object))
+    : await ctx.services.records.batchCreate(ctx.object, records, {
+        idempotencyKey: ctx.options.idempotencyKey,
+      });
   await ctx.services.output.render(response, {
     format: ctx.globalOptions.output,
     query: ctx.globalOptions.query,
   });
 }
+
+// --only-errors: a plain batch is one atomic request, so a rejection fails
+// every record; referenced records are created singly and fail individually.
+async function createReportingFailures(
+  ctx: ApiOperationContext,
+  records: Record<string, unknown>[],
+): Promise<FailureReport> {
+  const report: FailureReport = { action: "created", succeeded: 0, failures: [] };
+  if (hasBatchReferences(records)) {
+    const plan = planReferencedRecords(records, ctx.object);
+    report.succeeded = (await createReferencedRecords(ctx, plan, report)).length;
+    return report;
+  }
+
+  try {
+    await ctx.services.records.batchCreate(ctx.object, records, {
+      idempotencyKey: ctx.options.idempotencyKey,
+    });
+    report.succeeded = records.length;
+  } catch (error) {
+    report.failures = batchFailures(0, records.length, error);
+    report.firstError = error;
+  }
+  return report;
+}
+
+// Referenced records are created one at a time, in file order, so each
+// record's ID is known before any later record points at it. With a report,
+// failures are collected instead of thrown, and a record whose "$ref:" target
+// failed is reported as failed too rather than sent with the unresolved
+// reference. Without one there is no rollback, so the error lists what was
+// already created before the failure.
+async function createReferencedRecords(
+  ctx: ApiOperationContext,
+  plan: ReferencedRecord[],
+  report?: FailureReport,
+): Promise<unknown[]> {
+  const ids = new Map<string, string>();
+  const created: unknown[] = [];
+
+  for (const [index, entry] of plan.entries()) {
+    const missing = entry.refs.find((ref) => !ids.has(ref));
+    if (report && missing !== undefined) {
+      const dependency = plan.findIndex((other) => other.name === missing);
+      report.failures.push({
+        index: index + 1,
+        error: `Skipped: references "$ref:${missing}" from record ${dependency + 1}, which failed.`,
+      });
+      continue;
+    }
+    try {
+      created.push(await createReferencedRecord(ctx, entry, index, ids));
+    } catch (error) {
+      if (!report) {
+        throw created.length > 0 ? partialCreateError(plan, created, index, error) : error;
+      }
+      report.failures.push({ index: index + 1, error: describeFailure(error) });
+      report.firstError ??= error;
+      if (ctx.options.failFast) {
+        break;
+      }
+    }
+  }
+
+  return created;
+}
+
+async function createReferencedRecord(
+  ctx: ApiOperationContext,
+  entry: ReferencedRecord,
+  index: number,
+  ids: Map<string, string>,
+): Promise<unknown> {
+  const data = resolveReferences(entry.data, ids) as Record<string, unknown>;
+  // Each record is its own write, so a supplied key is suffixed per record.
+  const idempotencyKey = ctx.options.idempotencyKey
+    ? `${ctx.options.idempotencyKey}-${index + 1}`
+    : undefined;
+  const record = await ctx.services.records.create(entry.object, data, { idempotencyKey });
+  if (entry.name !== undefined) {
+    const id = (record as { id?: unknown } | null)?.id;
+    if (typeof id !== "string") {
+      throw new CliError(
+        `Record ${index + 1} ("${entry.name}") was created without an ID to reference.`,
+        "INVALID_ARGUMENTS",
+      );
+    }
+    ids.set(entry.name, id);
+  }
+
+  return record;
+}
+
+function partialCreateError(
+  plan: ReferencedRecord[],
+  created: unknown[],
+  index: number,
+  error: unknown,
+): CliError {
+  const lines = created.map((record, i) => {
+    const id = (record as { id?: unknown } | null)?.id;
+    const name = plan[i]!.name;
+    const tag = name !== undefined ? ` ($name ${name})` : "";
+    return `  ${i + 1}. ${plan[i]!.object} ${typeof id === "string" ? id : "(no id)"}${tag}`;
+  });
+  return errorWithCause(
+    [
+      `Record ${index + 1} failed: ${describeFailure(error)}`,
+      "Created before the failure:",
+      ...lines,
+    ].join("\n"),
+    error instanceof CliError ? error.code : "API_ERROR",
+    "Remove the created records from the input before re-running, or they will be created " +
+      "again; --only-errors continues past failures instead.",
+    error,
+  );
+}
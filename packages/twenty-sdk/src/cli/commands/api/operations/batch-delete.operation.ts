@@ -2,6 +2,7 @@ import { ApiOperationContext } from "./types";
 import { readJsonInput } from "../../../utilities/shared/io";
 import { CliError } from "../../../utilities/errors/cli-error";
 import { requireYes } from "../../../utilities/shared/confirmation";
+import { batchFailures, FailureReport, printFailureReport } from "./failure-report";
 
 export async function runBatchDeleteOperation(ctx: ApiOperationContext): Promise<void> {
   requireYes(ctx.options, "Batch delete");
@@ -31,6 +32,19 @@ export async function runBatchDeleteOperation(ctx: ApiOperationContext): Promise
     throw new CliError("No valid IDs provided.", "INVALID_ARGUMENTS");
   }
 
+  if (ctx.options.onlyErrors) {
+    const report: FailureReport = { action: "deleted", succeeded: 0, failures: [] };
+    try {
+      await ctx.services.records.batchDelete(ctx.object, ids);
+      report.succeeded = ids.length;
+    } catch (error) {
+      report.failures = batchFailures(0, ids.length, error, ids);
+      report.firstError = error;
+    }
+    printFailureReport(ctx, report);
+    return;
+  }
+
   const response = await ctx.services.records.batchDelete(ctx.object, ids);
   await ctx.services.output.render(response, {
     format: ctx.globalOptions.output,
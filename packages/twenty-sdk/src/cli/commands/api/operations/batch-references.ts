@@ -0,0 +1,98 @@
+import { CliError } from "../../../utilities/errors/cli-error";
+
+// Batch-create records may tag themselves with "$name" (and target another
+// object with "$object"); later records use "$ref:<name>" string values to
+// point at the created record's ID.
+const NAME_KEY = "$name";
+const OBJECT_KEY = "$object";
+const REF_PREFIX = "$ref:";
+
+export interface ReferencedRecord {
+  object: string;
+  name?: string;
+  data: Record<string, unknown>;
+  // The $name tags this record's "$ref:" values point at.
+  refs: string[];
+}
+
+export function hasBatchReferences(records: Record<string, unknown>[]): boolean {
+  return records.some(
+    (record) => NAME_KEY in record || OBJECT_KEY in record || collectRefs(record).length > 0,
+  );
+}
+
+// Validates tags and references up front so nothing is created when a file
+// contains an unknown or forward reference.
+export function planReferencedRecords(
+  records: Record<string, unknown>[],
+  defaultObject: string,
+): ReferencedRecord[] {
+  const allNames = new Set(records.map((record) => record[NAME_KEY]));
+  const created = new Set<string>();
+
+  return records.map((record, index) => {
+    const position = `Record ${index + 1}`;
+    const { [NAME_KEY]: rawName, [OBJECT_KEY]: rawObject, ...data } = record;
+    const name = parseTag(rawName, NAME_KEY, position);
+    const object = parseTag(rawObject, OBJECT_KEY, position);
+    if (name !== undefined && created.has(name)) {
+      throw new CliError(`${position} reuses ${NAME_KEY} "${name}".`, "INVALID_ARGUMENTS");
+    }
+
+    const refs = [...new Set(collectRefs(data))];
+    for (const ref of refs) {
+      if (!created.has(ref)) {
+        throw new CliError(
+          allNames.has(ref)
+            ? `${position} references "${REF_PREFIX}${ref}" before it is created.`
+            : `${position} references unknown "${REF_PREFIX}${ref}".`,
+          "INVALID_ARGUMENTS",
+          "Reference only records tagged with $name earlier in the same file.",
+        );
+      }
+    }
+
+    if (name !== undefined) {
+      created.add(name);
+    }
+    return { object: object ?? defaultObject, name, data, refs };
+  });
+}
+
+export function resolveReferences(value: unknown, ids: ReadonlyMap<string, string>): unknown {
+  if (typeof value === "string" && value.startsWith(REF_PREFIX)) {
+    return ids.get(value.slice(REF_PREFIX.length)) ?? value;
+  }
+  if (Array.isArray(value)) {
+    return value.map((item) => resolveReferences(item, ids));
+  }
+  if (typeof value === "object" && value !== null) {
+    return Object.fromEntries(
+      Object.entries(value).map(([key, item]) => [key, resolveReferences(item, ids)]),
+    );
+  }
+  return value;
+}
+
+function parseTag(value: unknown, key: string, position: string): string | undefined {
+  if (value === undefined) {
+    return undefined;
+  }
+  if (typeof value !== "string" || value === "") {
+    throw new CliError(`${position} has an invalid ${key}.`, "INVALID_ARGUMENTS");
+  }
+  return value;
+}
+
+function collectRefs(value: unknown): string[] {
+  if (typeof value === "string") {
+    return value.startsWith(REF_PREFIX) ? [value.slice(REF_PREFIX.length)] : [];
+  }
+  if (Array.isArray(value)) {
+    return value.flatMap(collectRefs);
+  }
+  if (typeof value === "object" && value !== null) {
+    return Object.values(value).flatMap(collectRefs);
+  }
+  return [];
+}
@@ -0,0 +1,40 @@
+import { CliError } from "../../../utilities/errors/cli-error";
+import { readFileOrStdin } from "../../../utilities/shared/io";
+import { renderRecordTemplate } from "../../../utilities/output/services/record-template";
+
+export async function loadBodyTemplate(filePath?: string): Promise<string | undefined> {
+  if (!filePath) {
+    return undefined;
+  }
+
+  return readFileOrStdin(filePath);
+}
+
+// Renders one import row through the body template and validates that the
+// result is a JSON object before anything is sent.
+export function renderBodyTemplate(
+  template: string,
+  row: Record<string, unknown>,
+  index: number,
+): Record<string, unknown> {
+  const rendered = renderRecordTemplate(template, row);
+  let body: unknown;
+  try {
+    body = JSON.parse(rendered);
+  } catch (error) {
+    throw new CliError(
+      `Record ${index + 1} template did not render valid JSON: ${(error as Error).message}`,
+      "INVALID_ARGUMENTS",
+      'Quote string values with {{.column | json}} instead of "{{.column}}".',
+    );
+  }
+
+  if (typeof body !== "object" || body === null || Array.isArray(body)) {
+    throw new CliError(
+      `Record ${index + 1} template must render a JSON object.`,
+      "INVALID_ARGUMENTS",
+    );
+  }
+
+  return body as Record<string, unknown>;
+}
@@ -0,0 +1,18 @@
+import { CliError } from "../../../utilities/errors/cli-error";
+import { ComputedColumn } from "../../../utilities/output/services/computed-columns";
+import { splitOnce } from "../../../utilities/shared/parse";
+
+export function parseComputedColumns(specs: string[] | undefined): ComputedColumn[] {
+  return (specs ?? []).map((spec) => {
+    const [rawName, template] = splitOnce(spec, "=");
+    const name = rawName.trim();
+    if (!name || !spec.includes("=")) {
+      throw new CliError(
+        `Invalid --computed value ${JSON.stringify(spec)}; expected name=template.`,
+        "INVALID_ARGUMENTS",
+        "Example: --computed fullName='{{.name.firstName}} {{.name.lastName}}'",
+      );
+    }
+    return { name, template };
+  });
+}
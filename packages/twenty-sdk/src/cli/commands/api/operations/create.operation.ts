@@ -1,11 +1,86 @@
 import { ApiOperationContext } from "./types";
+import { CliError } from "../../../utilities/errors/cli-error";
 import { parseBody } from "../../../utilities/shared/body";
+import { logVerbose } from "../../../utilities/shared/logger";
+import { printStatus } from "../../../utilities/output/services/status-printer";
 
 export async function runCreateOperation(ctx: ApiOperationContext): Promise<void> {
   const payload = await parseBody(ctx.options.data, ctx.options.file, ctx.options.set);
-  const record = await ctx.services.records.create(ctx.object, payload);
+  if (ctx.options.ifNotExists) {
+    await createIfNotExists(ctx, ctx.options.ifNotExists, payload);
+    return;
+  }
+
+  const record = await ctx.services.records.create(ctx.object, payload, {
+    idempotencyKey: ctx.options.idempotencyKey,
+  });
+  const id = (record as { id?: unknown } | null)?.id;
+  logVerbose(`Created ${ctx.object} record${typeof id === "string" ? ` ${id}` : ""}`);
   await ctx.services.output.render(record, {
     format: ctx.globalOptions.output,
     query: ctx.globalOptions.query,
   });
 }
+
+// --if-not-exists <field> looks the payload's value up first and skips the
+// create when a record already has it; a concurrent create that wins the
+// race is reported the same way.
+async function createIfNotExists(
+  ctx: ApiOperationContext,
+  field: string,
+  payload: Record<string, unknown>,
+): Promise<void> {
+  const value = getPathValue(payload, field);
+  if (value === undefined || value === null || value === "" || typeof value === "object") {
+    throw new CliError(
+      `--if-not-exists ${field} needs a ${field} value in the payload.`,
+      "INVALID_ARGUMENTS",
+      "Name a unique field that the payload sets, e.g. --if-not-exists emails.primaryEmail.",
+    );
+  }
+
+  const { record, created, updated } = await ctx.services.records.ensure(
+    ctx.object,
+    field,
+    String(value),
+    payload,
+  );
+  const id = (record as { id?: unknown } | null)?.id;
+  if (created) {
+    logVerbose(`Created ${ctx.object} record${typeof id === "string" ? ` ${id}` : ""}`);
+    await ctx.services.output.render(record, {
+      format: ctx.globalOptions.output,
+      query: ctx.globalOptions.query,
+    });
+    return;
+  }
+
+  // A record another writer created after the lookup was matched by the
+  // upsert and now carries the payload.
+  const existingId = typeof id === "string" ? id : undefined;
+  printStatus(
+    ctx.globalOptions,
+    {
+      action: updated ? "updated-existing" : "skipped-existing",
+      object: ctx.object,
+      id: existingId,
+      field,
+      value,
+      message:
+        (updated
+          ? `Updated: ${ctx.object} with ${field} = ${String(value)} was created concurrently`
+          : `Skipped: ${ctx.object} with ${field} = ${String(value)} already exists`) +
+        (existingId ? ` (${existingId})` : ""),
+    },
+    ctx.services.writer,
+  );
+}
+
+function getPathValue(record: Record<string, unknown>, path: string): unknown {
+  return path.split(".").reduce<unknown>((value, key) => {
+    if (value && typeof value === "object" && !Array.isArray(value)) {
+      return (value as Record<string, unknown>)[key];
+    }
+    return undefined;
+  }, record);
+}
@@ -0,0 +1,43 @@
+import { CliError } from "../../../utilities/errors/cli-error";
+import {
+  CSV_ARRAY_MODES,
+  CsvArrayMode,
+  CsvFlattenOptions,
+} from "../../../utilities/output/services/csv-flatten";
+import { ApiCommandOptions } from "./types";
+
+export function resolveCsvFlattenOptions(
+  options: ApiCommandOptions,
+): CsvFlattenOptions | undefined {
+  const depthRaw = options.flattenDepth;
+  const arraysRaw = options.flattenArrays;
+  if (!options.flatten && depthRaw === undefined && arraysRaw === undefined) {
+    return undefined;
+  }
+
+  const resolved: CsvFlattenOptions = {};
+
+  if (depthRaw !== undefined) {
+    const depth = Number(depthRaw);
+    if (!Number.isInteger(depth) || depth < 1) {
+      throw new CliError(
+        `Invalid --flatten-depth value ${JSON.stringify(depthRaw)}; expected a positive integer.`,
+        "INVALID_ARGUMENTS",
+      );
+    }
+    resolved.depth = depth;
+  }
+
+  if (arraysRaw !== undefined) {
+    const mode = arraysRaw.trim().toLowerCase() as CsvArrayMode;
+    if (!CSV_ARRAY_MODES.includes(mode)) {
+      throw new CliError(
+        `Invalid --flatten-arrays value ${JSON.stringify(arraysRaw)}; expected one of ${CSV_ARRAY_MODES.join(", ")}.`,
+        "INVALID_ARGUMENTS",
+      );
+    }
+    resolved.arrays = mode;
+  }
+
+  return resolved;
+}
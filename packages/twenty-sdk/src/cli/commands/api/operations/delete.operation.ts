@@ -1,18 +1,53 @@
 import { ApiOperationContext } from "./types";
 import { CliError } from "../../../utilities/errors/cli-error";
 import { requireYes } from "../../../utilities/shared/confirmation";
+import { printStatus } from "../../../utilities/output/services/status-printer";
+import { isNotFound } from "./not-found";
+import { readIdFileOption, runForEachId } from "./id-file";
+
+const ALREADY_ABSENT = Symbol("already-absent");
 
 export async function runDeleteOperation(ctx: ApiOperationContext): Promise<void> {
   const id = ctx.arg;
-  if (!id) {
+  if (!id && !ctx.options.idFile?.trim()) {
     throw new CliError("Missing record ID.", "INVALID_ARGUMENTS");
   }
   requireYes(ctx.options, "Delete");
+  const ids = await readIdFileOption(ctx);
 
-  const response = await ctx.services.records.delete(ctx.object, id);
+  if (ids) {
+    await runForEachId(ctx, ids, "deleted", async (recordId) => {
+      const absent = (await deleteRecord(ctx, recordId)) === ALREADY_ABSENT;
+      return { status: absent ? "already-absent" : "deleted" };
+    });
+    return;
+  }
+
+  const response = await deleteRecord(ctx, id!);
+  if (response === ALREADY_ABSENT) {
+    printStatus(
+      ctx.globalOptions,
+      {
+        action: "already-absent",
+        object: ctx.object,
+        id,
+        message: `${ctx.object} ${id} is already absent`,
+      },
+      ctx.services.writer,
+    );
+    return;
+  }
   if (response == null || (typeof response === "string" && response === "")) {
-    // eslint-disable-next-line no-console
-    console.log(`Deleted ${ctx.object} ${id}`);
+    printStatus(
+      ctx.globalOptions,
+      {
+        action: "deleted",
+        object: ctx.object,
+        id,
+        message: `Deleted ${ctx.object} ${id}`,
+      },
+      ctx.services.writer,
+    );
     return;
   }
   await ctx.services.output.render(response, {
@@ -20,3 +55,16 @@ export async function runDeleteOperation(ctx: ApiOperationContext): Promise<void
     query: ctx.globalOptions.query,
   });
 }
+
+// --if-exists makes reruns safe: a record that is already gone counts as
+// done, reported as already-absent rather than deleted.
+async function deleteRecord(ctx: ApiOperationContext, id: string): Promise<unknown> {
+  try {
+    return await ctx.services.records.delete(ctx.object, id);
+  } catch (error) {
+    if (!ctx.options.ifExists || !isNotFound(error)) {
+      throw error;
+    }
+    return ALREADY_ABSENT;
+  }
+}
@@ -2,6 +2,7 @@ import { ApiOperationContext } from "./types";
 import { CliError } from "../../../utilities/errors/cli-error";
 import { resolveBulkFilter } from "./bulk-filter";
 import { requireYes } from "../../../utilities/shared/confirmation";
+import { printStatus } from "../../../utilities/output/services/status-printer";
 
 export async function runDestroyOperation(ctx: ApiOperationContext): Promise<void> {
   const id = ctx.arg;
@@ -14,8 +15,16 @@ export async function runDestroyOperation(ctx: ApiOperationContext): Promise<voi
   if (id) {
     const response = await ctx.services.records.destroy(ctx.object, id);
     if (response == null || (typeof response === "string" && response === "")) {
-      // eslint-disable-next-line no-console
-      console.log(`Destroyed ${ctx.object} ${id}`);
+      printStatus(
+        ctx.globalOptions,
+        {
+          action: "destroyed",
+          object: ctx.object,
+          id,
+          message: `Destroyed ${ctx.object} ${id}`,
+        },
+        ctx.services.writer,
+      );
       return;
     }
     await ctx.services.output.render(response, {
@@ -0,0 +1,102 @@
+import { ApiOperationContext } from "./types";
+import { CliError } from "../../../utilities/errors/cli-error";
+import type { ListOptions } from "../../../utilities/records/services/api-records-read.service";
+
+export interface DistinctValue {
+  value: unknown;
+  count: number;
+}
+
+// Counts values as pages arrive, so only the distinct set is held in memory.
+// Array fields count each element; null, missing, and "" values are skipped.
+export class DistinctCounter {
+  private readonly counts = new Map<string, DistinctValue>();
+
+  constructor(private readonly field: string) {}
+
+  add(records: unknown[]): void {
+    for (const record of records) {
+      const value = getFieldValue(record, this.field);
+      for (const item of Array.isArray(value) ? value : [value]) {
+        if (item === null || item === undefined || item === "") {
+          continue;
+        }
+        const key = JSON.stringify(item);
+        const entry = this.counts.get(key);
+        if (entry) {
+          entry.count += 1;
+        } else {
+          this.counts.set(key, { value: item, count: 1 });
+        }
+      }
+    }
+  }
+
+  // Most frequent first; ties in value order so output is stable.
+  values(): DistinctValue[] {
+    return [...this.counts.values()].sort(
+      (a, b) => b.count - a.count || compareValues(a.value, b.value),
+    );
+  }
+}
+
+// --distinct walks every page, so it implies --all; --limit still sets the
+// page size. Only the field's top-level key is requested from the server.
+export async function runDistinctList(
+  ctx: ApiOperationContext,
+  field: string,
+  listOptions: ListOptions,
+): Promise<void> {
+  const { services, globalOptions } = ctx;
+  if (ctx.options.stream || ctx.options.outputFile) {
+    throw new CliError(
+      `--distinct does not support ${ctx.options.stream ? "--stream" : "--output-file"}.`,
+      "INVALID_ARGUMENTS",
+    );
+  }
+
+  const counter = new DistinctCounter(field);
+  await services.records.listAll(ctx.object, {
+    ...listOptions,
+    fields: [field.split(".")[0]!],
+    onPage: async (records) => counter.add(records),
+  });
+
+  const values = counter.values();
+  const format = globalOptions.output ?? "json";
+  const data = ctx.options.withCounts
+    ? values
+    : format === "json" || format === "jsonl"
+      ? values.map((entry) => entry.value)
+      : values.map((entry) => ({ value: entry.value }));
+  await services.output.render(data, { format, query: globalOptions.query });
+}
+
+export function resolveDistinctField(ctx: ApiOperationContext): string | undefined {
+  const field = ctx.options.distinct?.trim();
+  if (ctx.options.distinct !== undefined && !field) {
+    throw new CliError("--distinct needs a field name.", "INVALID_ARGUMENTS");
+  }
+  if (ctx.options.withCounts && !field) {
+    throw new CliError("--with-counts requires --distinct <field>.", "INVALID_ARGUMENTS");
+  }
+  return field;
+}
+
+function getFieldValue(record: unknown, path: string): unknown {
+  return path.split(".").reduce<unknown>((value, key) => {
+    if (value && typeof value === "object" && !Array.isArray(value)) {
+      return (value as Record<string, unknown>)[key];
+    }
+    return undefined;
+  }, record);
+}
+
+function compareValues(a: unknown, b: unknown): number {
+  if (typeof a === "number" && typeof b === "number") {
+    return a - b;
+  }
+  return String(typeof a === "object" ? JSON.stringify(a) : a).localeCompare(
+    String(typeof b === "object" ? JSON.stringify(b) : b),
+  );
+}
@@ -0,0 +1,56 @@
+import { CliError } from "../../../utilities/errors/cli-error";
+
+export function parseExpandRelations(raw: string | undefined): string[] {
+  if (raw === undefined) {
+    return [];
+  }
+
+  const relations = raw
+    .split(",")
+    .map((relation) => relation.trim())
+    .filter(Boolean);
+  if (relations.length === 0) {
+    throw new CliError("--expand requires at least one relation name.", "INVALID_ARGUMENTS");
+  }
+
+  return relations;
+}
+
+// Replaces each expanded relation object with a flat `<relation>Name` column so
+// exports stay one row per record. Missing relations yield an empty string.
+export function inlineRelationNames(
+  records: Record<string, unknown>[],
+  relations: string[],
+): Record<string, unknown>[] {
+  if (relations.length === 0) {
+    return records;
+  }
+
+  return records.map((record) => {
+    const result = { ...record };
+    for (const relation of relations) {
+      result[`${relation}Name`] = resolveDisplayName(record[relation]);
+      delete result[relation];
+    }
+    return result;
+  });
+}
+
+function resolveDisplayName(related: unknown): string {
+  if (typeof related !== "object" || related === null) {
+    return "";
+  }
+
+  const name = (related as Record<string, unknown>).name;
+  if (typeof name === "string") {
+    return name;
+  }
+  if (typeof name === "object" && name !== null) {
+    const { firstName, lastName } = name as Record<string, unknown>;
+    return [firstName, lastName]
+      .filter((part): part is string => typeof part === "string" && part.length > 0)
+      .join(" ");
+  }
+
+  return "";
+}
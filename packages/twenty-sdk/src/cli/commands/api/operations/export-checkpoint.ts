@@ -0,0 +1,136 @@
+import fs from "fs-extra";
+import { CliError } from "../../../utilities/errors/cli-error";
+import { writeFileAtomic } from "../../../utilities/file/services/atomic-write";
+import { log, logVerbose } from "../../../utilities/shared/logger";
+import { ApiOperationContext } from "./types";
+
+export interface ExportCheckpoint {
+  object: string;
+  format: "json" | "csv";
+  output: string;
+  // Cursor after the last page written; the next run starts here.
+  cursor: string;
+  records: number;
+  // Output size after that page, so bytes written past it can be dropped.
+  bytes: number;
+  // CSV header columns, reused so appended rows line up.
+  columns?: string[];
+  updatedAt: string;
+}
+
+export interface CheckpointPlan {
+  // Where progress is saved after each page.
+  path: string;
+  // The checkpoint being continued with --resume.
+  resumed?: ExportCheckpoint;
+}
+
+// --checkpoint <file> saves progress after every page; --resume <file> picks
+// up from a saved checkpoint and keeps updating it. Both need a single output
+// file that pages can be appended to: CSV, or JSON with --stream.
+export async function resolveCheckpointPlan(
+  ctx: ApiOperationContext,
+  details: { format: string; outputFile?: string; stream: boolean; split: boolean },
+): Promise<CheckpointPlan | undefined> {
+  const { checkpoint, resume } = ctx.options;
+  if (!checkpoint && !resume) {
+    return undefined;
+  }
+
+  const flag = resume ? "--resume" : "--checkpoint";
+  if (ctx.options.all !== true) {
+    throw new CliError(`${flag} requires --all.`, "INVALID_ARGUMENTS");
+  }
+  if (details.split) {
+    throw new CliError(
+      `${flag} cannot be combined with --split-size or --split-bytes.`,
+      "INVALID_ARGUMENTS",
+    );
+  }
+  if (details.format === "json" && !details.stream) {
+    throw new CliError(
+      `${flag} needs CSV or JSON with --stream.`,
+      "INVALID_ARGUMENTS",
+      "A JSON array cannot be appended to; use --stream to write NDJSON.",
+    );
+  }
+  if (!resume) {
+    if (!details.outputFile) {
+      throw new CliError("--checkpoint requires --output-file.", "INVALID_ARGUMENTS");
+    }
+    return { path: checkpoint! };
+  }
+
+  if (ctx.options.cursor) {
+    throw new CliError("--resume cannot be combined with --cursor.", "INVALID_ARGUMENTS");
+  }
+  const resumed = await readExportCheckpoint(resume);
+  if (resumed.object !== ctx.object || resumed.format !== details.format) {
+    throw new CliError(
+      `Checkpoint ${resume} is for a ${resumed.format} export of ${resumed.object}.`,
+      "INVALID_ARGUMENTS",
+    );
+  }
+  if (details.outputFile && details.outputFile !== resumed.output) {
+    throw new CliError(
+      `Checkpoint ${resume} continues ${resumed.output}, not ${details.outputFile}.`,
+      "INVALID_ARGUMENTS",
+    );
+  }
+  if (!(await fs.pathExists(resumed.output))) {
+    throw new CliError(
+      `Cannot resume: ${resumed.output} no longer exists.`,
+      "INVALID_ARGUMENTS",
+      "Start the export over with --checkpoint.",
+    );
+  }
+  return { path: checkpoint ?? resume, resumed };
+}
+
+async function readExportCheckpoint(checkpointPath: string): Promise<ExportCheckpoint> {
+  let parsed: Partial<ExportCheckpoint>;
+  try {
+    parsed = JSON.parse(await fs.readFile(checkpointPath, "utf-8"));
+  } catch (error) {
+    throw new CliError(
+      `Cannot read checkpoint ${checkpointPath}: ${(error as Error).message}`,
+      "INVALID_ARGUMENTS",
+    );
+  }
+  if (
+    typeof parsed.object !== "string" ||
+    (parsed.format !== "json" && parsed.format !== "csv") ||
+    typeof parsed.output !== "string" ||
+    typeof parsed.cursor !== "string" ||
+    typeof parsed.records !== "number" ||
+    typeof parsed.bytes !== "number"
+  ) {
+    throw new CliError(
+      `Checkpoint ${checkpointPath} is not a valid export checkpoint.`,
+      "INVALID_ARGUMENTS",
+    );
+  }
+  return parsed as ExportCheckpoint;
+}
+
+// Written atomically after every page, so a failure leaves either the previous
+// checkpoint or the new one, never a partial file.
+export async function saveExportCheckpoint(
+  checkpointPath: string,
+  checkpoint: Omit<ExportCheckpoint, "updatedAt">,
+): Promise<void> {
+  const content: ExportCheckpoint = { ...checkpoint, updatedAt: new Date().toISOString() };
+  await writeFileAtomic(checkpointPath, `${JSON.stringify(content, null, 2)}\n`);
+  logVerbose(`Checkpoint saved at ${checkpoint.records} records`, { path: checkpointPath });
+}
+
+// A finished export no longer needs its checkpoint; removing it keeps a later
+// --resume from appending a second copy of the last pages.
+export async function clearExportCheckpoint(checkpointPath: string): Promise<void> {
+  await fs.remove(checkpointPath);
+  logVerbose(`Export complete; removed checkpoint ${checkpointPath}`);
+}
+
+export function reportCheckpointResume(checkpointPath: string): void {
+  log("warn", `Progress is saved; resume with --resume ${checkpointPath}`);
+}
@@ -0,0 +1,71 @@
+import { writeFileAtomic } from "../../../utilities/file/services/atomic-write";
+import { log } from "../../../utilities/shared/logger";
+import { CLI_VERSION } from "../../../version";
+import { ApiOperationContext } from "./types";
+
+export interface ExportManifest {
+  exportedAt: string;
+  cliVersion: string;
+  object: string;
+  format: string;
+  records: number;
+  // Data files written; empty when the export went to stdout.
+  files: string[];
+  baseUrl: string;
+  profile?: string;
+  filters: ExportManifestFilters;
+}
+
+export interface ExportManifestFilters {
+  filter?: string;
+  sort?: string;
+  order?: string;
+  include?: string;
+  fields?: string[];
+  cursor?: string;
+  limit?: number;
+  all?: boolean;
+  params?: Record<string, string[]>;
+}
+
+// --manifest: a provenance sidecar written once the data is complete. It is
+// written atomically, so a reader never sees a partial manifest. The API token
+// is never included.
+export async function writeExportManifest(
+  ctx: ApiOperationContext,
+  details: Pick<ExportManifest, "format" | "records" | "files" | "filters">,
+): Promise<void> {
+  const manifestPath = ctx.options.manifest;
+  if (!manifestPath) {
+    return;
+  }
+
+  const { apiUrl, workspace } = await ctx.services.config.resolveApiConfig({
+    workspace: ctx.globalOptions.workspace,
+  });
+  const manifest: ExportManifest = {
+    exportedAt: new Date().toISOString(),
+    cliVersion: CLI_VERSION,
+    object: ctx.object,
+    format: details.format,
+    records: details.records,
+    files: details.files,
+    baseUrl: apiUrl,
+    ...(workspace ? { profile: workspace } : {}),
+    filters: dropEmpty(details.filters),
+  };
+
+  await writeFileAtomic(manifestPath, `${JSON.stringify(manifest, null, 2)}\n`);
+  log("info", `Wrote export manifest to ${manifestPath}`, { path: manifestPath });
+}
+
+function dropEmpty(filters: ExportManifestFilters): ExportManifestFilters {
+  return Object.fromEntries(
+    Object.entries(filters).filter(([, value]) => {
+      if (value === undefined || value === false) {
+        return false;
+      }
+      return typeof value !== "object" || Object.keys(value).length > 0;
+    }),
+  );
+}
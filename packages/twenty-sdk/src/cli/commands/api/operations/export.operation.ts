@@ -1,45 +1,424 @@
+import fs from "fs-extra";
 import { ApiOperationContext } from "./types";
-import { parseKeyValuePairs } from "../../../utilities/shared/parse";
+import { parseFieldList, parseQueryParams } from "../../../utilities/shared/parse";
 import { CliError } from "../../../utilities/errors/cli-error";
+import { maskRecordFields } from "../../../utilities/output/services/mask-fields";
+import { pruneRecordFields } from "../../../utilities/output/services/prune-fields";
+import { resolveCsvFlattenOptions } from "./csv-flatten-options";
+import {
+  clearExportCheckpoint,
+  reportCheckpointResume,
+  resolveCheckpointPlan,
+  saveExportCheckpoint,
+} from "./export-checkpoint";
+import { ExportManifestFilters, writeExportManifest } from "./export-manifest";
+import { inlineRelationNames, parseExpandRelations } from "./expand-relations";
+import { resolveJsonLayout, resolveStreamHeader, writeStreamHeader } from "./json-layout-options";
+import { LocalSort, parseLocalSort, sortRecordsLocally } from "./local-sort";
+import { resolvePageSize } from "./page-size-options";
+import { resolveSplitLimits } from "./split-export-options";
+import { reportInterrupted, runInterruptible } from "../../../utilities/shared/interrupt";
+import { printStatus } from "../../../utilities/output/services/status-printer";
+import { sortJsonKeys } from "../../../utilities/output/services/sort-keys";
+import type {
+  ListOptions,
+  ListResponse,
+  PageInfo,
+} from "../../../utilities/records/services/api-records-read.service";
 
-const OUTPUT_FORMATS = new Set(["json", "csv", "text"]);
+const OUTPUT_FORMATS = new Set(["json", "csv", "text", "table"]);
 
-export async function runExportOperation(ctx: ApiOperationContext): Promise<void> {
+export interface ExportHooks {
+  // Applied to each record just before CSV serialization.
+  csvRecord?: (record: Record<string, unknown>) => Record<string, unknown>;
+}
+
+export async function runExportOperation(
+  ctx: ApiOperationContext,
+  hooks: ExportHooks = {},
+): Promise<void> {
   const format = (ctx.options.format ?? "json").toLowerCase();
-  if (format !== "json" && format !== "csv") {
+  if (format !== "json" && format !== "csv" && format !== "parquet") {
     throw new CliError(`Unsupported export format ${JSON.stringify(format)}.`, "INVALID_ARGUMENTS");
   }
-  if (ctx.options.fields) {
+
+  const flatten = resolveCsvFlattenOptions(ctx.options);
+  if (flatten && format !== "csv") {
+    throw new CliError("--flatten requires --format csv.", "INVALID_ARGUMENTS");
+  }
+  const split = resolveSplitLimits(ctx.options);
+  const stream = resolveJsonLayout(ctx.options, format) === "stream";
+  const localSort = parseLocalSort(ctx.options.sortLocal);
+  if (format === "parquet" && (split || ctx.options.checkpoint || ctx.options.resume)) {
+    throw new CliError(
+      "--format parquet writes one file and cannot be combined with " +
+        "--split-size, --split-bytes, --checkpoint, or --resume.",
+      "INVALID_ARGUMENTS",
+    );
+  }
+  if (localSort && (split || ctx.options.checkpoint || ctx.options.resume)) {
     throw new CliError(
-      "--fields is not supported for export. Twenty REST find-many only supports depth-based field expansion.",
+      "--sort-local needs the full result and cannot be combined with " +
+        "--split-size, --split-bytes, --checkpoint, or --resume.",
       "INVALID_ARGUMENTS",
     );
   }
 
-  const params = parseKeyValuePairs(ctx.options.param);
+  const expand = parseExpandRelations(ctx.options.expand);
+  const params = parseQueryParams(ctx.options.param);
+  const pageSize = resolvePageSize(ctx.options.pageSize, ctx.options.all === true);
   const limit = ctx.options.limit ? Number(ctx.options.limit) : 200;
   const listOptions = {
-    limit: Number.isNaN(limit) ? 200 : limit,
+    limit: pageSize ?? (Number.isNaN(limit) ? 200 : limit),
     cursor: ctx.options.cursor,
     filter: ctx.options.filter,
-    include: ctx.options.include,
+    include: ctx.options.include ?? (expand.length > 0 ? expand.join(",") : undefined),
     sort: ctx.options.sort,
     order: ctx.options.order,
+    fields: parseFieldList(ctx.options.fields),
     params,
   };
-
-  const shouldAll = ctx.options.all === true;
-  const response = shouldAll
-    ? await ctx.services.records.listAll(ctx.object, listOptions)
-    : await ctx.services.records.list(ctx.object, listOptions);
+  // --fields also fixes the CSV and Parquet column order for fixed-schema importers.
+  const columns = format === "json" ? undefined : listOptions.fields;
 
   let outputFile = ctx.options.outputFile;
   if (!outputFile && ctx.options.output && !OUTPUT_FORMATS.has(ctx.options.output)) {
     outputFile = ctx.options.output;
   }
 
-  await ctx.services.exporter.export(response.data as Record<string, unknown>[], {
+  const toRows = (data: unknown[]): Record<string, unknown>[] => {
+    const pruned = pruneRecordFields(
+      inlineRelationNames(data as Record<string, unknown>[], expand),
+      ctx.globalOptions.pruneFields,
+    ) as Record<string, unknown>[];
+    const rows = format === "csv" && hooks.csvRecord ? pruned.map(hooks.csvRecord) : pruned;
+    const masked = maskRecordFields(rows, ctx.globalOptions.maskFields) as typeof rows;
+    // --sort-keys: stable key order so two JSON exports diff cleanly.
+    return format === "json" && ctx.globalOptions.sortKeys ? sortJsonKeys(masked) : masked;
+  };
+
+  const streamHeader = resolveStreamHeader(
+    ctx.options,
+    Boolean(outputFile || split || ctx.options.checkpoint || ctx.options.resume),
+  );
+  const shouldAll = ctx.options.all === true;
+  const checkpoint = await resolveCheckpointPlan(ctx, {
+    format,
+    outputFile,
+    stream,
+    split: split !== undefined,
+  });
+  if (checkpoint?.resumed) {
+    listOptions.cursor = checkpoint.resumed.cursor;
+  }
+  const manifestFilters: ExportManifestFilters = {
+    filter: listOptions.filter,
+    sort: listOptions.sort,
+    order: listOptions.order,
+    include: listOptions.include,
+    fields: listOptions.fields,
+    cursor: listOptions.cursor,
+    limit: listOptions.limit,
+    all: shouldAll,
+    params,
+  };
+  if (checkpoint) {
+    // Each page is appended to the output before its cursor is saved, so the
+    // checkpoint never points past data that is not on disk.
+    const output = outputFile ?? checkpoint.resumed!.output;
+    const resumed = checkpoint.resumed;
+    const writer = ctx.services.exporter.createAppendWriter({
+      format: format as "json" | "csv",
+      output,
+      ...(columns ? { columns } : {}),
+      ...(flatten ? { flatten } : {}),
+      ...(ctx.globalOptions.csvQuoteAll ? { quoteAll: true } : {}),
+      ...(ctx.globalOptions.csvBom ? { bom: true } : {}),
+      ...(ctx.globalOptions.csvNumbers ? { numbers: ctx.globalOptions.csvNumbers } : {}),
+      ...(resumed
+        ? {
+            resume: {
+              bytes: resumed.bytes,
+              records: resumed.records,
+              ...(resumed.columns ? { columns: resumed.columns } : {}),
+            },
+          }
+        : {}),
+    });
+    let cursor = resumed?.cursor;
+    const onPage = async (data: unknown[], pageInfo?: PageInfo) => {
+      await writer.write(toRows(data));
+      cursor = pageInfo?.endCursor ?? cursor;
+      if (cursor) {
+        await saveExportCheckpoint(checkpoint.path, {
+          object: ctx.object,
+          format: format as "json" | "csv",
+          output,
+          cursor,
+          records: writer.recordCount,
+          bytes: writer.byteCount,
+          ...(writer.csvColumns ? { columns: writer.csvColumns } : {}),
+        });
+      }
+    };
+    let response: ListResponse;
+    try {
+      response = await runInterruptible((signal) =>
+        ctx.services.records.listAll(ctx.object, { ...listOptions, signal, onPage }),
+      );
+    } catch (error) {
+      if (await fs.pathExists(checkpoint.path)) {
+        reportCheckpointResume(checkpoint.path);
+      }
+      throw error;
+    }
+    await writer.close();
+    printStatus(
+      ctx.globalOptions,
+      {
+        action: "exported",
+        object: ctx.object,
+        records: writer.recordCount,
+        files: [output],
+        message: `Exported ${writer.recordCount} ${ctx.object} records to ${output}`,
+      },
+      ctx.services.writer,
+    );
+    if (response.interrupted) {
+      reportInterrupted(
+        `Export interrupted after ${writer.recordCount} records. ` +
+          `Resume with --resume ${checkpoint.path}`,
+      );
+      return;
+    }
+    await clearExportCheckpoint(checkpoint.path);
+    await writeExportManifest(ctx, {
+      format,
+      records: writer.recordCount,
+      files: [output],
+      filters: manifestFilters,
+    });
+    return;
+  }
+
+  if (split) {
+    // Pages are written as they arrive so a large export never sits in memory.
+    const writer = ctx.services.exporter.createSplitWriter({
+      format: format as "json" | "csv",
+      output: outputFile ?? `${ctx.object}.${format}`,
+      ...split,
+      ...(stream ? { stream } : {}),
+      ...(columns ? { columns } : {}),
+      ...(flatten ? { flatten } : {}),
+      ...(ctx.globalOptions.csvQuoteAll ? { quoteAll: true } : {}),
+      ...(ctx.globalOptions.csvBom ? { bom: true } : {}),
+      ...(ctx.globalOptions.csvNumbers ? { numbers: ctx.globalOptions.csvNumbers } : {}),
+    });
+    const onPage = (data: unknown[]) => writer.write(toRows(data));
+    let response: ListResponse;
+    if (shouldAll) {
+      response = await runInterruptible((signal) =>
+        ctx.services.records.listAll(ctx.object, { ...listOptions, signal, onPage }),
+      );
+    } else {
+      response = await ctx.services.records.list(ctx.object, listOptions);
+      await onPage(response.data);
+    }
+    const files = await writer.close();
+    printStatus(
+      ctx.globalOptions,
+      {
+        action: "exported",
+        object: ctx.object,
+        records: writer.recordCount,
+        files,
+        message: [
+          `Exported ${writer.recordCount} ${ctx.object} records to ${files.length} files:`,
+          ...files,
+        ],
+      },
+      ctx.services.writer,
+    );
+    reportExportInterrupted(response, writer.recordCount, files);
+    if (!response.interrupted) {
+      await writeExportManifest(ctx, {
+        format,
+        records: writer.recordCount,
+        files,
+        filters: manifestFilters,
+      });
+    }
+    return;
+  }
+
+  if (format === "parquet") {
+    await exportParquet(ctx, {
+      output: outputFile ?? `${ctx.object}.parquet`,
+      listOptions,
+      columns,
+      toRows,
+      shouldAll,
+      localSort,
+      manifestFilters,
+    });
+    return;
+  }
+
+  if (stream && !outputFile && shouldAll && !localSort) {
+    // NDJSON to stdout: print each page as it arrives instead of buffering.
+    let written = 0;
+    let headerWritten = !streamHeader;
+    const response = await runInterruptible((signal) =>
+      ctx.services.records.listAll(ctx.object, {
+        ...listOptions,
+        signal,
+        onPage: async (data, _pageInfo, totalCount) => {
+          if (!headerWritten) {
+            writeStreamHeader(totalCount, ctx.services.writer);
+            headerWritten = true;
+          }
+          if (data.length === 0) {
+            return;
+          }
+          const rows = toRows(data);
+          await ctx.services.exporter.export(rows, { format: "json", stream });
+          written += rows.length;
+        },
+      }),
+    );
+    reportExportInterrupted(response, written);
+    if (!response.interrupted) {
+      await writeExportManifest(ctx, {
+        format,
+        records: written,
+        files: [],
+        filters: manifestFilters,
+      });
+    }
+    return;
+  }
+
+  // Ctrl-C during --all stops paging; the records fetched so far are still
+  // written and the cursor to resume from is printed.
+  const response = shouldAll
+    ? await runInterruptible((signal) =>
+        ctx.services.records.listAll(ctx.object, { ...listOptions, signal }),
+      )
+    : await ctx.services.records.list(ctx.object, listOptions);
+
+  const records = toRows(
+    localSort ? sortRecordsLocally(response.data, localSort) : response.data,
+  );
+  if (streamHeader) {
+    writeStreamHeader(response.totalCount, ctx.services.writer);
+  }
+  await ctx.services.exporter.export(records, {
     format: format as "json" | "csv",
     output: outputFile,
+    ...(stream ? { stream } : {}),
+    ...(columns ? { columns } : {}),
+    ...(flatten ? { flatten } : {}),
+    ...(ctx.globalOptions.csvQuoteAll ? { quoteAll: true } : {}),
+    ...(ctx.globalOptions.csvBom ? { bom: true } : {}),
+    ...(ctx.globalOptions.csvNumbers ? { numbers: ctx.globalOptions.csvNumbers } : {}),
   });
+  reportExportInterrupted(response, records.length, outputFile ? [outputFile] : []);
+  if (!response.interrupted) {
+    await writeExportManifest(ctx, {
+      format,
+      records: records.length,
+      files: outputFile ? [outputFile] : [],
+      filters: manifestFilters,
+    });
+  }
+}
+
+interface ParquetExportPlan {
+  output: string;
+  listOptions: ListOptions;
+  columns?: string[];
+  toRows: (data: unknown[]) => Record<string, unknown>[];
+  shouldAll: boolean;
+  localSort?: LocalSort;
+  manifestFilters: ExportManifestFilters;
+}
+
+// Parquet is binary, so it always goes to a file (<object>.parquet unless
+// --output-file is set). With --all, pages are spooled to disk as they arrive
+// and the file is written at the end; --sort-local still needs the full
+// result first.
+async function exportParquet(ctx: ApiOperationContext, plan: ParquetExportPlan): Promise<void> {
+  const { output, listOptions, localSort } = plan;
+  const writer = ctx.services.exporter.createParquetWriter({
+    output,
+    ...(plan.columns ? { columns: plan.columns } : {}),
+  });
+  const onPage = (data: unknown[]) => writer.write(plan.toRows(data));
+  let response: ListResponse;
+  try {
+    if (plan.shouldAll && !localSort) {
+      response = await runInterruptible((signal) =>
+        ctx.services.records.listAll(ctx.object, { ...listOptions, signal, onPage }),
+      );
+    } else {
+      response = plan.shouldAll
+        ? await runInterruptible((signal) =>
+            ctx.services.records.listAll(ctx.object, { ...listOptions, signal }),
+          )
+        : await ctx.services.records.list(ctx.object, listOptions);
+      await onPage(localSort ? sortRecordsLocally(response.data, localSort) : response.data);
+    }
+  } catch (error) {
+    // No partial Parquet file: the spooled pages are discarded.
+    await writer.abort();
+    throw error;
+  }
+  await writer.close();
+  printStatus(
+    ctx.globalOptions,
+    {
+      action: "exported",
+      object: ctx.object,
+      records: writer.recordCount,
+      files: [output],
+      message: `Exported ${writer.recordCount} ${ctx.object} records to ${output}`,
+    },
+    ctx.services.writer,
+  );
+  reportExportInterrupted(response, writer.recordCount, [output]);
+  if (!response.interrupted) {
+    await writeExportManifest(ctx, {
+      format: "parquet",
+      records: writer.recordCount,
+      files: [output],
+      filters: plan.manifestFilters,
+    });
+  }
+}
+
+// files are the ones this run wrote. A rerun with --cursor would write the
+// same names from scratch, so the hint asks for a different --output-file.
+function reportExportInterrupted(
+  response: ListResponse,
+  written: number,
+  files: string[] = [],
+): void {
+  if (!response.interrupted) {
+    return;
+  }
+  const cursor = response.pageInfo?.endCursor;
+  if (!cursor) {
+    reportInterrupted("Export interrupted before the first page completed; re-run to start over.");
+    return;
+  }
+  if (files.length === 0) {
+    reportInterrupted(`Export interrupted after ${written} records. Resume with --cursor ${cursor}`);
+    return;
+  }
+  const kept = files.length === 1 ? files[0] : `${files.length} files`;
+  reportInterrupted(
+    `Export interrupted after ${written} records, kept in ${kept}. ` +
+      `Resume with --cursor ${cursor} and a different --output-file; ` +
+      "reusing the same one overwrites them.",
+  );
 }
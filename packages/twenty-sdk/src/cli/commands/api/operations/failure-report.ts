@@ -0,0 +1,116 @@
+import { AxiosError } from "axios";
+import { ApiOperationContext } from "./types";
+import { CliError } from "../../../utilities/errors/cli-error";
+import { toExitCode } from "../../../utilities/errors/error-handler";
+import { capitalize } from "../../../utilities/shared/parse";
+import { printStatus } from "../../../utilities/output/services/status-printer";
+
+export interface RecordFailure {
+  // 1-based position in the input file or payload.
+  index: number;
+  id?: string;
+  // Set when one run covers several input files.
+  file?: string;
+  error: string;
+}
+
+export interface FailureReport {
+  action: string;
+  succeeded: number;
+  failures: RecordFailure[];
+  // The first error seen; decides the exit code under --fail-fast.
+  firstError?: unknown;
+}
+
+// Twenty's REST batch endpoints are atomic, so a rejected batch fails every
+// record in it; each one is reported against its own input index.
+export function batchFailures(
+  startIndex: number,
+  count: number,
+  error: unknown,
+  ids?: string[],
+): RecordFailure[] {
+  const message = describeFailure(error);
+  return Array.from({ length: count }, (_, offset) => ({
+    index: startIndex + offset + 1,
+    ...(ids ? { id: ids[offset] } : {}),
+    error: message,
+  }));
+}
+
+export function describeFailure(error: unknown): string {
+  const axiosError = error as AxiosError;
+  const status = axiosError?.isAxiosError ? axiosError.response?.status : undefined;
+  if (status === undefined) {
+    return error instanceof Error ? error.message : String(error);
+  }
+
+  const detail = describeResponseBody(axiosError.response?.data);
+  return detail ? `HTTP ${status}: ${detail}` : `HTTP ${status}`;
+}
+
+function describeResponseBody(data: unknown): string | undefined {
+  if (typeof data === "string") {
+    return data || undefined;
+  }
+  if (typeof data !== "object" || data === null) {
+    return undefined;
+  }
+
+  const body = data as { messages?: unknown; message?: unknown; error?: unknown };
+  if (Array.isArray(body.messages)) {
+    return body.messages.join("; ");
+  }
+  const detail = body.message ?? body.error;
+  return detail === undefined ? undefined : String(detail);
+}
+
+// --max-errors N caps how many failures are listed; 0 prints totals only.
+export function resolveMaxErrors(raw: string | undefined): number | undefined {
+  if (raw === undefined) {
+    return undefined;
+  }
+  const maxErrors = Number(raw);
+  if (!Number.isInteger(maxErrors) || maxErrors < 0) {
+    throw new CliError(
+      `Invalid --max-errors value ${JSON.stringify(raw)}; expected a non-negative integer.`,
+      "INVALID_ARGUMENTS",
+    );
+  }
+  return maxErrors;
+}
+
+// --only-errors output: one line per failed record plus a totals line.
+// Failures are reported, not fatal, unless --fail-fast stopped the run.
+// Past --max-errors the rest are only counted, but totals include them.
+export function printFailureReport(ctx: ApiOperationContext, report: FailureReport): void {
+  const failed = report.failures.length;
+  const maxErrors = resolveMaxErrors(ctx.options.maxErrors);
+  const shown = maxErrors === undefined ? report.failures : report.failures.slice(0, maxErrors);
+  const omitted = failed - shown.length;
+  printStatus(
+    ctx.globalOptions,
+    {
+      action: report.action,
+      object: ctx.object,
+      succeeded: report.succeeded,
+      failed,
+      failures: shown,
+      ...(omitted > 0 ? { omittedFailures: omitted } : {}),
+      message: [
+        ...shown.map(
+          (failure) =>
+            `${failure.file ? `${failure.file}: ` : ""}Record ${failure.index}` +
+            `${failure.id ? ` (${failure.id})` : ""} failed: ${failure.error}`,
+        ),
+        ...(omitted > 0 ? [`... and ${omitted} more errors`] : []),
+        `${capitalize(report.action)} ${report.succeeded} ${ctx.object}, ${failed} failed.`,
+      ],
+    },
+    ctx.services.writer,
+  );
+
+  if (ctx.options.failFast && failed > 0) {
+    process.exitCode = toExitCode(report.firstError);
+  }
+}
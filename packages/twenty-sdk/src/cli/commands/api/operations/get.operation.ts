@@ -1,12 +1,21 @@
 import { ApiOperationContext } from "./types";
 import { CliError } from "../../../utilities/errors/cli-error";
+import { readIdFileOption, runForEachId } from "./id-file";
 
 export async function runGetOperation(ctx: ApiOperationContext): Promise<void> {
+  const ids = await readIdFileOption(ctx);
+  const fetch = (id: string) =>
+    ctx.services.records.get(ctx.object, id, { include: ctx.options.include });
+  if (ids) {
+    await runForEachId(ctx, ids, "fetched", async (id) => ({ record: await fetch(id) }));
+    return;
+  }
+
   const id = ctx.arg;
   if (!id) {
     throw new CliError("Missing record ID.", "INVALID_ARGUMENTS");
   }
-  const record = await ctx.services.records.get(ctx.object, id, { include: ctx.options.include });
+  const record = await fetch(id);
   await ctx.services.output.render(record, {
     format: ctx.globalOptions.output,
     query: ctx.globalOptions.query,
@@ -1,10 +1,10 @@
 import { ApiOperationContext } from "./types";
 import { readJsonInput } from "../../../utilities/shared/io";
-import { parseKeyValuePairs } from "../../../utilities/shared/parse";
+import { parseQueryParams } from "../../../utilities/shared/parse";
 
 export async function runGroupByOperation(ctx: ApiOperationContext): Promise<void> {
   let payload: unknown | undefined;
-  const params = parseKeyValuePairs(ctx.options.param);
+  const params = parseQueryParams(ctx.options.param);
 
   if (ctx.options.data || ctx.options.file) {
     const rawPayload = await readJsonInput(ctx.options.data, ctx.options.file);
@@ -0,0 +1,73 @@
+import { ApiOperationContext } from "./types";
+import { CliError } from "../../../utilities/errors/cli-error";
+import { toExitCode } from "../../../utilities/errors/error-handler";
+import { readFileOrStdin } from "../../../utilities/shared/io";
+import { describeFailure, FailureReport, printFailureReport } from "./failure-report";
+
+// --id-file <path>: one record ID per line (use - for stdin). Blank lines and
+// lines starting with # are skipped. Returns undefined without --id-file.
+export async function readIdFileOption(ctx: ApiOperationContext): Promise<string[] | undefined> {
+  const path = ctx.options.idFile?.trim();
+  if (!path) {
+    return undefined;
+  }
+  if (ctx.arg) {
+    throw new CliError("Pass a record ID or --id-file, not both.", "INVALID_ARGUMENTS");
+  }
+  if (path === "-" && ctx.options.file?.trim() === "-") {
+    throw new CliError("--id-file and --file cannot both read stdin.", "INVALID_ARGUMENTS");
+  }
+
+  const ids = (await readFileOrStdin(path))
+    .split(/\r?\n/)
+    .map((line) => line.trim())
+    .filter((line) => line !== "" && !line.startsWith("#"));
+  if (ids.length === 0) {
+    throw new CliError(`No IDs found in ${path}.`, "INVALID_ARGUMENTS");
+  }
+  return ids;
+}
+
+// Runs one request per ID, in file order, and renders one entry per ID:
+// {id, ...result} on success or {id, error} on failure. A failed ID does not
+// stop the rest unless --fail-fast is set; any failure makes the exit code
+// non-zero. --only-errors prints the batch failure report instead.
+export async function runForEachId(
+  ctx: ApiOperationContext,
+  ids: string[],
+  action: string,
+  run: (id: string) => Promise<Record<string, unknown>>,
+): Promise<void> {
+  const entries: Record<string, unknown>[] = [];
+  const report: FailureReport = { action, succeeded: 0, failures: [] };
+
+  for (const [index, id] of ids.entries()) {
+    try {
+      entries.push({ id, ...(await run(id)) });
+      report.succeeded += 1;
+    } catch (error) {
+      const message = describeFailure(error);
+      entries.push({ id, error: message });
+      report.failures.push({ index: index + 1, id, error: message });
+      if (report.failures.length === 1) {
+        report.firstError = error;
+      }
+      if (ctx.options.failFast) {
+        break;
+      }
+    }
+  }
+
+  if (ctx.options.onlyErrors) {
+    printFailureReport(ctx, report);
+    return;
+  }
+
+  await ctx.services.output.render(entries, {
+    format: ctx.globalOptions.output,
+    query: ctx.globalOptions.query,
+  });
+  if (report.failures.length > 0) {
+    process.exitCode = toExitCode(report.firstError);
+  }
+}
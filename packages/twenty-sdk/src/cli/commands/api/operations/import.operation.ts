@@ -1,43 +1,326 @@
-import { ApiOperationContext } from "./types";
+import { ApiOperationContext, PreparedBatch, RecordWriteHooks } from "./types";
 import { chunkArray } from "../../../utilities/shared/parse";
 import { CliError } from "../../../utilities/errors/cli-error";
+import { logVerbose } from "../../../utilities/shared/logger";
+import { printStatus } from "../../../utilities/output/services/status-printer";
+import { OutputWriter } from "../../../utilities/output/services/output-writer";
+import { loadBodyTemplate, renderBodyTemplate } from "./body-template";
+import {
+  describeFailure,
+  printFailureReport,
+  RecordFailure,
+  resolveMaxErrors,
+} from "./failure-report";
+import { expandPathPatterns } from "../../../utilities/file/services/path-glob";
+import { toExitCode } from "../../../utilities/errors/error-handler";
+import { reportInterrupted, runInterruptible } from "../../../utilities/shared/interrupt";
+import { log } from "../../../utilities/shared/logger";
 
-export async function runImportOperation(ctx: ApiOperationContext): Promise<void> {
-  const filePath = ctx.arg;
-  if (!filePath) {
+export async function runImportOperation(
+  ctx: ApiOperationContext,
+  hooks: RecordWriteHooks = {},
+): Promise<void> {
+  const patterns = ctx.args ?? (ctx.arg ? [ctx.arg] : []);
+  if (patterns.length === 0) {
     throw new CliError("Missing import file path.", "INVALID_ARGUMENTS");
   }
+  // Checked before any records are written rather than at the report.
+  resolveMaxErrors(ctx.options.maxErrors);
 
+  const files = await expandPathPatterns(patterns);
+  if (files.length === 1) {
+    const result = await importFile(ctx, files[0]!, hooks);
+    if (result) {
+      printImportResult(ctx, result, hooks);
+    }
+    return;
+  }
+
+  await importFiles(ctx, files, hooks);
+}
+
+interface ImportResult {
+  imported: number;
+  failed: number;
+  failures: RecordFailure[];
+  firstError?: unknown;
+  // Set when Ctrl-C stopped the file; the 1-based index to resume from.
+  resumeFrom?: number;
+}
+
+// Imports one file. Returns undefined for --dry-run, which only previews.
+async function importFile(
+  ctx: ApiOperationContext,
+  filePath: string,
+  hooks: RecordWriteHooks,
+): Promise<ImportResult | undefined> {
   const batchSizeRaw = ctx.options.batchSize ? Number(ctx.options.batchSize) : 60;
   let batchSize = Number.isNaN(batchSizeRaw) || batchSizeRaw <= 0 ? 60 : batchSizeRaw;
   if (batchSize > 60) batchSize = 60;
 
-  const records = await ctx.services.importer.import(filePath, { dryRun: ctx.options.dryRun });
+  const template = await loadBodyTemplate(ctx.options.templateFile);
+  // With a template the rendered bodies are previewed below instead of raw rows.
+  const rows = await ctx.services.importer.import(filePath, {
+    dryRun: ctx.options.dryRun && !template,
+  });
+  // Mapped before the dry-run exit so invalid rows are reported either way.
+  const bodies = template
+    ? rows.map((row, index) => renderBodyTemplate(template, row, index))
+    : rows;
+  const mapped = hooks.record ? bodies.map(hooks.record) : bodies;
+  // Indices stay absolute so failures and resume hints match the input file.
+  const offset = resolveContinueFrom(ctx.options.continueFrom) - 1;
+  const records = mapped.slice(offset);
   if (ctx.options.dryRun) {
-    return;
+    if (template) {
+      printRenderedBodies(records, ctx.services.writer);
+    }
+    for (const line of hooks.preview ? await hooks.preview(records) : []) {
+      ctx.services.writer.line(line);
+    }
+    return undefined;
   }
+
+  const result: ImportResult = { imported: 0, failed: 0, failures: [] };
   if (records.length === 0) {
-    // eslint-disable-next-line no-console
-    console.log("No records to import.");
-    return;
+    return result;
   }
 
   const batches = chunkArray(records, batchSize);
-  let imported = 0;
-  let errors = 0;
+  const onlyErrors = ctx.options.onlyErrors === true;
+
+  // Ctrl-C stops before the next batch. The in-flight batch is a write, so it
+  // is allowed to finish and the resume index is exact.
+  await runInterruptible(async (signal) => {
+    for (const [index, batch] of batches.entries()) {
+      if (signal.aborted) {
+        result.resumeFrom = offset + index * batchSize + 1;
+        break;
+      }
+      logVerbose(`Importing batch ${index + 1}/${batches.length} (${batch.length} records)`);
+      const start = offset + index * batchSize;
+      // Positions of the rows sent, so a rejected batch reports the right ones.
+      let positions = batch.map((_, position) => position);
+      try {
+        const prepared = hooks.batch ? await hooks.batch(batch) : { records: batch, skipped: [] };
+        if (prepared.skipped.length > 0) {
+          recordSkippedRows(ctx, result, start, prepared.skipped);
+          const skipped = new Set(prepared.skipped.map((row) => row.position));
+          positions = positions.filter((position) => !skipped.has(position));
+        }
+        if (prepared.records.length > 0) {
+          await ctx.services.records.batchCreate(ctx.object, prepared.records);
+        }
+        result.imported += prepared.records.length;
+        if (onlyErrors && ctx.options.failFast && prepared.skipped.length > 0) {
+          break;
+        }
+      } catch (error) {
+        result.failed += positions.length;
+        logVerbose(`Batch ${index + 1}/${batches.length} failed`);
+        if (onlyErrors) {
+          const message = describeFailure(error);
+          result.failures.push(
+            ...positions.map((position) => ({ index: start + position + 1, error: message })),
+          );
+          result.firstError ??= error;
+          if (ctx.options.failFast) {
+            break;
+          }
+          continue;
+        }
+        if (!ctx.options.continueOnError) {
+          throw error;
+        }
+      }
+    }
+  });
+
+  return result;
+}
+
+// Rows a batch hook could not prepare fail one by one; without --only-errors
+// each is logged as it happens since the summary only counts them.
+function recordSkippedRows(
+  ctx: ApiOperationContext,
+  result: ImportResult,
+  start: number,
+  skipped: PreparedBatch["skipped"],
+): void {
+  for (const { position, error } of skipped) {
+    const index = start + position + 1;
+    result.failed += 1;
+    result.firstError ??= error;
+    if (ctx.options.onlyErrors) {
+      result.failures.push({ index, error: describeFailure(error) });
+    } else {
+      log("warn", `Record ${index} skipped: ${describeFailure(error)}`);
+    }
+  }
+}
+
+function printImportResult(
+  ctx: ApiOperationContext,
+  result: ImportResult,
+  hooks: RecordWriteHooks,
+): void {
+  if (result.resumeFrom !== undefined) {
+    reportInterrupted(`Import interrupted. Resume with --continue-from ${result.resumeFrom}`);
+  }
+
+  if (ctx.options.onlyErrors) {
+    printFailureReport(ctx, {
+      action: "imported",
+      succeeded: result.imported,
+      failures: result.failures,
+      firstError: result.firstError,
+    });
+    return;
+  }
 
-  for (const batch of batches) {
+  const { imported, failed } = result;
+  const report = hooks.report?.();
+  printStatus(
+    ctx.globalOptions,
+    {
+      action: "imported",
+      object: ctx.object,
+      imported,
+      failed,
+      ...report?.fields,
+      message: [
+        imported === 0 && failed === 0
+          ? "No records to import."
+          : `Import complete: ${imported} imported${failed ? `, ${failed} failed` : ""}.`,
+        ...(report?.lines ?? []),
+      ],
+    },
+    ctx.services.writer,
+  );
+}
+
+interface FileSummary {
+  file: string;
+  imported: number;
+  failed: number;
+  error?: string;
+}
+
+// Several files import in order with one combined summary. A file that fails
+// outright (unreadable, or a rejected batch without --continue-on-error) is
+// recorded and the rest still run, unless --fail-fast is set.
+async function importFiles(
+  ctx: ApiOperationContext,
+  files: string[],
+  hooks: RecordWriteHooks,
+): Promise<void> {
+  if (ctx.options.continueFrom !== undefined) {
+    throw new CliError(
+      "--continue-from applies to a single import file.",
+      "INVALID_ARGUMENTS",
+      "Import the interrupted file on its own with --continue-from, then the remaining files.",
+    );
+  }
+
+  const summaries: FileSummary[] = [];
+  const failures: RecordFailure[] = [];
+  let firstError: unknown;
+  for (const [position, file] of files.entries()) {
+    let result: ImportResult | undefined;
     try {
-      await ctx.services.records.batchCreate(ctx.object, batch);
-      imported += batch.length;
+      result = await importFile(ctx, file, hooks);
     } catch (error) {
-      errors += batch.length;
-      if (!ctx.options.continueOnError) {
+      if (ctx.options.failFast) {
         throw error;
       }
+      summaries.push({ file, imported: 0, failed: 0, error: describeFailure(error) });
+      firstError ??= error;
+      continue;
+    }
+    if (!result) {
+      continue;
+    }
+
+    summaries.push({ file, imported: result.imported, failed: result.failed });
+    failures.push(...result.failures.map((failure) => ({ ...failure, file })));
+    firstError ??= result.firstError;
+    if (result.resumeFrom !== undefined) {
+      const remaining = files.length - position - 1;
+      reportInterrupted(
+        `Import interrupted in ${file}. Resume it with --continue-from ${result.resumeFrom}` +
+          (remaining > 0 ? `; ${remaining} later files were not started.` : "."),
+      );
+      break;
+    }
+    if (ctx.options.failFast && result.failures.length > 0) {
+      break;
+    }
+  }
+
+  if (ctx.options.dryRun) {
+    return;
+  }
+
+  const imported = summaries.reduce((total, summary) => total + summary.imported, 0);
+  if (ctx.options.onlyErrors) {
+    printFailureReport(ctx, { action: "imported", succeeded: imported, failures, firstError });
+    for (const summary of summaries.filter((entry) => entry.error)) {
+      log("error", `${summary.file}: ${summary.error}`, { file: summary.file });
     }
+    return;
+  }
+
+  const failed = summaries.reduce((total, summary) => total + summary.failed, 0);
+  const failedFiles = summaries.filter((summary) => summary.error).length;
+  const report = hooks.report?.();
+  printStatus(
+    ctx.globalOptions,
+    {
+      action: "imported",
+      object: ctx.object,
+      imported,
+      failed,
+      files: summaries,
+      ...report?.fields,
+      message: [
+        ...summaries.map((summary) =>
+          summary.error
+            ? `${summary.file}: failed: ${summary.error}`
+            : `${summary.file}: ${summary.imported} imported` +
+              (summary.failed ? `, ${summary.failed} failed` : ""),
+        ),
+        `Import complete: ${imported} imported${failed ? `, ${failed} failed` : ""} ` +
+          `from ${summaries.length} files` +
+          (failedFiles ? ` (${failedFiles} could not be imported).` : "."),
+        ...(report?.lines ?? []),
+      ],
+    },
+    ctx.services.writer,
+  );
+  if (failedFiles > 0) {
+    process.exitCode = toExitCode(firstError);
+  }
+}
+
+function resolveContinueFrom(raw: string | undefined): number {
+  if (raw === undefined) {
+    return 1;
+  }
+
+  const index = Number(raw);
+  if (!Number.isInteger(index) || index <= 0) {
+    throw new CliError(
+      `Invalid --continue-from value ${JSON.stringify(raw)}; expected a positive record index.`,
+      "INVALID_ARGUMENTS",
+    );
   }
 
-  // eslint-disable-next-line no-console
-  console.log(`Import complete: ${imported} imported${errors ? `, ${errors} failed` : ""}.`);
+  return index;
+}
+
+function printRenderedBodies(records: Record<string, unknown>[], writer: OutputWriter): void {
+  writer.line(`Would import ${records.length} records`);
+  for (const record of records) {
+    writer.line(JSON.stringify(record));
+  }
 }
@@ -0,0 +1,57 @@
+import { CliError } from "../../../utilities/errors/cli-error";
+import {
+  OutputWriter,
+  stdoutWriter,
+} from "../../../utilities/output/services/output-writer";
+import { ApiCommandOptions } from "./types";
+
+export type JsonLayout = "array" | "stream";
+
+// --array (the default) writes one buffered JSON array; --stream writes one
+// record per line (NDJSON) so consumers can parse records as they arrive.
+export function resolveJsonLayout(
+  options: Pick<ApiCommandOptions, "array" | "stream">,
+  format: string | undefined,
+): JsonLayout {
+  if (options.array && options.stream) {
+    throw new CliError("Use only one of --array or --stream.", "INVALID_ARGUMENTS");
+  }
+
+  const flag = options.stream ? "--stream" : options.array ? "--array" : undefined;
+  const compatible =
+    format === undefined || format === "json" || (format === "jsonl" && options.stream);
+  if (flag && !compatible) {
+    throw new CliError(`${flag} applies to JSON output, not ${format}.`, "INVALID_ARGUMENTS");
+  }
+
+  return options.stream ? "stream" : "array";
+}
+
+// --stream-header starts NDJSON on stdout with {"totalCount":N} so consumers
+// can size progress without buffering. N comes from the first page and is
+// null when the server does not report a count.
+export function resolveStreamHeader(
+  options: Pick<ApiCommandOptions, "stream" | "streamHeader">,
+  toFile = false,
+): boolean {
+  if (!options.streamHeader) {
+    return false;
+  }
+  if (!options.stream) {
+    throw new CliError("--stream-header requires --stream.", "INVALID_ARGUMENTS");
+  }
+  if (toFile) {
+    throw new CliError(
+      "--stream-header applies to NDJSON on stdout, not to files.",
+      "INVALID_ARGUMENTS",
+    );
+  }
+  return true;
+}
+
+export function writeStreamHeader(
+  totalCount: number | undefined,
+  writer: OutputWriter = stdoutWriter,
+): void {
+  writer.line(JSON.stringify({ totalCount: totalCount ?? null }));
+}
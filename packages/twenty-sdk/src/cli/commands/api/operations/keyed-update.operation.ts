@@ -0,0 +1,105 @@
+import { ApiOperationContext } from "./types";
+import { describeFailure } from "./failure-report";
+import { CliError } from "../../../utilities/errors/cli-error";
+
+export interface UpdateKey {
+  // Column in the input file holding the lookup value, e.g. "email".
+  column: string;
+  // Record field matched against it, e.g. "emails.primaryEmail".
+  field: string;
+}
+
+export type KeyedUpdateStatus = "updated" | "would-update" | "not-found" | "failed";
+
+export interface KeyedUpdateResult {
+  // 1-based row in the input file.
+  row: number;
+  key: string;
+  status: KeyedUpdateStatus;
+  id?: string;
+  changes?: Record<string, unknown>;
+  error?: string;
+}
+
+// Patches records matched by a unique field rather than by ID, one input row
+// at a time. Every column except the key is written; empty CSV cells are left
+// unchanged rather than cleared. Nothing is ever created: rows whose key
+// matches no record are reported as not-found.
+export async function runKeyedUpdateOperation(
+  ctx: ApiOperationContext,
+  key: UpdateKey,
+): Promise<void> {
+  const file = ctx.options.fromFile;
+  if (!file) {
+    throw new CliError("Missing --from-file.", "INVALID_ARGUMENTS");
+  }
+
+  const rows = await ctx.services.importer.import(file);
+  const results: KeyedUpdateResult[] = [];
+  for (const [index, row] of rows.entries()) {
+    results.push(await updateRow(ctx, key, row, index + 1));
+  }
+
+  if (results.some((result) => result.status === "failed")) {
+    process.exitCode = 1;
+  }
+  await ctx.services.output.render(results, {
+    format: ctx.globalOptions.output,
+    query: ctx.globalOptions.query,
+  });
+}
+
+async function updateRow(
+  ctx: ApiOperationContext,
+  key: UpdateKey,
+  row: Record<string, unknown>,
+  index: number,
+): Promise<KeyedUpdateResult> {
+  const value = row[key.column];
+  if (value === undefined || value === null || String(value).trim() === "") {
+    return { row: index, key: "", status: "failed", error: `Missing ${key.column} value.` };
+  }
+  const keyValue = String(value).trim();
+  const changes = buildChanges(row, key.column);
+
+  let id: string;
+  try {
+    const record = await ctx.services.records.findUniqueBy(ctx.object, key.field, keyValue);
+    id = String((record as { id?: unknown }).id);
+  } catch (error) {
+    if (error instanceof CliError && error.code === "NOT_FOUND") {
+      return { row: index, key: keyValue, status: "not-found" };
+    }
+    return { row: index, key: keyValue, status: "failed", error: describeFailure(error) };
+  }
+
+  if (ctx.options.dryRun) {
+    return { row: index, key: keyValue, status: "would-update", id, changes };
+  }
+  try {
+    await ctx.services.records.update(ctx.object, id, changes);
+    return { row: index, key: keyValue, status: "updated", id };
+  } catch (error) {
+    return { row: index, key: keyValue, id, status: "failed", error: describeFailure(error) };
+  }
+}
+
+// Dotted columns (emails.primaryEmail) become nested objects, as with --set,
+// but CSV cells keep their text: a job title of "2024" stays a string.
+function buildChanges(row: Record<string, unknown>, keyColumn: string): Record<string, unknown> {
+  const changes: Record<string, unknown> = {};
+  for (const [column, value] of Object.entries(row)) {
+    if (column === keyColumn || column === "" || value === undefined || value === "") {
+      continue;
+    }
+    const parts = column.split(".");
+    let target = changes;
+    for (const part of parts.slice(0, -1)) {
+      const next = target[part];
+      target[part] = next && typeof next === "object" && !Array.isArray(next) ? next : {};
+      target = target[part] as Record<string, unknown>;
+    }
+    target[parts[parts.length - 1]!] = value;
+  }
+  return changes;
+}
@@ -1,18 +1,36 @@
+import path from "path";
 import { ApiOperationContext } from "./types";
-import { parseKeyValuePairs } from "../../../utilities/shared/parse";
 import { CliError } from "../../../utilities/errors/cli-error";
+import { maskRecordFields } from "../../../utilities/output/services/mask-fields";
+import { pruneRecordFields } from "../../../utilities/output/services/prune-fields";
+import { openInBrowser } from "../../../utilities/shared/browser";
+import {
+  capitalize,
+  parseFieldList,
+  parseQueryParams,
+  singularize,
+} from "../../../utilities/shared/parse";
+import { parseComputedColumns } from "./computed-columns-options";
+import { resolveCsvFlattenOptions } from "./csv-flatten-options";
+import { resolveDistinctField, runDistinctList } from "./distinct-values";
+import { resolveJsonLayout, resolveStreamHeader, writeStreamHeader } from "./json-layout-options";
+import { parseLocalSort, sortRecordsLocally } from "./local-sort";
+import { resolvePageSize } from "./page-size-options";
 
 export async function runListOperation(ctx: ApiOperationContext): Promise<void> {
   const { services, globalOptions } = ctx;
-  if (ctx.options.fields) {
-    throw new CliError(
-      "--fields is not supported for list. Twenty REST find-many only supports depth-based field expansion.",
-      "INVALID_ARGUMENTS",
-    );
-  }
-
-  const limit = ctx.options.limit ? Number(ctx.options.limit) : undefined;
-  const params = parseKeyValuePairs(ctx.options.param);
+  const csvFlatten = resolveCsvFlattenOptions(ctx.options);
+  const computed = parseComputedColumns(ctx.options.computed);
+  const pageSize = resolvePageSize(ctx.options.pageSize, ctx.options.all === true);
+  const limit = pageSize ?? (ctx.options.limit ? Number(ctx.options.limit) : undefined);
+  const distinct = resolveDistinctField(ctx);
+  const params = parseQueryParams(ctx.options.param);
+  const stream = resolveJsonLayout(ctx.options, globalOptions.output) === "stream";
+  const format = stream ? "jsonl" : globalOptions.output;
+  const streamHeader = resolveStreamHeader(ctx.options);
+  const outputFile = resolveHtmlOutputFile(ctx);
+  const withPageInfo = resolveWithPageInfo(ctx, stream);
+  const localSort = parseLocalSort(ctx.options.sortLocal);
 
   const listOptions = {
     limit,
@@ -21,15 +39,114 @@ export async function runListOperation(ctx: ApiOperationContext): Promise<void>
     include: ctx.options.include,
     sort: ctx.options.sort,
     order: ctx.options.order,
+    fields: parseFieldList(ctx.options.fields),
     params,
   };
 
+  if (distinct) {
+    await runDistinctList(ctx, distinct, listOptions);
+    return;
+  }
+
+  if (stream && ctx.options.all && !globalOptions.query && !localSort) {
+    // Each page is printed as it arrives; --query and --sort-local still need
+    // the full result.
+    let headerWritten = !streamHeader;
+    await services.records.listAll(ctx.object, {
+      ...listOptions,
+      onPage: async (data, _pageInfo, totalCount) => {
+        if (!headerWritten) {
+          writeStreamHeader(totalCount, ctx.services.writer);
+          headerWritten = true;
+        }
+        if (data.length > 0) {
+          await services.output.render(data, {
+            format,
+            ...(computed.length > 0 ? { computed } : {}),
+          });
+        }
+      },
+    });
+    return;
+  }
+
   const result = ctx.options.all
     ? await services.records.listAll(ctx.object, listOptions)
     : await services.records.list(ctx.object, listOptions);
+  if (localSort) {
+    result.data = sortRecordsLocally(result.data, localSort);
+  }
+
+  if (withPageInfo) {
+    // Pruning and masking apply to the records, not to the wrapper's keys.
+    const records = maskRecordFields(
+      pruneRecordFields(result.data, globalOptions.pruneFields),
+      globalOptions.maskFields,
+    );
+    await services.output.render(
+      {
+        records,
+        pageInfo: {
+          hasNextPage: result.pageInfo?.hasNextPage ?? false,
+          endCursor: result.pageInfo?.endCursor ?? null,
+        },
+        totalCount: result.totalCount ?? null,
+      },
+      { format, query: globalOptions.query, pruneFields: [], maskFields: [] },
+    );
+    return;
+  }
 
+  if (streamHeader) {
+    writeStreamHeader(result.totalCount, ctx.services.writer);
+  }
   await services.output.render(result.data, {
-    format: globalOptions.output,
+    format,
+    // --envelope names the list by its singular object, e.g. PersonList.
+    kind: `${capitalize(singularize(ctx.object))}List`,
     query: globalOptions.query,
+    ...(listOptions.fields ? { columns: listOptions.fields } : {}),
+    ...(outputFile ? { outputFile } : {}),
+    ...(csvFlatten ? { csvFlatten } : {}),
+    ...(computed.length > 0 ? { computed } : {}),
   });
+  if (outputFile && ctx.options.open) {
+    openInBrowser(path.resolve(outputFile));
+  }
+}
+
+// --with-page-info wraps JSON output as {records, pageInfo, totalCount} so
+// callers can page with --cursor themselves. Other formats have no place for
+// the metadata.
+function resolveWithPageInfo(ctx: ApiOperationContext, stream: boolean): boolean {
+  if (!ctx.options.withPageInfo) {
+    return false;
+  }
+  if (stream || (ctx.globalOptions.output ?? "json") !== "json") {
+    throw new CliError("--with-page-info requires JSON output.", "INVALID_ARGUMENTS");
+  }
+  if (ctx.options.distinct) {
+    throw new CliError(
+      "--with-page-info cannot be combined with --distinct.",
+      "INVALID_ARGUMENTS",
+    );
+  }
+  return true;
+}
+
+// list writes files only for html, a page meant for a browser; JSON and CSV
+// files come from export.
+function resolveHtmlOutputFile(ctx: ApiOperationContext): string | undefined {
+  const { outputFile, open } = ctx.options;
+  if (ctx.globalOptions.output !== "html" && (outputFile || open)) {
+    throw new CliError(
+      `${outputFile ? "--output-file" : "--open"} on list requires --output html.`,
+      "INVALID_ARGUMENTS",
+      "Use export --output-file to write JSON or CSV files.",
+    );
+  }
+  if (open && !outputFile) {
+    throw new CliError("--open requires --output-file.", "INVALID_ARGUMENTS");
+  }
+  return outputFile;
 }
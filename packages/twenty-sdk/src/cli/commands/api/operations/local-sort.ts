@@ -0,0 +1,94 @@
+import { CliError } from "../../../utilities/errors/cli-error";
+
+export interface LocalSort {
+  // Dotted path into each record, e.g. name.firstName.
+  path: string[];
+  descending: boolean;
+}
+
+// Values compare within their kind; across kinds, numbers come first, then
+// times, strings, and booleans.
+const KIND_ORDER = { number: 0, time: 1, string: 2, boolean: 3 } as const;
+
+type Kind = keyof typeof KIND_ORDER;
+
+// ISO dates and date-times, the shape Twenty uses for time fields.
+const ISO_TIME =
+  /^\d{4}-\d{2}-\d{2}(?:[T ]\d{2}:\d{2}(?::\d{2}(?:\.\d+)?)?(?:Z|[+-]\d{2}:?\d{2})?)?$/;
+
+// --sort-local field[:asc|:desc]
+export function parseLocalSort(value: string | undefined): LocalSort | undefined {
+  if (value === undefined) {
+    return undefined;
+  }
+  const [field, direction = "asc", ...rest] = value.trim().split(":");
+  const path = field.split(".").map((part) => part.trim());
+  if (rest.length > 0 || path.some((part) => part === "") || !/^(asc|desc)$/i.test(direction)) {
+    throw new CliError(
+      `Invalid --sort-local value ${JSON.stringify(value)}.`,
+      "INVALID_ARGUMENTS",
+      "Use field, field:asc, or field:desc, e.g. --sort-local createdAt:desc.",
+    );
+  }
+  return { path, descending: direction.toLowerCase() === "desc" };
+}
+
+// Stable, so records that compare equal keep the server's order. Missing
+// values (absent, null, or empty) go last in either direction.
+export function sortRecordsLocally<T>(records: T[], sort: LocalSort): T[] {
+  const keyed = records.map((record) => ({ record, key: sortKey(record, sort.path) }));
+  keyed.sort((a, b) => {
+    if (a.key === undefined || b.key === undefined) {
+      return a.key === undefined ? (b.key === undefined ? 0 : 1) : -1;
+    }
+    const order = compareKeys(a.key, b.key);
+    return sort.descending ? -order : order;
+  });
+  return keyed.map((entry) => entry.record);
+}
+
+interface SortKey {
+  kind: Kind;
+  value: number | string | boolean;
+}
+
+function sortKey(record: unknown, path: string[]): SortKey | undefined {
+  let value: unknown = record;
+  for (const part of path) {
+    if (typeof value !== "object" || value === null) {
+      return undefined;
+    }
+    value = (value as Record<string, unknown>)[part];
+  }
+
+  if (typeof value === "number") {
+    return Number.isNaN(value) ? undefined : { kind: "number", value };
+  }
+  if (typeof value === "boolean") {
+    return { kind: "boolean", value };
+  }
+  if (typeof value === "string" && value !== "") {
+    if (ISO_TIME.test(value)) {
+      const time = Date.parse(value);
+      if (!Number.isNaN(time)) {
+        return { kind: "time", value: time };
+      }
+    }
+    return { kind: "string", value };
+  }
+  if (typeof value === "object" && value !== null) {
+    return { kind: "string", value: JSON.stringify(value) };
+  }
+
+  return undefined;
+}
+
+function compareKeys(a: SortKey, b: SortKey): number {
+  if (a.kind !== b.kind) {
+    return KIND_ORDER[a.kind] - KIND_ORDER[b.kind];
+  }
+  if (a.kind === "string") {
+    return (a.value as string).localeCompare(b.value as string);
+  }
+  return Number(a.value) - Number(b.value);
+}
@@ -0,0 +1,23 @@
+import { AxiosError } from "axios";
+import { CliError } from "../../../utilities/errors/cli-error";
+
+// Twenty answers a missing ID with 404, or with a 400 whose message says the
+// record was not found.
+export function isNotFound(error: unknown): boolean {
+  if (error instanceof CliError) {
+    return error.code === "NOT_FOUND";
+  }
+  const response = (error as AxiosError | undefined)?.response;
+  if (!response) {
+    return false;
+  }
+  if (response.status === 404) {
+    return true;
+  }
+  if (response.status !== 400) {
+    return false;
+  }
+
+  const body = typeof response.data === "string" ? response.data : JSON.stringify(response.data);
+  return /not found/i.test(body ?? "");
+}
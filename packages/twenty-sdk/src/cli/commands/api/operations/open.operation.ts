@@ -0,0 +1,36 @@
+import { ApiOperationContext } from "./types";
+import { CliError } from "../../../utilities/errors/cli-error";
+import { openInBrowser } from "../../../utilities/shared/browser";
+import { singularize } from "../../../utilities/shared/parse";
+import { printStatus } from "../../../utilities/output/services/status-printer";
+import { resolveWebAppUrl } from "../../auth/auth-compat";
+
+// Opens the record's page in the web app, e.g. /object/person/<id>. With
+// --print-url the URL is only printed, for machines without a browser.
+export async function runOpenOperation(ctx: ApiOperationContext): Promise<void> {
+  const id = ctx.arg;
+  if (!id) {
+    throw new CliError("Missing record ID.", "INVALID_ARGUMENTS");
+  }
+
+  const { apiUrl } = await ctx.services.config.resolveApiConfig({
+    workspace: ctx.globalOptions.workspace,
+  });
+  const url = buildRecordUrl(apiUrl, ctx.object, id);
+  if (ctx.options.printUrl) {
+    ctx.services.writer.line(url);
+    return;
+  }
+
+  printStatus(
+    ctx.globalOptions,
+    { action: "opened", url, message: `Opening ${url}` },
+    ctx.services.writer,
+  );
+  openInBrowser(url);
+}
+
+export function buildRecordUrl(apiUrl: string, object: string, id: string): string {
+  const singular = encodeURIComponent(singularize(object));
+  return `${resolveWebAppUrl(apiUrl)}/object/${singular}/${encodeURIComponent(id)}`;
+}
@@ -0,0 +1,30 @@
+import { CliError } from "../../../utilities/errors/cli-error";
+
+// Twenty's REST find-many endpoints return at most 200 records per request and
+// silently truncate larger limits, so bigger pages are rejected up front.
+export const MAX_PAGE_SIZE = 200;
+
+// Without --all there is one request whose size --limit already sets, so
+// --page-size is rejected rather than silently replacing it.
+export function resolvePageSize(raw: string | undefined, all: boolean): number | undefined {
+  if (raw === undefined) {
+    return undefined;
+  }
+  if (!all) {
+    throw new CliError(
+      "--page-size only applies with --all.",
+      "INVALID_ARGUMENTS",
+      "Use --limit to size a single page.",
+    );
+  }
+
+  const pageSize = Number(raw);
+  if (!Number.isInteger(pageSize) || pageSize < 1 || pageSize > MAX_PAGE_SIZE) {
+    throw new CliError(
+      `Invalid --page-size value ${JSON.stringify(raw)}; expected an integer from 1 to ${MAX_PAGE_SIZE}.`,
+      "INVALID_ARGUMENTS",
+    );
+  }
+
+  return pageSize;
+}
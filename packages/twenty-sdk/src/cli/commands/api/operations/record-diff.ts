@@ -0,0 +1,62 @@
+import { isDeepStrictEqual } from "node:util";
+
+export interface FieldChange {
+  // Dotted path, e.g. name.firstName.
+  field: string;
+  from: unknown;
+  to: unknown;
+}
+
+// --show-diff: compares the fields an update sent, leaf by leaf, between the
+// record fetched before the update and the one the server returned, so values
+// the server normalizes show as stored. Fields the response omits fall back
+// to the sent value. Other fields (updatedAt, ...) are not compared; arrays
+// compare whole. A record created by --on-missing create has no before.
+export function diffUpdatedFields(
+  before: unknown,
+  after: unknown,
+  payload: Record<string, unknown>,
+): FieldChange[] {
+  return leafPaths(payload).flatMap((path) => {
+    const from = valueAt(before, path) ?? null;
+    const stored = valueAt(after, path);
+    const to = stored !== undefined ? stored : (valueAt(payload, path) ?? null);
+    return isDeepStrictEqual(from, to) ? [] : [{ field: path.join("."), from, to }];
+  });
+}
+
+// Rows for csv/text/table output, where values must be plain cells.
+export function formatFieldChanges(changes: FieldChange[]): Record<string, string>[] {
+  return changes.map((change) => ({
+    field: change.field,
+    from: formatCell(change.from),
+    to: formatCell(change.to),
+  }));
+}
+
+function leafPaths(value: Record<string, unknown>, prefix: string[] = []): string[][] {
+  return Object.entries(value).flatMap(([key, nested]) =>
+    isPlainObject(nested) && Object.keys(nested).length > 0
+      ? leafPaths(nested, [...prefix, key])
+      : [[...prefix, key]],
+  );
+}
+
+function valueAt(record: unknown, path: string[]): unknown {
+  let value = record;
+  for (const part of path) {
+    if (!isPlainObject(value)) {
+      return undefined;
+    }
+    value = value[part];
+  }
+  return value;
+}
+
+function formatCell(value: unknown): string {
+  return typeof value === "string" ? value : JSON.stringify(value);
+}
+
+function isPlainObject(value: unknown): value is Record<string, unknown> {
+  return typeof value === "object" && value !== null && !Array.isArray(value);
+}
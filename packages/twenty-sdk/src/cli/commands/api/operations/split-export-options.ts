@@ -0,0 +1,29 @@
+import { CliError } from "../../../utilities/errors/cli-error";
+import { parseByteSizeOption } from "../../../utilities/shared/global-options";
+import { ApiCommandOptions } from "./types";
+
+export interface SplitLimits {
+  maxRecords?: number;
+  maxBytes?: number;
+}
+
+export function resolveSplitLimits(options: ApiCommandOptions): SplitLimits | undefined {
+  if (options.splitSize === undefined && options.splitBytes === undefined) {
+    return undefined;
+  }
+
+  const limits: SplitLimits = {};
+  if (options.splitSize !== undefined) {
+    const maxRecords = Number(options.splitSize);
+    if (!Number.isInteger(maxRecords) || maxRecords < 1) {
+      throw new CliError(
+        `Invalid --split-size value ${JSON.stringify(options.splitSize)}; expected a positive record count.`,
+        "INVALID_ARGUMENTS",
+      );
+    }
+    limits.maxRecords = maxRecords;
+  }
+  limits.maxBytes = parseByteSizeOption("--split-bytes", options.splitBytes);
+
+  return limits;
+}
@@ -0,0 +1,131 @@
+import { ApiOperationContext, RecordWriteHooks } from "./types";
+import { CliError } from "../../../utilities/errors/cli-error";
+import { assertStrictJson } from "../../../utilities/shared/strict-json";
+import { log } from "../../../utilities/shared/logger";
+import { describeFailure, FailureReport, printFailureReport } from "./failure-report";
+
+const MAX_BATCH_SIZE = 60;
+const DEFAULT_CONCURRENCY = 4;
+
+interface PendingRecord {
+  // 1-based input line, used as the record index in failures.
+  line: number;
+  record: Record<string, unknown>;
+}
+
+// --stdin-jsonl: records are batched as lines arrive and at most --concurrency
+// batches are in flight. Reading waits for a free slot, so memory stays bounded
+// by batch size x concurrency however long the input is. Failed batches and
+// unparsable lines are reported at the end instead of stopping the run.
+export async function createFromJsonLines(
+  ctx: ApiOperationContext,
+  lines: AsyncIterable<string>,
+  hooks: RecordWriteHooks = {},
+): Promise<void> {
+  const batchSize = resolveBatchSize(ctx.options.batchSize);
+  const concurrency = resolveConcurrency(ctx.options.concurrency);
+  const report: FailureReport = { action: "created", succeeded: 0, failures: [] };
+  const inFlight = new Set<Promise<void>>();
+  let pending: PendingRecord[] = [];
+  let linesRead = 0;
+  let stopped = false;
+
+  const fail = (line: number, error: unknown, message = describeFailure(error)) => {
+    report.failures.push({ index: line, error: message });
+    report.firstError ??= error;
+    stopped ||= ctx.options.failFast === true;
+  };
+
+  const send = async (batch: PendingRecord[]): Promise<void> => {
+    try {
+      await ctx.services.records.batchCreate(ctx.object, batch.map((entry) => entry.record));
+      report.succeeded += batch.length;
+    } catch (error) {
+      const message = describeFailure(error);
+      for (const entry of batch) {
+        fail(entry.line, error, message);
+      }
+    }
+    log(
+      "info",
+      `Created ${report.succeeded}, failed ${report.failures.length} (${linesRead} lines read)`,
+      { created: report.succeeded, failed: report.failures.length, linesRead },
+    );
+  };
+
+  const dispatch = async (): Promise<void> => {
+    const batch = pending;
+    pending = [];
+    const task: Promise<void> = send(batch).finally(() => inFlight.delete(task));
+    inFlight.add(task);
+    if (inFlight.size >= concurrency) {
+      await Promise.race(inFlight);
+    }
+  };
+
+  for await (const line of lines) {
+    if (stopped) {
+      break;
+    }
+    linesRead += 1;
+    const text = line.trim();
+    if (text === "") {
+      continue;
+    }
+
+    const record = parseRecordLine(text, linesRead);
+    if (record instanceof CliError) {
+      fail(linesRead, record, record.message);
+      continue;
+    }
+    pending.push({
+      line: linesRead,
+      record: hooks.record ? hooks.record(record, linesRead - 1) : record,
+    });
+    if (pending.length >= batchSize) {
+      await dispatch();
+    }
+  }
+
+  if (pending.length > 0 && !stopped) {
+    await dispatch();
+  }
+  await Promise.all(inFlight);
+
+  report.failures.sort((left, right) => left.index - right.index);
+  printFailureReport(ctx, report);
+}
+
+function parseRecordLine(text: string, line: number): Record<string, unknown> | CliError {
+  let parsed: unknown;
+  try {
+    parsed = JSON.parse(text);
+    assertStrictJson(text);
+  } catch (error) {
+    const detail = error instanceof Error ? error.message : String(error);
+    return new CliError(`Line ${line} is not valid JSON: ${detail}`, "INVALID_ARGUMENTS");
+  }
+  if (typeof parsed !== "object" || parsed === null || Array.isArray(parsed)) {
+    return new CliError(`Line ${line} is not a JSON object.`, "INVALID_ARGUMENTS");
+  }
+  return parsed as Record<string, unknown>;
+}
+
+function resolveBatchSize(raw: string | undefined): number {
+  const size = raw ? Number(raw) : MAX_BATCH_SIZE;
+  return Number.isNaN(size) || size <= 0 ? MAX_BATCH_SIZE : Math.min(size, MAX_BATCH_SIZE);
+}
+
+function resolveConcurrency(raw: string | undefined): number {
+  if (raw === undefined) {
+    return DEFAULT_CONCURRENCY;
+  }
+  const concurrency = Number(raw);
+  if (!Number.isInteger(concurrency) || concurrency <= 0) {
+    throw new CliError(
+      `Invalid --concurrency value ${JSON.stringify(raw)}; expected a positive integer.`,
+      "INVALID_ARGUMENTS",
+    );
+  }
+  return concurrency;
+}
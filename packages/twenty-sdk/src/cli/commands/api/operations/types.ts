@@ -3,24 +3,62 @@ import { GlobalOptions } from "../../../utilities/shared/global-options";
 
 export interface ApiCommandOptions {
   limit?: string;
+  pageSize?: string;
   all?: boolean;
   filter?: string;
   include?: string;
   cursor?: string;
   sort?: string;
   order?: string;
+  sortLocal?: string;
   fields?: string;
+  distinct?: string;
+  withCounts?: boolean;
   param?: string[];
   data?: string;
   file?: string;
   set?: string[];
+  clear?: string[];
+  addTo?: string[];
+  removeFrom?: string[];
+  idempotencyKey?: string;
+  ifNotExists?: string;
+  ifExists?: boolean;
+  idFile?: string;
+  showDiff?: boolean;
+  onMissing?: string;
   yes?: boolean;
   ids?: string;
   format?: string;
   output?: string;
   outputFile?: string;
+  open?: boolean;
+  withPageInfo?: boolean;
+  printUrl?: boolean;
+  splitSize?: string;
+  splitBytes?: string;
+  manifest?: string;
+  checkpoint?: string;
+  resume?: string;
+  array?: boolean;
+  stream?: boolean;
+  streamHeader?: boolean;
+  flatten?: boolean;
+  flattenDepth?: string;
+  flattenArrays?: string;
+  expand?: string;
+  computed?: string[];
   batchSize?: string;
+  stdinJsonl?: boolean;
+  concurrency?: string;
+  templateFile?: string;
+  onlyErrors?: boolean;
+  failFast?: boolean;
+  maxErrors?: string;
+  continueFrom?: string;
   dryRun?: boolean;
+  fromFile?: string;
+  key?: string;
   continueOnError?: boolean;
   field?: string;
   fieldsList?: string;
@@ -29,10 +67,35 @@ export interface ApiCommandOptions {
   priority?: string;
 }
 
+// Lets object-specific commands reuse the generic write operations while
+// mapping each input row (e.g. CSV columns) onto the API's record shape.
+export interface RecordWriteHooks {
+  record?: (record: Record<string, unknown>, index: number) => Record<string, unknown>;
+  // Import only: resolves each batch just before it is written, e.g. to link
+  // related records. A rejection fails the batch like a write error; rows it
+  // skips fail on their own and the rest of the batch is still written.
+  batch?: (records: Record<string, unknown>[]) => Promise<PreparedBatch>;
+  // Import only: lines describing what --dry-run would also do, e.g. the
+  // related records it would create.
+  preview?: (records: Record<string, unknown>[]) => Promise<string[]>;
+  // Import only: extra status fields and lines for the final summary.
+  report?: () => { fields: Record<string, unknown>; lines: string[] };
+}
+
+export interface PreparedBatch {
+  // The rows to write, in input order, without the skipped ones.
+  records: Record<string, unknown>[];
+  // Positions in the input batch (0-based) of rows that could not be prepared.
+  skipped: { position: number; error: unknown }[];
+}
+
 export interface ApiOperationContext {
   object: string;
   arg?: string;
   arg2?: string;
+  // Every positional after the object, for operations that take several
+  // (import reads each file in turn). arg is the first of them.
+  args?: string[];
   options: ApiCommandOptions;
   services: CliServices;
   globalOptions: GlobalOptions;
@@ -1,16 +1,150 @@
 import { ApiOperationContext } from "./types";
 import { parseBody } from "../../../utilities/shared/body";
 import { CliError } from "../../../utilities/errors/cli-error";
+import { mergeArrayEdits, parseArrayEdits } from "./array-edits";
+import { isNotFound } from "./not-found";
+import { readIdFileOption, runForEachId } from "./id-file";
+import { readFileOrStdin } from "../../../utilities/shared/io";
+import { diffUpdatedFields, FieldChange, formatFieldChanges } from "./record-diff";
+
+type OnMissing = "error" | "create";
 
 export async function runUpdateOperation(ctx: ApiOperationContext): Promise<void> {
+  const ids = await readIdFileOption(ctx);
   const id = ctx.arg;
-  if (!id) {
+  if (!ids && !id) {
     throw new CliError("Missing record ID.", "INVALID_ARGUMENTS");
   }
-  const payload = await parseBody(ctx.options.data, ctx.options.file, ctx.options.set);
-  const record = await ctx.services.records.update(ctx.object, id, payload);
-  await ctx.services.output.render(record, {
+  const onMissing = resolveOnMissing(ctx.options.onMissing);
+  // Cleared fields are sent as explicit nulls, after --data and --set.
+  const clears = (ctx.options.clear ?? []).map((field) => `${field}=null`);
+  // --add-to/--remove-from read the current record and send the merged arrays,
+  // since PATCH replaces array fields wholesale.
+  const edits = parseArrayEdits(ctx.options.addTo, ctx.options.removeFrom);
+  if (edits.length > 0 && onMissing === "create") {
+    throw new CliError(
+      "--on-missing create cannot be combined with --add-to or --remove-from.",
+      "INVALID_ARGUMENTS",
+      "Use --set to give array fields their full value.",
+    );
+  }
+  // With --id-file the same changes go to every ID, so a --file payload is
+  // read once rather than per record (stdin cannot be read twice).
+  const data =
+    ids && !ctx.options.data?.trim() && ctx.options.file?.trim()
+      ? await readFileOrStdin(ctx.options.file.trim())
+      : ctx.options.data;
+  const file = ids ? undefined : ctx.options.file;
+  const showDiff = ctx.options.showDiff === true;
+
+  const update = async (recordId: string): Promise<UpdateResult> => {
+    // Fetched for --add-to/--remove-from and --show-diff; with --on-missing
+    // create a missing record simply has no before.
+    const before =
+      edits.length > 0 || showDiff ? await fetchCurrent(ctx, recordId, onMissing) : undefined;
+    const merges = edits.length > 0 ? mergeArrayEdits(before, edits) : [];
+    const payload = await parseBody(data, file, [
+      ...(ctx.options.set ?? []),
+      ...clears,
+      ...merges,
+    ]);
+    const { created, record } = await applyUpdate(ctx, recordId, payload, onMissing);
+    return {
+      created,
+      record,
+      ...(showDiff ? { changes: diffUpdatedFields(before, record, payload) } : {}),
+    };
+  };
+
+  if (ids) {
+    await runForEachId(ctx, ids, "updated", async (recordId) => {
+      const { created, record, changes } = await update(recordId);
+      const result = changes ? { changes } : { record };
+      return onMissing === "create" ? { created, ...result } : result;
+    });
+    return;
+  }
+
+  const { created, record, changes } = await update(id!);
+  if (changes) {
+    await renderDiff(ctx, id!, changes, onMissing === "create" ? created : undefined);
+    return;
+  }
+  await ctx.services.output.render(onMissing === "create" ? { created, record } : record, {
     format: ctx.globalOptions.output,
     query: ctx.globalOptions.query,
   });
 }
+
+interface UpdateResult {
+  created: boolean;
+  record: unknown;
+  changes?: FieldChange[];
+}
+
+async function fetchCurrent(
+  ctx: ApiOperationContext,
+  id: string,
+  onMissing: OnMissing,
+): Promise<unknown> {
+  try {
+    return await ctx.services.records.get(ctx.object, id);
+  } catch (error) {
+    if (onMissing === "create" && isNotFound(error)) {
+      return undefined;
+    }
+    throw error;
+  }
+}
+
+// --on-missing create: a missing ID is created with the same fields, keeping
+// the requested ID so later updates by that ID find it.
+async function applyUpdate(
+  ctx: ApiOperationContext,
+  id: string,
+  payload: Record<string, unknown>,
+  onMissing: OnMissing,
+): Promise<{ created: boolean; record: unknown }> {
+  try {
+    return { created: false, record: await ctx.services.records.update(ctx.object, id, payload) };
+  } catch (error) {
+    if (onMissing !== "create" || !isNotFound(error)) {
+      throw error;
+    }
+    return {
+      created: true,
+      record: await ctx.services.records.create(ctx.object, { ...payload, id }),
+    };
+  }
+}
+
+// JSON keeps the values' types: {id, changes: [{field, from, to}]}. csv, text,
+// and table get one row per changed field.
+async function renderDiff(
+  ctx: ApiOperationContext,
+  id: string,
+  changes: FieldChange[],
+  created: boolean | undefined,
+): Promise<void> {
+  const format = ctx.globalOptions.output ?? "json";
+  const tabular = format !== "json" && format !== "jsonl";
+  await ctx.services.output.render(
+    tabular
+      ? formatFieldChanges(changes)
+      : { id, ...(created === undefined ? {} : { created }), changes },
+    {
+      format: ctx.globalOptions.output,
+      query: ctx.globalOptions.query,
+    },
+  );
+}
+
+function resolveOnMissing(value: string | undefined): OnMissing {
+  if (value === undefined || value === "error" || value === "create") {
+    return value ?? "error";
+  }
+  throw new CliError(
+    `Invalid --on-missing value ${JSON.stringify(value)}; expected error or create.`,
+    "INVALID_ARGUMENTS",
+  );
+}
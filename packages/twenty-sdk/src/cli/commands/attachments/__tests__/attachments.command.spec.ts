@@ -0,0 +1,139 @@
+import { afterEach, beforeEach, describe, expect, it, vi } from "vitest";
+import { Command } from "commander";
+import fs from "fs-extra";
+import { registerAttachmentsCommand, resolveAttachmentSource } from "../attachments.command";
+
+const mockCreateCommandContext = vi.hoisted(() => vi.fn());
+
+vi.mock("../../../utilities/shared/context", async () => {
+  const actual = await vi.importActual<typeof import("../../../utilities/shared/context")>(
+    "../../../utilities/shared/context",
+  );
+
+  return {
+    ...actual,
+    createCommandContext: mockCreateCommandContext,
+  };
+});
+vi.mock("fs-extra");
+
+describe("attachments command", () => {
+  let program: Command;
+  let consoleSpy: ReturnType<typeof vi.spyOn>;
+  let mockList: ReturnType<typeof vi.fn>;
+  let mockGet: ReturnType<typeof vi.fn>;
+  let mockRequest: ReturnType<typeof vi.fn>;
+  let mockRender: ReturnType<typeof vi.fn>;
+
+  beforeEach(() => {
+    program = new Command();
+    program.exitOverride();
+    registerAttachmentsCommand(program);
+    consoleSpy = vi.spyOn(console, "log").mockImplementation(() => {});
+    mockList = vi.fn().mockResolvedValue({ data: [{ id: "att-1", name: "contract.pdf" }] });
+    mockGet = vi.fn();
+    mockRequest = vi.fn().mockResolvedValue({ data: Buffer.from([0x25, 0x50, 0x44, 0x46]) });
+    mockRender = vi.fn();
+    mockCreateCommandContext.mockReset();
+    mockCreateCommandContext.mockReturnValue({
+      globalOptions: { output: "json", query: undefined },
+      services: {
+        records: { list: mockList, get: mockGet },
+        publicHttp: { request: mockRequest },
+        output: { render: mockRender },
+      },
+    } as never);
+  });
+
+  afterEach(() => {
+    consoleSpy.mockRestore();
+    vi.clearAllMocks();
+  });
+
+  it("lists attachments for a record across target types", async () => {
+    await program.parseAsync(["node", "test", "attachments", "list", "--record-id", "p-1"]);
+
+    expect(mockList).toHaveBeenCalledWith("attachments", {
+      filter:
+        'or(personId[eq]:"p-1",companyId[eq]:"p-1",opportunityId[eq]:"p-1",noteId[eq]:"p-1",taskId[eq]:"p-1")',
+    });
+    expect(mockRender).toHaveBeenCalledWith([{ id: "att-1", name: "contract.pdf" }], {
+      format: "json",
+      query: undefined,
+    });
+  });
+
+  it("narrows the list to one object type", async () => {
+    await program.parseAsync([
+      "node",
+      "test",
+      "attachments",
+      "list",
+      "--record-id",
+      "c-1",
+      "--object",
+      "company",
+      "--limit",
+      "5",
+    ]);
+
+    expect(mockList).toHaveBeenCalledWith("attachments", {
+      filter: 'companyId[eq]:"c-1"',
+      limit: 5,
+    });
+  });
+
+  it("downloads the signed /files path as binary under the attachment name", async () => {
+    mockGet.mockResolvedValue({
+      id: "att-1",
+      name: "../contract.pdf",
+      fullPath: "attachment/abc.pdf?token=signed",
+    });
+
+    await program.parseAsync(["node", "test", "attachments", "download", "att-1"]);
+
+    expect(mockGet).toHaveBeenCalledWith("attachments", "att-1");
+    expect(mockRequest).toHaveBeenCalledWith({
+      authMode: "optional",
+      method: "get",
+      path: "/files/attachment/abc.pdf?token=signed",
+      responseType: "arraybuffer",
+    });
+    expect(fs.writeFile).toHaveBeenCalledWith(
+      "contract.pdf",
+      Buffer.from([0x25, 0x50, 0x44, 0x46]),
+    );
+    expect(consoleSpy).toHaveBeenCalledWith("Downloaded to contract.pdf");
+  });
+
+  it("fetches external signed URLs without the API token", async () => {
+    mockGet.mockResolvedValue({
+      id: "att-2",
+      file: [{ url: "https://bucket.example.com/a/b.png?sig=1" }],
+    });
+
+    await program.parseAsync([
+      "node",
+      "test",
+      "attachments",
+      "download",
+      "att-2",
+      "--out",
+      "x.png",
+    ]);
+
+    expect(mockRequest).toHaveBeenCalledWith(
+      expect.objectContaining({
+        authMode: "none",
+        path: "https://bucket.example.com/a/b.png?sig=1",
+      }),
+    );
+    expect(fs.writeFile).toHaveBeenCalledWith("x.png", expect.any(Buffer));
+  });
+
+  it("fails clearly when an attachment has no file", () => {
+    expect(() => resolveAttachmentSource("att-3", { id: "att-3", name: "empty" })).toThrow(
+      "Attachment att-3 has no downloadable file.",
+    );
+  });
+});
@@ -0,0 +1,127 @@
+import fs from "fs-extra";
+import path from "path";
+import { Command } from "commander";
+import { CliError } from "../../utilities/errors/cli-error";
+import { inferOutputPath, toOutputBuffer } from "../../utilities/file/services/download";
+import { printStatus } from "../../utilities/output/services/status-printer";
+import { applyGlobalOptions } from "../../utilities/shared/global-options";
+import { createCommandContext } from "../../utilities/shared/context";
+import { requestPublic } from "../../utilities/shared/request-transport";
+
+interface ListOptions {
+  recordId?: string;
+  object?: string;
+  limit?: string;
+}
+
+interface DownloadOptions {
+  outputFile?: string;
+  out?: string;
+}
+
+// Objects an attachment can belong to, by the singular name used in its
+// foreign key (personId, companyId, ...).
+const ATTACHMENT_TARGETS = ["person", "company", "opportunity", "note", "task"] as const;
+
+export function registerAttachmentsCommand(program: Command): void {
+  const cmd = program.command("attachments").description("List and download record attachments");
+  applyGlobalOptions(cmd);
+
+  const listCmd = cmd
+    .command("list")
+    .description("List the attachments of a record")
+    .requiredOption("--record-id <id>", "ID of the person, company, or other record")
+    .option("--object <name>", `Record type: ${ATTACHMENT_TARGETS.join(", ")} (default: any)`)
+    .option("--limit <n>", "Maximum attachments to return");
+  applyGlobalOptions(listCmd);
+  listCmd.action(async (options: ListOptions, command: Command) => {
+    const { globalOptions, services } = createCommandContext(command);
+    const result = await services.records.list("attachments", {
+      filter: buildRecordFilter(options.recordId!, options.object),
+      ...(options.limit ? { limit: Number(options.limit) } : {}),
+    });
+    await services.output.render(result.data, {
+      format: globalOptions.output,
+      query: globalOptions.query,
+    });
+  });
+
+  const downloadCmd = cmd
+    .command("download")
+    .description("Download an attachment's file")
+    .argument("<attachmentId>", "Attachment ID")
+    .option("--output-file <path>", "Output file path (default: the attachment name)")
+    .option("--out <path>", "Alias for --output-file");
+  applyGlobalOptions(downloadCmd);
+  downloadCmd.action(async (id: string, options: DownloadOptions, command: Command) => {
+    const { globalOptions, services } = createCommandContext(command);
+    const attachment = await services.records.get("attachments", id);
+    const source = resolveAttachmentSource(id, attachment);
+    // Absolute URLs are already signed and may point at another host (e.g.
+    // S3), so the API token is only sent to the Twenty server itself.
+    const response = await requestPublic<ArrayBuffer | Buffer | string>(services, {
+      authMode: source.external ? "none" : "optional",
+      method: "get",
+      path: source.url,
+      responseType: "arraybuffer",
+    });
+
+    const outputPath = options.outputFile ?? options.out ?? source.name;
+    await fs.writeFile(outputPath, toOutputBuffer(response.data));
+    printStatus(
+      globalOptions,
+      {
+        action: "downloaded",
+        id,
+        path: outputPath,
+        message: `Downloaded to ${outputPath}`,
+      },
+      services.writer,
+    );
+  });
+}
+
+export function buildRecordFilter(recordId: string, object?: string): string {
+  const value = JSON.stringify(recordId);
+  if (object === undefined) {
+    return `or(${ATTACHMENT_TARGETS.map((target) => `${target}Id[eq]:${value}`).join(",")})`;
+  }
+
+  const target = object.toLowerCase();
+  if (!ATTACHMENT_TARGETS.includes(target as (typeof ATTACHMENT_TARGETS)[number])) {
+    throw new CliError(
+      `Unsupported --object "${object}". Expected one of: ${ATTACHMENT_TARGETS.join(", ")}.`,
+      "INVALID_ARGUMENTS",
+    );
+  }
+  return `${target}Id[eq]:${value}`;
+}
+
+// Twenty returns the file location as fullPath (a signed path under /files
+// or a full signed URL) or, on newer servers, as a FILES field with a url.
+export function resolveAttachmentSource(
+  id: string,
+  attachment: unknown,
+): { url: string; name: string; external: boolean } {
+  const record = isRecord(attachment) ? attachment : {};
+  const files = Array.isArray(record.file) ? record.file : [record.file];
+  const fileUrl = files.map((file) => (isRecord(file) ? file.url : undefined)).find(isNonEmpty);
+  const location = isNonEmpty(record.fullPath) ? record.fullPath : fileUrl;
+  if (!location) {
+    throw new CliError(`Attachment ${id} has no downloadable file.`, "NOT_FOUND");
+  }
+
+  const external = /^https?:\/\//i.test(location);
+  const url = external || location.startsWith("/") ? location : `/files/${location}`;
+  // Only the base name is used so a stored name cannot write outside the cwd.
+  const name = isNonEmpty(record.name) ? path.basename(record.name) : inferOutputPath(location);
+  return { url, name, external };
+}
+
+function isRecord(value: unknown): value is Record<string, unknown> {
+  return typeof value === "object" && value !== null && !Array.isArray(value);
+}
+
+function isNonEmpty(value: unknown): value is string {
+  return typeof value === "string" && value.trim() !== "";
+}
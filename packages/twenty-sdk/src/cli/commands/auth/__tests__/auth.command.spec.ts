@@ -11,10 +11,33 @@ import {
 import { CliError } from "../../../utilities/errors/cli-error";
 import { mockConstructor } from "../../../test-utils/mock-constructor";
 import { loadCliEnvironment } from "../../../utilities/config/services/environment.service";
+import { readStdin } from "../../../utilities/shared/io";
+import { confirmPrompt, selectPrompt } from "../../../utilities/shared/confirmation";
 
 vi.mock("../../../utilities/config/services/config.service");
 vi.mock("../../../utilities/api/services/api.service");
 vi.mock("../../../utilities/api/services/public-http.service");
+vi.mock("../../../utilities/shared/io", async () => {
+  const actual = await vi.importActual<typeof import("../../../utilities/shared/io")>(
+    "../../../utilities/shared/io",
+  );
+
+  return {
+    ...actual,
+    readStdin: vi.fn(),
+  };
+});
+vi.mock("../../../utilities/shared/confirmation", async () => {
+  const actual = await vi.importActual<typeof import("../../../utilities/shared/confirmation")>(
+    "../../../utilities/shared/confirmation",
+  );
+
+  return {
+    ...actual,
+    confirmPrompt: vi.fn(),
+    selectPrompt: vi.fn(),
+  };
+});
 vi.mock("../../../utilities/config/services/environment.service", () => ({
   loadCliEnvironment: vi.fn(),
   resolveEnvFileFromArgv: vi.fn(),
@@ -34,6 +57,8 @@ describe("auth commands", () => {
     mockPost = vi.fn();
     mockPublicRequest = vi.fn();
     vi.mocked(loadCliEnvironment).mockReset();
+    vi.mocked(confirmPrompt).mockResolvedValue(false);
+    vi.mocked(ConfigService.prototype.hasDefaultWorkspace).mockResolvedValue(true);
     vi.mocked(ConfigService.prototype.resolveApiConfig).mockResolvedValue({
       apiUrl: "https://api.twenty.com",
       apiKey: "",
@@ -121,6 +146,53 @@ describe("auth commands", () => {
       expect(ConfigService.prototype.setDefaultWorkspace).toHaveBeenCalledWith("production");
       expect(consoleSpy).toHaveBeenCalledWith('Switched to workspace "production".');
     });
+
+    it("accepts auth use as an alias for switch", async () => {
+      vi.mocked(ConfigService.prototype.setDefaultWorkspace).mockResolvedValue(undefined);
+
+      await program.parseAsync(["node", "test", "auth", "use", "staging"]);
+
+      expect(ConfigService.prototype.setDefaultWorkspace).toHaveBeenCalledWith("staging");
+      expect(consoleSpy).toHaveBeenCalledWith('Switched to workspace "staging".');
+    });
+
+    it("picks the workspace from a list when no name is given", async () => {
+      vi.mocked(ConfigService.prototype.listWorkspaces).mockResolvedValue([
+        { name: "production", isDefault: true },
+        { name: "staging", isDefault: false },
+      ] as WorkspaceInfo[]);
+      vi.mocked(selectPrompt).mockResolvedValue("staging");
+
+      await program.parseAsync(["node", "test", "auth", "switch"]);
+
+      expect(selectPrompt).toHaveBeenCalledWith("Switch to which workspace?", [
+        "production (default)",
+        "staging",
+      ]);
+      expect(ConfigService.prototype.setDefaultWorkspace).toHaveBeenCalledWith("staging");
+    });
+
+    it("fails without a name when nothing is picked", async () => {
+      vi.mocked(ConfigService.prototype.listWorkspaces).mockResolvedValue([
+        { name: "production", isDefault: true },
+      ] as WorkspaceInfo[]);
+      vi.mocked(selectPrompt).mockResolvedValue(undefined);
+      vi.mocked(ConfigService.prototype.setDefaultWorkspace).mockClear();
+
+      await expect(program.parseAsync(["node", "test", "auth", "switch"])).rejects.toMatchObject({
+        message: "No workspace selected.",
+        code: "INVALID_ARGUMENTS",
+      });
+      expect(ConfigService.prototype.setDefaultWorkspace).not.toHaveBeenCalled();
+    });
+
+    it("prints a status object with --output json", async () => {
+      await program.parseAsync(["node", "test", "auth", "switch", "staging", "-o", "json"]);
+
+      expect(consoleSpy).toHaveBeenCalledWith(
+        JSON.stringify({ status: "ok", action: "switched", workspace: "staging" }),
+      );
+    });
   });
 
   describe("auth login", () => {
@@ -160,6 +232,130 @@ describe("auth commands", () => {
       expect(consoleSpy).toHaveBeenCalledWith('Workspace "production" configured.');
       expect(consoleSpy).toHaveBeenCalledWith("API URL: https://custom.twenty.com");
     });
+
+    it("reads a pasted token with --device", async () => {
+      const stderrSpy = vi.spyOn(console, "error").mockImplementation(() => {});
+      vi.mocked(readStdin).mockResolvedValue("  pasted-token\n");
+      vi.mocked(ConfigService.prototype.saveWorkspace).mockResolvedValue(undefined);
+
+      await program.parseAsync([
+        "node",
+        "test",
+        "auth",
+        "login",
+        "--device",
+        "--base-url",
+        "https://crm.example.com/",
+      ]);
+
+      expect(stderrSpy).toHaveBeenCalledWith(
+        "Open https://crm.example.com/settings/api-webhooks on any device and create an API key.",
+      );
+      expect(ConfigService.prototype.saveWorkspace).toHaveBeenCalledWith("default", {
+        apiKey: "pasted-token",
+        apiUrl: "https://crm.example.com/",
+      });
+      stderrSpy.mockRestore();
+    });
+
+    it("normalizes --profile and only replaces that profile", async () => {
+      await program.parseAsync([
+        "node",
+        "test",
+        "auth",
+        "login",
+        "--token",
+        "staging-token",
+        "--profile",
+        " Staging EU ",
+      ]);
+
+      expect(ConfigService.prototype.saveWorkspace).toHaveBeenCalledTimes(1);
+      expect(ConfigService.prototype.saveWorkspace).toHaveBeenCalledWith("staging-eu", {
+        apiKey: "staging-token",
+        apiUrl: "https://api.twenty.com",
+      });
+      expect(confirmPrompt).not.toHaveBeenCalled();
+      expect(ConfigService.prototype.setDefaultWorkspace).not.toHaveBeenCalled();
+    });
+
+    it("reads --profile given to the root program", async () => {
+      const root = new Command();
+      root.exitOverride();
+      root.option("--profile <name>", "Workspace profile for all subcommands");
+      registerAuthCommand(root);
+
+      await root.parseAsync(["node", "test", "auth", "login", "--token", "t", "--profile", "qa"]);
+
+      expect(ConfigService.prototype.saveWorkspace).toHaveBeenCalledWith("qa", expect.anything());
+    });
+
+    it("offers to make the profile default when no default exists", async () => {
+      vi.mocked(ConfigService.prototype.hasDefaultWorkspace).mockResolvedValue(false);
+      vi.mocked(confirmPrompt).mockResolvedValue(true);
+
+      await program.parseAsync(["node", "test", "auth", "login", "--token", "t", "--profile", "qa"]);
+
+      expect(confirmPrompt).toHaveBeenCalledWith('No default profile is set. Make "qa" the default?');
+      expect(ConfigService.prototype.setDefaultWorkspace).toHaveBeenCalledWith("qa");
+      expect(consoleSpy).toHaveBeenCalledWith('Default workspace set to "qa".');
+    });
+
+    it("sets the default without prompting when --default is passed", async () => {
+      await program.parseAsync([
+        "node",
+        "test",
+        "auth",
+        "login",
+        "--token",
+        "t",
+        "--profile",
+        "qa",
+        "--default",
+      ]);
+
+      expect(confirmPrompt).not.toHaveBeenCalled();
+      expect(ConfigService.prototype.setDefaultWorkspace).toHaveBeenCalledWith("qa");
+    });
+
+    it("rejects profile names that cannot be normalized", async () => {
+      await expect(
+        program.parseAsync(["node", "test", "auth", "login", "--token", "t", "--profile", "../x"]),
+      ).rejects.toMatchObject({ message: 'Invalid profile name "../x".', code: "INVALID_ARGUMENTS" });
+      expect(ConfigService.prototype.saveWorkspace).not.toHaveBeenCalled();
+    });
+
+    it("requires --token or --device", async () => {
+      await expect(program.parseAsync(["node", "test", "auth", "login"])).rejects.toMatchObject({
+        message: "Missing API token.",
+        code: "INVALID_ARGUMENTS",
+      });
+    });
+  });
+
+  describe("auth token rotation", () => {
+    it("stages a token for the active workspace", async () => {
+      vi.mocked(ConfigService.prototype.stageWorkspaceToken).mockResolvedValue(undefined);
+
+      await program.parseAsync(["node", "test", "auth", "stage-token", "--token", "next-token"]);
+
+      expect(ConfigService.prototype.stageWorkspaceToken).toHaveBeenCalledWith(
+        "production",
+        "next-token",
+      );
+      expect(consoleSpy).toHaveBeenCalledWith('Staged token for workspace "production".');
+    });
+
+    it("promotes the staged token for the selected workspace", async () => {
+      vi.mocked(ConfigService.prototype.promoteWorkspaceToken).mockResolvedValue(undefined);
+
+      await program.parseAsync(["node", "test", "auth", "promote-token", "--workspace", "staging"]);
+
+      expect(ConfigService.prototype.resolveApiConfig).toHaveBeenCalledWith(
+        expect.objectContaining({ workspace: "staging" }),
+      );
+      expect(ConfigService.prototype.promoteWorkspaceToken).toHaveBeenCalledWith("production");
+    });
   });
 
   describe("auth logout", () => {
@@ -33,6 +33,7 @@ const SSO_URL_MUTATION = `mutation GetAuthorizationUrlForSSO($input: GetAuthoriz
 }`;
 
 const HOSTED_API_HOSTNAME = "api.twenty.com";
+const HOSTED_APP_URL = "https://app.twenty.com";
 const DEFAULT_AUTH_MUTATION_PATH = "/graphql";
 const HOSTED_AUTH_MUTATION_PATH = "/metadata";
 
@@ -51,6 +52,13 @@ export function isHostedTwentyApiUrl(apiUrl: string): boolean {
   }
 }
 
+// The web app lives on app.twenty.com for the hosted API and on the API's own
+// origin when self-hosted.
+export function resolveWebAppUrl(apiUrl: string): string {
+  const appUrl = isHostedTwentyApiUrl(apiUrl) ? HOSTED_APP_URL : apiUrl;
+  return appUrl.replace(/\/+$/, "");
+}
+
 export async function resolveAuthRequestSurface(
   configService: AuthConfigService,
   workspace: string | undefined,
@@ -1,14 +1,20 @@
 import { Command } from "commander";
 import { requireGraphqlField, type GraphQLResponse } from "../../utilities/api/graphql-response";
+import { ConfigService } from "../../utilities/config/services/config.service";
 import { CliError } from "../../utilities/errors/cli-error";
 import { applyGlobalOptions, resolveGlobalOptions } from "../../utilities/shared/global-options";
 import { createServices } from "../../utilities/shared/services";
 import { createCommandContext } from "../../utilities/shared/context";
+import { confirmPrompt, selectPrompt } from "../../utilities/shared/confirmation";
+import { printStatus } from "../../utilities/output/services/status-printer";
+import { normalizeProfileName } from "../../utilities/config/profile-name";
+import { readStdin } from "../../utilities/shared/io";
 import { requestPublic } from "../../utilities/shared/request-transport";
 import {
   buildRenewTokenRequestData,
   buildSsoUrlRequestData,
   resolveAuthRequestSurface,
+  resolveWebAppUrl,
 } from "./auth-compat";
 
 const CURRENT_WORKSPACE_QUERY = `query CurrentWorkspace {
@@ -75,6 +81,44 @@ function maskToken(token: string): string {
   return token.slice(0, 4) + "****" + token.slice(-4);
 }
 
+// Headless login: nothing listens locally, so the key is created in the web app
+// on any device with a browser and pasted back on stdin.
+async function promptForPastedToken(baseUrl: string): Promise<string> {
+  // eslint-disable-next-line no-console
+  console.error(`Open ${resolveApiKeySettingsUrl(baseUrl)} on any device and create an API key.`);
+  // eslint-disable-next-line no-console
+  console.error("Paste the key here, then press Enter and Ctrl-D:");
+
+  const input = await readStdin();
+  return input.trim().split(/\r?\n/)[0]?.trim() ?? "";
+}
+
+function resolveApiKeySettingsUrl(baseUrl: string): string {
+  return `${resolveWebAppUrl(baseUrl)}/settings/api-webhooks`;
+}
+
+async function pickWorkspace(config: ConfigService): Promise<string> {
+  const workspaces = await config.listWorkspaces();
+  if (workspaces.length === 0) {
+    throw new CliError(
+      "No workspaces configured.",
+      "INVALID_ARGUMENTS",
+      'Use "twenty auth login" to add a workspace.',
+    );
+  }
+  const labels = workspaces.map((ws) => (ws.isDefault ? `${ws.name} (default)` : ws.name));
+  const label = await selectPrompt("Switch to which workspace?", labels);
+  const picked = workspaces[labels.indexOf(label ?? "")];
+  if (!picked) {
+    throw new CliError(
+      "No workspace selected.",
+      "INVALID_ARGUMENTS",
+      'Pass the workspace name, e.g. "twenty auth switch staging", or run in a terminal.',
+    );
+  }
+  return picked.name;
+}
+
 function applyEnvFileOption(command: Command): Command {
   return command.option("--env-file <path>", "Load environment variables from file");
 }
@@ -108,18 +152,29 @@ export function registerAuthCommand(program: Command): void {
     });
   });
 
-  // auth switch
+  // auth switch (alias: auth use)
   applyEnvFileOption(
     authCmd
       .command("switch")
-      .description("Set default workspace")
-      .argument("<workspace>", "Workspace name"),
-  ).action(async (workspace: string, _options: { envFile?: string }, command: Command) => {
-    const { services } = createCommandContext(command);
-    await services.config.setDefaultWorkspace(workspace);
-    // eslint-disable-next-line no-console
-    console.log(`Switched to workspace "${workspace}".`);
-  });
+      .alias("use")
+      .description("Set default workspace used when no --profile or TWENTY_PROFILE is given")
+      .argument("[workspace]", "Workspace name; omit to pick from a list"),
+  )
+    .option("-o, --output <format>", "Output format: json, jsonl, csv, text, table, html")
+    .action(async (name: string | undefined, _options: { envFile?: string }, command: Command) => {
+      const { globalOptions, services } = createCommandContext(command);
+      const workspace = name ?? (await pickWorkspace(services.config));
+      await services.config.setDefaultWorkspace(workspace);
+      printStatus(
+        globalOptions,
+        {
+          action: "switched",
+          workspace,
+          message: `Switched to workspace "${workspace}".`,
+        },
+        services.writer,
+      );
+    });
 
   // auth status
   const statusCmd = authCmd
@@ -299,28 +354,122 @@ export function registerAuthCommand(program: Command): void {
   authCmd
     .command("login")
     .description("Configure API credentials")
-    .requiredOption("--token <token>", "API token")
+    .option("--token <token>", "API token")
+    .option("--device", "Create the token on another device and paste it here")
     .option("--base-url <url>", "API base URL", "https://api.twenty.com")
-    .option("--workspace <name>", "Workspace name", "default")
+    .option("--workspace <name>", "Profile to create or update (default: default)")
+    .option("--profile <name>", "Alias for --workspace")
+    .option("--default", "Make this profile the default")
     .option("--env-file <path>", "Load environment variables from file")
+    // Own --workspace rules out applyGlobalOptions; -o still selects a JSON status.
+    .option("-o, --output <format>", "Output format: json, jsonl, csv, text, table, html")
     .action(
       async (
-        options: { token: string; baseUrl: string; workspace: string; envFile?: string },
+        options: {
+          token?: string;
+          device?: boolean;
+          baseUrl: string;
+          workspace?: string;
+          default?: boolean;
+          envFile?: string;
+        },
         command: Command,
       ) => {
-        const { services } = createCommandContext(command);
+        // --profile may be parsed by the root program, so read it with globals.
+        const profile = command.optsWithGlobals().profile as string | undefined;
+        const name = normalizeProfileName(options.workspace ?? profile ?? "default");
+        if (options.token && options.device) {
+          throw new CliError("Use only one of --token or --device.", "INVALID_ARGUMENTS");
+        }
+        const token = options.device ? await promptForPastedToken(options.baseUrl) : options.token;
+        if (!token) {
+          throw new CliError(
+            "Missing API token.",
+            "INVALID_ARGUMENTS",
+            "Pass --token <token>, or --device to paste a token created on another device.",
+          );
+        }
+        const { globalOptions, services } = createCommandContext(command);
 
-        await services.config.saveWorkspace(options.workspace, {
-          apiKey: options.token,
+        // Only this profile's entry is replaced; other profiles keep their tokens.
+        await services.config.saveWorkspace(name, {
+          apiKey: token,
           apiUrl: options.baseUrl,
         });
 
-        // eslint-disable-next-line no-console
-        console.log(`Workspace "${options.workspace}" configured.`);
-        // eslint-disable-next-line no-console
-        console.log(`API URL: ${options.baseUrl}`);
+        const makeDefault = Boolean(
+          options.default ||
+            (!(await services.config.hasDefaultWorkspace()) &&
+              (await confirmPrompt(`No default profile is set. Make "${name}" the default?`))),
+        );
+        if (makeDefault) {
+          await services.config.setDefaultWorkspace(name);
+        }
+
+        printStatus(
+          globalOptions,
+          {
+            action: "configured",
+            workspace: name,
+            apiUrl: options.baseUrl,
+            default: makeDefault,
+            message: [
+              `Workspace "${name}" configured.`,
+              `API URL: ${options.baseUrl}`,
+              ...(makeDefault ? [`Default workspace set to "${name}".`] : []),
+            ],
+          },
+          services.writer,
+        );
+      },
+    );
+
+  // auth stage-token
+  const stageTokenCmd = authCmd
+    .command("stage-token")
+    .description("Store a replacement token without activating it")
+    .requiredOption("--token <token>", "API token to stage");
+  applyGlobalOptions(stageTokenCmd);
+  stageTokenCmd.action(async (options: { token: string }, command: Command) => {
+    const { globalOptions, services } = createCommandContext(command);
+    const { workspace = "default" } = await services.config.resolveApiConfig({
+      workspace: globalOptions.workspace,
+    });
+
+    await services.config.stageWorkspaceToken(workspace, options.token);
+    printStatus(
+      globalOptions,
+      {
+        action: "staged",
+        workspace,
+        message: `Staged token for workspace "${workspace}".`,
       },
+      services.writer,
     );
+  });
+
+  // auth promote-token
+  const promoteTokenCmd = authCmd
+    .command("promote-token")
+    .description("Replace the active token with the staged token");
+  applyGlobalOptions(promoteTokenCmd);
+  promoteTokenCmd.action(async (_options: unknown, command: Command) => {
+    const { globalOptions, services } = createCommandContext(command);
+    const { workspace = "default" } = await services.config.resolveApiConfig({
+      workspace: globalOptions.workspace,
+    });
+
+    await services.config.promoteWorkspaceToken(workspace);
+    printStatus(
+      globalOptions,
+      {
+        action: "promoted",
+        workspace,
+        message: `Promoted staged token for workspace "${workspace}".`,
+      },
+      services.writer,
+    );
+  });
 
   // auth logout
   authCmd
@@ -329,20 +478,28 @@ export function registerAuthCommand(program: Command): void {
     .option("--workspace <name>", "Workspace name to remove")
     .option("--all", "Remove all workspaces")
     .option("--env-file <path>", "Load environment variables from file")
+    .option("-o, --output <format>", "Output format: json, jsonl, csv, text, table, html")
     .action(
       async (
         options: { workspace?: string; all?: boolean; envFile?: string },
         command: Command,
       ) => {
-        const { services } = createCommandContext(command);
+        const { globalOptions, services } = createCommandContext(command);
 
         if (options.all) {
           const workspaces = await services.config.listWorkspaces();
           for (const ws of workspaces) {
             await services.config.removeWorkspace(ws.name);
           }
-          // eslint-disable-next-line no-console
-          console.log("All workspaces removed.");
+          printStatus(
+            globalOptions,
+            {
+              action: "removed",
+              workspaces: workspaces.map((ws) => ws.name),
+              message: "All workspaces removed.",
+            },
+            services.writer,
+          );
           return;
         }
 
@@ -364,8 +521,15 @@ export function registerAuthCommand(program: Command): void {
         }
 
         await services.config.removeWorkspace(workspaceToRemove);
-        // eslint-disable-next-line no-console
-        console.log(`Workspace "${workspaceToRemove}" removed.`);
+        printStatus(
+          globalOptions,
+          {
+            action: "removed",
+            workspaces: [workspaceToRemove],
+            message: `Workspace "${workspaceToRemove}" removed.`,
+          },
+          services.writer,
+        );
       },
     );
 }
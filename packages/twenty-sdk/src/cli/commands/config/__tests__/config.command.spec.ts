@@ -0,0 +1,157 @@
+import { afterEach, beforeEach, describe, expect, it, vi } from "vitest";
+import { Command } from "commander";
+import { registerConfigCommand } from "../config.command";
+import { CliError } from "../../../utilities/errors/cli-error";
+
+const mockCreateCommandContext = vi.hoisted(() => vi.fn());
+
+vi.mock("../../../utilities/shared/context", async () => {
+  const actual = await vi.importActual<typeof import("../../../utilities/shared/context")>(
+    "../../../utilities/shared/context",
+  );
+
+  return {
+    ...actual,
+    createCommandContext: mockCreateCommandContext,
+  };
+});
+
+describe("config doctor", () => {
+  let program: Command;
+  let mockLoadConfigFile: ReturnType<typeof vi.fn>;
+  let mockResolveApiConfig: ReturnType<typeof vi.fn>;
+  let mockGet: ReturnType<typeof vi.fn>;
+  let mockRender: ReturnType<typeof vi.fn>;
+  let context: { globalOptions: Record<string, unknown>; services: Record<string, unknown> };
+
+  beforeEach(() => {
+    program = new Command();
+    program.exitOverride();
+    registerConfigCommand(program);
+    const resolved = { apiUrl: "https://crm.example.com", apiKey: "token", workspace: "default" };
+    mockLoadConfigFile = vi.fn().mockResolvedValue({ workspaces: {} });
+    mockResolveApiConfig = vi.fn().mockResolvedValue(resolved);
+    mockGet = vi.fn().mockResolvedValue({ status: 200, data: {} });
+    mockRender = vi.fn();
+    context = {
+      globalOptions: { output: "json", query: undefined },
+      services: {
+        config: {
+          loadConfigFile: mockLoadConfigFile,
+          resolveApiConfig: mockResolveApiConfig,
+          getConfig: vi.fn().mockResolvedValue(resolved),
+        },
+        api: { get: mockGet },
+        output: { render: mockRender },
+      },
+    };
+    mockCreateCommandContext.mockReset();
+    mockCreateCommandContext.mockReturnValue(context as never);
+  });
+
+  afterEach(() => {
+    process.exitCode = undefined;
+  });
+
+  function statuses(): Record<string, string> {
+    const report = mockRender.mock.calls[0][0] as { checks: { name: string; status: string }[] };
+    return Object.fromEntries(report.checks.map((check) => [check.name, check.status]));
+  }
+
+  it("passes every check when the configuration is usable", async () => {
+    await program.parseAsync(["node", "test", "config", "doctor"]);
+
+    expect(mockRender).toHaveBeenCalledWith(expect.objectContaining({ ok: true }), {
+      format: "json",
+      query: undefined,
+    });
+    expect(statuses()).toEqual({
+      output: "pass",
+      credential_store: "pass",
+      base_url: "pass",
+      token: "pass",
+      reachable: "pass",
+    });
+    expect(process.exitCode).toBeUndefined();
+  });
+
+  it("fails and skips the reachability check when the profile has no token", async () => {
+    mockResolveApiConfig.mockResolvedValue({
+      apiUrl: "https://crm.example.com",
+      apiKey: "",
+      workspace: "staging",
+    });
+
+    await program.parseAsync(["node", "test", "config", "doctor"]);
+
+    const report = mockRender.mock.calls[0][0];
+    expect(report.ok).toBe(false);
+    expect(report.checks).toContainEqual(
+      expect.objectContaining({
+        name: "token",
+        status: "fail",
+        remediation: "Run `twenty auth login --workspace staging` or set TWENTY_TOKEN.",
+      }),
+    );
+    expect(statuses().reachable).toBe("skip");
+    expect(mockGet).not.toHaveBeenCalled();
+    expect(process.exitCode).toBe(1);
+  });
+
+  it("rejects a base URL that does not parse", async () => {
+    mockResolveApiConfig.mockResolvedValue({
+      apiUrl: "crm.example.com",
+      apiKey: "token",
+      workspace: "default",
+    });
+
+    await program.parseAsync(["node", "test", "config", "doctor"]);
+
+    expect(statuses()).toMatchObject({ base_url: "fail", reachable: "skip" });
+    expect(process.exitCode).toBe(1);
+  });
+
+  it("reports an unreachable instance with a remediation", async () => {
+    mockGet.mockRejectedValue(
+      Object.assign(new Error("connect ECONNREFUSED"), { isAxiosError: true }),
+    );
+
+    await program.parseAsync(["node", "test", "config", "doctor"]);
+
+    expect(mockRender.mock.calls[0][0].checks).toContainEqual(
+      expect.objectContaining({
+        name: "reachable",
+        status: "fail",
+        message: "Network unreachable: connect ECONNREFUSED",
+      }),
+    );
+    expect(process.exitCode).toBe(1);
+  });
+
+  it("reports an invalid output format instead of failing before the checks run", async () => {
+    mockCreateCommandContext
+      .mockImplementationOnce(() => {
+        throw new CliError('Unsupported output format "yaml".', "INVALID_ARGUMENTS");
+      })
+      .mockReturnValueOnce(context as never);
+
+    await program.parseAsync(["node", "test", "config", "doctor"]);
+
+    expect(mockCreateCommandContext).toHaveBeenLastCalledWith(expect.anything(), {
+      output: "json",
+    });
+    expect(statuses().output).toBe("fail");
+    expect(process.exitCode).toBe(1);
+  });
+
+  it("prints the checklist rows for table output", async () => {
+    context.globalOptions.output = "table";
+
+    await program.parseAsync(["node", "test", "config", "doctor"]);
+
+    expect(mockRender).toHaveBeenCalledWith(
+      expect.arrayContaining([expect.objectContaining({ name: "reachable", status: "pass" })]),
+      { format: "table", query: undefined },
+    );
+  });
+});
@@ -0,0 +1,219 @@
+import { Command } from "commander";
+import { CliError } from "../../utilities/errors/cli-error";
+import { applyGlobalOptions, GlobalOptions } from "../../utilities/shared/global-options";
+import { CommandContext, createCommandContext } from "../../utilities/shared/context";
+import { CliServices } from "../../utilities/shared/services";
+import { ping } from "../ping/ping.command";
+
+export type DoctorCheckStatus = "pass" | "fail" | "skip";
+
+export interface DoctorCheck {
+  name: string;
+  status: DoctorCheckStatus;
+  critical: boolean;
+  message: string;
+  remediation?: string;
+}
+
+export interface DoctorReport {
+  ok: boolean;
+  checks: DoctorCheck[];
+}
+
+export function registerConfigCommand(program: Command): void {
+  const configCmd = program.command("config").description("Inspect the effective CLI configuration");
+
+  const doctorCmd = configCmd
+    .command("doctor")
+    .description("Validate the effective configuration and check the API is reachable");
+  applyGlobalOptions(doctorCmd);
+  doctorCmd.action(async (_options: Record<string, unknown>, command: Command) => {
+    const { context, outputError } = createDoctorContext(command);
+    const { globalOptions, services } = context;
+    const report = await runDoctor(services, globalOptions, outputError);
+
+    // JSON keeps the summary; table/text/csv print the checklist rows.
+    const payload =
+      globalOptions.output === "json" || globalOptions.output === "jsonl" ? report : report.checks;
+    await services.output.render(payload, {
+      format: globalOptions.output,
+      query: globalOptions.query,
+    });
+
+    if (!report.ok) {
+      process.exitCode = 1;
+    }
+  });
+}
+
+// An invalid --output/TWENTY_OUTPUT would stop every command before it runs,
+// so the doctor falls back to JSON and reports the format as a failed check.
+function createDoctorContext(command: Command): {
+  context: CommandContext;
+  outputError?: CliError;
+} {
+  try {
+    return { context: createCommandContext(command) };
+  } catch (error) {
+    if (!(error instanceof CliError) || error.code !== "INVALID_ARGUMENTS") {
+      throw error;
+    }
+    // Anything still invalid with the output forced to JSON is not an output
+    // problem; surface it as the usual argument error.
+    return { context: createCommandContext(command, { output: "json" }), outputError: error };
+  }
+}
+
+export async function runDoctor(
+  services: CliServices,
+  globalOptions: Pick<GlobalOptions, "output" | "workspace">,
+  outputError?: CliError,
+): Promise<DoctorReport> {
+  const checks: DoctorCheck[] = [];
+
+  checks.push(
+    outputError
+      ? {
+          name: "output",
+          status: "fail",
+          critical: true,
+          message: outputError.message,
+          remediation:
+            "Set --output, TWENTY_OUTPUT, or the config output setting to one of json, jsonl, csv, text, table, html.",
+        }
+      : {
+          name: "output",
+          status: "pass",
+          critical: true,
+          message: `Output format is ${globalOptions.output ?? "json"}.`,
+        },
+  );
+
+  // Credentials live in ~/.twenty/config.json (or a token file); there is no
+  // separate keyring, so the store check is that the file parses.
+  try {
+    const file = await services.config.loadConfigFile();
+    checks.push({
+      name: "credential_store",
+      status: "pass",
+      critical: true,
+      message: file
+        ? "Config file is readable."
+        : "No config file; credentials come from the environment.",
+    });
+  } catch (error) {
+    checks.push({
+      name: "credential_store",
+      status: "fail",
+      critical: true,
+      message: (error as Error).message,
+      remediation: "Fix or remove ~/.twenty/config.json, then run `twenty auth login`.",
+    });
+    checks.push(
+      skipped("base_url", "Config file could not be read."),
+      skipped("token", "Config file could not be read."),
+      skipped("reachable", "Config file could not be read."),
+    );
+    return summarize(checks);
+  }
+
+  let apiUrl: string;
+  let apiKey: string;
+  let workspace: string;
+  try {
+    ({ apiUrl, apiKey, workspace } = await services.config.resolveApiConfig({
+      workspace: globalOptions.workspace,
+    }));
+  } catch (error) {
+    // Only an unreadable token file gets here.
+    checks.push(skipped("base_url", "Configuration could not be resolved."), {
+      name: "token",
+      status: "fail",
+      critical: true,
+      message: (error as Error).message,
+      remediation: (error as CliError).suggestion,
+    });
+    checks.push(skipped("reachable", "No usable token."));
+    return summarize(checks);
+  }
+
+  const urlCheck = checkBaseUrl(apiUrl);
+  checks.push(urlCheck);
+  checks.push(
+    apiKey
+      ? {
+          name: "token",
+          status: "pass",
+          critical: true,
+          message: `A token is configured for profile "${workspace}".`,
+        }
+      : {
+          name: "token",
+          status: "fail",
+          critical: true,
+          message: `No token is configured for profile "${workspace}".`,
+          remediation: `Run \`twenty auth login --workspace ${workspace}\` or set TWENTY_TOKEN.`,
+        },
+  );
+
+  if (urlCheck.status !== "pass" || !apiKey) {
+    checks.push(skipped("reachable", "Needs a valid base URL and token."));
+    return summarize(checks);
+  }
+
+  const result = await ping(services, globalOptions.workspace);
+  checks.push(
+    result.ok
+      ? { name: "reachable", status: "pass", critical: true, message: result.message }
+      : {
+          name: "reachable",
+          status: "fail",
+          critical: true,
+          message: result.message,
+          remediation:
+            result.status === "auth_failed"
+              ? "Create a new API key in Twenty settings and run `twenty auth login`."
+              : "Check the base URL, network access, and that the instance is running.",
+        },
+  );
+
+  return summarize(checks);
+}
+
+function checkBaseUrl(apiUrl: string): DoctorCheck {
+  const remediation = "Set TWENTY_BASE_URL or run `twenty auth login --base-url <url>`.";
+  let url: URL;
+  try {
+    url = new URL(apiUrl);
+  } catch {
+    return {
+      name: "base_url",
+      status: "fail",
+      critical: true,
+      message: `Base URL ${JSON.stringify(apiUrl)} is not a valid URL.`,
+      remediation,
+    };
+  }
+  if (url.protocol !== "https:" && url.protocol !== "http:") {
+    return {
+      name: "base_url",
+      status: "fail",
+      critical: true,
+      message: `Base URL ${JSON.stringify(apiUrl)} must use http or https.`,
+      remediation,
+    };
+  }
+
+  return { name: "base_url", status: "pass", critical: true, message: `Base URL is ${apiUrl}.` };
+}
+
+function skipped(name: string, reason: string): DoctorCheck {
+  return { name, status: "skip", critical: true, message: `Skipped: ${reason}` };
+}
+
+function summarize(checks: DoctorCheck[]): DoctorReport {
+  return {
+    ok: checks.every((check) => !check.critical || check.status === "pass"),
+    checks,
+  };
+}
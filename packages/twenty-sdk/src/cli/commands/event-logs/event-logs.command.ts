@@ -3,6 +3,7 @@ import { assertGraphqlSuccess, type GraphQLResponse } from "../../utilities/api/
 import { CliError } from "../../utilities/errors/cli-error";
 import { applyGlobalOptions } from "../../utilities/shared/global-options";
 import { createCommandContext } from "../../utilities/shared/context";
+import { parsePositiveInteger } from "../../utilities/shared/parse";
 
 interface EventLogsOptions {
   table?: string;
@@ -163,13 +164,3 @@ function normalizeTable(rawTable: string | undefined): EventLogTable {
 
   return table;
 }
-
-function parsePositiveInteger(rawValue: string, label: string): number {
-  const parsed = Number.parseInt(rawValue, 10);
-
-  if (!Number.isFinite(parsed) || parsed <= 0) {
-    throw new CliError(`${label} must be a positive integer.`, "INVALID_ARGUMENTS");
-  }
-
-  return parsed;
-}
@@ -7,6 +7,8 @@ import { CliError } from "../../utilities/errors/cli-error";
 import { applyGlobalOptions } from "../../utilities/shared/global-options";
 import { createCommandContext } from "../../utilities/shared/context";
 import { requestPublic } from "../../utilities/shared/request-transport";
+import { printStatus } from "../../utilities/output/services/status-printer";
+import { inferOutputPath, toOutputBuffer } from "../../utilities/file/services/download";
 
 interface FilesOptions {
   outputFile?: string;
@@ -140,33 +142,6 @@ function buildGraphqlUploadForm(
   return form;
 }
 
-function inferOutputPath(reference: string): string {
-  let pathname = reference;
-
-  try {
-    pathname = new URL(reference).pathname;
-  } catch {
-    pathname = reference;
-  }
-
-  const cleanPath = pathname.split("?")[0] ?? pathname;
-  const fileName = path.basename(cleanPath);
-
-  return fileName || "download";
-}
-
-function toOutputBuffer(data: string | ArrayBuffer | Buffer): Buffer {
-  if (Buffer.isBuffer(data)) {
-    return data;
-  }
-
-  if (typeof data === "string") {
-    return Buffer.from(data);
-  }
-
-  return Buffer.from(new Uint8Array(data));
-}
-
 function buildDownloadUrl(pathOrId: string, options: FilesOptions): string {
   if (
     pathOrId.startsWith("http://") ||
@@ -362,7 +337,7 @@ async function runUploadCommand(filePath: string | undefined, command: Command):
 }
 
 async function runDownloadCommand(pathOrId: string | undefined, command: Command): Promise<void> {
-  const { services } = createCommandContext(command);
+  const { globalOptions, services } = createCommandContext(command);
   const options = getFilesOptions(command);
 
   if (!pathOrId) {
@@ -379,15 +354,22 @@ async function runDownloadCommand(pathOrId: string | undefined, command: Command
   });
 
   await fs.writeFile(outputPath, toOutputBuffer(response.data));
-  // eslint-disable-next-line no-console
-  console.log(`Downloaded to ${outputPath}`);
+  printStatus(
+    globalOptions,
+    {
+      action: "downloaded",
+      path: outputPath,
+      message: `Downloaded to ${outputPath}`,
+    },
+    services.writer,
+  );
 }
 
 async function runPublicAssetCommand(
   assetPath: string | undefined,
   command: Command,
 ): Promise<void> {
-  const { services } = createCommandContext(command);
+  const { globalOptions, services } = createCommandContext(command);
   const options = getFilesOptions(command);
 
   if (!assetPath) {
@@ -404,8 +386,15 @@ async function runPublicAssetCommand(
   });
 
   await fs.writeFile(outputPath, toOutputBuffer(response.data));
-  // eslint-disable-next-line no-console
-  console.log(`Downloaded to ${outputPath}`);
+  printStatus(
+    globalOptions,
+    {
+      action: "downloaded",
+      path: outputPath,
+      message: `Downloaded to ${outputPath}`,
+    },
+    services.writer,
+  );
 }
 
 export function registerFilesCommand(program: Command): void {
@@ -0,0 +1,135 @@
+import { afterEach, beforeEach, describe, expect, it, vi } from "vitest";
+import { Command } from "commander";
+import { registerJobsCommand } from "../jobs.command";
+import { ApiService } from "../../../utilities/api/services/api.service";
+import { CliError } from "../../../utilities/errors/cli-error";
+import { mockConstructor } from "../../../test-utils/mock-constructor";
+
+vi.mock("../../../utilities/api/services/api.service");
+vi.mock("../../../utilities/config/services/config.service", () => ({
+  ConfigService: vi.fn(function MockConfigService() {
+    return {
+      getConfig: vi.fn().mockResolvedValue({
+        apiUrl: "https://api.twenty.com",
+        apiKey: "test-token",
+        workspace: "default",
+      }),
+    };
+  }),
+}));
+
+describe("jobs command", () => {
+  let program: Command;
+  let consoleSpy: ReturnType<typeof vi.spyOn>;
+  let mockPost: ReturnType<typeof vi.fn>;
+
+  beforeEach(() => {
+    program = new Command();
+    program.exitOverride();
+    registerJobsCommand(program);
+    consoleSpy = vi.spyOn(console, "log").mockImplementation(() => {});
+    mockPost = vi.fn();
+    vi.mocked(ApiService).mockImplementation(
+      mockConstructor(
+        () =>
+          ({
+            post: mockPost,
+            get: vi.fn(),
+            put: vi.fn(),
+            patch: vi.fn(),
+            delete: vi.fn(),
+            request: vi.fn(),
+          }) as unknown as ApiService,
+      ),
+    );
+  });
+
+  afterEach(() => {
+    consoleSpy.mockRestore();
+    vi.clearAllMocks();
+  });
+
+  it("lists jobs in a queue with a normalized state", async () => {
+    const job = { id: "42", name: "RunWorkflowJob", state: "waiting", attemptsMade: 0 };
+    mockPost.mockResolvedValue({
+      data: {
+        data: { getQueueJobs: { jobs: [job], count: 1, totalCount: 3, hasMore: true } },
+      },
+    });
+
+    await program.parseAsync([
+      "node",
+      "test",
+      "jobs",
+      "list",
+      "--queue",
+      "workflow-queue",
+      "--state",
+      "Waiting",
+      "--limit",
+      "1",
+      "-o",
+      "json",
+      "--full",
+    ]);
+
+    expect(mockPost).toHaveBeenCalledWith("/graphql", {
+      query: expect.stringContaining("getQueueJobs"),
+      variables: { queueName: "workflow-queue", state: "WAITING", limit: 1 },
+    });
+    expect(JSON.parse(consoleSpy.mock.calls[0][0] as string)).toEqual([job]);
+  });
+
+  it("explains when the instance does not expose a jobs API", async () => {
+    mockPost.mockResolvedValue({
+      data: {
+        errors: [{ message: 'Cannot query field "getQueueJobs" on type "Query".' }],
+      },
+    });
+
+    await expect(
+      program.parseAsync(["node", "test", "jobs", "list", "--queue", "workflow-queue"]),
+    ).rejects.toMatchObject({
+      message:
+        "Background jobs are not available on this instance because it does not expose getQueueJobs.",
+    });
+  });
+
+  it("rejects unknown states", async () => {
+    await expect(
+      program.parseAsync(["node", "test", "jobs", "list", "--queue", "q", "--state", "paused"]),
+    ).rejects.toThrow(CliError);
+    expect(mockPost).not.toHaveBeenCalled();
+  });
+
+  it("cancels jobs only with --yes", async () => {
+    const result = { deletedCount: 2, results: [{ jobId: "1", success: true }] };
+    mockPost.mockResolvedValue({ data: { data: { deleteJobs: result } } });
+
+    await expect(
+      program.parseAsync(["node", "test", "jobs", "cancel", "1", "2", "--queue", "q"]),
+    ).rejects.toMatchObject({ message: "Cancel requires --yes." });
+    expect(mockPost).not.toHaveBeenCalled();
+
+    await program.parseAsync([
+      "node",
+      "test",
+      "jobs",
+      "cancel",
+      "1",
+      "2",
+      "--queue",
+      "q",
+      "--yes",
+      "-o",
+      "json",
+      "--full",
+    ]);
+
+    expect(mockPost).toHaveBeenCalledWith("/graphql", {
+      query: expect.stringContaining("deleteJobs"),
+      variables: { queueName: "q", jobIds: ["1", "2"] },
+    });
+    expect(JSON.parse(consoleSpy.mock.calls[0][0] as string)).toEqual(result);
+  });
+});
@@ -0,0 +1,174 @@
+import { Command } from "commander";
+import {
+  assertGraphqlSuccess,
+  hasSchemaErrorSymbol,
+  type GraphQLResponse,
+} from "../../utilities/api/graphql-response";
+import { CliError } from "../../utilities/errors/cli-error";
+import { requireYes } from "../../utilities/shared/confirmation";
+import { applyGlobalOptions } from "../../utilities/shared/global-options";
+import { createCommandContext } from "../../utilities/shared/context";
+import { parseNonNegativeIntegerOption, parsePositiveInteger } from "../../utilities/shared/parse";
+
+interface JobsListOptions {
+  queue: string;
+  state?: string;
+  limit?: string;
+  offset?: string;
+  includePageInfo?: boolean;
+}
+
+interface JobsCancelOptions {
+  queue: string;
+  yes?: boolean;
+}
+
+interface QueueJobsResult {
+  jobs: unknown[];
+  count: number;
+  totalCount: number;
+  hasMore: boolean;
+}
+
+// Twenty exposes its background queues (BullMQ) only through the admin panel
+// API: listing needs an admin token and a server with the admin panel, so a
+// missing field or a permission error is reported as such, not as a crash.
+const endpoint = "/graphql";
+
+const GET_QUEUE_JOBS_QUERY = `query GetQueueJobs($queueName: String!, $state: JobState!, $limit: Int, $offset: Int) {
+  getQueueJobs(queueName: $queueName, state: $state, limit: $limit, offset: $offset) {
+    jobs {
+      id
+      name
+      state
+      timestamp
+      processedOn
+      finishedOn
+      attemptsMade
+      failedReason
+    }
+    count
+    totalCount
+    hasMore
+  }
+}`;
+
+const DELETE_JOBS_MUTATION = `mutation DeleteJobs($queueName: String!, $jobIds: [String!]!) {
+  deleteJobs(queueName: $queueName, jobIds: $jobIds) {
+    deletedCount
+    results {
+      jobId
+      success
+      error
+    }
+  }
+}`;
+
+const JOB_STATES = {
+  active: "ACTIVE",
+  waiting: "WAITING",
+  delayed: "DELAYED",
+  prioritized: "PRIORITIZED",
+  "waiting-children": "WAITING_CHILDREN",
+  failed: "FAILED",
+  completed: "COMPLETED",
+} as const;
+
+type JobState = (typeof JOB_STATES)[keyof typeof JOB_STATES];
+
+export function registerJobsCommand(program: Command): void {
+  const cmd = program.command("jobs").description("Inspect and cancel background jobs");
+  applyGlobalOptions(cmd);
+
+  const listCmd = cmd.command("list").description("List jobs in a background queue");
+  listCmd
+    .requiredOption("--queue <name>", "Queue name, e.g. workflow-queue or webhook-queue")
+    .option("--state <state>", `Job state: ${Object.keys(JOB_STATES).join(", ")}`, "active")
+    .option("--limit <count>", "Number of jobs to fetch", "50")
+    .option("--offset <count>", "Number of jobs to skip")
+    .option("--include-page-info", "Render jobs plus count, totalCount, and hasMore");
+  applyGlobalOptions(listCmd);
+  listCmd.action(async (options: JobsListOptions, command: Command) => {
+    const { globalOptions, services } = createCommandContext(command);
+    const variables = {
+      queueName: options.queue,
+      state: normalizeState(options.state),
+      limit: parsePositiveInteger(options.limit ?? "50", "--limit"),
+      ...(options.offset !== undefined
+        ? { offset: parseNonNegativeIntegerOption("--offset", options.offset) }
+        : {}),
+    };
+    const response = await services.api.post<GraphQLResponse<{ getQueueJobs: QueueJobsResult }>>(
+      endpoint,
+      { query: GET_QUEUE_JOBS_QUERY, variables },
+    );
+    const data = assertJobsSuccess(response.data, "getQueueJobs", "Failed to list jobs.");
+    const result = data.getQueueJobs ?? { jobs: [], count: 0, totalCount: 0, hasMore: false };
+
+    await services.output.render(options.includePageInfo ? result : result.jobs, {
+      format: globalOptions.output,
+      query: globalOptions.query,
+    });
+  });
+
+  // BullMQ removes waiting, delayed, and finished jobs; a job a worker holds
+  // (active) cannot be removed and comes back with success false and a reason.
+  const cancelCmd = cmd
+    .command("cancel")
+    .description("Cancel queued jobs by removing them from their queue")
+    .argument("<ids...>", "Job IDs")
+    .requiredOption("--queue <name>", "Queue the jobs belong to")
+    .option("--yes", "Confirm cancellation");
+  applyGlobalOptions(cancelCmd);
+  cancelCmd.action(async (ids: string[], options: JobsCancelOptions, command: Command) => {
+    requireYes(options, "Cancel");
+    const { globalOptions, services } = createCommandContext(command);
+    const response = await services.api.post<GraphQLResponse<{ deleteJobs: unknown }>>(endpoint, {
+      query: DELETE_JOBS_MUTATION,
+      variables: { queueName: options.queue, jobIds: ids },
+    });
+    const data = assertJobsSuccess(response.data, "deleteJobs", "Failed to cancel jobs.");
+
+    await services.output.render(data.deleteJobs, {
+      format: globalOptions.output,
+      query: globalOptions.query,
+    });
+  });
+}
+
+function assertJobsSuccess<T>(
+  response: GraphQLResponse<T> | undefined,
+  key: string,
+  fallbackMessage: string,
+): T {
+  const payload = response ?? {};
+  if (hasSchemaErrorSymbol(payload, [`Cannot query field "${key}"`])) {
+    throw new CliError(
+      `Background jobs are not available on this instance because it does not expose ${key}.`,
+      "API_ERROR",
+    );
+  }
+  if (hasSchemaErrorSymbol(payload, ["Forbidden", "permission", "not allowed"])) {
+    throw new CliError(
+      "Background jobs are only visible to server admins.",
+      "API_ERROR",
+      "Use a token for a user with admin panel access.",
+    );
+  }
+
+  return assertGraphqlSuccess(payload, fallbackMessage);
+}
+
+function normalizeState(rawState: string | undefined): JobState {
+  const normalized = (rawState ?? "active").toLowerCase();
+  const state = JOB_STATES[normalized as keyof typeof JOB_STATES];
+
+  if (!state) {
+    throw new CliError(
+      `Unsupported --state "${rawState}". Expected one of: ${Object.keys(JOB_STATES).join(", ")}.`,
+      "INVALID_ARGUMENTS",
+    );
+  }
+
+  return state;
+}
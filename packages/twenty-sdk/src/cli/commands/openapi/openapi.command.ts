@@ -4,6 +4,7 @@ import { CliError } from "../../utilities/errors/cli-error";
 import { applyGlobalOptions, resolveGlobalOptions } from "../../utilities/shared/global-options";
 import { createServices } from "../../utilities/shared/services";
 import { requestPrivate } from "../../utilities/shared/request-transport";
+import { log } from "../../utilities/shared/logger";
 
 interface OpenApiOptions {
   outputFile?: string;
@@ -32,8 +33,9 @@ export function registerOpenApiCommand(program: Command): void {
 
     if (rawOptions.outputFile) {
       await fs.writeFile(rawOptions.outputFile, JSON.stringify(response.data, null, 2));
-      // eslint-disable-next-line no-console
-      console.error(`Wrote OpenAPI schema to ${rawOptions.outputFile}`);
+      log("info", `Wrote OpenAPI schema to ${rawOptions.outputFile}`, {
+        path: rawOptions.outputFile,
+      });
       return;
     }
 
@@ -0,0 +1,376 @@
+import { afterEach, beforeEach, describe, expect, it, vi } from "vitest";
+import { Command } from "commander";
+import { registerOpportunitiesCommand } from "../opportunities.command";
+
+const mockCreateCommandContext = vi.hoisted(() => vi.fn());
+
+vi.mock("../../../utilities/shared/context", async () => {
+  const actual = await vi.importActual<typeof import("../../../utilities/shared/context")>(
+    "../../../utilities/shared/context",
+  );
+
+  return {
+    ...actual,
+    createCommandContext: mockCreateCommandContext,
+  };
+});
+
+const STAGE_OPTIONS = [
+  { value: "NEW", label: "New" },
+  { value: "PROPOSAL", label: "Proposal" },
+  { value: "CUSTOMER", label: "Customer" },
+  { value: "LOST", label: "Lost" },
+];
+
+const OPPORTUNITIES = [
+  { id: "opp-1", name: "Acme", amount: { amountMicros: 1250000000, currencyCode: "USD" } },
+  { id: "opp-2", name: "Tokyo", amount: { amountMicros: "5000000000", currencyCode: "JPY" } },
+  { id: "opp-3", name: "Draft", amount: { amountMicros: null, currencyCode: "EUR" } },
+];
+
+const CSV_ROWS = [
+  {
+    name: "Acme renewal",
+    amount: "1,250.50",
+    currency: "usd",
+    stage: "PROPOSAL",
+    closeDate: "2026-03-31",
+    companyId: "company-1",
+  },
+  { name: "Pilot", amount: "", currency: "", stage: "NEW", closeDate: "", companyId: "" },
+];
+
+describe("opportunities command", () => {
+  let program: Command;
+  let mockGetObject: ReturnType<typeof vi.fn>;
+  let mockUpdate: ReturnType<typeof vi.fn>;
+  let mockGet: ReturnType<typeof vi.fn>;
+  let mockRender: ReturnType<typeof vi.fn>;
+  let mockList: ReturnType<typeof vi.fn>;
+  let mockListAll: ReturnType<typeof vi.fn>;
+  let mockExport: ReturnType<typeof vi.fn>;
+  let mockImport: ReturnType<typeof vi.fn>;
+  let mockBatchCreate: ReturnType<typeof vi.fn>;
+
+  beforeEach(() => {
+    program = new Command();
+    program.exitOverride();
+    registerOpportunitiesCommand(program);
+    mockGetObject = vi.fn().mockResolvedValue({
+      id: "object-1",
+      nameSingular: "opportunity",
+      fields: [
+        { id: "field-1", name: "stage", options: STAGE_OPTIONS },
+        { id: "field-2", name: "closeDate" },
+        { id: "field-3", name: "probability" },
+      ],
+    });
+    mockUpdate = vi.fn().mockResolvedValue({ id: "opp-1", stage: "CUSTOMER" });
+    mockGet = vi.fn().mockResolvedValue({ id: "opp-1", stage: "PROPOSAL", name: "Acme" });
+    mockRender = vi.fn();
+    mockList = vi.fn().mockResolvedValue({ data: OPPORTUNITIES });
+    mockListAll = vi.fn().mockResolvedValue({ data: OPPORTUNITIES });
+    mockExport = vi.fn();
+    mockImport = vi.fn().mockResolvedValue(CSV_ROWS);
+    mockBatchCreate = vi.fn().mockResolvedValue([{ id: "opp-1" }, { id: "opp-2" }]);
+    mockCreateCommandContext.mockReset();
+    mockCreateCommandContext.mockReturnValue({
+      globalOptions: { output: "json", query: undefined },
+      services: {
+        metadata: { getObject: mockGetObject },
+        records: {
+          update: mockUpdate,
+          get: mockGet,
+          list: mockList,
+          listAll: mockListAll,
+          batchCreate: mockBatchCreate,
+        },
+        output: { render: mockRender },
+        exporter: { export: mockExport },
+        importer: { import: mockImport },
+      },
+    } as never);
+  });
+
+  it("closes as won using the inferred stage and full probability", async () => {
+    await program.parseAsync([
+      "node",
+      "test",
+      "opportunities",
+      "close",
+      "opp-1",
+      "--won",
+      "--close-date",
+      "2026-03-01",
+    ]);
+
+    expect(mockGetObject).toHaveBeenCalledWith("opportunity");
+    expect(mockUpdate).toHaveBeenCalledWith("opportunities", "opp-1", {
+      stage: "CUSTOMER",
+      closeDate: "2026-03-01",
+      probability: 100,
+    });
+    expect(mockRender).toHaveBeenCalledWith(
+      { id: "opp-1", stage: "CUSTOMER" },
+      { format: "json", query: undefined },
+    );
+  });
+
+  it("defaults the close date to today and omits probability when the field is absent", async () => {
+    mockGetObject.mockResolvedValue({
+      id: "object-1",
+      fields: [{ id: "field-1", name: "stage", options: STAGE_OPTIONS }],
+    });
+
+    await program.parseAsync(["node", "test", "opportunities", "close", "opp-1", "--lost"]);
+
+    expect(mockUpdate).toHaveBeenCalledWith("opportunities", "opp-1", {
+      stage: "LOST",
+      closeDate: new Date().toISOString().slice(0, 10),
+    });
+  });
+
+  it("rejects a --stage value that is not a workspace stage option", async () => {
+    await expect(
+      program.parseAsync([
+        "node",
+        "test",
+        "opportunities",
+        "close",
+        "opp-1",
+        "--won",
+        "--stage",
+        "WON",
+      ]),
+    ).rejects.toMatchObject({
+      message: 'Unknown opportunity stage "WON".',
+      code: "INVALID_ARGUMENTS",
+      suggestion: "Valid stages: NEW, PROPOSAL, CUSTOMER, LOST.",
+    });
+    expect(mockUpdate).not.toHaveBeenCalled();
+  });
+
+  it("requires exactly one of --won or --lost", async () => {
+    await expect(
+      program.parseAsync(["node", "test", "opportunities", "close", "opp-1"]),
+    ).rejects.toMatchObject({ message: "Missing close outcome.", code: "INVALID_ARGUMENTS" });
+    await expect(
+      program.parseAsync(["node", "test", "opportunities", "close", "opp-1", "--won", "--lost"]),
+    ).rejects.toMatchObject({ message: "Use only one of --won or --lost." });
+  });
+
+  it("updates with --show-diff, fetching the opportunity first", async () => {
+    await program.parseAsync(
+      [
+        "opportunities",
+        "update",
+        "opp-1",
+        "--set",
+        "stage=CUSTOMER",
+        "--set",
+        "name=Acme",
+        "--show-diff",
+      ],
+      { from: "user" },
+    );
+
+    expect(mockGet).toHaveBeenCalledWith("opportunities", "opp-1");
+    expect(mockUpdate).toHaveBeenCalledWith("opportunities", "opp-1", {
+      stage: "CUSTOMER",
+      name: "Acme",
+    });
+    expect(mockRender).toHaveBeenCalledWith(
+      { id: "opp-1", changes: [{ field: "stage", from: "PROPOSAL", to: "CUSTOMER" }] },
+      expect.any(Object),
+    );
+  });
+
+  describe("export", () => {
+    it("paginates every page with --all and writes JSON with raw currency values", async () => {
+      await program.parseAsync([
+        "node",
+        "test",
+        "opportunities",
+        "export",
+        "--all",
+        "--output-file",
+        "opportunities.json",
+      ]);
+
+      expect(mockListAll).toHaveBeenCalledWith(
+        "opportunities",
+        expect.objectContaining({ limit: 200 }),
+      );
+      expect(mockList).not.toHaveBeenCalled();
+      expect(mockExport).toHaveBeenCalledWith(OPPORTUNITIES, {
+        format: "json",
+        output: "opportunities.json",
+      });
+    });
+
+    it("formats currency columns in CSV using each currency's minor units", async () => {
+      await program.parseAsync([
+        "node",
+        "test",
+        "opportunities",
+        "export",
+        "--format",
+        "csv",
+        "--limit",
+        "50",
+      ]);
+
+      expect(mockList).toHaveBeenCalledWith(
+        "opportunities",
+        expect.objectContaining({ limit: 50 }),
+      );
+      expect(mockExport).toHaveBeenCalledWith(
+        [
+          { id: "opp-1", name: "Acme", amount: "1250.00 USD" },
+          { id: "opp-2", name: "Tokyo", amount: "5000 JPY" },
+          { id: "opp-3", name: "Draft", amount: "" },
+        ],
+        { format: "csv", output: undefined },
+      );
+    });
+
+    it("renders CSV currency as minor units or raw micros with --currency-format", async () => {
+      await program.parseAsync([
+        "node",
+        "test",
+        "opportunities",
+        "export",
+        "--format",
+        "csv",
+        "--currency-format",
+        "minor",
+      ]);
+      await program.parseAsync([
+        "node",
+        "test",
+        "opportunities",
+        "export",
+        "--format",
+        "csv",
+        "--currency-format",
+        "micros",
+      ]);
+
+      expect(mockExport.mock.calls[0][0].map((record: any) => record.amount)).toEqual([
+        "125000 USD",
+        "5000 JPY",
+        "",
+      ]);
+      expect(mockExport.mock.calls[1][0].map((record: any) => record.amount)).toEqual([
+        "1250000000 USD",
+        "5000000000 JPY",
+        "",
+      ]);
+    });
+
+    it("rejects an unknown --currency-format", async () => {
+      await expect(
+        program.parseAsync([
+          "node",
+          "test",
+          "opportunities",
+          "export",
+          "--currency-format",
+          "cents",
+        ]),
+      ).rejects.toMatchObject({
+        message:
+          'Invalid --currency-format value "cents"; expected one of major, minor, micros.',
+        code: "INVALID_ARGUMENTS",
+      });
+      expect(mockExport).not.toHaveBeenCalled();
+    });
+  });
+
+  describe("import and batch-create", () => {
+    let consoleSpy: ReturnType<typeof vi.spyOn>;
+
+    beforeEach(() => {
+      consoleSpy = vi.spyOn(console, "log").mockImplementation(() => {});
+    });
+
+    afterEach(() => {
+      consoleSpy.mockRestore();
+    });
+
+    it("imports CSV rows with major-unit amounts converted to micros", async () => {
+      await program.parseAsync(["node", "test", "opportunities", "import", "deals.csv"]);
+
+      expect(mockImport).toHaveBeenCalledWith("deals.csv", { dryRun: undefined });
+      expect(mockBatchCreate).toHaveBeenCalledWith("opportunities", [
+        {
+          name: "Acme renewal",
+          amount: { amountMicros: 1250500000, currencyCode: "USD" },
+          stage: "PROPOSAL",
+          closeDate: "2026-03-31",
+          companyId: "company-1",
+        },
+        { name: "Pilot", stage: "NEW" },
+      ]);
+      expect(consoleSpy).toHaveBeenCalledWith("Import complete: 2 imported.");
+    });
+
+    it("validates amounts during --dry-run without creating records", async () => {
+      mockImport.mockResolvedValue([{ name: "Bad", amount: "lots" }]);
+
+      await expect(
+        program.parseAsync(["node", "test", "opportunities", "import", "deals.csv", "--dry-run"]),
+      ).rejects.toMatchObject({
+        message: 'Record 1 has an invalid amount "lots"; expected a number in major units.',
+        code: "INVALID_ARGUMENTS",
+      });
+      expect(mockBatchCreate).not.toHaveBeenCalled();
+    });
+
+    it("reports partial failures with --continue-on-error", async () => {
+      mockImport.mockResolvedValue([{ name: "A" }, { name: "B" }]);
+      mockBatchCreate.mockRejectedValueOnce(new Error("boom")).mockResolvedValueOnce([]);
+
+      await program.parseAsync([
+        "node",
+        "test",
+        "opportunities",
+        "import",
+        "deals.json",
+        "--batch-size",
+        "1",
+        "--continue-on-error",
+      ]);
+
+      expect(mockBatchCreate).toHaveBeenCalledTimes(2);
+      expect(consoleSpy).toHaveBeenCalledWith("Import complete: 1 imported, 1 failed.");
+    });
+
+    it("batch-creates from --data, keeping composite amounts as given", async () => {
+      await program.parseAsync([
+        "node",
+        "test",
+        "opportunities",
+        "batch-create",
+        "--data",
+        JSON.stringify([
+          { name: "Deal", amount: 99, currency: "EUR" },
+          { name: "Raw", amount: { amountMicros: 5000000, currencyCode: "GBP" } },
+        ]),
+      ]);
+
+      expect(mockBatchCreate).toHaveBeenCalledWith(
+        "opportunities",
+        [
+          { name: "Deal", amount: { amountMicros: 99000000, currencyCode: "EUR" } },
+          { name: "Raw", amount: { amountMicros: 5000000, currencyCode: "GBP" } },
+        ],
+        { idempotencyKey: undefined },
+      );
+      expect(mockRender).toHaveBeenCalledWith(
+        [{ id: "opp-1" }, { id: "opp-2" }],
+        { format: "json", query: undefined },
+      );
+    });
+  });
+});
@@ -0,0 +1,256 @@
+import { Command } from "commander";
+import { CliError } from "../../utilities/errors/cli-error";
+import { applyGlobalOptions } from "../../utilities/shared/global-options";
+import { createCommandContext } from "../../utilities/shared/context";
+import type { FieldMetadata } from "../../utilities/metadata/services/metadata.service";
+import {
+  CURRENCY_FORMATS,
+  CurrencyFormat,
+  formatCurrencyFields,
+} from "../../utilities/output/services/currency";
+import { runBatchCreateOperation } from "../api/operations/batch-create.operation";
+import { runExportOperation } from "../api/operations/export.operation";
+import { runImportOperation } from "../api/operations/import.operation";
+import { runOpenOperation } from "../api/operations/open.operation";
+import { runUpdateOperation } from "../api/operations/update.operation";
+import type { ApiCommandOptions } from "../api/operations/types";
+import { toOpportunityInput } from "./opportunity-input";
+
+interface CloseOptions {
+  won?: boolean;
+  lost?: boolean;
+  stage?: string;
+  closeDate?: string;
+}
+
+interface ExportOptions extends ApiCommandOptions {
+  currencyFormat?: string;
+}
+
+type CloseOutcome = "won" | "lost";
+
+// Stage values tried in order when --stage is not given. Twenty's default
+// pipeline ends in CUSTOMER and has no lost stage, so lost needs a custom value.
+const CLOSED_STAGE_CANDIDATES: Record<CloseOutcome, string[]> = {
+  won: ["WON", "CLOSED_WON", "CUSTOMER"],
+  lost: ["LOST", "CLOSED_LOST"],
+};
+
+export function registerOpportunitiesCommand(program: Command): void {
+  const cmd = program.command("opportunities").description("Opportunity shortcuts");
+  applyGlobalOptions(cmd);
+
+  const closeCmd = cmd
+    .command("close")
+    .description("Mark an opportunity as won or lost")
+    .argument("<id>", "Opportunity ID")
+    .option("--won", "Close as won (probability 100)")
+    .option("--lost", "Close as lost (probability 0)")
+    .option("--stage <value>", "Closed stage value to use instead of the inferred one")
+    .option("--close-date <date>", "Close date (default: today)");
+  applyGlobalOptions(closeCmd);
+  closeCmd.action(async (id: string, options: CloseOptions, command: Command) => {
+    const outcome = resolveOutcome(options);
+    const { globalOptions, services } = createCommandContext(command);
+
+    const opportunity = await services.metadata.getObject("opportunity");
+    const fields = opportunity.fields ?? [];
+    const stageField = findField(fields, "stage");
+    if (!stageField) {
+      throw new CliError(
+        "Opportunity object has no stage field.",
+        "INVALID_ARGUMENTS",
+        'Use "twenty api update opportunities <id>" to set closing fields directly.',
+      );
+    }
+
+    const payload: Record<string, unknown> = {
+      stage: resolveClosedStage(stageField, outcome, options.stage),
+      closeDate: options.closeDate ?? new Date().toISOString().slice(0, 10),
+    };
+    if (findField(fields, "probability")) {
+      payload.probability = outcome === "won" ? 100 : 0;
+    }
+
+    const response = await services.records.update("opportunities", id, payload);
+    await services.output.render(response, {
+      format: globalOptions.output,
+      query: globalOptions.query,
+    });
+  });
+
+  // Runs the same export path as "api export" so pagination and file output
+  // stay identical; only CSV currency columns are rendered differently.
+  const exportCmd = cmd
+    .command("export")
+    .description("Export opportunities as JSON or CSV")
+    .option("--all", "Fetch every page using cursor pagination")
+    .option("--limit <n>", "Records per page (default 200)")
+    .option("--page-size <n>", "Records per request while paginating (max 200)")
+    .option("--cursor <cursor>", "Start from a pagination cursor")
+    .option("--filter <expression>", "Filter expression")
+    .option("--sort <field>", "Sort field")
+    .option("--order <direction>", "Sort order (asc or desc)")
+    .option("--sort-local <field[:desc]>", "Sort fetched records client-side, e.g. city:desc")
+    .option("--include <relations>", "Include related records")
+    .option("--fields <fields>", "Comma-separated top-level fields to return")
+    .option("--format <format>", "Export format (json, csv, or parquet)")
+    .option("--output-file <path>", "Output file path")
+    .option("--split-size <records>", "Rotate output files every N records")
+    .option("--split-bytes <size>", "Rotate output files before they exceed a size, e.g. 50MB")
+    .option("--array", "Write JSON as one buffered array (default)")
+    .option("--stream", "Write JSON as NDJSON, one record per line")
+    .option("--manifest <path>", "Write a JSON provenance manifest to this path after exporting")
+    .option("--checkpoint <path>", "Save the export cursor to this file after each page")
+    .option("--resume <path>", "Continue an export from a saved checkpoint, appending to its file")
+    .option("--currency-format <format>", "CSV currency values: major, minor, or micros", "major");
+  applyGlobalOptions(exportCmd);
+  exportCmd.action(async (options: ExportOptions, command: Command) => {
+    const currencyFormat = resolveCurrencyFormat(options.currencyFormat);
+    const { globalOptions, services } = createCommandContext(command);
+    await runExportOperation(
+      { object: "opportunities", options, services, globalOptions },
+      { csvRecord: (record) => formatCurrencyFields(record, currencyFormat) },
+    );
+  });
+
+  // Import and batch-create reuse the api operations; rows are mapped so an
+  // "amount" in major units plus a "currency" column become amountMicros.
+  const importCmd = cmd
+    .command("import")
+    .description("Import opportunities from a CSV or JSON file")
+    .argument("<files...>", "CSV or JSON files, or a file-name pattern such as 'exports/*.csv'")
+    .option("--dry-run", "Preview without importing")
+    .option("--batch-size <n>", "Records per batch (max 60)")
+    .option("--continue-on-error", "Keep importing after a failed batch")
+    .option("--fail-fast", "Stop at the first failed file instead of importing the rest")
+    .option("--template-file <path>", "Render each row through a JSON body template")
+    .option("--continue-from <index>", "Start at this 1-based record index");
+  applyGlobalOptions(importCmd);
+  importCmd.action(async (files: string[], options: ApiCommandOptions, command: Command) => {
+    const { globalOptions, services } = createCommandContext(command);
+    await runImportOperation(
+      { object: "opportunities", arg: files[0], args: files, options, services, globalOptions },
+      { record: toOpportunityInput },
+    );
+  });
+
+  const batchCreateCmd = cmd
+    .command("batch-create")
+    .description("Create several opportunities from a JSON array or CSV file")
+    .option("-d, --data <json>", "JSON array payload")
+    .option("-f, --file <path>", "JSON/CSV file payload (use - for stdin)")
+    .option("--idempotency-key <key>", "Idempotency-Key header for the batch request");
+  applyGlobalOptions(batchCreateCmd);
+  batchCreateCmd.action(async (options: ApiCommandOptions, command: Command) => {
+    const { globalOptions, services } = createCommandContext(command);
+    await runBatchCreateOperation(
+      { object: "opportunities", options, services, globalOptions },
+      { record: toOpportunityInput },
+    );
+  });
+
+  // Same path as "api update opportunities"; --show-diff fetches the record
+  // first and prints old and new values for the changed fields.
+  const updateCmd = cmd
+    .command("update")
+    .description("Update an opportunity")
+    .argument("[id]", "Opportunity ID")
+    .option("--id-file <path>", "Apply the same changes to each ID in a file, one per line")
+    .option("-d, --data <json>", "JSON payload")
+    .option("-f, --file <path>", "JSON file payload (use - for stdin)")
+    .option("--set <key=value>", "Set a field value", collect)
+    .option("--clear <field>", "Set a field to null", collect)
+    .option("--show-diff", "Print the changed fields as old and new values");
+  applyGlobalOptions(updateCmd);
+  updateCmd.action(async (id: string | undefined, options: ApiCommandOptions, command: Command) => {
+    const { globalOptions, services } = createCommandContext(command);
+    const context = { object: "opportunities", arg: id, options, services, globalOptions };
+    await runUpdateOperation(context);
+  });
+
+  const openCmd = cmd
+    .command("open")
+    .description("Open an opportunity in the web app")
+    .argument("<id>", "Opportunity ID")
+    .option("--print-url", "Print the record URL instead of opening a browser");
+  applyGlobalOptions(openCmd);
+  openCmd.action(async (id: string, options: ApiCommandOptions, command: Command) => {
+    const { globalOptions, services } = createCommandContext(command);
+    await runOpenOperation({ object: "opportunities", arg: id, options, services, globalOptions });
+  });
+}
+
+function resolveOutcome(options: CloseOptions): CloseOutcome {
+  if (options.won && options.lost) {
+    throw new CliError("Use only one of --won or --lost.", "INVALID_ARGUMENTS");
+  }
+  if (options.won) return "won";
+  if (options.lost) return "lost";
+
+  throw new CliError("Missing close outcome.", "INVALID_ARGUMENTS", "Pass --won or --lost.");
+}
+
+function resolveCurrencyFormat(value: string | undefined): CurrencyFormat {
+  const format = (value ?? "major").toLowerCase() as CurrencyFormat;
+  if (!CURRENCY_FORMATS.includes(format)) {
+    throw new CliError(
+      `Invalid --currency-format value ${JSON.stringify(value)}; expected one of ${CURRENCY_FORMATS.join(", ")}.`,
+      "INVALID_ARGUMENTS",
+    );
+  }
+
+  return format;
+}
+
+function findField(fields: FieldMetadata[], name: string): FieldMetadata | undefined {
+  return fields.find((field) => field.name === name);
+}
+
+function resolveClosedStage(
+  stageField: FieldMetadata,
+  outcome: CloseOutcome,
+  explicitStage?: string,
+): string {
+  const values = extractOptionValues(stageField);
+
+  if (explicitStage) {
+    if (!values.includes(explicitStage)) {
+      throw new CliError(
+        `Unknown opportunity stage ${JSON.stringify(explicitStage)}.`,
+        "INVALID_ARGUMENTS",
+        `Valid stages: ${values.join(", ")}.`,
+      );
+    }
+    return explicitStage;
+  }
+
+  const match = CLOSED_STAGE_CANDIDATES[outcome].find((candidate) => values.includes(candidate));
+  if (!match) {
+    throw new CliError(
+      `No ${outcome} stage found on opportunity stage field.`,
+      "INVALID_ARGUMENTS",
+      `Pass --stage with one of: ${values.join(", ")}.`,
+    );
+  }
+
+  return match;
+}
+
+function extractOptionValues(field: FieldMetadata): string[] {
+  if (!Array.isArray(field.options)) {
+    return [];
+  }
+
+  return field.options
+    .map((option: unknown) =>
+      typeof option === "object" && option !== null
+        ? (option as { value?: unknown }).value
+        : option,
+    )
+    .filter((value: unknown): value is string => typeof value === "string");
+}
+
+function collect(value: string, previous: string[] = []): string[] {
+  return [...previous, value];
+}
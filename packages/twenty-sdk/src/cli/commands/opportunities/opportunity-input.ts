@@ -0,0 +1,51 @@
+import { CliError } from "../../utilities/errors/cli-error";
+
+const MICROS_PER_UNIT = 1_000_000;
+
+// Maps an import row onto Twenty's opportunity shape. CSV rows carry the
+// amount in major units with an optional "currency" column; both fold into the
+// {amountMicros, currencyCode} composite. Empty cells are dropped so optional
+// fields like closeDate are omitted instead of sent as "".
+export function toOpportunityInput(
+  row: Record<string, unknown>,
+  index: number,
+): Record<string, unknown> {
+  const { amount, currency, ...rest } = row;
+  const input: Record<string, unknown> = {};
+  for (const [key, value] of Object.entries(rest)) {
+    if (value !== "" && value !== undefined) {
+      input[key] = value;
+    }
+  }
+
+  const currencyCode = typeof currency === "string" ? currency.trim().toUpperCase() : undefined;
+  if (isComposite(amount)) {
+    input.amount = amount;
+  } else if (amount !== undefined && amount !== null && String(amount).trim() !== "") {
+    input.amount = {
+      amountMicros: toAmountMicros(amount, index),
+      ...(currencyCode ? { currencyCode } : {}),
+    };
+  } else if (currencyCode) {
+    input.amount = { amountMicros: null, currencyCode };
+  }
+
+  return input;
+}
+
+function toAmountMicros(amount: unknown, index: number): number {
+  // Accept "1,250.50" as exported by spreadsheets.
+  const major = Number(String(amount).replace(/,/g, "").trim());
+  if (!Number.isFinite(major)) {
+    throw new CliError(
+      `Record ${index + 1} has an invalid amount ${JSON.stringify(amount)}; expected a number in major units.`,
+      "INVALID_ARGUMENTS",
+    );
+  }
+
+  return Math.round(major * MICROS_PER_UNIT);
+}
+
+function isComposite(value: unknown): value is Record<string, unknown> {
+  return typeof value === "object" && value !== null && !Array.isArray(value);
+}
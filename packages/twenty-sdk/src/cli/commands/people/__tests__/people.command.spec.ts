@@ -0,0 +1,664 @@
+import { beforeEach, describe, expect, it, vi } from "vitest";
+import { Command } from "commander";
+import { registerPeopleCommand } from "../people.command";
+import { CliError } from "../../../utilities/errors/cli-error";
+import { stdoutWriter } from "../../../utilities/output/services/output-writer";
+
+const mockCreateCommandContext = vi.hoisted(() => vi.fn());
+
+vi.mock("../../../utilities/shared/context", async () => {
+  const actual = await vi.importActual<typeof import("../../../utilities/shared/context")>(
+    "../../../utilities/shared/context",
+  );
+
+  return {
+    ...actual,
+    createCommandContext: mockCreateCommandContext,
+  };
+});
+
+describe("people command", () => {
+  let program: Command;
+  let mockGet: ReturnType<typeof vi.fn>;
+  let mockFindUniqueBy: ReturnType<typeof vi.fn>;
+  let mockEnsure: ReturnType<typeof vi.fn>;
+  let mockList: ReturnType<typeof vi.fn>;
+  let mockGroupBy: ReturnType<typeof vi.fn>;
+  let mockUpdate: ReturnType<typeof vi.fn>;
+  let mockImport: ReturnType<typeof vi.fn>;
+  let mockBatchCreate: ReturnType<typeof vi.fn>;
+  let mockRender: ReturnType<typeof vi.fn>;
+
+  beforeEach(() => {
+    program = new Command();
+    program.exitOverride();
+    registerPeopleCommand(program);
+    mockGet = vi.fn().mockResolvedValue({ id: "person-1" });
+    mockFindUniqueBy = vi.fn().mockResolvedValue({ id: "person-2" });
+    mockEnsure = vi.fn().mockResolvedValue({ record: { id: "person-3" }, created: true });
+    mockList = vi.fn().mockResolvedValue({ data: [], totalCount: 0 });
+    mockGroupBy = vi.fn().mockResolvedValue([]);
+    mockUpdate = vi.fn().mockResolvedValue({ id: "person-1" });
+    mockImport = vi.fn().mockResolvedValue([{ jobTitle: "CEO" }]);
+    mockBatchCreate = vi.fn().mockResolvedValue([{ id: "person-4" }]);
+    mockRender = vi.fn();
+    mockCreateCommandContext.mockReset();
+    mockCreateCommandContext.mockReturnValue({
+      globalOptions: { output: "json", query: undefined },
+      services: {
+        records: {
+          get: mockGet,
+          findUniqueBy: mockFindUniqueBy,
+          ensure: mockEnsure,
+          update: mockUpdate,
+          list: mockList,
+          groupBy: mockGroupBy,
+          batchCreate: mockBatchCreate,
+        },
+        importer: { import: mockImport },
+        output: { render: mockRender },
+        writer: stdoutWriter,
+      },
+    } as never);
+  });
+
+  it("gets a person by ID", async () => {
+    await program.parseAsync(["node", "test", "people", "get", "person-1"]);
+
+    expect(mockGet).toHaveBeenCalledWith("people", "person-1", { include: undefined });
+    expect(mockRender).toHaveBeenCalledWith(
+      { id: "person-1" },
+      { format: "json", query: undefined },
+    );
+  });
+
+  it("looks up a person by primary email", async () => {
+    await program.parseAsync([
+      "node",
+      "test",
+      "people",
+      "get",
+      "--email",
+      "john@example.com",
+      "--include",
+      "company",
+    ]);
+
+    expect(mockFindUniqueBy).toHaveBeenCalledWith(
+      "people",
+      "emails.primaryEmail",
+      "john@example.com",
+      { include: "company" },
+    );
+    expect(mockGet).not.toHaveBeenCalled();
+    expect(mockRender).toHaveBeenCalledWith(
+      { id: "person-2" },
+      { format: "json", query: undefined },
+    );
+  });
+
+  it("looks up a person by an arbitrary field", async () => {
+    await program.parseAsync(["node", "test", "people", "get", "--by", "jobTitle=Head of Ops"]);
+
+    expect(mockFindUniqueBy).toHaveBeenCalledWith("people", "jobTitle", "Head of Ops", {
+      include: undefined,
+    });
+  });
+
+  it("rejects a --by value without a field name", async () => {
+    await expect(
+      program.parseAsync(["node", "test", "people", "get", "--by", "CEO"]),
+    ).rejects.toMatchObject({ message: 'Invalid --by value "CEO".', code: "INVALID_ARGUMENTS" });
+  });
+
+  it("requires exactly one selector", async () => {
+    await expect(program.parseAsync(["node", "test", "people", "get"])).rejects.toMatchObject({
+      message: "Missing person selector.",
+      code: "INVALID_ARGUMENTS",
+    });
+    await expect(
+      program.parseAsync(["node", "test", "people", "get", "person-1", "--email", "a@b.co"]),
+    ).rejects.toMatchObject({ message: "Use only one of <id>, --email, or --by." });
+  });
+
+  it("ensures a person by email and reports whether it was created", async () => {
+    await program.parseAsync([
+      "node",
+      "test",
+      "people",
+      "ensure",
+      "--email",
+      "john@example.com",
+      "--data",
+      '{"name":{"firstName":"John"}}',
+    ]);
+
+    expect(mockEnsure).toHaveBeenCalledWith("people", "emails.primaryEmail", "john@example.com", {
+      name: { firstName: "John" },
+      emails: { primaryEmail: "john@example.com" },
+    });
+    expect(mockRender).toHaveBeenCalledWith(
+      { created: true, record: { id: "person-3" } },
+      { format: "json", query: undefined },
+    );
+  });
+
+  it("ensures a person by field without a payload and keeps the value a string", async () => {
+    await program.parseAsync([
+      "node",
+      "test",
+      "people",
+      "ensure",
+      "--by",
+      "phones.primaryPhoneNumber=555",
+    ]);
+
+    expect(mockEnsure).toHaveBeenCalledWith("people", "phones.primaryPhoneNumber", "555", {
+      phones: { primaryPhoneNumber: "555" },
+    });
+  });
+
+  it("requires --email or --by for ensure", async () => {
+    await expect(
+      program.parseAsync(["node", "test", "people", "ensure", "--data", "{}"]),
+    ).rejects.toMatchObject({ message: "Missing person selector.", code: "INVALID_ARGUMENTS" });
+    expect(mockEnsure).not.toHaveBeenCalled();
+  });
+
+  it("filters the list by creator and last editor", async () => {
+    const creator = "1B4E28BA-2FA1-11D2-883F-0016D3CCA427";
+    const editor = "6f9619ff-8b86-d011-b42d-00c04fc964ff";
+    await program.parseAsync([
+      "node",
+      "test",
+      "people",
+      "list",
+      "--filter",
+      'city[eq]:"Paris"',
+      "--created-by",
+      creator,
+      "--updated-by",
+      editor,
+    ]);
+
+    expect(mockList).toHaveBeenCalledWith(
+      "people",
+      expect.objectContaining({
+        filter:
+          'and(city[eq]:"Paris",' +
+          `createdBy.workspaceMemberId[eq]:"${creator.toLowerCase()}",` +
+          `updatedBy.workspaceMemberId[eq]:"${editor}")`,
+      }),
+    );
+  });
+
+  it("rejects a --created-by value that is not a member ID", async () => {
+    await expect(
+      program.parseAsync(["node", "test", "people", "list", "--created-by", "alice"]),
+    ).rejects.toMatchObject({
+      message: 'Invalid --created-by value "alice"; expected a workspace member ID.',
+      code: "INVALID_ARGUMENTS",
+    });
+    expect(mockList).not.toHaveBeenCalled();
+  });
+
+  it("imports people through the shared api import path", async () => {
+    const consoleSpy = vi.spyOn(console, "log").mockImplementation(() => {});
+
+    try {
+      await program.parseAsync(["node", "test", "people", "import", "people.csv"]);
+
+      expect(mockImport).toHaveBeenCalledWith("people.csv", { dryRun: undefined });
+      expect(mockBatchCreate).toHaveBeenCalledWith("people", [{ jobTitle: "CEO" }]);
+      expect(consoleSpy).toHaveBeenCalledWith("Import complete: 1 imported.");
+    } finally {
+      consoleSpy.mockRestore();
+    }
+  });
+
+  it("links rows to companies by name with --auto-create-company", async () => {
+    const consoleSpy = vi.spyOn(console, "log").mockImplementation(() => {});
+    mockImport.mockResolvedValue([
+      { jobTitle: "CEO", companyName: "Acme" },
+      { jobTitle: "CTO", companyName: "Acme" },
+      { jobTitle: "CFO", company: "Globex" },
+      { jobTitle: "COO", companyName: "Initech", companyId: "company-9" },
+    ]);
+    mockEnsure.mockImplementation(async (_object: string, _field: string, name: string) =>
+      name === "Acme"
+        ? { record: { id: "company-1" }, created: true }
+        : { record: { id: "company-2" }, created: false },
+    );
+
+    try {
+      await program.parseAsync([
+        "node",
+        "test",
+        "people",
+        "import",
+        "people.csv",
+        "--auto-create-company",
+      ]);
+
+      expect(mockEnsure).toHaveBeenCalledTimes(2);
+      expect(mockEnsure).toHaveBeenCalledWith("companies", "name", "Acme", { name: "Acme" });
+      expect(mockBatchCreate).toHaveBeenCalledWith("people", [
+        { jobTitle: "CEO", companyId: "company-1" },
+        { jobTitle: "CTO", companyId: "company-1" },
+        { jobTitle: "CFO", companyId: "company-2" },
+        { jobTitle: "COO", companyId: "company-9" },
+      ]);
+      expect(consoleSpy).toHaveBeenCalledWith("Import complete: 4 imported.");
+      expect(consoleSpy).toHaveBeenCalledWith("Created 1 company: Acme");
+    } finally {
+      consoleSpy.mockRestore();
+    }
+  });
+
+  it("links company names that differ only in case or spacing to one company", async () => {
+    const consoleSpy = vi.spyOn(console, "log").mockImplementation(() => {});
+    mockImport.mockResolvedValue([
+      { jobTitle: "CEO", companyName: "Acme  Corp" },
+      { jobTitle: "CTO", companyName: " acme corp" },
+    ]);
+    mockEnsure.mockResolvedValue({ record: { id: "company-1" }, created: true });
+
+    try {
+      await program.parseAsync([
+        "node",
+        "test",
+        "people",
+        "import",
+        "people.csv",
+        "--auto-create-company",
+      ]);
+
+      expect(mockEnsure).toHaveBeenCalledTimes(1);
+      expect(mockEnsure).toHaveBeenCalledWith("companies", "name", "Acme Corp", {
+        name: "Acme Corp",
+      });
+      expect(mockBatchCreate).toHaveBeenCalledWith("people", [
+        { jobTitle: "CEO", companyId: "company-1" },
+        { jobTitle: "CTO", companyId: "company-1" },
+      ]);
+    } finally {
+      consoleSpy.mockRestore();
+    }
+  });
+
+  it("fails only the rows whose company name is ambiguous", async () => {
+    const consoleSpy = vi.spyOn(console, "log").mockImplementation(() => {});
+    const errorSpy = vi.spyOn(console, "error").mockImplementation(() => {});
+    mockImport.mockResolvedValue([
+      { jobTitle: "CEO", companyName: "Acme" },
+      { jobTitle: "CTO", companyName: "Twin" },
+      { jobTitle: "CFO", companyName: "twin" },
+    ]);
+    mockEnsure.mockImplementation(async (_object: string, _field: string, name: string) => {
+      if (name === "Twin") {
+        throw new CliError(
+          "Multiple companies records found with name = Twin.",
+          "INVALID_ARGUMENTS",
+        );
+      }
+      return { record: { id: "company-1" }, created: false };
+    });
+
+    try {
+      await program.parseAsync([
+        "node",
+        "test",
+        "people",
+        "import",
+        "people.csv",
+        "--auto-create-company",
+      ]);
+
+      expect(mockEnsure).toHaveBeenCalledTimes(2);
+      expect(mockBatchCreate).toHaveBeenCalledWith("people", [
+        { jobTitle: "CEO", companyId: "company-1" },
+      ]);
+      expect(errorSpy).toHaveBeenCalledWith(
+        "Record 2 skipped: Multiple companies records found with name = Twin.",
+      );
+      expect(errorSpy).toHaveBeenCalledWith(
+        "Record 3 skipped: Multiple companies records found with name = Twin.",
+      );
+      expect(consoleSpy).toHaveBeenCalledWith("Import complete: 1 imported, 2 failed.");
+    } finally {
+      consoleSpy.mockRestore();
+      errorSpy.mockRestore();
+    }
+  });
+
+  it("lists the companies a --dry-run import would create", async () => {
+    const consoleSpy = vi.spyOn(console, "log").mockImplementation(() => {});
+    mockImport.mockResolvedValue([
+      { jobTitle: "CEO", companyName: "Acme" },
+      { jobTitle: "CTO", companyName: "Newco" },
+      { jobTitle: "CFO", companyName: "newco" },
+    ]);
+    mockFindUniqueBy.mockImplementation(async (_object: string, field: string, value: string) => {
+      if (value === "Acme") {
+        return { id: "company-1" };
+      }
+      throw new CliError(`No companies record found with ${field} = ${value}.`, "NOT_FOUND");
+    });
+
+    try {
+      await program.parseAsync([
+        "node",
+        "test",
+        "people",
+        "import",
+        "people.csv",
+        "--auto-create-company",
+        "--dry-run",
+      ]);
+
+      expect(mockFindUniqueBy).toHaveBeenCalledTimes(2);
+      expect(mockEnsure).not.toHaveBeenCalled();
+      expect(mockBatchCreate).not.toHaveBeenCalled();
+      expect(consoleSpy).toHaveBeenCalledWith("Would create 1 company: Newco");
+    } finally {
+      consoleSpy.mockRestore();
+    }
+  });
+
+  it("adds to and removes from array fields on update", async () => {
+    mockGet.mockResolvedValue({
+      id: "person-1",
+      emails: { primaryEmail: "ann@example.com", additionalEmails: ["old@example.com"] },
+    });
+
+    await program.parseAsync([
+      "node",
+      "test",
+      "people",
+      "update",
+      "person-1",
+      "--add-to",
+      "emails.additionalEmails=new@example.com",
+      "--remove-from",
+      "emails.additionalEmails=old@example.com",
+    ]);
+
+    expect(mockGet).toHaveBeenCalledWith("people", "person-1");
+    expect(mockUpdate).toHaveBeenCalledWith("people", "person-1", {
+      emails: { additionalEmails: ["new@example.com"] },
+    });
+  });
+
+  describe("update --from-file", () => {
+    it("patches each row's person by email and reports the outcome per row", async () => {
+      mockImport.mockResolvedValue([
+        { email: "ann@example.com", jobTitle: "CTO", "name.lastName": "" },
+        { email: "nobody@example.com", jobTitle: "CEO" },
+      ]);
+      mockFindUniqueBy.mockImplementation(async (_object: string, field: string, value: string) => {
+        if (value === "ann@example.com") {
+          return { id: "person-1" };
+        }
+        throw new CliError(`No people record found with ${field} = ${value}.`, "NOT_FOUND");
+      });
+
+      await program.parseAsync([
+        "node",
+        "test",
+        "people",
+        "update",
+        "--from-file",
+        "updates.csv",
+        "--key",
+        "email",
+      ]);
+
+      expect(mockImport).toHaveBeenCalledWith("updates.csv");
+      expect(mockFindUniqueBy).toHaveBeenCalledWith(
+        "people",
+        "emails.primaryEmail",
+        "ann@example.com",
+      );
+      expect(mockUpdate).toHaveBeenCalledTimes(1);
+      expect(mockUpdate).toHaveBeenCalledWith("people", "person-1", { jobTitle: "CTO" });
+      expect(mockRender).toHaveBeenCalledWith(
+        [
+          { row: 1, key: "ann@example.com", status: "updated", id: "person-1" },
+          { row: 2, key: "nobody@example.com", status: "not-found" },
+        ],
+        { format: "json", query: undefined },
+      );
+    });
+
+    it("only looks up rows with --dry-run", async () => {
+      mockImport.mockResolvedValue([{ jobTitle: "CTO", "city.name": "Paris" }]);
+
+      await program.parseAsync([
+        "node",
+        "test",
+        "people",
+        "update",
+        "--from-file",
+        "updates.json",
+        "--key",
+        "jobTitle",
+        "--dry-run",
+      ]);
+
+      expect(mockFindUniqueBy).toHaveBeenCalledWith("people", "jobTitle", "CTO");
+      expect(mockUpdate).not.toHaveBeenCalled();
+      expect(mockRender).toHaveBeenCalledWith(
+        [
+          {
+            row: 1,
+            key: "CTO",
+            status: "would-update",
+            id: "person-2",
+            changes: { city: { name: "Paris" } },
+          },
+        ],
+        { format: "json", query: undefined },
+      );
+    });
+
+    it("rejects an ID or inline fields alongside --from-file", async () => {
+      await expect(
+        program.parseAsync([
+          "node",
+          "test",
+          "people",
+          "update",
+          "person-1",
+          "--from-file",
+          "updates.csv",
+        ]),
+      ).rejects.toThrow("--from-file cannot be combined with <id>");
+      expect(mockImport).not.toHaveBeenCalled();
+    });
+  });
+
+  describe("stats", () => {
+    it("summarizes totals, recent additions, and top companies", async () => {
+      mockList.mockImplementation(async (object: string, options: { filter?: string }) => {
+        if (object === "companies") {
+          return { data: [{ id: "company-1", name: "Acme" }] };
+        }
+        if (options.filter === undefined) {
+          return { data: [], totalCount: 120 };
+        }
+        return { data: [], totalCount: options.filter.includes("createdAt") ? 4 : 0 };
+      });
+      mockGroupBy.mockResolvedValue([
+        { groupByDimensionValues: ["company-2"], countNotEmptyId: "3" },
+        { groupByDimensionValues: [null], countNotEmptyId: "50" },
+        { groupByDimensionValues: ["company-1"], countNotEmptyId: "12" },
+      ]);
+
+      await program.parseAsync(["node", "test", "people", "stats", "--top", "2"]);
+
+      expect(mockGroupBy).toHaveBeenCalledWith(
+        "people",
+        { groupBy: [{ companyId: true }] },
+        { aggregate: ["countNotEmptyId"] },
+      );
+      expect(mockList).toHaveBeenCalledWith("people", {
+        limit: 1,
+        filter: expect.stringMatching(/^createdAt\[gte\]:"\d{4}-\d{2}-\d{2}T/),
+      });
+      expect(mockList).toHaveBeenCalledWith("companies", {
+        filter: "id[in]:[company-1,company-2]",
+        limit: 2,
+      });
+      expect(mockRender).toHaveBeenCalledWith(
+        {
+          total: 120,
+          addedLast7Days: 4,
+          addedLast30Days: 4,
+          topCompanies: [
+            { companyId: "company-1", name: "Acme", people: 12 },
+            { companyId: "company-2", people: 3 },
+          ],
+        },
+        { format: "json", query: undefined },
+      );
+    });
+
+    it("reports group-by as unavailable when the server rejects aggregation", async () => {
+      mockList.mockResolvedValue({ data: [], totalCount: 7 });
+      mockGroupBy.mockRejectedValue({ message: "Bad Request", response: { status: 400 } });
+
+      await program.parseAsync(["node", "test", "people", "stats"]);
+
+      expect(mockRender).toHaveBeenCalledWith(
+        {
+          total: 7,
+          addedLast7Days: 7,
+          addedLast30Days: 7,
+          topCompanies: null,
+          unavailable: ["topCompanies"],
+        },
+        expect.anything(),
+      );
+    });
+
+    it("propagates auth failures instead of degrading", async () => {
+      mockList.mockRejectedValue({ message: "Unauthorized", response: { status: 401 } });
+
+      await expect(program.parseAsync(["node", "test", "people", "stats"])).rejects.toMatchObject({
+        response: { status: 401 },
+      });
+      expect(mockRender).not.toHaveBeenCalled();
+    });
+
+    it("rejects a non-positive --top", async () => {
+      await expect(
+        program.parseAsync(["node", "test", "people", "stats", "--top", "0"]),
+      ).rejects.toThrow('Invalid --top value "0"; expected a positive integer.');
+      expect(mockList).not.toHaveBeenCalled();
+    });
+  });
+
+  describe("timeline", () => {
+    it("merges notes, tasks, and activities newest first", async () => {
+      mockList.mockImplementation(async (object: string) => {
+        if (object === "noteTargets") {
+          return {
+            data: [
+              {
+                id: "nt-1",
+                note: { id: "note-1", title: "Intro call", createdAt: "2024-05-02T10:00:00Z" },
+              },
+            ],
+          };
+        }
+        if (object === "taskTargets") {
+          return {
+            data: [
+              {
+                id: "tt-1",
+                task: {
+                  id: "task-1",
+                  title: "Send deck",
+                  status: "TODO",
+                  createdAt: "2024-05-03T09:00:00Z",
+                },
+              },
+            ],
+          };
+        }
+        return {
+          data: [{ id: "activity-1", name: "person.created", happensAt: "2024-05-01T08:00:00Z" }],
+        };
+      });
+
+      await program.parseAsync(["node", "test", "people", "timeline", "person-1", "--limit", "2"]);
+
+      expect(mockList).toHaveBeenCalledWith("noteTargets", {
+        filter: "personId[eq]:person-1",
+        include: "note",
+        sort: "createdAt",
+        order: "desc",
+        limit: 2,
+      });
+      expect(mockList).toHaveBeenCalledWith("timelineActivities", {
+        filter: "personId[eq]:person-1",
+        sort: "happensAt",
+        order: "desc",
+        limit: 2,
+      });
+      expect(mockRender).toHaveBeenCalledWith(
+        {
+          personId: "person-1",
+          entries: [
+            {
+              at: "2024-05-03T09:00:00Z",
+              type: "task",
+              id: "task-1",
+              title: "Send deck",
+              detail: "TODO",
+            },
+            { at: "2024-05-02T10:00:00Z", type: "note", id: "note-1", title: "Intro call" },
+          ],
+        },
+        { format: "json", query: undefined },
+      );
+    });
+
+    it("filters by --since and reports sources the server rejects", async () => {
+      mockList.mockImplementation(async (object: string) => {
+        if (object === "timelineActivities") {
+          throw { message: "Bad Request", response: { status: 400 } };
+        }
+        return { data: [] };
+      });
+
+      await program.parseAsync([
+        "node",
+        "test",
+        "people",
+        "timeline",
+        "person-1",
+        "--since",
+        "2024-05-01",
+      ]);
+
+      expect(mockList).toHaveBeenCalledWith(
+        "taskTargets",
+        expect.objectContaining({
+          filter: 'personId[eq]:person-1,createdAt[gte]:"2024-05-01T00:00:00.000Z"',
+        }),
+      );
+      expect(mockRender).toHaveBeenCalledWith(
+        { personId: "person-1", entries: [], unavailable: ["activities"] },
+        expect.anything(),
+      );
+    });
+
+    it("rejects an unparseable --since", async () => {
+      await expect(
+        program.parseAsync(["node", "test", "people", "timeline", "person-1", "--since", "soon"]),
+      ).rejects.toThrow('Invalid --since value "soon".');
+      expect(mockList).not.toHaveBeenCalled();
+    });
+  });
+});
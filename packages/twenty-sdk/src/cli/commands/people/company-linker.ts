@@ -0,0 +1,159 @@
+import { CliError } from "../../utilities/errors/cli-error";
+import type { RecordsService } from "../../utilities/records/services/records.service";
+import type { PreparedBatch, RecordWriteHooks } from "../api/operations/types";
+
+export interface CreatedCompany {
+  id: string;
+  name: string;
+}
+
+// people import --auto-create-company: rows may name their company (a
+// companyName column, or company as a plain string) instead of giving a
+// companyId. Each name is looked up, created when no company has it, and
+// cached for the run so a name repeated across rows or files links to one
+// company; the cache ignores case and extra whitespace. Rows that already
+// carry a companyId are left as they are. A name that matches several
+// companies fails only its rows.
+export class CompanyLinker {
+  readonly created: CreatedCompany[] = [];
+  private ids = new Map<string, string>();
+  private failures = new Map<string, CliError>();
+  private previewed = new Set<string>();
+
+  constructor(private records: Pick<RecordsService, "ensure" | "findUniqueBy">) {}
+
+  hooks(): RecordWriteHooks {
+    return {
+      batch: async (records) => {
+        const prepared: PreparedBatch = { records: [], skipped: [] };
+        for (const [position, record] of records.entries()) {
+          try {
+            prepared.records.push(await this.link(record));
+          } catch (error) {
+            if (!isRowError(error)) {
+              throw error;
+            }
+            prepared.skipped.push({ position, error });
+          }
+        }
+        return prepared;
+      },
+      preview: (records) => this.preview(records),
+      report: () => ({
+        fields: { createdCompanies: this.created },
+        lines: this.created.length > 0 ? [describeCreated(this.created)] : [],
+      }),
+    };
+  }
+
+  private async link(record: Record<string, unknown>): Promise<Record<string, unknown>> {
+    const { companyName, ...rest } = record;
+    const name = companyNameOf(companyName) ?? companyNameOf(rest.company);
+    if (typeof rest.company === "string") {
+      delete rest.company;
+    }
+    if (!name || hasCompanyId(rest.companyId)) {
+      return rest;
+    }
+
+    return { ...rest, companyId: await this.resolve(name) };
+  }
+
+  private async resolve(name: string): Promise<string> {
+    const key = cacheKey(name);
+    const cached = this.ids.get(key);
+    if (cached) {
+      return cached;
+    }
+    const failure = this.failures.get(key);
+    if (failure) {
+      throw failure;
+    }
+
+    let result: Awaited<ReturnType<RecordsService["ensure"]>>;
+    try {
+      result = await this.records.ensure("companies", "name", name, { name });
+    } catch (error) {
+      // An ambiguous name fails the same way for every row that uses it.
+      if (isRowError(error)) {
+        this.failures.set(key, error);
+      }
+      throw error;
+    }
+    const id = (result.record as { id?: unknown } | undefined)?.id;
+    if (typeof id !== "string") {
+      throw new CliError(
+        `Could not resolve company ${JSON.stringify(name)} to an ID.`,
+        "API_ERROR",
+      );
+    }
+    this.ids.set(key, id);
+    if (result.created) {
+      this.created.push({ id, name });
+    }
+    return id;
+  }
+
+  // --dry-run: looks each name up without creating anything and lists the
+  // companies that would be created, and the names whose rows would fail,
+  // once per run.
+  private async preview(records: Record<string, unknown>[]): Promise<string[]> {
+    const missing: string[] = [];
+    const ambiguous: string[] = [];
+    for (const record of records) {
+      const name = companyNameOf(record.companyName) ?? companyNameOf(record.company);
+      if (!name || hasCompanyId(record.companyId) || this.previewed.has(cacheKey(name))) {
+        continue;
+      }
+      this.previewed.add(cacheKey(name));
+      try {
+        await this.records.findUniqueBy("companies", "name", name);
+      } catch (error) {
+        if (isRowError(error)) {
+          ambiguous.push(name);
+        } else if (error instanceof CliError && error.code === "NOT_FOUND") {
+          missing.push(name);
+        } else {
+          throw error;
+        }
+      }
+    }
+
+    const lines: string[] = [];
+    if (missing.length > 0) {
+      const noun = missing.length === 1 ? "company" : "companies";
+      lines.push(`Would create ${missing.length} ${noun}: ${missing.join(", ")}`);
+    }
+    if (ambiguous.length > 0) {
+      lines.push(
+        `Would fail rows whose company name matches several companies: ${ambiguous.join(", ")}`,
+      );
+    }
+    return lines;
+  }
+}
+
+function describeCreated(created: CreatedCompany[]): string {
+  const noun = created.length === 1 ? "company" : "companies";
+  return `Created ${created.length} ${noun}: ${created.map((company) => company.name).join(", ")}`;
+}
+
+// Runs of whitespace collapse to one space, so "Acme  Corp " names "Acme Corp".
+function companyNameOf(value: unknown): string | undefined {
+  const name = typeof value === "string" ? value.trim().replace(/\s+/g, " ") : "";
+  return name !== "" ? name : undefined;
+}
+
+function cacheKey(name: string): string {
+  return name.toLowerCase();
+}
+
+// A problem with the row's company name (e.g. several companies share it), as
+// opposed to a request failure, which still fails the whole batch.
+function isRowError(error: unknown): error is CliError {
+  return error instanceof CliError && error.code === "INVALID_ARGUMENTS";
+}
+
+function hasCompanyId(value: unknown): boolean {
+  return typeof value === "string" ? value.trim() !== "" : value !== undefined && value !== null;
+}
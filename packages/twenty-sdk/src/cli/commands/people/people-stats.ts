@@ -0,0 +1,165 @@
+import { CliServices } from "../../utilities/shared/services";
+import { logVerbose } from "../../utilities/shared/logger";
+
+const DAY_MS = 24 * 60 * 60 * 1000;
+
+export interface TopCompany {
+  companyId: string;
+  name?: string;
+  people: number;
+}
+
+export interface PeopleStats {
+  total: number | null;
+  addedLast7Days: number | null;
+  addedLast30Days: number | null;
+  topCompanies: TopCompany[] | null;
+  // Sections the server could not answer; their values are null.
+  unavailable?: string[];
+}
+
+export interface PeopleStatsOptions {
+  top: number;
+  now?: Date;
+}
+
+type StatsRecords = Pick<CliServices["records"], "list" | "groupBy">;
+
+// Composes the summary from existing count (list totalCount) and group-by
+// endpoints. A section the server rejects is reported as unavailable rather
+// than failing the whole command.
+export async function collectPeopleStats(
+  records: StatsRecords,
+  options: PeopleStatsOptions,
+): Promise<PeopleStats> {
+  const now = options.now ?? new Date();
+  const unavailable: string[] = [];
+  const section = async <T>(name: string, load: () => Promise<T>): Promise<T | null> => {
+    try {
+      return await load();
+    } catch (error) {
+      if (!isUnsupportedQueryError(error)) {
+        throw error;
+      }
+      logVerbose(`people stats: ${name} unavailable (${(error as Error).message})`);
+      unavailable.push(name);
+      return null;
+    }
+  };
+
+  const total = await section("total", () => countPeople(records));
+  const addedLast7Days = await section("addedLast7Days", () =>
+    countPeople(records, createdSince(now, 7)),
+  );
+  const addedLast30Days = await section("addedLast30Days", () =>
+    countPeople(records, createdSince(now, 30)),
+  );
+  const topCompanies = await section("topCompanies", () => loadTopCompanies(records, options.top));
+
+  return {
+    total,
+    addedLast7Days,
+    addedLast30Days,
+    topCompanies,
+    ...(unavailable.length > 0 ? { unavailable } : {}),
+  };
+}
+
+async function countPeople(records: StatsRecords, filter?: string): Promise<number | null> {
+  const response = await records.list("people", { limit: 1, filter });
+  return response.totalCount ?? null;
+}
+
+function createdSince(now: Date, days: number): string {
+  return `createdAt[gte]:"${new Date(now.getTime() - days * DAY_MS).toISOString()}"`;
+}
+
+async function loadTopCompanies(records: StatsRecords, top: number): Promise<TopCompany[]> {
+  const response = await records.groupBy(
+    "people",
+    { groupBy: [{ companyId: true }] },
+    { aggregate: ["countNotEmptyId"] },
+  );
+  const companies = extractGroups(response)
+    .map((group) => ({
+      companyId: group.groupByDimensionValues?.[0],
+      people: Number(group.countNotEmptyId ?? group.totalCount ?? 0),
+    }))
+    .filter((group): group is TopCompany => typeof group.companyId === "string")
+    .sort((a, b) => b.people - a.people)
+    .slice(0, top);
+
+  return attachCompanyNames(records, companies);
+}
+
+// Names are a convenience; the IDs and counts stand on their own if the
+// lookup is rejected.
+async function attachCompanyNames(
+  records: StatsRecords,
+  companies: TopCompany[],
+): Promise<TopCompany[]> {
+  if (companies.length === 0) {
+    return companies;
+  }
+
+  const ids = companies.map((company) => company.companyId);
+  let names: Map<string, string>;
+  try {
+    const response = await records.list("companies", {
+      filter: `id[in]:[${ids.join(",")}]`,
+      limit: ids.length,
+    });
+    names = new Map(
+      response.data
+        .filter(isRecord)
+        .filter((company) => typeof company.id === "string" && typeof company.name === "string")
+        .map((company) => [company.id as string, company.name as string]),
+    );
+  } catch (error) {
+    if (!isUnsupportedQueryError(error)) {
+      throw error;
+    }
+    logVerbose(`people stats: company names unavailable (${(error as Error).message})`);
+    return companies;
+  }
+
+  return companies.map((company) => {
+    const name = names.get(company.companyId);
+    return name === undefined ? company : { ...company, name };
+  });
+}
+
+interface GroupByRow {
+  groupByDimensionValues?: unknown[];
+  countNotEmptyId?: number | string;
+  totalCount?: number | string;
+}
+
+function extractGroups(response: unknown): GroupByRow[] {
+  if (Array.isArray(response)) {
+    return response.filter(isRecord) as GroupByRow[];
+  }
+  if (!isRecord(response)) {
+    return [];
+  }
+  if (Array.isArray(response.data)) {
+    return extractGroups(response.data);
+  }
+  if (isRecord(response.data)) {
+    const nested = Object.values(response.data).find(Array.isArray);
+    return nested ? extractGroups(nested) : [];
+  }
+
+  return [];
+}
+
+// The server answered but cannot serve this query (unknown aggregate, filter
+// operator, or route). Auth failures and network errors still propagate.
+function isUnsupportedQueryError(error: unknown): boolean {
+  const status = (error as { response?: { status?: number } })?.response?.status;
+  return typeof status === "number" && status !== 401 && status !== 403;
+}
+
+function isRecord(value: unknown): value is Record<string, unknown> {
+  return typeof value === "object" && value !== null && !Array.isArray(value);
+}
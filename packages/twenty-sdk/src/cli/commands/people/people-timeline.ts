@@ -0,0 +1,178 @@
+import { CliServices } from "../../utilities/shared/services";
+import { logVerbose } from "../../utilities/shared/logger";
+
+export type TimelineEntryType = "note" | "task" | "activity";
+
+export interface TimelineEntry {
+  at: string;
+  type: TimelineEntryType;
+  id: string;
+  title: string;
+  // Task status; the activity's linked record name when it has one.
+  detail?: string;
+}
+
+export interface PeopleTimeline {
+  personId: string;
+  entries: TimelineEntry[];
+  // Sources the server could not answer; their entries are missing.
+  unavailable?: string[];
+}
+
+export interface PeopleTimelineOptions {
+  limit: number;
+  since?: Date;
+}
+
+type TimelineRecords = Pick<CliServices["records"], "list">;
+
+// Merges a person's notes, tasks, and timeline activities, newest first. Each
+// source is one list call on its junction object (noteTargets, taskTargets)
+// or timelineActivities, asking for at most --limit rows since the merged
+// result never needs more from any one source.
+export async function collectPeopleTimeline(
+  records: TimelineRecords,
+  personId: string,
+  options: PeopleTimelineOptions,
+): Promise<PeopleTimeline> {
+  const unavailable: string[] = [];
+  const source = async (
+    name: string,
+    load: () => Promise<TimelineEntry[]>,
+  ): Promise<TimelineEntry[]> => {
+    try {
+      return await load();
+    } catch (error) {
+      if (!isUnsupportedQueryError(error)) {
+        throw error;
+      }
+      logVerbose(`people timeline: ${name} unavailable (${(error as Error).message})`);
+      unavailable.push(name);
+      return [];
+    }
+  };
+
+  const notes = await source("notes", () =>
+    loadTargets(records, "noteTargets", "note", personId, options),
+  );
+  const tasks = await source("tasks", () =>
+    loadTargets(records, "taskTargets", "task", personId, options),
+  );
+  const activities = await source("activities", () =>
+    loadActivities(records, personId, options),
+  );
+
+  const since = options.since?.getTime();
+  const entries = [...notes, ...tasks, ...activities]
+    .filter((entry) => since === undefined || Date.parse(entry.at) >= since)
+    .sort((a, b) => Date.parse(b.at) - Date.parse(a.at))
+    .slice(0, options.limit);
+
+  return {
+    personId,
+    entries,
+    ...(unavailable.length > 0 ? { unavailable } : {}),
+  };
+}
+
+// Parses --since: an ISO date or date-time, or a relative age such as 7d,
+// 12h, or 2w.
+export function parseSince(value: string, now: Date = new Date()): Date | undefined {
+  const relative = /^(\d+)([hdw])$/.exec(value.trim());
+  if (relative) {
+    const hours = { h: 1, d: 24, w: 24 * 7 }[relative[2] as "h" | "d" | "w"];
+    return new Date(now.getTime() - Number(relative[1]) * hours * 60 * 60 * 1000);
+  }
+  const parsed = Date.parse(value);
+  return Number.isNaN(parsed) ? undefined : new Date(parsed);
+}
+
+async function loadTargets(
+  records: TimelineRecords,
+  object: "noteTargets" | "taskTargets",
+  relation: "note" | "task",
+  personId: string,
+  options: PeopleTimelineOptions,
+): Promise<TimelineEntry[]> {
+  const response = await records.list(object, {
+    filter: withSince(`personId[eq]:${personId}`, "createdAt", options.since),
+    include: relation,
+    sort: "createdAt",
+    order: "desc",
+    limit: options.limit,
+  });
+
+  return response.data
+    .filter(isRecord)
+    .map((target) => target[relation])
+    .filter(isRecord)
+    .flatMap((record) => {
+      const at = stringField(record, "createdAt");
+      const id = stringField(record, "id");
+      if (!at || !id) {
+        return [];
+      }
+      const status = stringField(record, "status");
+      return [
+        {
+          at,
+          type: relation,
+          id,
+          title: stringField(record, "title") ?? "",
+          ...(status ? { detail: status } : {}),
+        },
+      ];
+    });
+}
+
+async function loadActivities(
+  records: TimelineRecords,
+  personId: string,
+  options: PeopleTimelineOptions,
+): Promise<TimelineEntry[]> {
+  const response = await records.list("timelineActivities", {
+    filter: withSince(`personId[eq]:${personId}`, "happensAt", options.since),
+    sort: "happensAt",
+    order: "desc",
+    limit: options.limit,
+  });
+
+  return response.data.filter(isRecord).flatMap((activity) => {
+    const at = stringField(activity, "happensAt");
+    const id = stringField(activity, "id");
+    if (!at || !id) {
+      return [];
+    }
+    const linked = stringField(activity, "linkedRecordCachedName");
+    return [
+      {
+        at,
+        type: "activity" as const,
+        id,
+        title: stringField(activity, "name") ?? "",
+        ...(linked ? { detail: linked } : {}),
+      },
+    ];
+  });
+}
+
+// Conditions joined by a comma are ANDed by the REST filter syntax.
+function withSince(filter: string, field: string, since?: Date): string {
+  return since ? `${filter},${field}[gte]:"${since.toISOString()}"` : filter;
+}
+
+function stringField(record: Record<string, unknown>, field: string): string | undefined {
+  const value = record[field];
+  return typeof value === "string" && value !== "" ? value : undefined;
+}
+
+// The server answered but cannot serve this query (unknown object, filter
+// field, or route). Auth failures and network errors still propagate.
+function isUnsupportedQueryError(error: unknown): boolean {
+  const status = (error as { response?: { status?: number } })?.response?.status;
+  return typeof status === "number" && status !== 401 && status !== 403;
+}
+
+function isRecord(value: unknown): value is Record<string, unknown> {
+  return typeof value === "object" && value !== null && !Array.isArray(value);
+}
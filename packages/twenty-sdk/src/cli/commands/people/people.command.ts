@@ -0,0 +1,434 @@
+import { Command } from "commander";
+import { CliError } from "../../utilities/errors/cli-error";
+import { parseBody } from "../../utilities/shared/body";
+import { applyGlobalOptions } from "../../utilities/shared/global-options";
+import { createCommandContext } from "../../utilities/shared/context";
+import { log } from "../../utilities/shared/logger";
+import { splitOnce } from "../../utilities/shared/parse";
+import { runBatchCreateOperation } from "../api/operations/batch-create.operation";
+import { runExportOperation } from "../api/operations/export.operation";
+import { runImportOperation } from "../api/operations/import.operation";
+import { runListOperation } from "../api/operations/list.operation";
+import { runOpenOperation } from "../api/operations/open.operation";
+import { runKeyedUpdateOperation, UpdateKey } from "../api/operations/keyed-update.operation";
+import { runUpdateOperation } from "../api/operations/update.operation";
+import type { ApiCommandOptions } from "../api/operations/types";
+import { CompanyLinker } from "./company-linker";
+import { collectPeopleStats } from "./people-stats";
+import { collectPeopleTimeline, parseSince } from "./people-timeline";
+
+interface LookupOptions {
+  email?: string;
+  by?: string;
+}
+
+interface GetOptions extends LookupOptions {
+  include?: string;
+}
+
+interface EnsureOptions extends LookupOptions {
+  data?: string;
+  file?: string;
+  set?: string[];
+}
+
+interface StatsOptions {
+  top: string;
+}
+
+interface TimelineOptions {
+  limit: string;
+  since?: string;
+}
+
+interface PeopleImportOptions extends ApiCommandOptions {
+  autoCreateCompany?: boolean;
+}
+
+interface ListOptions extends ApiCommandOptions {
+  createdBy?: string;
+  updatedBy?: string;
+}
+
+interface UniqueLookup {
+  field: string;
+  value: string;
+}
+
+const EMAIL_FIELD = "emails.primaryEmail";
+const MEMBER_ID_PATTERN = /^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$/i;
+const DEFAULT_STATS_TOP = "5";
+const DEFAULT_TIMELINE_LIMIT = "50";
+
+export function registerPeopleCommand(program: Command): void {
+  const cmd = program.command("people").description("Person shortcuts");
+  applyGlobalOptions(cmd);
+
+  const getCmd = cmd
+    .command("get")
+    .description("Get a person by ID, email, or another unique field")
+    .argument("[id]", "Person ID")
+    .option("--email <email>", "Match the primary email address")
+    .option("--by <field=value>", "Match an exact field value, e.g. jobTitle=CEO")
+    .option("--include <relations>", "Include related records");
+  applyGlobalOptions(getCmd);
+  getCmd.action(async (id: string | undefined, options: GetOptions, command: Command) => {
+    if (id !== undefined && (options.email !== undefined || options.by !== undefined)) {
+      throw new CliError("Use only one of <id>, --email, or --by.", "INVALID_ARGUMENTS");
+    }
+    const lookup = resolveLookup(options);
+    if (id === undefined && !lookup) {
+      throw new CliError(
+        "Missing person selector.",
+        "INVALID_ARGUMENTS",
+        "Pass a person ID, --email, or --by <field=value>.",
+      );
+    }
+    const { globalOptions, services } = createCommandContext(command);
+
+    const response = lookup
+      ? await services.records.findUniqueBy("people", lookup.field, lookup.value, {
+          include: options.include,
+        })
+      : await services.records.get("people", id as string, { include: options.include });
+    await services.output.render(response, {
+      format: globalOptions.output,
+      query: globalOptions.query,
+    });
+  });
+
+  const ensureCmd = cmd
+    .command("ensure")
+    .description("Get a person by email or unique field, creating it when missing")
+    .option("--email <email>", "Match the primary email address")
+    .option("--by <field=value>", "Match an exact field value, e.g. jobTitle=CEO")
+    .option("-d, --data <json>", "JSON payload used when creating")
+    .option("-f, --file <path>", "JSON file payload used when creating (use - for stdin)")
+    .option("--set <key=value>", "Set a field value used when creating", collect);
+  applyGlobalOptions(ensureCmd);
+  ensureCmd.action(async (options: EnsureOptions, command: Command) => {
+    const lookup = resolveLookup(options);
+    if (!lookup) {
+      throw new CliError(
+        "Missing person selector.",
+        "INVALID_ARGUMENTS",
+        "Pass --email or --by <field=value>.",
+      );
+    }
+    // The lookup value is always written so the created record matches it.
+    const payload = await parseBody(options.data, options.file, [
+      ...(options.set ?? []),
+      `${lookup.field}=${JSON.stringify(lookup.value)}`,
+    ]);
+    const { globalOptions, services } = createCommandContext(command);
+
+    const result = await services.records.ensure("people", lookup.field, lookup.value, payload);
+    await services.output.render(
+      {
+        created: result.created,
+        ...(result.updated ? { updated: true } : {}),
+        record: result.record,
+      },
+      {
+        format: globalOptions.output,
+        query: globalOptions.query,
+      },
+    );
+  });
+
+  // Same path as "api list people"; with --output html --output-file the
+  // page can be shared or opened straight away with --open. --distinct
+  // aggregates one field page by page, e.g. cities for a dropdown.
+  // --created-by/--updated-by match the workspace member on the audit
+  // (actor) fields, ANDed with any --filter.
+  const listCmd = cmd
+    .command("list")
+    .description("List people, optionally as an HTML page for the browser")
+    .option("--all", "Fetch every page using cursor pagination")
+    .option("--limit <n>", "Records per page (default 200)")
+    .option("--cursor <cursor>", "Start from a pagination cursor")
+    .option("--filter <expression>", "Filter expression")
+    .option("--created-by <memberId>", "Only people created by this workspace member")
+    .option("--updated-by <memberId>", "Only people last updated by this workspace member")
+    .option("--sort <field>", "Sort field")
+    .option("--order <direction>", "Sort order (asc or desc)")
+    .option("--sort-local <field[:desc]>", "Sort fetched records client-side, e.g. city:desc")
+    .option("--fields <fields>", "Comma-separated top-level fields, in column order")
+    .option("--param <key=value>", "Extra query parameter sent as-is (repeatable)", collect)
+    .option("--distinct <field>", "Unique values of a field across all pages, e.g. city")
+    .option("--with-counts", "With --distinct, include how many people have each value")
+    .option("--with-page-info", "Wrap JSON as {records, pageInfo, totalCount} for paging")
+    .option("--output-file <path>", "Write --output html to this file")
+    .option("--open", "Open the --output-file page in the default browser");
+  applyGlobalOptions(listCmd);
+  listCmd.action(async (options: ListOptions, command: Command) => {
+    const filter = withAuditFilters(options);
+    const { globalOptions, services } = createCommandContext(command);
+    await runListOperation({
+      object: "people",
+      options: { ...options, filter },
+      services,
+      globalOptions,
+    });
+  });
+
+  // Same path as "api update people"; --add-to/--remove-from edit array fields
+  // such as emails.additionalEmails without replacing the existing elements.
+  // --from-file patches many people matched by --key instead of by ID, e.g. a
+  // spreadsheet of email,jobTitle rows. --on-missing create turns a 404 into a
+  // create with the same ID, the ID-keyed counterpart of "people ensure".
+  const updateCmd = cmd
+    .command("update")
+    .description("Update a person, or many people from a file matched by a key column")
+    .argument("[id]", "Person ID")
+    .option("--id-file <path>", "Apply the same changes to each ID in a file, one per line")
+    .option("-d, --data <json>", "JSON payload")
+    .option("-f, --file <path>", "JSON file payload (use - for stdin)")
+    .option("--set <key=value>", "Set a field value", collect)
+    .option("--clear <field>", "Set a field to null", collect)
+    .option("--add-to <field=value>", "Append to an array field unless already present", collect)
+    .option("--remove-from <field=value>", "Remove an element from an array field", collect)
+    .option("--on-missing <action>", "When the ID does not exist: error (default) or create")
+    .option("--show-diff", "Print the changed fields as old and new values")
+    .option("--from-file <path>", "CSV or JSON rows to apply, matched by --key")
+    .option("--key <column>", "With --from-file, the unique column to match (default: email)")
+    .option("--dry-run", "With --from-file, look up each row and show the changes only");
+  applyGlobalOptions(updateCmd);
+  updateCmd.action(async (id: string | undefined, options: ApiCommandOptions, command: Command) => {
+    const { globalOptions, services } = createCommandContext(command);
+    const context = { object: "people", arg: id, options, services, globalOptions };
+    if (options.fromFile === undefined) {
+      await runUpdateOperation(context);
+      return;
+    }
+    if (id !== undefined || options.idFile !== undefined || hasInlineChanges(options)) {
+      throw new CliError(
+        "--from-file cannot be combined with <id>, --id-file, --data, --file, or field flags.",
+        "INVALID_ARGUMENTS",
+      );
+    }
+    await runKeyedUpdateOperation(context, resolveUpdateKey(options.key ?? "email"));
+  });
+
+  // Same path as "api batch-create people"; --stdin-jsonl creates records
+  // while NDJSON is still arriving, e.g. piped from another workspace's export.
+  const batchCreateCmd = cmd
+    .command("batch-create")
+    .description("Create many people from a JSON array or streamed NDJSON")
+    .option("-d, --data <json>", "JSON array payload")
+    .option("-f, --file <path>", "JSON or CSV file payload (use - for stdin)")
+    .option("--stdin-jsonl", "Read one JSON record per line from stdin, creating as they arrive")
+    .option("--batch-size <n>", "Records per batch with --stdin-jsonl (max 60)")
+    .option("--concurrency <n>", "Batches in flight with --stdin-jsonl (default 4)")
+    .option("--fail-fast", "With --stdin-jsonl, stop reading at the first failure");
+  applyGlobalOptions(batchCreateCmd);
+  batchCreateCmd.action(async (options: ApiCommandOptions, command: Command) => {
+    const { globalOptions, services } = createCommandContext(command);
+    await runBatchCreateOperation({ object: "people", options, services, globalOptions });
+  });
+
+  // Same path as "api export people"; --split-size/--split-bytes stream pages
+  // into numbered files (people-0001.csv, ...) instead of one large file.
+  const exportCmd = cmd
+    .command("export")
+    .description("Export people as JSON or CSV, optionally split across files")
+    .option("--all", "Fetch every page using cursor pagination")
+    .option("--limit <n>", "Records per page (default 200)")
+    .option("--page-size <n>", "Records per request while paginating (max 200)")
+    .option("--cursor <cursor>", "Start from a pagination cursor")
+    .option("--filter <expression>", "Filter expression")
+    .option("--sort <field>", "Sort field")
+    .option("--order <direction>", "Sort order (asc or desc)")
+    .option("--sort-local <field[:desc]>", "Sort fetched records client-side, e.g. city:desc")
+    .option("--include <relations>", "Include related records")
+    .option("--fields <fields>", "Comma-separated top-level fields to return")
+    .option("--format <format>", "Export format (json, csv, or parquet)")
+    .option("--output-file <path>", "Output file path")
+    .option("--flatten", "Flatten nested fields into dotted CSV columns")
+    .option("--split-size <records>", "Rotate output files every N records")
+    .option("--split-bytes <size>", "Rotate output files before they exceed a size, e.g. 50MB")
+    .option("--array", "Write JSON as one buffered array (default)")
+    .option("--stream", "Write JSON as NDJSON, one record per line")
+    .option("--manifest <path>", "Write a JSON provenance manifest to this path after exporting")
+    .option("--checkpoint <path>", "Save the export cursor to this file after each page")
+    .option("--resume <path>", "Continue an export from a saved checkpoint, appending to its file");
+  applyGlobalOptions(exportCmd);
+  exportCmd.action(async (options: ApiCommandOptions, command: Command) => {
+    const { globalOptions, services } = createCommandContext(command);
+    await runExportOperation({ object: "people", options, services, globalOptions });
+  });
+
+  // Same path as "api import people"; --template-file builds nested bodies
+  // (e.g. emails.additionalEmails) that flat CSV columns cannot express.
+  const importCmd = cmd
+    .command("import")
+    .description("Import people from a CSV or JSON file")
+    .argument("<files...>", "CSV or JSON files, or a file-name pattern such as 'exports/*.csv'")
+    .option("--template-file <path>", "Render each row through a JSON body template")
+    .option("--dry-run", "Preview without importing")
+    .option("--batch-size <n>", "Records per batch (max 60)")
+    .option("--continue-on-error", "Keep importing after a failed batch")
+    .option("--fail-fast", "Stop at the first failed file instead of importing the rest")
+    .option("--continue-from <index>", "Start at this 1-based record index")
+    .option("--auto-create-company", "Link rows by companyName, creating missing companies");
+  applyGlobalOptions(importCmd);
+  importCmd.action(async (files: string[], options: PeopleImportOptions, command: Command) => {
+    const { globalOptions, services } = createCommandContext(command);
+    const linker = options.autoCreateCompany ? new CompanyLinker(services.records) : undefined;
+    await runImportOperation(
+      {
+        object: "people",
+        arg: files[0],
+        args: files,
+        options,
+        services,
+        globalOptions,
+      },
+      linker ? linker.hooks() : {},
+    );
+  });
+
+  const openCmd = cmd
+    .command("open")
+    .description("Open a person in the web app")
+    .argument("<id>", "Person ID")
+    .option("--print-url", "Print the record URL instead of opening a browser");
+  applyGlobalOptions(openCmd);
+  openCmd.action(async (id: string, options: ApiCommandOptions, command: Command) => {
+    const { globalOptions, services } = createCommandContext(command);
+    await runOpenOperation({ object: "people", arg: id, options, services, globalOptions });
+  });
+
+  const statsCmd = cmd
+    .command("stats")
+    .description("Summarize people: total, recently added, and top companies")
+    .option("--top <n>", "Number of companies to list by people count", DEFAULT_STATS_TOP);
+  applyGlobalOptions(statsCmd);
+  statsCmd.action(async (options: StatsOptions, command: Command) => {
+    const top = Number(options.top);
+    if (!Number.isInteger(top) || top <= 0) {
+      throw new CliError(
+        `Invalid --top value ${JSON.stringify(options.top)}; expected a positive integer.`,
+        "INVALID_ARGUMENTS",
+      );
+    }
+    const { globalOptions, services } = createCommandContext(command);
+
+    const stats = await collectPeopleStats(services.records, { top });
+    await services.output.render(stats, {
+      format: globalOptions.output,
+      query: globalOptions.query,
+    });
+  });
+
+  const timelineCmd = cmd
+    .command("timeline")
+    .description("Show a person's notes, tasks, and activity, newest first")
+    .argument("<id>", "Person ID")
+    .option("--limit <n>", "Maximum number of entries", DEFAULT_TIMELINE_LIMIT)
+    .option("--since <date>", "Only entries at or after an ISO date or an age such as 7d");
+  applyGlobalOptions(timelineCmd);
+  timelineCmd.action(async (id: string, options: TimelineOptions, command: Command) => {
+    const limit = Number(options.limit);
+    if (!Number.isInteger(limit) || limit <= 0) {
+      throw new CliError(
+        `Invalid --limit value ${JSON.stringify(options.limit)}; expected a positive integer.`,
+        "INVALID_ARGUMENTS",
+      );
+    }
+    const since = options.since === undefined ? undefined : parseSince(options.since);
+    if (options.since !== undefined && !since) {
+      throw new CliError(
+        `Invalid --since value ${JSON.stringify(options.since)}.`,
+        "INVALID_ARGUMENTS",
+        "Use an ISO date such as 2024-05-01 or an age such as 12h, 7d, or 2w.",
+      );
+    }
+    const { globalOptions, services } = createCommandContext(command);
+
+    const timeline = await collectPeopleTimeline(services.records, id, { limit, since });
+    // JSON keeps the envelope so scripts can see unavailable sources; the
+    // other formats tabulate the entries and note gaps on stderr.
+    if (globalOptions.output !== "json" && timeline.unavailable) {
+      log("warn", `Timeline is missing ${timeline.unavailable.join(", ")} (not available).`);
+    }
+    await services.output.render(globalOptions.output === "json" ? timeline : timeline.entries, {
+      format: globalOptions.output,
+      query: globalOptions.query,
+    });
+  });
+}
+
+// "email" is the spreadsheet-friendly name for the primary email; any other
+// key names both the column and the field, e.g. linkedinLink.primaryLinkUrl.
+function resolveUpdateKey(key: string): UpdateKey {
+  const column = key.trim();
+  if (!column) {
+    throw new CliError("--key needs a column name.", "INVALID_ARGUMENTS");
+  }
+  return { column, field: column === "email" ? EMAIL_FIELD : column };
+}
+
+function hasInlineChanges(options: ApiCommandOptions): boolean {
+  return [
+    options.data,
+    options.file,
+    options.set,
+    options.clear,
+    options.addTo,
+    options.removeFrom,
+    options.onMissing,
+    options.showDiff,
+  ].some((value) => value !== undefined);
+}
+
+function resolveLookup(options: LookupOptions): UniqueLookup | undefined {
+  if (options.email !== undefined && options.by !== undefined) {
+    throw new CliError("Use only one of --email or --by.", "INVALID_ARGUMENTS");
+  }
+  if (options.email !== undefined) {
+    return { field: EMAIL_FIELD, value: options.email };
+  }
+  if (options.by !== undefined) {
+    const [rawField, value] = splitOnce(options.by, "=");
+    const field = rawField.trim();
+    if (!field || !options.by.includes("=")) {
+      throw new CliError(
+        `Invalid --by value "${options.by}".`,
+        "INVALID_ARGUMENTS",
+        "Use --by <field=value>, e.g. --by jobTitle=CEO.",
+      );
+    }
+    return { field, value };
+  }
+
+  return undefined;
+}
+
+function collect(value: string, previous: string[] = []): string[] {
+  return [...previous, value];
+}
+
+function withAuditFilters(options: ListOptions): string | undefined {
+  const clauses = [
+    ...(options.filter ? [options.filter] : []),
+    ...memberClause("createdBy", "--created-by", options.createdBy),
+    ...memberClause("updatedBy", "--updated-by", options.updatedBy),
+  ];
+  return clauses.length > 1 ? `and(${clauses.join(",")})` : clauses[0];
+}
+
+function memberClause(field: string, flag: string, memberId: string | undefined): string[] {
+  if (memberId === undefined) {
+    return [];
+  }
+  const normalized = memberId.trim().toLowerCase();
+  if (!MEMBER_ID_PATTERN.test(normalized)) {
+    throw new CliError(
+      `Invalid ${flag} value ${JSON.stringify(memberId)}; expected a workspace member ID.`,
+      "INVALID_ARGUMENTS",
+      'Run "twenty api list workspaceMembers" to find member IDs.',
+    );
+  }
+  return [`${field}.workspaceMemberId[eq]:${JSON.stringify(normalized)}`];
+}
@@ -0,0 +1,123 @@
+import { afterEach, beforeEach, describe, expect, it, vi } from "vitest";
+import { Command } from "commander";
+import { registerPingCommand } from "../ping.command";
+import { CliError } from "../../../utilities/errors/cli-error";
+
+const mockCreateCommandContext = vi.hoisted(() => vi.fn());
+
+vi.mock("../../../utilities/shared/context", async () => {
+  const actual = await vi.importActual<typeof import("../../../utilities/shared/context")>(
+    "../../../utilities/shared/context",
+  );
+
+  return {
+    ...actual,
+    createCommandContext: mockCreateCommandContext,
+  };
+});
+
+describe("ping command", () => {
+  let program: Command;
+  let mockGetConfig: ReturnType<typeof vi.fn>;
+  let mockGet: ReturnType<typeof vi.fn>;
+  let mockRender: ReturnType<typeof vi.fn>;
+
+  beforeEach(() => {
+    program = new Command();
+    program.exitOverride();
+    registerPingCommand(program);
+    mockGetConfig = vi.fn().mockResolvedValue({
+      apiUrl: "https://crm.example.com",
+      apiKey: "token",
+      workspace: "default",
+    });
+    mockGet = vi.fn().mockResolvedValue({ status: 200, data: { data: { people: [] } } });
+    mockRender = vi.fn();
+    mockCreateCommandContext.mockReset();
+    mockCreateCommandContext.mockReturnValue({
+      globalOptions: { output: "json", query: undefined },
+      services: {
+        config: { getConfig: mockGetConfig },
+        api: { get: mockGet },
+        output: { render: mockRender },
+      },
+    } as never);
+  });
+
+  afterEach(() => {
+    process.exitCode = undefined;
+  });
+
+  it("reports ok with latency when the authenticated request succeeds", async () => {
+    await program.parseAsync(["node", "test", "ping"]);
+
+    expect(mockGet).toHaveBeenCalledWith("/rest/people", { params: { limit: 1 } });
+    expect(mockCreateCommandContext).toHaveBeenCalledWith(expect.any(Command), { noRetry: true });
+    expect(mockRender).toHaveBeenCalledWith(
+      expect.objectContaining({
+        status: "ok",
+        ok: true,
+        workspace: "default",
+        apiUrl: "https://crm.example.com",
+        httpStatus: 200,
+        latencyMs: expect.any(Number),
+      }),
+      { format: "json", query: undefined },
+    );
+    expect(process.exitCode).toBeUndefined();
+  });
+
+  it("reports auth_failed and exits 3 when the token is rejected", async () => {
+    mockGet.mockRejectedValue({ isAxiosError: true, message: "401", response: { status: 401 } });
+
+    await program.parseAsync(["node", "test", "ping"]);
+
+    expect(mockRender).toHaveBeenCalledWith(
+      expect.objectContaining({ status: "auth_failed", ok: false, httpStatus: 401 }),
+      expect.anything(),
+    );
+    expect(process.exitCode).toBe(3);
+  });
+
+  it("reports auth_failed when no API token is configured", async () => {
+    mockGetConfig.mockRejectedValue(new CliError("Missing API token.", "AUTH"));
+
+    await program.parseAsync(["node", "test", "ping"]);
+
+    expect(mockGet).not.toHaveBeenCalled();
+    expect(mockRender).toHaveBeenCalledWith(
+      expect.objectContaining({ status: "auth_failed", message: "Auth failed: Missing API token." }),
+      expect.anything(),
+    );
+    expect(process.exitCode).toBe(3);
+  });
+
+  it("reports unreachable and exits 4 on network errors", async () => {
+    mockGet.mockRejectedValue({ isAxiosError: true, message: "connect ECONNREFUSED" });
+
+    await program.parseAsync(["node", "test", "ping"]);
+
+    expect(mockRender).toHaveBeenCalledWith(
+      expect.objectContaining({
+        status: "unreachable",
+        ok: false,
+        apiUrl: "https://crm.example.com",
+        message: "Network unreachable: connect ECONNREFUSED",
+      }),
+      expect.anything(),
+    );
+    expect(process.exitCode).toBe(4);
+  });
+
+  it("reports other HTTP failures as errors with exit code 1", async () => {
+    mockGet.mockRejectedValue({ isAxiosError: true, message: "500", response: { status: 500 } });
+
+    await program.parseAsync(["node", "test", "ping"]);
+
+    expect(mockRender).toHaveBeenCalledWith(
+      expect.objectContaining({ status: "error", httpStatus: 500 }),
+      expect.anything(),
+    );
+    expect(process.exitCode).toBe(1);
+  });
+});
@@ -0,0 +1,112 @@
+import { Command } from "commander";
+import { AxiosError } from "axios";
+import { CliError } from "../../utilities/errors/cli-error";
+import { applyGlobalOptions } from "../../utilities/shared/global-options";
+import { createCommandContext } from "../../utilities/shared/context";
+import { CliServices } from "../../utilities/shared/services";
+
+export type PingStatus = "ok" | "auth_failed" | "unreachable" | "error";
+
+export interface PingResult {
+  status: PingStatus;
+  ok: boolean;
+  workspace?: string;
+  apiUrl?: string;
+  latencyMs: number;
+  httpStatus?: number;
+  message: string;
+}
+
+// Exit codes match toExitCode so scripts can treat AUTH/NETWORK the same way
+// whether they come from ping or any other command.
+const PING_EXIT_CODES: Record<PingStatus, number> = {
+  ok: 0,
+  error: 1,
+  auth_failed: 3,
+  unreachable: 4,
+};
+
+export function registerPingCommand(program: Command): void {
+  const cmd = program
+    .command("ping")
+    .description("Check that the instance is reachable and the API token is accepted");
+  applyGlobalOptions(cmd);
+
+  cmd.action(async (_options: Record<string, unknown>, command: Command) => {
+    // One request, no retries: backoff would delay the verdict on a down host
+    // by several seconds and inflate the reported latency.
+    const { globalOptions, services } = createCommandContext(command, { noRetry: true });
+    const result = await ping(services, globalOptions.workspace);
+
+    await services.output.render(result, {
+      format: globalOptions.output,
+      query: globalOptions.query,
+    });
+
+    if (!result.ok) {
+      process.exitCode = PING_EXIT_CODES[result.status];
+    }
+  });
+}
+
+export async function ping(services: CliServices, workspace?: string): Promise<PingResult> {
+  const startedAt = Date.now();
+  const elapsed = () => Date.now() - startedAt;
+  let target: Pick<PingResult, "workspace" | "apiUrl"> = {};
+
+  try {
+    const config = await services.config.getConfig({ workspace });
+    target = { workspace: config.workspace, apiUrl: config.apiUrl };
+    // A one-record list is the cheapest request that still exercises auth.
+    const response = await services.api.get("/rest/people", { params: { limit: 1 } });
+    const latencyMs = elapsed();
+
+    return {
+      status: "ok",
+      ok: true,
+      ...target,
+      latencyMs,
+      httpStatus: response.status,
+      message: `OK: authenticated to ${config.apiUrl} in ${latencyMs}ms.`,
+    };
+  } catch (error) {
+    return { ...target, ...classifyPingError(error), latencyMs: elapsed() };
+  }
+}
+
+function classifyPingError(
+  error: unknown,
+): Pick<PingResult, "status" | "ok" | "httpStatus" | "message"> {
+  if (error instanceof CliError && error.code === "AUTH") {
+    return { status: "auth_failed", ok: false, message: `Auth failed: ${error.message}` };
+  }
+
+  const axiosError = error as AxiosError;
+  if (axiosError?.isAxiosError !== true) {
+    throw error;
+  }
+
+  const httpStatus = axiosError.response?.status;
+  if (!httpStatus) {
+    return {
+      status: "unreachable",
+      ok: false,
+      message: `Network unreachable: ${axiosError.message}`,
+    };
+  }
+  if (httpStatus === 401 || httpStatus === 403) {
+    return {
+      status: "auth_failed",
+      ok: false,
+      httpStatus,
+      message: `Auth failed: the server rejected the API token (HTTP ${httpStatus}).`,
+    };
+  }
+
+  return {
+    status: "error",
+    ok: false,
+    httpStatus,
+    message: `Request failed with status ${httpStatus}.`,
+  };
+}
@@ -111,6 +111,29 @@ describe("rest command", () => {
     });
   });
 
+  describe("envelope unwrapping", () => {
+    it("passes --unwrap through to the output service options", async () => {
+      await program.parseAsync(["node", "test", "raw", "rest", "GET", "/people", "--unwrap"]);
+
+      expect(createServices).toHaveBeenCalledWith(expect.objectContaining({ unwrap: true }));
+    });
+
+    it("keeps the raw envelope by default", async () => {
+      await program.parseAsync(["node", "test", "raw", "rest", "GET", "/people"]);
+
+      expect(createServices).toHaveBeenCalledWith(expect.objectContaining({ unwrap: false }));
+    });
+
+    it("always unwraps list envelopes for jsonl output", async () => {
+      await program.parseAsync(["node", "test", "raw", "rest", "GET", "/people", "-o", "jsonl"]);
+
+      expect(mockServices.output.render).toHaveBeenCalledWith(
+        { id: "test-id" },
+        { format: "jsonl", query: undefined, unwrap: true },
+      );
+    });
+  });
+
   describe("POST request", () => {
     it("makes POST request with JSON data", async () => {
       vi.mocked(readJsonInput).mockResolvedValue({ name: "John Doe" });
@@ -158,6 +181,81 @@ describe("rest command", () => {
     });
   });
 
+  describe("form-encoded body", () => {
+    it("encodes a flat --data object when the content type is form", async () => {
+      vi.mocked(readJsonInput).mockResolvedValue({
+        grant_type: "refresh_token",
+        token: "a b&c",
+        remember: true,
+      });
+
+      await program.parseAsync([
+        "node",
+        "test",
+        "raw",
+        "rest",
+        "POST",
+        "/auth/token",
+        "--data",
+        '{"grant_type":"refresh_token","token":"a b&c","remember":true}',
+        "--content-type",
+        "application/x-www-form-urlencoded",
+      ]);
+
+      expect(mockServices.api.request).toHaveBeenCalledWith({
+        method: "post",
+        url: "/auth/token",
+        params: undefined,
+        data: "grant_type=refresh_token&token=a+b%26c&remember=true",
+        headers: { "Content-Type": "application/x-www-form-urlencoded" },
+      });
+    });
+
+    it("rejects nested values when form encoding", async () => {
+      vi.mocked(readJsonInput).mockResolvedValue({ name: { first: "Ada" } });
+
+      await expect(
+        program.parseAsync([
+          "node",
+          "test",
+          "raw",
+          "rest",
+          "POST",
+          "/auth/token",
+          "--data",
+          '{"name":{"first":"Ada"}}',
+          "--content-type",
+          "application/x-www-form-urlencoded; charset=utf-8",
+        ]),
+      ).rejects.toThrow('Form payload field "name" must be a string, number, or boolean');
+      expect(mockServices.api.request).not.toHaveBeenCalled();
+    });
+
+    it("passes other content types through with the JSON payload", async () => {
+      vi.mocked(readJsonInput).mockResolvedValue({ name: "Acme" });
+
+      await program.parseAsync([
+        "node",
+        "test",
+        "raw",
+        "rest",
+        "POST",
+        "/companies",
+        "--data",
+        '{"name":"Acme"}',
+        "--content-type",
+        "application/merge-patch+json",
+      ]);
+
+      expect(mockServices.api.request).toHaveBeenCalledWith(
+        expect.objectContaining({
+          data: { name: "Acme" },
+          headers: { "Content-Type": "application/merge-patch+json" },
+        }),
+      );
+    });
+  });
+
   describe("PATCH request", () => {
     it("makes PATCH request with JSON data", async () => {
       vi.mocked(readJsonInput).mockResolvedValue({ name: "Updated Name" });
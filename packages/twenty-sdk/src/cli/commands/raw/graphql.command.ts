@@ -8,6 +8,7 @@ import { createServices } from "../../utilities/shared/services";
 import fs from "fs-extra";
 import { readFileOrStdin, readJsonInput } from "../../utilities/shared/io";
 import { CliError } from "../../utilities/errors/cli-error";
+import { log } from "../../utilities/shared/logger";
 import { resolveOperationAlias } from "../../utilities/shared/command-aliases";
 
 const GRAPHQL_OPERATIONS = ["query", "mutate", "schema"] as const;
@@ -116,8 +117,7 @@ async function outputGraphqlResult(
   if (outputFile) {
     const content = typeof data === "string" ? data : JSON.stringify(data, null, 2);
     await fs.writeFile(outputFile, content);
-    // eslint-disable-next-line no-console
-    console.error(`Wrote schema output to ${outputFile}`);
+    log("info", `Wrote schema output to ${outputFile}`, { path: outputFile });
     return;
   }
 
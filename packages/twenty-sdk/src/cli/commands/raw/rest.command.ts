@@ -2,7 +2,8 @@ import { Command } from "commander";
 import { applyGlobalOptions, resolveGlobalOptions } from "../../utilities/shared/global-options";
 import { createServices } from "../../utilities/shared/services";
 import { readJsonInput } from "../../utilities/shared/io";
-import { parseKeyValuePairs } from "../../utilities/shared/parse";
+import { encodeFormBody, FORM_CONTENT_TYPE } from "../../utilities/shared/body";
+import { parseQueryParams } from "../../utilities/shared/parse";
 
 export function registerRestCommand(parent: Command): void {
   const cmd = parent
@@ -12,7 +13,11 @@ export function registerRestCommand(parent: Command): void {
     .argument("<path>", "REST path")
     .option("-d, --data <json>", "JSON payload")
     .option("-f, --file <path>", "JSON file payload (use - for stdin)")
-    .option("--param <key=value>", "Query param", collect);
+    .option("--param <key=value>", "Query param", collect)
+    .option(
+      "--content-type <type>",
+      `Request Content-Type; ${FORM_CONTENT_TYPE} form-encodes a flat --data object`,
+    );
 
   applyGlobalOptions(cmd);
 
@@ -20,37 +25,50 @@ export function registerRestCommand(parent: Command): void {
     async (
       method: string,
       path: string,
-      options: { data?: string; file?: string; param?: string[] } | Command,
+      options: RestCommandOptions | Command,
       command?: Command,
     ) => {
       const resolvedCommand = command ?? (options instanceof Command ? options : cmd);
       const globalOptions = resolveGlobalOptions(resolvedCommand);
       const services = createServices(globalOptions);
 
-      const rawOptions = resolvedCommand.opts() as {
-        data?: string;
-        file?: string;
-        param?: string[];
-      };
+      const rawOptions = resolvedCommand.opts() as RestCommandOptions;
       const payload = await readJsonInput(rawOptions.data, rawOptions.file);
-      const params = normalizeQueryParams(parseKeyValuePairs(rawOptions.param));
+      const contentType = rawOptions.contentType;
+      const params = normalizeQueryParams(parseQueryParams(rawOptions.param));
       const url = path.startsWith("/") ? path : `/${path}`;
 
       const response = await services.api.request({
         method: method.toLowerCase(),
         url,
         params: Object.keys(params).length ? params : undefined,
-        data: payload,
+        data: isFormContentType(contentType) ? encodeFormBody(payload) : payload,
+        ...(contentType ? { headers: { "Content-Type": contentType } } : {}),
       });
 
+      // jsonl emits one line per record, so list envelopes ({data: {people: [...]}})
+      // are always unwrapped; other formats keep the raw envelope unless --unwrap.
       await services.output.render(response.data, {
         format: globalOptions.output,
         query: globalOptions.query,
+        ...(globalOptions.output === "jsonl" ? { unwrap: true } : {}),
       });
     },
   );
 }
 
+interface RestCommandOptions {
+  data?: string;
+  file?: string;
+  param?: string[];
+  contentType?: string;
+}
+
+// Compare the media type only, so "...; charset=utf-8" still form-encodes.
+function isFormContentType(contentType: string | undefined): boolean {
+  return contentType?.split(";")[0]?.trim().toLowerCase() === FORM_CONTENT_TYPE;
+}
+
 function collect(value: string, previous: string[] = []): string[] {
   return previous.concat([value]);
 }
@@ -2,7 +2,7 @@ import { Command } from "commander";
 import { CliError } from "../../utilities/errors/cli-error";
 import { applyGlobalOptions } from "../../utilities/shared/global-options";
 import { readJsonInput } from "../../utilities/shared/io";
-import { parseKeyValuePairs } from "../../utilities/shared/parse";
+import { parseKeyValuePairs, parseQueryParams } from "../../utilities/shared/parse";
 import { createCommandContext } from "../../utilities/shared/context";
 
 interface RouteInvokeOptions {
@@ -34,7 +34,7 @@ export function registerRoutesCommand(program: Command): void {
     const { globalOptions, services } = createCommandContext(command);
     const method = normalizeMethod(options.method);
     const payload = await readPayload(method, options);
-    const params = normalizeQueryParams(parseKeyValuePairs(options.param));
+    const params = normalizeQueryParams(parseQueryParams(options.param));
 
     const response = await services.publicHttp.request({
       authMode: "optional",
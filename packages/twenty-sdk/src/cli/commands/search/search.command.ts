@@ -36,7 +36,7 @@ export function registerSearchCommand(program: Command): void {
 
     const output = options.includePageInfo
       ? response
-      : globalOptions.output === "text"
+      : globalOptions.output === "text" || globalOptions.output === "table"
         ? formatTextSearchResults(response.data, query)
         : response.data;
 
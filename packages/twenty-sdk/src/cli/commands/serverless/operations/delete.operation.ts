@@ -1,6 +1,7 @@
 import { Command } from "commander";
 import { CliError } from "../../../utilities/errors/cli-error";
 import { requireYes } from "../../../utilities/shared/confirmation";
+import { printStatus } from "../../../utilities/output/services/status-printer";
 import { createServerlessOperationContext, executeCompatibleOperation } from "../serverless.shared";
 
 export async function runServerlessDeleteOperation(
@@ -28,6 +29,14 @@ export async function runServerlessDeleteOperation(
     },
   });
 
-  // eslint-disable-next-line no-console
-  console.log(`Serverless function ${id} deleted.`);
+  printStatus(
+    context.globalOptions,
+    {
+      action: "deleted",
+      object: "serverless",
+      id,
+      message: `Serverless function ${id} deleted.`,
+    },
+    context.services.writer,
+  );
 }
@@ -245,6 +245,7 @@ export async function streamLogicFunctionLogs(
   const subscription = new MetadataSubscriptionService(undefined, {
     workspace: context.globalOptions.workspace,
     debug: context.globalOptions.debug,
+    allowInsecureHttp: context.globalOptions.allowInsecureHttp,
   });
   const controller = new AbortController();
   const collected: unknown[] = [];
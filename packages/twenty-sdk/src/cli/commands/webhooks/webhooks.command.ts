@@ -3,6 +3,7 @@ import { requireGraphqlField, type GraphQLResponse } from "../../utilities/api/g
 import { CliError } from "../../utilities/errors/cli-error";
 import { applyGlobalOptions } from "../../utilities/shared/global-options";
 import { createCommandContext } from "../../utilities/shared/context";
+import { printStatus } from "../../utilities/output/services/status-printer";
 import { parseBody } from "../../utilities/shared/body";
 
 interface WebhooksOptions {
@@ -124,7 +125,7 @@ export function registerWebhooksCommand(program: Command): void {
     .argument("[id]", "Webhook ID");
   applyGlobalOptions(deleteCmd);
   deleteCmd.action(async (id: string | undefined, _options: unknown, command: Command) => {
-    const { services } = createCommandContext(command);
+    const { globalOptions, services } = createCommandContext(command);
     if (!id) throw new CliError("Missing webhook ID.", "INVALID_ARGUMENTS");
     const response = await services.api.post<GraphQLResponse<{ deleteWebhook: boolean }>>(
       endpoint,
@@ -141,7 +142,15 @@ export function registerWebhooksCommand(program: Command): void {
     if (!deleted) {
       throw new CliError(`Failed to delete webhook ${id}.`, "API_ERROR");
     }
-    // eslint-disable-next-line no-console
-    console.log(`Webhook ${id} deleted.`);
+    printStatus(
+      globalOptions,
+      {
+        action: "deleted",
+        object: "webhooks",
+        id,
+        message: `Webhook ${id} deleted.`,
+      },
+      services.writer,
+    );
   });
 }
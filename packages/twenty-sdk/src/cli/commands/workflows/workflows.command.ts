@@ -4,7 +4,7 @@ import { PublicHttpService } from "../../utilities/api/services/public-http.serv
 import { CliError } from "../../utilities/errors/cli-error";
 import { applyGlobalOptions, resolveGlobalOptions } from "../../utilities/shared/global-options";
 import { readJsonInput } from "../../utilities/shared/io";
-import { parseKeyValuePairs } from "../../utilities/shared/parse";
+import { parseQueryParams } from "../../utilities/shared/parse";
 import { createServices } from "../../utilities/shared/services";
 import { createCommandContext } from "../../utilities/shared/context";
 
@@ -72,7 +72,7 @@ export function registerWorkflowsCommand(program: Command): void {
       const method = normalizeMethod(options.method);
       const workspaceId = await resolveWorkspaceId(options.workspaceId, services.publicHttp);
       const payload = await readPayload(method, options);
-      const params = normalizeQueryParams(parseKeyValuePairs(options.param));
+      const params = normalizeQueryParams(parseQueryParams(options.param));
 
       const response = await services.publicHttp.request({
         authMode: "optional",
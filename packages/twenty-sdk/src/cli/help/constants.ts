@@ -81,11 +81,16 @@ export const OUTPUT_CONTRACT: HelpOutputContract = {
     },
     {
       name: "csv",
-      summary: "Wraps singleton values as one record and JSON-encodes nested values.",
+      summary:
+        "Wraps singleton values as one record and JSON-encodes nested values unless --flatten is set.",
     },
     {
       name: "text",
-      summary: "Best-effort table rendering for objects and arrays.",
+      summary: "Key/value detail view for a single record; lists render as a table.",
+    },
+    {
+      name: "table",
+      summary: "Column table rendering for objects and arrays.",
     },
   ],
 };
@@ -171,6 +176,7 @@ export const METADATA: Record<string, HelpMetadata> = {
     ],
     examples: [
       "twenty api list people --limit 10 -o json",
+      "twenty api export people --all --stream --output-file people.ndjson",
       'twenty api create notes --data \'{"title":"Hello"}\'',
     ],
   },
@@ -505,6 +511,109 @@ export const METADATA: Record<string, HelpMetadata> = {
       { name: "delete", summary: "Delete a webhook", mutates: true },
     ],
   },
+  "twenty opportunities": {
+    operations: [
+      {
+        name: "close",
+        summary: "Set a closed stage, probability, and close date",
+        mutates: true,
+      },
+      {
+        name: "export",
+        summary: "Export opportunities as JSON or CSV with formatted currency columns",
+        mutates: false,
+      },
+      {
+        name: "import",
+        summary: "Import opportunities from CSV or JSON, converting amounts to micros",
+        mutates: true,
+      },
+      {
+        name: "batch-create",
+        summary: "Create several opportunities from a JSON array or CSV file",
+        mutates: true,
+      },
+    ],
+    examples: [
+      "twenty opportunities export --all --format csv --output-file opportunities.csv",
+      "twenty opportunities import ./deals.csv --dry-run",
+      "twenty opportunities close <opportunity-id> --won",
+      "twenty opportunities close <opportunity-id> --lost --close-date 2026-01-31",
+    ],
+  },
+  "twenty people": {
+    operations: [
+      {
+        name: "get",
+        summary: "Get one person by ID, primary email, or a unique field",
+        mutates: false,
+      },
+      {
+        name: "list",
+        summary: "List people, as a browser page with --output html or unique values with --distinct",
+        mutates: false,
+      },
+      {
+        name: "ensure",
+        summary: "Get a person by email or unique field, creating it when missing",
+        mutates: true,
+      },
+      {
+        name: "export",
+        summary: "Export people as JSON, CSV, or Parquet, optionally split across numbered files",
+        mutates: false,
+      },
+      {
+        name: "update",
+        summary: "Update a person; --add-to/--remove-from merge array fields",
+        mutates: true,
+      },
+      {
+        name: "batch-create",
+        summary: "Create many people from a JSON array or NDJSON streamed on stdin",
+        mutates: true,
+      },
+      {
+        name: "import",
+        summary: "Import people from CSV or JSON files, optionally through a body template",
+        mutates: true,
+      },
+      {
+        name: "stats",
+        summary: "Summarize total people, recent additions, and top companies",
+        mutates: false,
+      },
+    ],
+    examples: [
+      "twenty people get --email john@example.com",
+      'twenty people ensure --email john@example.com --data \'{"jobTitle":"CEO"}\'',
+      "twenty people get --by jobTitle=CEO --include company",
+      "twenty people list --output html --output-file report.html --open",
+      "twenty people list --distinct city --with-counts -o csv",
+      "twenty people update <id> --add-to emails.additionalEmails=ann@example.com",
+      "twenty people update --from-file updates.csv --key email --dry-run",
+      "twenty people export --all --format csv --split-size 10000",
+      "twenty people export --all --format parquet --output-file people.parquet",
+      "twenty api export people --all --stream | twenty people batch-create --stdin-jsonl",
+      "twenty people import ./people.csv --template-file person.tmpl --dry-run",
+      "twenty people import 'exports/*.csv' --fail-fast",
+      "twenty people import ./leads.csv --auto-create-company",
+      "twenty people stats --top 10 -o json",
+    ],
+  },
+  "twenty config": {
+    operations: [
+      {
+        name: "doctor",
+        summary: "Validate output, credentials, base URL, token, and reachability",
+        mutates: false,
+      },
+    ],
+    examples: ["twenty config doctor", "twenty config doctor -o table --profile staging"],
+  },
+  "twenty ping": {
+    examples: ["twenty ping", "twenty ping -o json --profile staging"],
+  },
   "twenty route-triggers": {
     operations: [
       { name: "list", summary: "List route triggers", mutates: false },
@@ -584,6 +693,17 @@ export const METADATA: Record<string, HelpMetadata> = {
       "twenty event-logs list --table object-event --object-metadata-id <object-metadata-id>",
     ],
   },
+  "twenty jobs": {
+    operations: [
+      { name: "list", summary: "List jobs in a background queue", mutates: false },
+      { name: "cancel", summary: "Remove queued jobs from their queue", mutates: true },
+    ],
+    examples: [
+      "twenty jobs list --queue workflow-queue",
+      "twenty jobs list --queue webhook-queue --state failed -o json",
+      "twenty jobs cancel <job-id> --queue workflow-queue --yes",
+    ],
+  },
   "twenty openapi": {
     examples: [
       "twenty openapi core",
@@ -597,4 +717,14 @@ export const METADATA: Record<string, HelpMetadata> = {
       { name: "public-asset", summary: "Download a public asset", mutates: false },
     ],
   },
+  "twenty attachments": {
+    operations: [
+      { name: "list", summary: "List the attachments of a record", mutates: false },
+      { name: "download", summary: "Download an attachment's file", mutates: false },
+    ],
+    examples: [
+      "twenty attachments list --record-id <person-id> --object person",
+      "twenty attachments download <attachment-id> --out contract.pdf",
+    ],
+  },
 };
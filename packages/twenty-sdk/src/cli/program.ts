@@ -6,6 +6,7 @@ import { registerApiMetadataCommand } from "./commands/api-metadata/api-metadata
 import { registerRawCommand } from "./commands/raw/raw.command";
 import { registerGraphqlCommand } from "./commands/graphql/graphql.command";
 import { registerAuthCommand } from "./commands/auth/auth.command";
+import { registerConfigCommand } from "./commands/config/config.command";
 import { registerSearchCommand } from "./commands/search/search.command";
 import { registerWebhooksCommand } from "./commands/webhooks/webhooks.command";
 import { registerApiKeysCommand } from "./commands/api-keys/api-keys.command";
@@ -14,8 +15,13 @@ import { registerConnectedAccountsCommand } from "./commands/connected-accounts/
 import { registerDashboardsCommand } from "./commands/dashboards/dashboards.command";
 import { registerEmailingDomainsCommand } from "./commands/emailing-domains/emailing-domains.command";
 import { registerEventLogsCommand } from "./commands/event-logs/event-logs.command";
+import { registerJobsCommand } from "./commands/jobs/jobs.command";
 import { registerFilesCommand } from "./commands/files/files.command";
+import { registerAttachmentsCommand } from "./commands/attachments/attachments.command";
 import { registerMessageChannelsCommand } from "./commands/message-channels/message-channels.command";
+import { registerOpportunitiesCommand } from "./commands/opportunities/opportunities.command";
+import { registerPeopleCommand } from "./commands/people/people.command";
+import { registerPingCommand } from "./commands/ping/ping.command";
 import { registerPostgresProxyCommand } from "./commands/postgres-proxy/postgres-proxy.command";
 import { registerRolesCommand } from "./commands/roles/roles.command";
 import { registerPublicDomainsCommand } from "./commands/public-domains/public-domains.command";
@@ -41,6 +47,8 @@ export function buildProgram(): Command {
   program.description("Twenty CLI (TypeScript port)");
   program.version(CLI_VERSION);
   program.option("--env-file <path>", "Load environment variables from file");
+  program.option("--profile <name>", "Workspace profile for all subcommands");
+  program.option("--no-retry", "Disable automatic retry for all subcommands");
   program.exitOverride();
 
   registerApiCommand(program);
@@ -50,6 +58,7 @@ export function buildProgram(): Command {
   registerRawCommand(program);
   registerGraphqlCommand(program);
   registerAuthCommand(program);
+  registerConfigCommand(program);
   registerSearchCommand(program);
   registerWebhooksCommand(program);
   registerApiKeysCommand(program);
@@ -58,8 +67,13 @@ export function buildProgram(): Command {
   registerDashboardsCommand(program);
   registerEmailingDomainsCommand(program);
   registerEventLogsCommand(program);
+  registerJobsCommand(program);
   registerFilesCommand(program);
+  registerAttachmentsCommand(program);
   registerMessageChannelsCommand(program);
+  registerOpportunitiesCommand(program);
+  registerPeopleCommand(program);
+  registerPingCommand(program);
   registerOpenApiCommand(program);
   registerCoverageCommand(program);
   registerSchemaCommand(program);
@@ -0,0 +1,55 @@
+import {
+  AxiosAdapter,
+  AxiosError,
+  AxiosResponse,
+  AxiosResponseHeaders,
+  InternalAxiosRequestConfig,
+} from "axios";
+
+export interface MockAdapterResponse {
+  status?: number;
+  data?: unknown;
+  headers?: Record<string, string>;
+}
+
+export type MockAdapterHandler = (
+  config: InternalAxiosRequestConfig,
+) => MockAdapterResponse | Promise<MockAdapterResponse>;
+
+export interface MockAdapter extends AxiosAdapter {
+  requests: InternalAxiosRequestConfig[];
+}
+
+// Builds an axios adapter that answers requests in-process, so HTTP services can
+// be exercised end to end (interceptors, retries, auth headers) without a server.
+export function createMockAdapter(handler: MockAdapterHandler): MockAdapter {
+  const requests: InternalAxiosRequestConfig[] = [];
+
+  const adapter = (async (config: InternalAxiosRequestConfig) => {
+    requests.push(config);
+    const result = await handler(config);
+    const response: AxiosResponse = {
+      data: result.data ?? null,
+      status: result.status ?? 200,
+      statusText: "",
+      headers: (result.headers ?? {}) as AxiosResponseHeaders,
+      config,
+      request: {},
+    };
+
+    if (!config.validateStatus || config.validateStatus(response.status)) {
+      return response;
+    }
+
+    throw new AxiosError(
+      `Request failed with status code ${response.status}`,
+      response.status >= 500 ? AxiosError.ERR_BAD_RESPONSE : AxiosError.ERR_BAD_REQUEST,
+      config,
+      response.request,
+      response,
+    );
+  }) as MockAdapter;
+  adapter.requests = requests;
+
+  return adapter;
+}
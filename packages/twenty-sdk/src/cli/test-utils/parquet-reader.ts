@@ -0,0 +1,199 @@
+// Reads back the Parquet files the export writer produces, so tests can check
+// values rather than bytes. It decodes the footer and pages from the
+// parquet.thrift definitions on its own, without the writer's encoder, and
+// supports what the writer emits: optional flat columns, one PLAIN data page
+// (v1) per column chunk, no compression.
+export interface ParquetFileContents {
+  numRows: number;
+  createdBy?: string;
+  columns: { name: string; type: number; convertedType?: number }[];
+  rowGroups: number;
+  rows: Record<string, unknown>[];
+}
+
+const TYPE_BOOLEAN = 0;
+const TYPE_INT64 = 2;
+const TYPE_DOUBLE = 5;
+const TYPE_BYTE_ARRAY = 6;
+const CONVERTED_UTF8 = 0;
+const CONVERTED_TIMESTAMP_MILLIS = 9;
+
+type ThriftStruct = Map<number, unknown>;
+
+export function readParquet(buffer: Buffer): ParquetFileContents {
+  if (buffer.subarray(0, 4).toString() !== "PAR1" || buffer.subarray(-4).toString() !== "PAR1") {
+    throw new Error("Not a Parquet file");
+  }
+  const footerLength = buffer.readUInt32LE(buffer.length - 8);
+  const footerStart = buffer.length - 8 - footerLength;
+  const meta = new CompactReader(buffer, footerStart).struct();
+
+  const schema = (meta.get(2) as ThriftStruct[]).slice(1);
+  const columns = schema.map((element) => ({
+    name: (element.get(4) as Buffer).toString("utf-8"),
+    type: element.get(1) as number,
+    ...(element.has(6) ? { convertedType: element.get(6) as number } : {}),
+  }));
+
+  const rows: Record<string, unknown>[] = [];
+  const rowGroups = (meta.get(4) as ThriftStruct[] | undefined) ?? [];
+  for (const group of rowGroups) {
+    const count = Number(group.get(3));
+    const groupRows = Array.from({ length: count }, () => ({}) as Record<string, unknown>);
+    (group.get(1) as ThriftStruct[]).forEach((chunk, i) => {
+      const chunkMeta = chunk.get(3) as ThriftStruct;
+      const values = readColumnChunk(buffer, Number(chunkMeta.get(9)), columns[i]!);
+      values.forEach((value, row) => {
+        groupRows[row]![columns[i]!.name] = value;
+      });
+    });
+    rows.push(...groupRows);
+  }
+
+  return {
+    numRows: Number(meta.get(3)),
+    ...(meta.has(6) ? { createdBy: (meta.get(6) as Buffer).toString("utf-8") } : {}),
+    columns,
+    rowGroups: rowGroups.length,
+    rows,
+  };
+}
+
+function readColumnChunk(
+  buffer: Buffer,
+  offset: number,
+  column: ParquetFileContents["columns"][number],
+): unknown[] {
+  const reader = new CompactReader(buffer, offset);
+  const header = reader.struct();
+  const numValues = (header.get(5) as ThriftStruct).get(1) as number;
+  let position = reader.position;
+
+  const levelsLength = buffer.readUInt32LE(position);
+  position += 4;
+  const levels = decodeBitWidthOne(buffer.subarray(position, position + levelsLength), numValues);
+  position += levelsLength;
+
+  const present = levels.filter((level) => level === 1).length;
+  const values: unknown[] = [];
+  for (let i = 0; i < present; i++) {
+    switch (column.type) {
+      case TYPE_BOOLEAN:
+        values.push(((buffer[position + (i >> 3)]! >> (i & 7)) & 1) === 1);
+        break;
+      case TYPE_INT64: {
+        const millis = Number(buffer.readBigInt64LE(position + i * 8));
+        values.push(
+          column.convertedType === CONVERTED_TIMESTAMP_MILLIS
+            ? new Date(millis).toISOString()
+            : millis,
+        );
+        break;
+      }
+      case TYPE_DOUBLE:
+        values.push(buffer.readDoubleLE(position + i * 8));
+        break;
+      case TYPE_BYTE_ARRAY: {
+        const length = buffer.readUInt32LE(position);
+        const bytes = buffer.subarray(position + 4, position + 4 + length);
+        values.push(column.convertedType === CONVERTED_UTF8 ? bytes.toString("utf-8") : bytes);
+        position += 4 + length;
+        break;
+      }
+      default:
+        throw new Error(`Unsupported physical type ${column.type}`);
+    }
+  }
+
+  let next = 0;
+  return levels.map((level) => (level === 1 ? values[next++] : null));
+}
+
+// RLE/bit-packed hybrid runs for bit width 1 (definition levels of a flat
+// optional column).
+function decodeBitWidthOne(data: Buffer, count: number): number[] {
+  const reader = new CompactReader(data, 0);
+  const levels: number[] = [];
+  while (levels.length < count && reader.position < data.length) {
+    const header = reader.varint();
+    if (header & 1) {
+      const bytes = header >> 1;
+      for (let i = 0; i < bytes * 8; i++) {
+        levels.push((data[reader.position + (i >> 3)]! >> (i & 7)) & 1);
+      }
+      reader.position += bytes;
+    } else {
+      const value = data[reader.position]! & 1;
+      reader.position += 1;
+      levels.push(...Array.from({ length: header >> 1 }, () => value));
+    }
+  }
+  return levels.slice(0, count);
+}
+
+class CompactReader {
+  constructor(
+    private readonly buffer: Buffer,
+    public position: number,
+  ) {}
+
+  struct(): ThriftStruct {
+    const fields: ThriftStruct = new Map();
+    let lastId = 0;
+    for (;;) {
+      const byte = this.buffer[this.position++]!;
+      if (byte === 0) {
+        return fields;
+      }
+      const type = byte & 0x0f;
+      const delta = byte >> 4;
+      const id = delta !== 0 ? lastId + delta : unzigzag(this.varint());
+      fields.set(id, this.value(type));
+      lastId = id;
+    }
+  }
+
+  varint(): number {
+    let result = 0;
+    let shift = 0;
+    for (;;) {
+      const byte = this.buffer[this.position++]!;
+      result += (byte & 0x7f) * 2 ** shift;
+      if ((byte & 0x80) === 0) {
+        return result;
+      }
+      shift += 7;
+    }
+  }
+
+  private value(type: number): unknown {
+    switch (type) {
+      case 1:
+        return true;
+      case 2:
+        return false;
+      case 5:
+      case 6:
+        return unzigzag(this.varint());
+      case 8: {
+        const length = this.varint();
+        const bytes = this.buffer.subarray(this.position, this.position + length);
+        this.position += length;
+        return bytes;
+      }
+      case 9: {
+        const header = this.buffer[this.position++]!;
+        const size = header >> 4 === 15 ? this.varint() : header >> 4;
+        return Array.from({ length: size }, () => this.value(header & 0x0f));
+      }
+      case 12:
+        return this.struct();
+      default:
+        throw new Error(`Unsupported Thrift compact type ${type}`);
+    }
+  }
+}
+
+function unzigzag(value: number): number {
+  return value % 2 === 0 ? value / 2 : -(value + 1) / 2;
+}
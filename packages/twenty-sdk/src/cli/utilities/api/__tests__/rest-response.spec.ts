@@ -4,7 +4,9 @@ import {
   extractDeleteResult,
   extractFirstValue,
   extractResource,
+  findRecordArray,
   getDataSection,
+  unwrapRestEnvelope,
 } from "../rest-response";
 
 describe("REST response helpers", () => {
@@ -50,4 +52,36 @@ describe("REST response helpers", () => {
     expect(extractDeleteResult({ data: true })).toBe(true);
     expect(extractDeleteResult({})).toBe(false);
   });
+
+  it("unwraps data and single-resource envelopes but leaves other payloads alone", () => {
+    expect(unwrapRestEnvelope({ data: { person: { id: "1" } } })).toEqual({ id: "1" });
+    expect(
+      unwrapRestEnvelope({ data: { people: [{ id: "1" }] }, pageInfo: {}, totalCount: 1 }),
+    ).toEqual([{ id: "1" }]);
+    expect(unwrapRestEnvelope({ data: { success: true } })).toEqual({ success: true });
+    expect(unwrapRestEnvelope({ data: { a: [], b: [] } })).toEqual({ a: [], b: [] });
+    expect(unwrapRestEnvelope({ id: "1", data: { payload: {} } })).toEqual({
+      id: "1",
+      data: { payload: {} },
+    });
+    expect(unwrapRestEnvelope([{ id: "1" }])).toEqual([{ id: "1" }]);
+  });
+
+  it("finds the record list in raw REST and GraphQL payloads", () => {
+    expect(findRecordArray([{ id: "1" }])).toEqual([{ id: "1" }]);
+    expect(
+      findRecordArray({ data: { people: [{ id: "1" }] }, pageInfo: { hasNextPage: false } }),
+    ).toEqual([{ id: "1" }]);
+    expect(
+      findRecordArray({
+        data: { people: { edges: [{ node: { id: "1" } }, { node: { id: "2" } }] } },
+      }),
+    ).toEqual([{ id: "1" }, { id: "2" }]);
+  });
+
+  it("leaves single records and non-envelope objects alone", () => {
+    expect(findRecordArray({ data: { person: { id: "1" } } })).toBeUndefined();
+    expect(findRecordArray({ message: "ok", skills: [] })).toBeUndefined();
+    expect(findRecordArray(null)).toBeUndefined();
+  });
 });
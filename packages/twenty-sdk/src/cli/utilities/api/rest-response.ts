@@ -18,6 +18,54 @@ export function extractFirstValue(dataSection: RestObject): unknown {
   return values.length === 0 ? undefined : values[0];
 }
 
+const ENVELOPE_SIBLING_KEYS = new Set(["data", "pageInfo", "totalCount"]);
+
+// Strips Twenty's `{ data: { <resource>: ... } }` envelope. Payloads that carry
+// keys beyond data/pageInfo/totalCount are not envelopes and pass through.
+export function unwrapRestEnvelope(payload: unknown): unknown {
+  if (!isRestObject(payload) || !("data" in payload)) {
+    return payload;
+  }
+  if (Object.keys(payload).some((key) => !ENVELOPE_SIBLING_KEYS.has(key))) {
+    return payload;
+  }
+
+  const data = payload.data;
+  if (isRestObject(data)) {
+    const values = Object.values(data);
+    if (values.length === 1 && typeof values[0] === "object" && values[0] !== null) {
+      return values[0];
+    }
+  }
+
+  return data;
+}
+
+// The record list inside a raw API payload, for tabular output of generic
+// commands such as "rest": a bare array, a REST list envelope
+// ({data: {people: [...]}, pageInfo}), or a GraphQL connection
+// ({data: {people: {edges: [{node}]}}}). Single records and other shapes
+// return undefined so callers keep the payload as it is.
+export function findRecordArray(payload: unknown): unknown[] | undefined {
+  if (Array.isArray(payload)) {
+    return payload;
+  }
+
+  const unwrapped = unwrapRestEnvelope(payload);
+  if (Array.isArray(unwrapped)) {
+    return unwrapped;
+  }
+  if (isGraphqlConnection(unwrapped)) {
+    return unwrapped.edges.map((edge) => (isRestObject(edge) && "node" in edge ? edge.node : edge));
+  }
+
+  return undefined;
+}
+
+function isGraphqlConnection(value: unknown): value is RestObject & { edges: unknown[] } {
+  return isRestObject(value) && Array.isArray(value.edges);
+}
+
 export function extractCollection(payload: unknown, key: string): RestObject[] {
   if (Array.isArray(payload)) {
     return payload.filter(isRestObject);
@@ -1,7 +1,8 @@
 import { describe, it, expect, vi, beforeEach, afterEach } from "vitest";
 import axios, { AxiosError, InternalAxiosRequestConfig, AxiosHeaders } from "axios";
 import axiosRetry from "axios-retry";
-import { ApiService } from "../api.service";
+import { ApiService, ApiServiceOptions } from "../api.service";
+import { configureLogger } from "../../../shared/logger";
 
 // Mock axios and axios-retry
 vi.mock("axios", async () => {
@@ -86,6 +87,13 @@ describe("ApiService", () => {
       expect(mockAxiosInstance.interceptors.response.use).toHaveBeenCalled();
     });
 
+    it("passes a custom adapter to axios.create", () => {
+      const adapter = vi.fn();
+      new ApiService(mockConfigService as any, { adapter });
+
+      expect(axios.create).toHaveBeenCalledWith({ adapter });
+    });
+
     it("configures axios-retry by default", () => {
       new ApiService(mockConfigService as any);
 
@@ -105,6 +113,27 @@ describe("ApiService", () => {
 
       expect(axiosRetry).not.toHaveBeenCalled();
     });
+
+    it("uses maxRetries as the retry count", () => {
+      new ApiService(mockConfigService as any, { maxRetries: 5 });
+
+      expect(axiosRetry).toHaveBeenCalledWith(
+        mockAxiosInstance,
+        expect.objectContaining({ retries: 5 }),
+      );
+    });
+
+    it("does not configure axios-retry when maxRetries is 0", () => {
+      new ApiService(mockConfigService as any, { maxRetries: 0 });
+
+      expect(axiosRetry).not.toHaveBeenCalled();
+    });
+
+    it("lets noRetry override maxRetries", () => {
+      new ApiService(mockConfigService as any, { noRetry: true, maxRetries: 5 });
+
+      expect(axiosRetry).not.toHaveBeenCalled();
+    });
   });
 
   describe("request interceptor", () => {
@@ -227,6 +256,45 @@ describe("ApiService", () => {
       expect(retryCondition(error)).toBe(false);
     });
 
+    it("retries a 500 whose body matches retryBodyMatch", () => {
+      new ApiService(mockConfigService as any, { retryBodyMatch: /deadlock detected/ });
+
+      const retryConfig = vi.mocked(axiosRetry).mock.calls[0][1];
+      const retryCondition = retryConfig?.retryCondition as (error: AxiosError) => boolean;
+
+      const matching = {
+        response: { status: 500, data: { messages: ["ERROR: deadlock detected"] } },
+      } as AxiosError;
+      const nonMatching = {
+        response: { status: 500, data: { messages: ["ERROR: null value in column"] } },
+      } as AxiosError;
+
+      expect(retryCondition(matching)).toBe(true);
+      expect(retryCondition(nonMatching)).toBe(false);
+    });
+
+    it("matches retryBodyMatch against plain-text bodies", () => {
+      new ApiService(mockConfigService as any, { retryBodyMatch: /try again/i });
+
+      const retryConfig = vi.mocked(axiosRetry).mock.calls[0][1];
+      const retryCondition = retryConfig?.retryCondition as (error: AxiosError) => boolean;
+
+      const error = {
+        response: { status: 400, data: "Temporary failure, Try Again later" },
+      } as AxiosError;
+
+      expect(retryCondition(error)).toBe(true);
+    });
+
+    it("does not consult retryBodyMatch when retries are disabled", () => {
+      new ApiService(mockConfigService as any, {
+        noRetry: true,
+        retryBodyMatch: /deadlock detected/,
+      });
+
+      expect(axiosRetry).not.toHaveBeenCalled();
+    });
+
     it("respects Retry-After header", () => {
       new ApiService(mockConfigService as any);
 
@@ -289,6 +357,80 @@ describe("ApiService", () => {
       expect(delay).toBeLessThan(5000);
     });
 
+    it("scales exponential backoff with retryBaseDelay", () => {
+      new ApiService(mockConfigService as any, { retryBaseDelay: 100 });
+
+      const retryConfig = vi.mocked(axiosRetry).mock.calls[0][1];
+      const retryDelay = retryConfig?.retryDelay as (
+        retryCount: number,
+        error: AxiosError,
+      ) => number;
+
+      const error = {
+        response: {
+          headers: {},
+        },
+      } as unknown as AxiosError;
+
+      // For retryCount = 2: baseDelay = 2^2 * 100 = 400, jitter = 0-100
+      const delay = retryDelay(2, error);
+      expect(delay).toBeGreaterThanOrEqual(400);
+      expect(delay).toBeLessThan(500);
+    });
+
+    describe("backoff strategies", () => {
+      const error = { response: { headers: {} } } as unknown as AxiosError;
+
+      function delaysFor(options: ApiServiceOptions): number[] {
+        new ApiService(mockConfigService as any, options);
+        const calls = vi.mocked(axiosRetry).mock.calls;
+        const retryConfig = calls[calls.length - 1][1];
+        const retryDelay = retryConfig?.retryDelay as (
+          retryCount: number,
+          error: AxiosError,
+        ) => number;
+        return [1, 2, 3, 4].map((retryCount) => retryDelay(retryCount, error));
+      }
+
+      it("doubles the delay by default", () => {
+        const random = vi.spyOn(Math, "random").mockReturnValue(0);
+        try {
+          expect(delaysFor({ retryBaseDelay: 100 })).toEqual([200, 400, 800, 1600]);
+          expect(delaysFor({ retryBaseDelay: 100, backoffStrategy: "exponential" })).toEqual([
+            200, 400, 800, 1600,
+          ]);
+        } finally {
+          random.mockRestore();
+        }
+      });
+
+      it("adds one base delay per retry with linear backoff", () => {
+        expect(delaysFor({ retryBaseDelay: 100, backoffStrategy: "linear" })).toEqual([
+          100, 200, 300, 400,
+        ]);
+      });
+
+      it("waits the base delay every time with constant backoff", () => {
+        expect(delaysFor({ retryBaseDelay: 100, backoffStrategy: "constant" })).toEqual([
+          100, 100, 100, 100,
+        ]);
+      });
+
+      it("still honors Retry-After", () => {
+        new ApiService(mockConfigService as any, { backoffStrategy: "constant" });
+        const retryConfig = vi.mocked(axiosRetry).mock.calls[0][1];
+        const retryDelay = retryConfig?.retryDelay as (
+          retryCount: number,
+          error: AxiosError,
+        ) => number;
+
+        const rateLimited = {
+          response: { status: 429, headers: { "retry-after": "7" } },
+        } as unknown as AxiosError;
+        expect(retryDelay(3, rateLimited)).toBe(7000);
+      });
+    });
+
     it("handles invalid Retry-After header", () => {
       new ApiService(mockConfigService as any);
 
@@ -311,6 +453,44 @@ describe("ApiService", () => {
     });
   });
 
+  describe("verbose mode", () => {
+    let consoleErrorSpy: ReturnType<typeof vi.spyOn>;
+
+    beforeEach(() => {
+      consoleErrorSpy = vi.spyOn(console, "error").mockImplementation(() => {});
+      configureLogger({ verbose: true });
+    });
+
+    afterEach(() => {
+      configureLogger({});
+      consoleErrorSpy.mockRestore();
+    });
+
+    it("logs request lines without bodies", async () => {
+      new ApiService(mockConfigService as any);
+
+      await requestInterceptor({
+        method: "post",
+        url: "/rest/people",
+        headers: new AxiosHeaders(),
+        data: { name: "Secret Person" },
+      } as InternalAxiosRequestConfig);
+
+      expect(consoleErrorSpy).toHaveBeenCalledWith("→ POST https://api.twenty.com/rest/people");
+      expect(consoleErrorSpy).toHaveBeenCalledTimes(1);
+    });
+
+    it("logs retries with the triggering status", () => {
+      new ApiService(mockConfigService as any);
+
+      const retryConfig = vi.mocked(axiosRetry).mock.calls[0][1];
+      const onRetry = retryConfig?.onRetry as (retryCount: number, error: AxiosError) => void;
+      onRetry(1, { message: "Too Many Requests", response: { status: 429 } } as AxiosError);
+
+      expect(consoleErrorSpy).toHaveBeenCalledWith("Retrying after 429 (retry 1/3)");
+    });
+  });
+
   describe("debug mode", () => {
     let consoleErrorSpy: ReturnType<typeof vi.spyOn>;
 
@@ -0,0 +1,108 @@
+import os from "os";
+import path from "path";
+import fs from "fs-extra";
+import { afterEach, beforeEach, describe, expect, it, vi } from "vitest";
+import { ApiService } from "../api.service";
+import { EtagCache } from "../etag-cache";
+import { createMockAdapter } from "../../../../test-utils/mock-adapter";
+
+function createConfigService(apiKey = "test-token") {
+  return {
+    getConfig: vi.fn().mockResolvedValue({
+      apiUrl: "https://crm.example.com",
+      apiKey,
+      workspace: "default",
+    }),
+  };
+}
+
+describe("EtagCache", () => {
+  let dir: string;
+
+  beforeEach(async () => {
+    dir = await fs.mkdtemp(path.join(os.tmpdir(), "twenty-etag-cache-"));
+  });
+
+  afterEach(async () => {
+    await fs.remove(dir);
+  });
+
+  it("sends If-None-Match on a repeated GET and serves the cached body on 304", async () => {
+    const adapter = createMockAdapter((config) =>
+      config.headers["If-None-Match"] === '"v1"'
+        ? { status: 304 }
+        : { data: { data: { people: [{ id: "1" }] } }, headers: { etag: '"v1"' } },
+    );
+    const api = new ApiService(createConfigService() as any, {
+      adapter,
+      etagCache: new EtagCache(dir),
+    });
+
+    const first = await api.get("/rest/people", { params: { limit: 1 } });
+    const second = await api.get("/rest/people", { params: { limit: 1 } });
+
+    expect(adapter.requests[0]?.headers["If-None-Match"]).toBeUndefined();
+    expect(adapter.requests[1]?.headers["If-None-Match"]).toBe('"v1"');
+    expect(second.status).toBe(200);
+    expect(second.data).toEqual(first.data);
+  });
+
+  it("uses the fresh body when the resource changed", async () => {
+    const versions = [
+      { data: { name: "old" }, headers: { etag: '"v1"' } },
+      { data: { name: "new" }, headers: { etag: '"v2"' } },
+      { status: 304 },
+    ];
+    const adapter = createMockAdapter(() => versions.shift()!);
+    const api = new ApiService(createConfigService() as any, {
+      adapter,
+      etagCache: new EtagCache(dir),
+    });
+
+    await api.get("/rest/people/1");
+    expect((await api.get("/rest/people/1")).data).toEqual({ name: "new" });
+    expect((await api.get("/rest/people/1")).data).toEqual({ name: "new" });
+    expect(adapter.requests[2]?.headers["If-None-Match"]).toBe('"v2"');
+  });
+
+  it("keys entries by URL and token and skips servers without ETags", async () => {
+    const adapter = createMockAdapter((config) => ({
+      data: { url: config.url },
+      headers: config.url === "/rest/people" ? { etag: '"v1"' } : {},
+    }));
+    const cache = new EtagCache(dir);
+    const api = new ApiService(createConfigService() as any, { adapter, etagCache: cache });
+    const otherToken = new ApiService(createConfigService("other-token") as any, {
+      adapter,
+      etagCache: cache,
+    });
+
+    await api.get("/rest/people");
+    await api.get("/rest/companies");
+    await api.get("/rest/companies");
+    await otherToken.get("/rest/people");
+
+    expect(adapter.requests.map((request) => request.headers["If-None-Match"])).toEqual([
+      undefined,
+      undefined,
+      undefined,
+      undefined,
+    ]);
+    expect(await fs.readdir(dir)).toHaveLength(1);
+  });
+
+  it("never caches writes", async () => {
+    const adapter = createMockAdapter(() => ({ data: { ok: true }, headers: { etag: '"v1"' } }));
+    const api = new ApiService(createConfigService() as any, {
+      adapter,
+      etagCache: new EtagCache(dir),
+    });
+
+    await api.post("/rest/people", { name: "Ada" });
+    await api.post("/rest/people", { name: "Ada" });
+
+    expect(adapter.requests[1]?.headers["If-None-Match"]).toBeUndefined();
+    expect(await fs.pathExists(dir)).toBe(true);
+    expect(await fs.readdir(dir)).toHaveLength(0);
+  });
+});
@@ -0,0 +1,445 @@
+import { describe, expect, it, vi } from "vitest";
+import { AxiosError } from "axios";
+import { ApiService } from "../api.service";
+import { createMockAdapter } from "../../../../test-utils/mock-adapter";
+import { RecordsService } from "../../../records/services/records.service";
+import { CliError } from "../../../errors/cli-error";
+import { formatError, toExitCode } from "../../../errors/error-handler";
+import { getRetryStats, resetRetryStats } from "../retry-stats";
+
+function createConfigService() {
+  return {
+    getConfig: vi.fn().mockResolvedValue({
+      apiUrl: "https://crm.example.com",
+      apiKey: "test-token",
+      workspace: "default",
+    }),
+  };
+}
+
+describe("ApiService with an injected adapter", () => {
+  it("routes requests through the adapter with resolved base URL and auth", async () => {
+    const adapter = createMockAdapter(() => ({ data: { data: { people: [] } } }));
+    const api = new ApiService(createConfigService() as any, { adapter });
+
+    const response = await api.get("/rest/people", { params: { limit: 1 } });
+
+    expect(response.data).toEqual({ data: { people: [] } });
+    expect(adapter.requests).toHaveLength(1);
+    expect(adapter.requests[0]?.baseURL).toBe("https://crm.example.com");
+    expect(adapter.requests[0]?.url).toBe("/rest/people");
+    expect(adapter.requests[0]?.headers.Authorization).toBe("Bearer test-token");
+  });
+
+  it("names the profile, never the token, when the server rejects the token", async () => {
+    const adapter = createMockAdapter(() => ({
+      status: 401,
+      data: { message: "Token invalid" },
+    }));
+    const configService = createConfigService();
+    configService.getConfig.mockResolvedValue({
+      apiUrl: "https://crm.example.com",
+      apiKey: "secret-token-value",
+      workspace: "staging",
+    });
+    const api = new ApiService(configService as any, { adapter });
+
+    const error = await api.get("/rest/people").catch((caught: unknown) => caught);
+
+    const lines = formatError(error);
+    expect(lines[0]).toBe(
+      'Authentication failed for profile "staging" (HTTP 401); ' +
+        "your token may be expired or revoked.",
+    );
+    expect(lines[lines.length - 1]).toBe(
+      "Suggestion: Run twenty auth login --profile staging, or update TWENTY_TOKEN if it is set.",
+    );
+    expect(lines.join("\n")).not.toContain("secret-token-value");
+    expect(toExitCode(error)).toBe(3);
+  });
+
+  it("sends the workspace ID header when one is resolved", async () => {
+    const adapter = createMockAdapter(() => ({ data: {} }));
+    const configService = createConfigService();
+    configService.getConfig.mockResolvedValue({
+      apiUrl: "https://crm.example.com",
+      apiKey: "test-token",
+      workspace: "default",
+      workspaceId: "3b8e6458-5fc1-4e63-8563-008ccddaa6db",
+    });
+    const api = new ApiService(configService as any, { adapter });
+
+    await api.get("/rest/people");
+
+    expect(adapter.requests[0]?.headers["X-Workspace-Id"]).toBe(
+      "3b8e6458-5fc1-4e63-8563-008ccddaa6db",
+    );
+  });
+
+  it("retries transient statuses through the adapter", async () => {
+    const statuses = [503, 200];
+    const adapter = createMockAdapter(() => ({ status: statuses.shift(), data: { ok: true } }));
+    const api = new ApiService(createConfigService() as any, { adapter, retryBaseDelay: 0 });
+
+    const response = await api.get("/rest/people");
+
+    expect(response.status).toBe(200);
+    expect(adapter.requests).toHaveLength(2);
+  });
+
+  it("numbers each attempt with X-Retry-Attempt when enabled", async () => {
+    const statuses = [503, 502, 200];
+    const attempts: unknown[] = [];
+    const adapter = createMockAdapter((config) => {
+      attempts.push(config.headers["X-Retry-Attempt"]);
+      return { status: statuses.shift() };
+    });
+    const api = new ApiService(createConfigService() as any, {
+      adapter,
+      retryBaseDelay: 0,
+      retryAttemptHeader: true,
+    });
+
+    await api.post("/rest/people", { name: "Ann" }, { headers: { "Idempotency-Key": "key-1" } });
+
+    expect(attempts).toEqual(["1", "2", "3"]);
+    expect(adapter.requests.map((request) => request.headers["Idempotency-Key"])).toEqual([
+      "key-1",
+      "key-1",
+      "key-1",
+    ]);
+
+    const plainAdapter = createMockAdapter(() => ({ data: {} }));
+    const plainApi = new ApiService(createConfigService() as any, { adapter: plainAdapter });
+    await plainApi.get("/rest/people");
+    expect(plainAdapter.requests[0]?.headers["X-Retry-Attempt"]).toBeUndefined();
+  });
+
+  it("retries connection resets by default", async () => {
+    let attempts = 0;
+    const adapter = createMockAdapter((config) => {
+      attempts += 1;
+      if (attempts === 1) {
+        throw new AxiosError("socket hang up", "ECONNRESET", config);
+      }
+      return { data: { ok: true } };
+    });
+    const api = new ApiService(createConfigService() as any, { adapter, retryBaseDelay: 0 });
+
+    const response = await api.get("/rest/people");
+
+    expect(response.status).toBe(200);
+    expect(adapter.requests).toHaveLength(2);
+  });
+
+  it("retries a refused first connection and DNS failures before any response", async () => {
+    const failures = [
+      { message: "connect ECONNREFUSED 10.0.0.1:443", code: "ECONNREFUSED" },
+      { message: "getaddrinfo ENOTFOUND crm.example.com", code: "ENOTFOUND" },
+    ];
+    const adapter = createMockAdapter((config) => {
+      const failure = failures.shift();
+      if (failure) {
+        throw new AxiosError(failure.message, failure.code, config);
+      }
+      return { data: { ok: true } };
+    });
+    const api = new ApiService(createConfigService() as any, { adapter, retryBaseDelay: 0 });
+
+    const response = await api.get("/rest/people");
+
+    expect(response.status).toBe(200);
+    expect(adapter.requests).toHaveLength(3);
+  });
+
+  it("does not resend a POST after a connection reset by default", async () => {
+    const adapter = createMockAdapter((config) => {
+      throw new AxiosError("socket hang up", "ECONNRESET", config);
+    });
+    const api = new ApiService(createConfigService() as any, { adapter, retryBaseDelay: 0 });
+
+    await expect(api.post("/graphql", { query: "mutation { x }" })).rejects.toMatchObject({
+      code: "ECONNRESET",
+    });
+    expect(adapter.requests).toHaveLength(1);
+  });
+
+  it("does not resend a batch create after a reset when its key was generated", async () => {
+    const adapter = createMockAdapter((config) => {
+      throw new AxiosError("socket hang up", "ECONNRESET", config);
+    });
+    const records = new RecordsService(
+      new ApiService(createConfigService() as any, { adapter, retryBaseDelay: 0 }),
+    );
+
+    await expect(records.batchCreate("people", [{ name: "Alice" }])).rejects.toMatchObject({
+      code: "ECONNRESET",
+    });
+    expect(adapter.requests).toHaveLength(1);
+    expect(adapter.requests[0]?.headers["Idempotency-Key"]).toEqual(expect.any(String));
+  });
+
+  it("resends a POST after a reset with a caller-supplied key or retryUnsafe", async () => {
+    const flaky = () => {
+      let attempts = 0;
+      return createMockAdapter((config) => {
+        attempts += 1;
+        if (attempts === 1) {
+          throw new AxiosError("socket hang up", "ECONNRESET", config);
+        }
+        return { data: { ok: true } };
+      });
+    };
+    const keyedAdapter = flaky();
+    const keyedRecords = new RecordsService(
+      new ApiService(createConfigService() as any, { adapter: keyedAdapter, retryBaseDelay: 0 }),
+    );
+    const unsafeAdapter = flaky();
+    const unsafeApi = new ApiService(createConfigService() as any, {
+      adapter: unsafeAdapter,
+      retryBaseDelay: 0,
+      retryUnsafe: true,
+    });
+
+    await keyedRecords.batchCreate("people", [{ name: "Alice" }], { idempotencyKey: "key-1" });
+    await unsafeApi.post("/graphql", {});
+
+    expect(keyedAdapter.requests).toHaveLength(2);
+    expect(keyedAdapter.requests[1]?.headers["Idempotency-Key"]).toBe("key-1");
+    expect(unsafeAdapter.requests).toHaveLength(2);
+  });
+
+  it("resends a POST whose connection was refused, since nothing was sent", async () => {
+    let attempts = 0;
+    const adapter = createMockAdapter((config) => {
+      attempts += 1;
+      if (attempts === 1) {
+        throw new AxiosError("connect ECONNREFUSED 10.0.0.1:443", "ECONNREFUSED", config);
+      }
+      return { data: { ok: true } };
+    });
+    const api = new ApiService(createConfigService() as any, { adapter, retryBaseDelay: 0 });
+
+    await api.post("/graphql", {});
+
+    expect(adapter.requests).toHaveLength(2);
+  });
+
+  it("fails fast on network errors but still retries 429s when retryNetworkErrors is false", async () => {
+    const resetAdapter = createMockAdapter((config) => {
+      throw new AxiosError("socket hang up", "ECONNRESET", config);
+    });
+    const resetApi = new ApiService(createConfigService() as any, {
+      adapter: resetAdapter,
+      retryBaseDelay: 0,
+      retryNetworkErrors: false,
+    });
+
+    await expect(resetApi.get("/rest/people")).rejects.toMatchObject({ code: "ECONNRESET" });
+    expect(resetAdapter.requests).toHaveLength(1);
+
+    const statuses = [429, 200];
+    const rateLimitedAdapter = createMockAdapter(() => ({ status: statuses.shift() }));
+    const rateLimitedApi = new ApiService(createConfigService() as any, {
+      adapter: rateLimitedAdapter,
+      retryBaseDelay: 0,
+      retryNetworkErrors: false,
+    });
+
+    const response = await rateLimitedApi.get("/rest/people");
+
+    expect(response.status).toBe(200);
+    expect(rateLimitedAdapter.requests).toHaveLength(2);
+  });
+
+  it("retries 409 conflicts only when listed in retryStatusCodes", async () => {
+    const defaultAdapter = createMockAdapter(() => ({ status: 409 }));
+    const defaultApi = new ApiService(createConfigService() as any, {
+      adapter: defaultAdapter,
+      retryBaseDelay: 0,
+    });
+
+    await expect(defaultApi.post("/rest/people", {})).rejects.toMatchObject({
+      response: { status: 409 },
+    });
+    expect(defaultAdapter.requests).toHaveLength(1);
+
+    const statuses = [409, 201];
+    const adapter = createMockAdapter(() => ({ status: statuses.shift(), data: { id: "1" } }));
+    // The 1s base delay applies to 429/5xx; a conflict retries on its own
+    // short schedule, well inside the test timeout.
+    const api = new ApiService(createConfigService() as any, {
+      adapter,
+      retryStatusCodes: [409],
+    });
+
+    const started = Date.now();
+    const response = await api.post("/rest/people", {});
+
+    expect(response.status).toBe(201);
+    expect(adapter.requests).toHaveLength(2);
+    expect(Date.now() - started).toBeLessThan(1000);
+  });
+
+  it("fails on the first 429 with abortOnRateLimit but still retries 5xx", async () => {
+    const statuses = [503, 429, 200];
+    const adapter = createMockAdapter(() => ({
+      status: statuses.shift(),
+      headers: { "retry-after": "30" },
+    }));
+    const api = new ApiService(createConfigService() as any, {
+      adapter,
+      retryBaseDelay: 0,
+      abortOnRateLimit: true,
+    });
+
+    const error = await api.get("/rest/people").catch((caught: unknown) => caught);
+
+    expect(error).toBeInstanceOf(CliError);
+    expect(error).toMatchObject({
+      code: "RATE_LIMIT",
+      suggestion: "The server asked to retry after 30 seconds.",
+    });
+    expect(toExitCode(error)).toBe(5);
+    expect(adapter.requests).toHaveLength(2);
+  });
+
+  it("counts each retry and its delay in the retry stats", async () => {
+    resetRetryStats();
+    const statuses = [503, 503, 200];
+    const adapter = createMockAdapter(() => ({ status: statuses.shift(), data: { ok: true } }));
+    const api = new ApiService(createConfigService() as any, { adapter, retryBaseDelay: 0 });
+
+    await api.get("/rest/people");
+
+    expect(getRetryStats()).toEqual({ retries: 2, waitedMs: 0, reasons: { "503": 2 } });
+    resetRetryStats();
+  });
+
+  it("surfaces non-retryable statuses as axios errors", async () => {
+    const adapter = createMockAdapter(() => ({ status: 400, data: { error: "Bad" } }));
+    const api = new ApiService(createConfigService() as any, { adapter, noRetry: true });
+
+    await expect(api.get("/rest/people")).rejects.toMatchObject({
+      response: { status: 400, data: { error: "Bad" } },
+    });
+    expect(adapter.requests).toHaveLength(1);
+  });
+
+  it("rejects responses larger than maxResponseSize", async () => {
+    const adapter = createMockAdapter(() => ({
+      data: { data: { people: [] } },
+      headers: { "content-length": "2048" },
+    }));
+    const api = new ApiService(createConfigService() as any, { adapter, maxResponseSize: 1024 });
+
+    await expect(api.get("/rest/people")).rejects.toMatchObject({
+      message: "Response body exceeded the 1024-byte limit.",
+      code: "RESPONSE_TOO_LARGE",
+    });
+
+    const roomy = new ApiService(createConfigService() as any, { adapter, maxResponseSize: 4096 });
+    await expect(roomy.get("/rest/people")).resolves.toMatchObject({ status: 200 });
+  });
+
+  it("translates axios maxContentLength aborts into a clear error", async () => {
+    const adapter = createMockAdapter((config) => {
+      expect(config.maxContentLength).toBe(512);
+      throw new Error("maxContentLength size of 512 exceeded");
+    });
+    const api = new ApiService(createConfigService() as any, { adapter, maxResponseSize: 512 });
+
+    const error = await api.get("/rest/people").catch((caught: unknown) => caught);
+    expect(error).toBeInstanceOf(CliError);
+    expect((error as CliError).suggestion).toContain("--max-body-size");
+  });
+
+  it("sends explicit nulls in record update bodies", async () => {
+    const adapter = createMockAdapter(() => ({ data: { data: { updatePerson: { id: "1" } } } }));
+    const records = new RecordsService(new ApiService(createConfigService() as any, { adapter }));
+
+    await records.update("people", "1", { jobTitle: null });
+
+    expect(adapter.requests[0]?.method).toBe("patch");
+    expect(adapter.requests[0]?.data).toBe('{"jobTitle":null}');
+  });
+
+  it("keeps the Idempotency-Key header stable across a rate-limited retry", async () => {
+    const statuses = [429, 200];
+    const adapter = createMockAdapter(() => ({
+      status: statuses.shift(),
+      data: { data: { createPerson: { id: "1" } } },
+    }));
+    const records = new RecordsService(
+      new ApiService(createConfigService() as any, { adapter, retryBaseDelay: 0 }),
+    );
+
+    await records.create("people", { name: "Alice" });
+
+    expect(adapter.requests).toHaveLength(2);
+    const key = adapter.requests[0]?.headers["Idempotency-Key"];
+    expect(key).toEqual(expect.any(String));
+    expect(adapter.requests[1]?.headers["Idempotency-Key"]).toBe(key);
+  });
+});
+
+describe("plaintext HTTP guard", () => {
+  function configFor(apiUrl: string) {
+    return {
+      getConfig: vi.fn().mockResolvedValue({ apiUrl, apiKey: "test-token", workspace: "default" }),
+    };
+  }
+
+  it("rejects http:// base URLs for remote hosts before sending anything", async () => {
+    const adapter = createMockAdapter(() => ({ data: {} }));
+    const api = new ApiService(configFor("http://crm.example.com") as any, { adapter });
+
+    await expect(api.get("/rest/people")).rejects.toMatchObject({
+      code: "INVALID_ARGUMENTS",
+      message: "Refusing to send requests to http://crm.example.com over plaintext HTTP.",
+    });
+    expect(adapter.requests).toHaveLength(0);
+  });
+
+  it("allows http:// for localhost and loopback addresses", async () => {
+    for (const apiUrl of ["http://localhost:3000", "http://127.0.0.1:3000", "http://[::1]:3000"]) {
+      const adapter = createMockAdapter(() => ({ data: {} }));
+      const api = new ApiService(configFor(apiUrl) as any, { adapter });
+
+      await api.get("/rest/people");
+
+      expect(adapter.requests).toHaveLength(1);
+    }
+  });
+
+  it("allows remote http:// when allowInsecureHttp is set", async () => {
+    const adapter = createMockAdapter(() => ({ data: {} }));
+    const api = new ApiService(configFor("http://crm.example.com") as any, {
+      adapter,
+      allowInsecureHttp: true,
+    });
+
+    await api.get("/rest/people");
+
+    expect(adapter.requests).toHaveLength(1);
+  });
+
+  it("keeps rejecting an insecure base URL after the first check", async () => {
+    const adapter = createMockAdapter(() => ({ data: {} }));
+    const api = new ApiService(configFor("http://crm.example.com") as any, { adapter });
+
+    await expect(api.get("/rest/people")).rejects.toThrow("over plaintext HTTP");
+    await expect(api.get("/rest/companies")).rejects.toThrow("over plaintext HTTP");
+    expect(adapter.requests).toHaveLength(0);
+  });
+
+  it("checks absolute request URLs on top of the base URL", async () => {
+    const adapter = createMockAdapter(() => ({ data: {} }));
+    const api = new ApiService(configFor("https://crm.example.com") as any, { adapter });
+
+    await expect(api.post("http://crm.example.com/mcp", {})).rejects.toThrow(
+      "over plaintext HTTP",
+    );
+    expect(adapter.requests).toHaveLength(0);
+  });
+});
@@ -176,6 +176,12 @@ describe("PublicHttpService", () => {
     expect(retryCondition({ response: { status: 500 } } as AxiosError)).toBe(false);
   });
 
+  it("skips retry configuration when noRetry is set", () => {
+    new PublicHttpService(mockConfigService as any, { noRetry: true });
+
+    expect(axiosRetry).not.toHaveBeenCalled();
+  });
+
   it("inherits the shared debug behavior", async () => {
     const consoleErrorSpy = vi.spyOn(console, "error").mockImplementation(() => {});
     const service = new PublicHttpService(mockConfigService as any, { debug: true });
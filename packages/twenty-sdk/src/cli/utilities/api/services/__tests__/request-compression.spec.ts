@@ -0,0 +1,63 @@
+import { gunzipSync } from "zlib";
+import { describe, expect, it, vi } from "vitest";
+import { ApiService } from "../api.service";
+import { createMockAdapter } from "../../../../test-utils/mock-adapter";
+
+function createConfigService() {
+  return {
+    getConfig: vi.fn().mockResolvedValue({
+      apiUrl: "https://crm.example.com",
+      apiKey: "test-token",
+      workspace: "default",
+    }),
+  };
+}
+
+const largeBody = { name: "x".repeat(2048) };
+
+describe("RequestCompressor", () => {
+  it("gzips large JSON bodies and sets Content-Encoding", async () => {
+    const adapter = createMockAdapter(() => ({ data: { ok: true } }));
+    const api = new ApiService(createConfigService() as any, { adapter, compressRequests: true });
+
+    await api.post("/rest/people", largeBody);
+
+    const request = adapter.requests[0]!;
+    expect(request.headers["Content-Encoding"]).toBe("gzip");
+    expect(JSON.parse(gunzipSync(request.data as Buffer).toString())).toEqual(largeBody);
+  });
+
+  it("sends small bodies and disabled clients uncompressed", async () => {
+    const adapter = createMockAdapter(() => ({ data: { ok: true } }));
+    const compressing = new ApiService(createConfigService() as any, {
+      adapter,
+      compressRequests: true,
+    });
+    const plain = new ApiService(createConfigService() as any, { adapter });
+
+    await compressing.post("/rest/people", { name: "Ada" });
+    await plain.post("/rest/people", largeBody);
+
+    expect(adapter.requests[0]?.headers["Content-Encoding"]).toBeUndefined();
+    expect(adapter.requests[0]?.data).toBe(JSON.stringify({ name: "Ada" }));
+    expect(adapter.requests[1]?.headers["Content-Encoding"]).toBeUndefined();
+  });
+
+  it("resends uncompressed after a 415 and stops compressing", async () => {
+    const adapter = createMockAdapter((config) =>
+      config.headers["Content-Encoding"] === "gzip"
+        ? { status: 415, data: { message: "Unsupported Media Type" } }
+        : { data: { ok: true } },
+    );
+    const api = new ApiService(createConfigService() as any, { adapter, compressRequests: true });
+
+    const first = await api.post("/rest/people", largeBody);
+    await api.post("/rest/people", largeBody);
+
+    expect(first.data).toEqual({ ok: true });
+    expect(adapter.requests).toHaveLength(3);
+    expect(adapter.requests[1]?.headers["Content-Encoding"]).toBeUndefined();
+    expect(JSON.parse(adapter.requests[1]?.data as string)).toEqual(largeBody);
+    expect(adapter.requests[2]?.headers["Content-Encoding"]).toBeUndefined();
+  });
+});
@@ -0,0 +1,52 @@
+import { afterEach, describe, expect, it, vi } from "vitest";
+import {
+  configureRetryStats,
+  formatRetryStats,
+  getRetryStats,
+  recordRetry,
+  reportRetryStats,
+  resetRetryStats,
+} from "../retry-stats";
+
+describe("retry stats", () => {
+  afterEach(() => {
+    resetRetryStats();
+    configureRetryStats(false);
+  });
+
+  it("accumulates retries, delay, and triggers", () => {
+    recordRetry(503, 2000);
+    recordRetry(503, 4000);
+    recordRetry(undefined, 1200);
+
+    expect(getRetryStats()).toEqual({
+      retries: 3,
+      waitedMs: 7200,
+      reasons: { "503": 2, network: 1 },
+    });
+  });
+
+  it("formats a one-line summary", () => {
+    expect(formatRetryStats({ retries: 0, waitedMs: 0, reasons: {} })).toBe(
+      "retries: 0, waited 0ms",
+    );
+    expect(
+      formatRetryStats({ retries: 3, waitedMs: 7000, reasons: { network: 1, "429": 2 } }),
+    ).toBe("retries: 3, waited 7s (429 x2, network x1)");
+  });
+
+  it("prints to stderr only when enabled", () => {
+    const errorSpy = vi.spyOn(console, "error").mockImplementation(() => {});
+    try {
+      recordRetry(429, 250);
+      reportRetryStats();
+      expect(errorSpy).not.toHaveBeenCalled();
+
+      configureRetryStats(true);
+      reportRetryStats();
+      expect(errorSpy).toHaveBeenCalledWith("retries: 1, waited 250ms (429 x1)");
+    } finally {
+      errorSpy.mockRestore();
+    }
+  });
+});
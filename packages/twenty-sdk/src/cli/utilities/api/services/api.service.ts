@@ -1,4 +1,6 @@
 import axios, {
+  AxiosAdapter,
+  AxiosError,
   AxiosInstance,
   AxiosRequestConfig,
   AxiosResponse,
@@ -6,22 +8,94 @@ import axios, {
 } from "axios";
 import axiosRetry from "axios-retry";
 import { ConfigService } from "../../config/services/config.service";
+import { CliError, errorWithCause } from "../../errors/cli-error";
+import { log, logVerbose, redactSensitive } from "../../shared/logger";
+import { EtagCache } from "./etag-cache";
+import { RequestCompressor } from "./request-compression";
+import { recordRetry } from "./retry-stats";
+import { assertSecureTransport } from "./transport-security";
 
-export interface ApiServiceOptions {
-  workspace?: string;
-  debug?: boolean;
-  noRetry?: boolean;
-}
+export const DEFAULT_MAX_RETRIES = 3;
+export const DEFAULT_RETRY_BASE_DELAY_MS = 1000;
+// 409s come from write contention that clears in milliseconds, so they back
+// off on a shorter schedule than rate limits and gateway errors.
+export const CONFLICT_RETRY_BASE_DELAY_MS = 100;
+// How the wait between retries grows: doubling with jitter (the default), one
+// base delay more per retry, or the base delay every time. Linear and constant
+// add no jitter, so a cron job can predict how long its retries take.
+export const BACKOFF_STRATEGIES = ["exponential", "linear", "constant"] as const;
+export type BackoffStrategy = (typeof BACKOFF_STRATEGIES)[number];
+const RETRYABLE_STATUSES = [429, 502, 503, 504];
+// Selects the workspace when one token serves several on the same instance.
+export const WORKSPACE_ID_HEADER = "X-Workspace-Id";
+// Sent on record creates so a server that dedupes on it can drop a repeat.
+export const IDEMPOTENCY_KEY_HEADER = "Idempotency-Key";
+// 1 on the first attempt and one more on each retry, for matching server logs.
+export const RETRY_ATTEMPT_HEADER = "X-Retry-Attempt";
+// Methods that leave the same state however many times they are applied.
+const IDEMPOTENT_METHODS = new Set(["get", "head", "options", "put", "patch", "delete"]);
+// Failures before a connection existed: the request never reached the server,
+// so resending it cannot apply it twice.
+const UNSENT_ERROR_CODES = new Set(["ECONNREFUSED", "ENOTFOUND", "EAI_AGAIN"]);
+export const DEFAULT_MAX_RESPONSE_BYTES = 100 * 1024 * 1024;
 
 export interface SharedHttpServiceOptions {
   workspace?: string;
   debug?: boolean;
   noRetry?: boolean;
+  maxRetries?: number;
+  retryBaseDelay?: number;
+  // Defaults to exponential; Retry-After still wins over every strategy.
+  backoffStrategy?: BackoffStrategy;
+  retryBodyMatch?: RegExp;
+  // Statuses retried on top of 429/502/503/504, e.g. 409 for contended writes.
+  retryStatusCodes?: number[];
+  // Retry connection resets and timeouts of requests that are safe to resend
+  // (see canResend); defaults to true. Status retries are unaffected.
+  retryNetworkErrors?: boolean;
+  // Also resend POSTs without a caller-supplied idempotency key after a reset
+  // or timeout; the server may already have applied them.
+  retryUnsafe?: boolean;
+  // Fail on the first 429 instead of waiting out the backoff; 5xx and
+  // network retries still apply.
+  abortOnRateLimit?: boolean;
+  // Largest response body accepted, in bytes; defaults to DEFAULT_MAX_RESPONSE_BYTES.
+  maxResponseSize?: number;
+  // Allow http:// to hosts other than localhost; tokens then travel in plaintext.
+  allowInsecureHttp?: boolean;
+  // Conditional GETs with If-None-Match; 304s are answered from this cache.
+  etagCache?: EtagCache;
+  // Gzip large JSON request bodies; a 415 resends them uncompressed.
+  compressRequests?: boolean;
+  // Send RETRY_ATTEMPT_HEADER on every attempt.
+  retryAttemptHeader?: boolean;
+  // Custom transport for tests or instrumentation; defaults to axios' HTTP adapter.
+  adapter?: AxiosAdapter;
 }
 
+// ApiService and PublicHttpService share one HTTP client setup.
+export type ApiServiceOptions = SharedHttpServiceOptions;
+
 export interface RequestResolution {
   apiUrl: string;
   apiKey?: string;
+  workspaceId?: string;
+  // Profile the token belongs to; named when the server rejects the token.
+  // Unset for --connection tokens, which no profile or login can replace.
+  profile?: string;
+}
+
+// Set on requests that carry a token, so a 401/403 can name the profile
+// whose token was rejected (see formatError).
+export interface AuthenticatedRequestConfig extends InternalAxiosRequestConfig {
+  twentyProfile?: string;
+}
+
+// Set on a POST whose Idempotency-Key the caller chose (--idempotency-key),
+// vouching that the server dedupes on it. Only then is the POST resent after a
+// reset or timeout; a generated key alone proves nothing about the server.
+export interface IdempotentRequestConfig extends AxiosRequestConfig {
+  twentyIdempotent?: boolean;
 }
 
 type RequestConfigResolver = (config: InternalAxiosRequestConfig) => Promise<RequestResolution>;
@@ -30,74 +104,144 @@ export function createHttpClient(
   resolveRequestConfig: RequestConfigResolver,
   options: SharedHttpServiceOptions = {},
 ): AxiosInstance {
-  const client = axios.create();
+  const maxResponseSize = options.maxResponseSize ?? DEFAULT_MAX_RESPONSE_BYTES;
+  // axios' HTTP adapter aborts the download once maxContentLength is exceeded,
+  // so an oversized body is never fully buffered.
+  const client = axios.create({
+    maxContentLength: maxResponseSize,
+    ...(options.adapter ? { adapter: options.adapter } : {}),
+  });
+  // noRetry always wins over maxRetries; total attempts are 1 + retries.
+  const retries = options.noRetry ? 0 : (options.maxRetries ?? DEFAULT_MAX_RETRIES);
+  const retryBaseDelay = options.retryBaseDelay ?? DEFAULT_RETRY_BASE_DELAY_MS;
+  const backoffStrategy = options.backoffStrategy ?? "exponential";
+  const retryStatuses = new Set([...RETRYABLE_STATUSES, ...(options.retryStatusCodes ?? [])]);
+  const compressor = options.compressRequests ? new RequestCompressor() : undefined;
 
-  if (!options.noRetry) {
+  if (retries > 0) {
     axiosRetry(client, {
-      retries: 3,
+      retries,
+      // axios-retry asks for the delay only once it has decided to retry, so
+      // this is where each retry and its wait are counted.
       retryDelay: (retryCount, error) => {
-        const retryAfter = error.response?.headers?.["retry-after"];
-        if (retryAfter) {
-          const seconds = Number.parseInt(String(retryAfter), 10);
-          if (!Number.isNaN(seconds)) {
-            return seconds * 1000;
-          }
-        }
-        const baseDelay = Math.pow(2, retryCount) * 1000;
-        const jitter = Math.random() * 1000;
-        return baseDelay + jitter;
+        const delay = computeRetryDelay(retryCount, error, retryBaseDelay, backoffStrategy);
+        recordRetry(error.response?.status, delay);
+        return delay;
       },
       retryCondition: (error) => {
+        if (isNetworkError(error)) {
+          return options.retryNetworkErrors !== false && canResend(error, options.retryUnsafe);
+        }
         const status = error.response?.status;
-        return status === 429 || status === 502 || status === 503 || status === 504;
+        if (status === 429 && options.abortOnRateLimit) {
+          return false;
+        }
+        if (status !== undefined && retryStatuses.has(status)) {
+          return true;
+        }
+        // Opt-in escape hatch for transient server errors such as deadlocks that
+        // surface as ordinary 5xx responses; the body is matched but never logged.
+        return (
+          options.retryBodyMatch !== undefined &&
+          error.response !== undefined &&
+          options.retryBodyMatch.test(stringifyResponseBody(error.response.data))
+        );
       },
       onRetry: (retryCount, error) => {
+        const status = error.response?.status;
         if (options.debug) {
-          // eslint-disable-next-line no-console
-          console.error(`Retry ${retryCount}: ${error.message}`);
+          log("debug", `Retry ${retryCount}: ${error.message}`, { retry: retryCount, status });
+        } else {
+          logVerbose(
+            `Retrying after ${status ?? "network error"} (retry ${retryCount}/${retries})`,
+            { retry: retryCount, status },
+          );
         }
       },
     });
   }
 
+  // The base URL comes from async config resolution (which may prompt for a
+  // profile), so it is checked once per client when first resolved rather
+  // than on every request. Only absolute request URLs, which MCP posts to and
+  // axios uses instead of baseURL, are checked per request.
+  const checkedBaseUrls = new Set<string>();
   client.interceptors.request.use(async (config) => {
     const resolved = await resolveRequestConfig(config);
+    if (!checkedBaseUrls.has(resolved.apiUrl)) {
+      assertSecureTransport(resolved.apiUrl, options.allowInsecureHttp);
+      checkedBaseUrls.add(resolved.apiUrl);
+    }
+    if (/^https?:\/\//i.test(config.url ?? "")) {
+      assertSecureTransport(config.url!, options.allowInsecureHttp);
+    }
 
     config.baseURL = resolved.apiUrl;
     config.headers = config.headers ?? {};
 
     if (resolved.apiKey) {
       config.headers.Authorization = `Bearer ${resolved.apiKey}`;
+      (config as AuthenticatedRequestConfig).twentyProfile = resolved.profile;
     } else if ("Authorization" in config.headers) {
       delete config.headers.Authorization;
     }
+    if (resolved.workspaceId) {
+      config.headers[WORKSPACE_ID_HEADER] = resolved.workspaceId;
+    }
+    if (options.retryAttemptHeader) {
+      // axios-retry counts retries on the config before it resends, so this
+      // interceptor sees the count for the attempt about to go out. Any
+      // Idempotency-Key is left as is, so retries stay deduplicated.
+      const retryCount = config["axios-retry"]?.retryCount ?? 0;
+      config.headers[RETRY_ATTEMPT_HEADER] = String(retryCount + 1);
+    }
 
+    const url = `${config.baseURL ?? ""}${config.url ?? ""}`;
+    const method = config.method?.toUpperCase();
     if (options.debug) {
-      const url = `${config.baseURL ?? ""}${config.url ?? ""}`;
-      // eslint-disable-next-line no-console
-      console.error(`→ ${config.method?.toUpperCase()} ${url}`);
+      log("debug", `→ ${method} ${url}`, { method, url });
       if (config.data) {
-        const preview = JSON.stringify(config.data).slice(0, 500);
-        // eslint-disable-next-line no-console
-        console.error(`  Body: ${preview}`);
+        // Redacted by key before truncating, so a cut-off value cannot leak.
+        const preview = JSON.stringify(redactSensitive(config.data)).slice(0, 500);
+        log("debug", `  Body: ${preview}`, { method, url });
       }
+    } else {
+      logVerbose(`→ ${method} ${url}`, { method, url });
     }
 
+    await options.etagCache?.prepare(config, resolved.apiKey);
+    // After the debug preview, which shows the JSON rather than gzip bytes.
+    compressor?.compress(config);
     return config;
   });
 
   client.interceptors.response.use(
     (response) => {
       if (options.debug) {
-        // eslint-disable-next-line no-console
-        console.error(`← ${response.status} ${response.statusText}`);
+        log("debug", `← ${response.status} ${response.statusText}`, { status: response.status });
+      }
+      // Adapters that do not enforce maxContentLength still report the size.
+      const contentLength = Number(response.headers?.["content-length"]);
+      if (Number.isFinite(contentLength) && contentLength > maxResponseSize) {
+        throw responseTooLargeError(maxResponseSize);
       }
-      return response;
+      return options.etagCache ? options.etagCache.resolve(response) : response;
     },
     (error) => {
       if (options.debug) {
-        // eslint-disable-next-line no-console
-        console.error(`← ${error.response?.status ?? ""} ${error.message}`);
+        log("debug", `← ${error.response?.status ?? ""} ${error.message}`, {
+          status: error.response?.status,
+        });
+      }
+      if (typeof error?.message === "string" && error.message.includes("maxContentLength")) {
+        throw responseTooLargeError(maxResponseSize);
+      }
+      if (options.abortOnRateLimit && error?.response?.status === 429) {
+        throw rateLimitedError(error);
+      }
+      const uncompressed = compressor?.fallback(error);
+      if (uncompressed) {
+        return client.request(uncompressed);
       }
       throw error;
     },
@@ -106,6 +250,91 @@ export function createHttpClient(
   return client;
 }
 
+// Retry-After wins when the server sends one; otherwise the backoff strategy
+// sets the delay, on the shorter conflict base for 409s.
+function computeRetryDelay(
+  retryCount: number,
+  error: AxiosError,
+  retryBaseDelay: number,
+  strategy: BackoffStrategy,
+): number {
+  const retryAfter = error.response?.headers?.["retry-after"];
+  if (retryAfter) {
+    const seconds = Number.parseInt(String(retryAfter), 10);
+    if (!Number.isNaN(seconds)) {
+      return seconds * 1000;
+    }
+  }
+  const base = error.response?.status === 409 ? CONFLICT_RETRY_BASE_DELAY_MS : retryBaseDelay;
+  switch (strategy) {
+    case "constant":
+      return base;
+    case "linear":
+      return retryCount * base;
+    default:
+      return Math.pow(2, retryCount) * base + Math.random() * base;
+  }
+}
+
+// A request that failed before any response arrived (reset, refused, DNS
+// lookup failed, timed out), including a command's very first connection.
+// Cancellations and size-limit aborts are deliberate and never retried.
+function isNetworkError(error: AxiosError): boolean {
+  return (
+    error.response === undefined &&
+    error.code !== undefined &&
+    error.code !== AxiosError.ERR_CANCELED &&
+    !error.message.includes("maxContentLength")
+  );
+}
+
+// A reset or timeout leaves it unknown whether the server applied the request.
+// Idempotent methods are safe to resend anyway; a POST only with a
+// caller-supplied idempotency key or with --retry-unsafe. Record creates send a
+// generated key too, but nothing shows the server dedupes on it, so that alone
+// does not make a create safe to repeat.
+function canResend(error: AxiosError, retryUnsafe: boolean | undefined): boolean {
+  if (retryUnsafe || UNSENT_ERROR_CODES.has(error.code ?? "")) {
+    return true;
+  }
+  const method = (error.config?.method ?? "get").toLowerCase();
+  if (IDEMPOTENT_METHODS.has(method)) {
+    return true;
+  }
+  return (error.config as IdempotentRequestConfig | undefined)?.twentyIdempotent === true;
+}
+
+function responseTooLargeError(maxResponseSize: number): CliError {
+  return new CliError(
+    `Response body exceeded the ${maxResponseSize}-byte limit.`,
+    "RESPONSE_TOO_LARGE",
+    "Narrow the request (e.g. --limit or --filter) or raise the limit with --max-body-size.",
+  );
+}
+
+function rateLimitedError(error: AxiosError): CliError {
+  const retryAfter = error.response?.headers?.["retry-after"];
+  return errorWithCause(
+    "Rate limited (HTTP 429); not retrying because --abort-on-rate-limit is set.",
+    "RATE_LIMIT",
+    retryAfter
+      ? `The server asked to retry after ${retryAfter} seconds.`
+      : "Retry later, or drop --abort-on-rate-limit to wait out the backoff.",
+    error,
+  );
+}
+
+function stringifyResponseBody(data: unknown): string {
+  if (typeof data === "string") {
+    return data;
+  }
+  try {
+    return JSON.stringify(data) ?? "";
+  } catch {
+    return "";
+  }
+}
+
 export class ApiService {
   private client: AxiosInstance;
   private configService: ConfigService;
@@ -122,6 +351,8 @@ export class ApiService {
       return {
         apiUrl: resolved.apiUrl,
         apiKey: resolved.apiKey,
+        workspaceId: resolved.workspaceId,
+        profile: resolved.fromConnection ? undefined : resolved.workspace,
       };
     }, options);
   }
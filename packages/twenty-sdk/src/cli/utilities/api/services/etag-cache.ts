@@ -0,0 +1,108 @@
+import crypto from "crypto";
+import os from "os";
+import path from "path";
+import axios, { AxiosResponse, InternalAxiosRequestConfig } from "axios";
+import fs from "fs-extra";
+import { logVerbose } from "../../shared/logger";
+
+interface EtagCacheEntry {
+  etag: string;
+  data: unknown;
+  contentType?: string;
+}
+
+type EtagRequestConfig = InternalAxiosRequestConfig & {
+  twentyEtagKey?: string;
+  twentyEtagEntry?: EtagCacheEntry;
+};
+
+// Conditional GETs for --etag-cache. A GET whose response carried an ETag is
+// stored on disk; the next identical GET sends If-None-Match and a 304 is
+// answered from the stored body. Entries are keyed by token and full URL, so
+// profiles never share bodies. Servers without ETags are left alone, and any
+// cache read or write failure just means a normal request.
+export class EtagCache {
+  constructor(
+    private readonly directory: string = path.join(os.homedir(), ".twenty", "http-cache"),
+  ) {}
+
+  async prepare(config: InternalAxiosRequestConfig, apiKey: string | undefined): Promise<void> {
+    if (!isCacheable(config)) return;
+
+    const key = cacheKey(config, apiKey);
+    const request = config as EtagRequestConfig;
+    request.twentyEtagKey = key;
+    const entry = await this.read(key);
+    if (!entry) return;
+
+    request.twentyEtagEntry = entry;
+    config.headers["If-None-Match"] = entry.etag;
+    const validateStatus = config.validateStatus;
+    config.validateStatus = (status) =>
+      status === 304 || (validateStatus ? validateStatus(status) : status >= 200 && status < 300);
+  }
+
+  async resolve(response: AxiosResponse): Promise<AxiosResponse> {
+    const request = response.config as EtagRequestConfig;
+    const key = request.twentyEtagKey;
+    if (!key) return response;
+
+    if (response.status === 304 && request.twentyEtagEntry) {
+      logVerbose("← 304 Not Modified; using cached response");
+      return { ...response, status: 200, data: request.twentyEtagEntry.data };
+    }
+
+    if (response.status >= 200 && response.status < 300) {
+      const etag = response.headers?.etag;
+      if (typeof etag === "string" && etag !== "") {
+        const contentType = response.headers?.["content-type"];
+        await this.write(key, {
+          etag,
+          data: response.data,
+          ...(typeof contentType === "string" ? { contentType } : {}),
+        });
+      } else if (request.twentyEtagEntry) {
+        // The server stopped sending validators; drop the stale entry.
+        await fs.remove(this.entryPath(key)).catch(() => undefined);
+      }
+    }
+    return response;
+  }
+
+  private async read(key: string): Promise<EtagCacheEntry | undefined> {
+    try {
+      const entry = (await fs.readJson(this.entryPath(key))) as EtagCacheEntry;
+      return typeof entry?.etag === "string" ? entry : undefined;
+    } catch {
+      return undefined;
+    }
+  }
+
+  private async write(key: string, entry: EtagCacheEntry): Promise<void> {
+    try {
+      await fs.outputJson(this.entryPath(key), entry, { mode: 0o600 });
+    } catch {
+      // Best effort: an unwritable cache only costs the next request its 304.
+    }
+  }
+
+  private entryPath(key: string): string {
+    return path.join(this.directory, `${key}.json`);
+  }
+}
+
+// Only plain JSON GETs are cached; downloads and caller-managed validators are not.
+function isCacheable(config: InternalAxiosRequestConfig): boolean {
+  return (
+    (config.method ?? "get").toLowerCase() === "get" &&
+    (config.responseType === undefined || config.responseType === "json") &&
+    config.headers?.["If-None-Match"] === undefined
+  );
+}
+
+function cacheKey(config: InternalAxiosRequestConfig, apiKey: string | undefined): string {
+  return crypto
+    .createHash("sha256")
+    .update(`${apiKey ?? ""}\n${axios.getUri(config)}`)
+    .digest("hex");
+}
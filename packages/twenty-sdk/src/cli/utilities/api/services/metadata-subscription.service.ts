@@ -1,5 +1,8 @@
 import { ConfigService } from "../../config/services/config.service";
 import { CliError } from "../../errors/cli-error";
+import { log } from "../../shared/logger";
+import { WORKSPACE_ID_HEADER } from "./api.service";
+import { assertSecureTransport } from "./transport-security";
 
 interface GraphqlSubscriptionResponse<T = unknown> {
   data?: T;
@@ -14,6 +17,7 @@ interface SseMessage {
 export interface MetadataSubscriptionServiceOptions {
   workspace?: string;
   debug?: boolean;
+  allowInsecureHttp?: boolean;
 }
 
 export interface MetadataSubscriptionRequest {
@@ -33,10 +37,10 @@ export class MetadataSubscriptionService {
       workspace: this.options.workspace,
     });
     const url = `${resolved.apiUrl.replace(/\/+$/, "")}/metadata`;
+    assertSecureTransport(url, this.options.allowInsecureHttp);
 
     if (this.options.debug) {
-      // eslint-disable-next-line no-console
-      console.error(`→ SUBSCRIBE ${url}`);
+      log("debug", `→ SUBSCRIBE ${url}`, { url });
     }
 
     const response = await fetch(url, {
@@ -45,6 +49,7 @@ export class MetadataSubscriptionService {
         "content-type": "application/json",
         accept: "text/event-stream",
         authorization: `Bearer ${resolved.apiKey}`,
+        ...(resolved.workspaceId ? { [WORKSPACE_ID_HEADER]: resolved.workspaceId } : {}),
       },
       body: JSON.stringify({
         query: request.query,
@@ -47,6 +47,8 @@ export class PublicHttpService {
       return {
         apiUrl: resolved.apiUrl,
         apiKey: authMode === "none" ? undefined : resolved.apiKey,
+        workspaceId: resolved.workspaceId,
+        profile: resolved.fromConnection ? undefined : resolved.workspace,
       };
     }, options);
   }
@@ -0,0 +1,63 @@
+import { gzipSync } from "zlib";
+import { AxiosError, AxiosHeaders, InternalAxiosRequestConfig } from "axios";
+import { logVerbose } from "../../shared/logger";
+
+// Smaller bodies are sent as-is; gzip saves little on them.
+export const COMPRESS_MIN_BYTES = 1024;
+
+type CompressibleRequestConfig = InternalAxiosRequestConfig & {
+  // The JSON body before compression, resent as-is after a 415.
+  twentyUncompressedData?: unknown;
+  twentySkipCompression?: boolean;
+};
+
+// --compress-requests: JSON bodies of at least COMPRESS_MIN_BYTES are gzipped
+// and sent with Content-Encoding: gzip. Not every server accepts that, so a
+// 415 answer to a compressed request resends it uncompressed and turns
+// compression off for the rest of the run.
+export class RequestCompressor {
+  private rejected = false;
+
+  compress(config: InternalAxiosRequestConfig): void {
+    const request = config as CompressibleRequestConfig;
+    if (this.rejected || request.twentySkipCompression || !isJsonBody(config.data)) return;
+
+    const json = JSON.stringify(config.data);
+    if (Buffer.byteLength(json) < COMPRESS_MIN_BYTES) return;
+
+    request.twentyUncompressedData = config.data;
+    config.data = gzipSync(json);
+    config.headers["Content-Type"] = "application/json";
+    config.headers["Content-Encoding"] = "gzip";
+  }
+
+  // The request to resend uncompressed, when the server refused a gzip body.
+  fallback(error: AxiosError): InternalAxiosRequestConfig | undefined {
+    const request = error.config as CompressibleRequestConfig | undefined;
+    if (error.response?.status !== 415 || request?.twentyUncompressedData === undefined) {
+      return undefined;
+    }
+
+    this.rejected = true;
+    logVerbose("Server rejected a gzip request body (415); sending requests uncompressed");
+    const headers = AxiosHeaders.from(request.headers);
+    headers.delete("Content-Encoding");
+    headers.delete("Content-Length");
+    return {
+      ...request,
+      headers,
+      data: request.twentyUncompressedData,
+      twentyUncompressedData: undefined,
+      twentySkipCompression: true,
+    } as CompressibleRequestConfig;
+  }
+}
+
+// Plain objects and arrays, which axios would serialize as JSON. Strings
+// (form bodies), buffers, and streams are left alone.
+function isJsonBody(data: unknown): boolean {
+  if (Array.isArray(data)) return true;
+  if (typeof data !== "object" || data === null) return false;
+  const prototype = Object.getPrototypeOf(data);
+  return prototype === Object.prototype || prototype === null;
+}
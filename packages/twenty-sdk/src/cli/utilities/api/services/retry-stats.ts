@@ -0,0 +1,60 @@
+import { log } from "../../shared/logger";
+
+// Process-wide retry counters for --show-retry-stats. Every HTTP client in a
+// command records into the same totals; only statuses and delays are kept,
+// never URLs, headers, or bodies.
+export interface RetryStats {
+  retries: number;
+  waitedMs: number;
+  // Retries per trigger: an HTTP status such as "503", or "network".
+  reasons: Record<string, number>;
+}
+
+let reportEnabled = false;
+let stats: RetryStats = emptyStats();
+
+export function configureRetryStats(enabled: boolean | undefined): void {
+  reportEnabled = Boolean(enabled);
+}
+
+export function recordRetry(status: number | undefined, delayMs: number): void {
+  const reason = status === undefined ? "network" : String(status);
+  stats.retries += 1;
+  stats.waitedMs += Math.max(0, delayMs);
+  stats.reasons[reason] = (stats.reasons[reason] ?? 0) + 1;
+}
+
+export function getRetryStats(): RetryStats {
+  return { ...stats, reasons: { ...stats.reasons } };
+}
+
+export function resetRetryStats(): void {
+  stats = emptyStats();
+}
+
+// e.g. "retries: 3, waited 7.2s (503 x2, network x1)"
+export function formatRetryStats(value: RetryStats): string {
+  const reasons = Object.entries(value.reasons)
+    .sort(([a], [b]) => a.localeCompare(b))
+    .map(([reason, count]) => `${reason} x${count}`);
+  const summary = `retries: ${value.retries}, waited ${formatDuration(value.waitedMs)}`;
+  return reasons.length > 0 ? `${summary} (${reasons.join(", ")})` : summary;
+}
+
+// Called once when the command finishes, whether it succeeded or failed.
+export function reportRetryStats(): void {
+  if (reportEnabled) {
+    log("info", formatRetryStats(stats), { ...getRetryStats() });
+  }
+}
+
+function emptyStats(): RetryStats {
+  return { retries: 0, waitedMs: 0, reasons: {} };
+}
+
+function formatDuration(ms: number): string {
+  if (ms < 1000) {
+    return `${Math.round(ms)}ms`;
+  }
+  return `${(ms / 1000).toFixed(1).replace(/\.0$/, "")}s`;
+}
@@ -0,0 +1,32 @@
+import { CliError } from "../../errors/cli-error";
+
+// Plaintext HTTP is fine for a local instance (and the auth callback), but a
+// remote http:// URL would send the API token in the clear.
+export function assertSecureTransport(url: string, allowInsecureHttp = false): void {
+  let parsed: URL;
+  try {
+    parsed = new URL(url);
+  } catch {
+    // Malformed URLs are reported by the transport itself.
+    return;
+  }
+
+  if (parsed.protocol !== "http:" || allowInsecureHttp || isLoopbackHost(parsed.hostname)) {
+    return;
+  }
+
+  throw new CliError(
+    `Refusing to send requests to ${parsed.origin} over plaintext HTTP.`,
+    "INVALID_ARGUMENTS",
+    "Use an https:// base URL, or pass --insecure-allow-http (TWENTY_INSECURE_ALLOW_HTTP=true) for a trusted network.",
+  );
+}
+
+function isLoopbackHost(hostname: string): boolean {
+  return (
+    hostname === "localhost" ||
+    hostname.endsWith(".localhost") ||
+    hostname === "[::1]" ||
+    /^127(\.\d{1,3}){3}$/.test(hostname)
+  );
+}
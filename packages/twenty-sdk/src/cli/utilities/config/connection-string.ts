@@ -0,0 +1,69 @@
+import { CliError } from "../errors/cli-error";
+import { normalizeWorkspaceId } from "./workspace-id";
+
+export interface ConnectionSettings {
+  apiUrl: string;
+  apiKey: string;
+  workspaceId?: string;
+  // Set for TWENTY_CONNECTION, whose token ranks below --token-file.
+  fromEnv?: boolean;
+}
+
+// twenty:// connects over https; twenty+http:// is for local servers and is
+// still subject to --insecure-allow-http.
+const CONNECTION_SCHEMES: Record<string, string> = {
+  "twenty:": "https:",
+  "twenty+http:": "http:",
+};
+
+// --connection / TWENTY_CONNECTION: twenty://<token>@<host>[:port][/<workspace-id>].
+// The string carries the token, so errors name its source but never repeat it.
+export function parseConnectionString(value: string, source: string): ConnectionSettings {
+  let url: URL;
+  try {
+    url = new URL(value.trim());
+  } catch {
+    throw invalidConnection(source, "it is not a URL");
+  }
+
+  const protocol = CONNECTION_SCHEMES[url.protocol];
+  if (!protocol) {
+    throw invalidConnection(source, "use the twenty:// or twenty+http:// scheme");
+  }
+  // A token containing ":" would be split into username and password.
+  const userinfo = url.password ? `${url.username}:${url.password}` : url.username;
+  let apiKey: string;
+  try {
+    apiKey = decodeURIComponent(userinfo);
+  } catch {
+    throw invalidConnection(source, "the token has a malformed percent-encoding");
+  }
+  if (!apiKey) {
+    throw invalidConnection(source, "the token before @ is missing");
+  }
+  if (!url.host) {
+    throw invalidConnection(source, "the host is missing");
+  }
+  if (url.search || url.hash) {
+    throw invalidConnection(source, "query strings and fragments are not supported");
+  }
+
+  const segments = url.pathname.split("/").filter(Boolean);
+  if (segments.length > 1) {
+    throw invalidConnection(source, "the path may only hold a workspace ID");
+  }
+
+  return {
+    apiUrl: `${protocol}//${url.host}`,
+    apiKey,
+    ...(segments[0] ? { workspaceId: normalizeWorkspaceId(segments[0], source) } : {}),
+  };
+}
+
+function invalidConnection(source: string, reason: string): CliError {
+  return new CliError(
+    `Invalid connection string from ${source}: ${reason}.`,
+    "INVALID_ARGUMENTS",
+    "Use twenty://<token>@<host>[/<workspace-id>], percent-encoding special characters.",
+  );
+}
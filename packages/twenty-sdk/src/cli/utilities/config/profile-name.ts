@@ -0,0 +1,18 @@
+import { CliError } from "../errors/cli-error";
+
+const PROFILE_NAME_PATTERN = /^[a-z0-9][a-z0-9._-]*$/;
+
+// Profile names are stored lowercase with dashes so "Staging EU" and
+// "staging-eu" address the same entry.
+export function normalizeProfileName(name: string): string {
+  const normalized = name.trim().toLowerCase().replace(/\s+/g, "-");
+  if (!PROFILE_NAME_PATTERN.test(normalized)) {
+    throw new CliError(
+      `Invalid profile name ${JSON.stringify(name)}.`,
+      "INVALID_ARGUMENTS",
+      "Use letters, digits, dots, dashes, or underscores.",
+    );
+  }
+
+  return normalized;
+}
@@ -22,7 +22,12 @@ function getWorkspaceDbConfig(config: TwentyConfigFile, workspace: string) {
 describe("ConfigService", () => {
   const mockHomedir = "/home/testuser";
   const mockConfigPath = `${mockHomedir}/.twenty/config.json`;
-  const envKeys = ["TWENTY_TOKEN", "TWENTY_BASE_URL", "TWENTY_PROFILE"] as const;
+  const envKeys = [
+    "TWENTY_TOKEN",
+    "TWENTY_TOKEN_FILE",
+    "TWENTY_BASE_URL",
+    "TWENTY_PROFILE",
+  ] as const;
   let originalEnv: NodeJS.ProcessEnv;
 
   beforeEach(() => {
@@ -161,6 +166,214 @@ describe("ConfigService", () => {
     });
   });
 
+  describe("profile picker", () => {
+    const mockConfig = (config: TwentyConfigFile) => {
+      vi.mocked(fs.pathExists).mockResolvedValue(true as never);
+      vi.mocked(fs.readFile).mockResolvedValue(JSON.stringify(config) as never);
+    };
+
+    it("asks once when no profile is selected and no default is set", async () => {
+      mockConfig({
+        workspaces: {
+          staging: { apiUrl: "https://staging.example.com", apiKey: "staging-token" },
+          production: { apiUrl: "https://crm.example.com", apiKey: "prod-token" },
+        },
+      });
+      const selectWorkspace = vi.fn().mockResolvedValue("staging");
+      const service = new ConfigService(undefined, { selectWorkspace });
+
+      const first = await service.getConfig();
+      const second = await service.getConfig();
+
+      expect(selectWorkspace).toHaveBeenCalledTimes(1);
+      expect(selectWorkspace).toHaveBeenCalledWith(["staging", "production"]);
+      expect(first).toEqual({
+        apiUrl: "https://staging.example.com",
+        apiKey: "staging-token",
+        workspace: "staging",
+      });
+      expect(second.workspace).toBe("staging");
+    });
+
+    it("does not ask when a profile is selected or a default exists", async () => {
+      mockConfig({
+        workspaces: { staging: { apiKey: "staging-token" }, production: { apiKey: "prod" } },
+        defaultWorkspace: "production",
+      });
+      const selectWorkspace = vi.fn();
+      const service = new ConfigService(undefined, { selectWorkspace });
+
+      expect((await service.getConfig()).workspace).toBe("production");
+      expect((await service.getConfig({ workspace: "staging" })).workspace).toBe("staging");
+      expect(selectWorkspace).not.toHaveBeenCalled();
+    });
+
+    it("keeps the missing-token error when nothing is picked", async () => {
+      mockConfig({ workspaces: { staging: { apiKey: "staging-token" } } });
+      const service = new ConfigService(undefined, {
+        selectWorkspace: vi.fn().mockResolvedValue(undefined),
+      });
+
+      await expect(service.getConfig()).rejects.toMatchObject({ code: "AUTH" });
+    });
+
+    it("asks despite a default with alwaysSelectWorkspace, and needs an answer", async () => {
+      mockConfig({
+        workspaces: { staging: { apiKey: "staging-token" }, production: { apiKey: "prod" } },
+        defaultWorkspace: "production",
+      });
+      const picked = new ConfigService(undefined, {
+        selectWorkspace: vi.fn().mockResolvedValue("staging"),
+        alwaysSelectWorkspace: true,
+      });
+      const unanswered = new ConfigService(undefined, {
+        selectWorkspace: vi.fn().mockResolvedValue(undefined),
+        alwaysSelectWorkspace: true,
+      });
+
+      expect((await picked.getConfig()).workspace).toBe("staging");
+      await expect(unanswered.getConfig()).rejects.toMatchObject({
+        message: "--select-profile needs an interactive terminal.",
+        code: "INVALID_ARGUMENTS",
+      });
+    });
+  });
+
+  describe("workspace ID", () => {
+    it("prefers the option over the profile's workspaceId and validates the profile", async () => {
+      const config: TwentyConfigFile = {
+        workspaces: {
+          default: { apiKey: "token", workspaceId: "3B8E6458-5FC1-4E63-8563-008CCDDAA6DB" },
+          broken: { apiKey: "token", workspaceId: "acme" },
+        },
+      };
+      vi.mocked(fs.pathExists).mockResolvedValue(true as never);
+      vi.mocked(fs.readFile).mockResolvedValue(JSON.stringify(config) as never);
+
+      const fromProfile = await new ConfigService().getConfig();
+      const fromOption = await new ConfigService(undefined, {
+        workspaceId: "00000000-0000-4000-8000-000000000000",
+      }).getConfig();
+
+      expect(fromProfile.workspaceId).toBe("3b8e6458-5fc1-4e63-8563-008ccddaa6db");
+      expect(fromOption.workspaceId).toBe("00000000-0000-4000-8000-000000000000");
+      await expect(new ConfigService().getConfig({ workspace: "broken" })).rejects.toMatchObject({
+        message: 'Invalid workspace ID "acme" from profile "broken"; expected a UUID.',
+        code: "INVALID_ARGUMENTS",
+      });
+    });
+  });
+
+  describe("token files", () => {
+    const config: TwentyConfigFile = {
+      workspaces: { default: { apiKey: "stored-token" } },
+      defaultWorkspace: "default",
+    };
+
+    function mockFiles(files: Record<string, string>) {
+      vi.mocked(fs.pathExists).mockResolvedValue(true as never);
+      vi.mocked(fs.readFile).mockImplementation((async (filePath: string) => {
+        if (filePath === mockConfigPath) return JSON.stringify(config);
+        if (filePath in files) return files[filePath];
+        throw new Error("ENOENT");
+      }) as never);
+    }
+
+    it("reads and trims TWENTY_TOKEN_FILE ahead of the stored apiKey", async () => {
+      mockFiles({ "/run/secrets/twenty": "  mounted-token\n" });
+      process.env.TWENTY_TOKEN_FILE = "/run/secrets/twenty";
+
+      const result = await new ConfigService().resolveApiConfig();
+
+      expect(result.apiKey).toBe("mounted-token");
+    });
+
+    it("prefers TWENTY_TOKEN over TWENTY_TOKEN_FILE", async () => {
+      mockFiles({ "/run/secrets/twenty": "mounted-token" });
+      process.env.TWENTY_TOKEN = "env-token";
+      process.env.TWENTY_TOKEN_FILE = "/run/secrets/twenty";
+
+      const result = await new ConfigService().resolveApiConfig();
+
+      expect(result.apiKey).toBe("env-token");
+    });
+
+    it("prefers the --token-file option over TWENTY_TOKEN", async () => {
+      mockFiles({ "/tmp/flag-token": "flag-token\n" });
+      process.env.TWENTY_TOKEN = "env-token";
+
+      const service = new ConfigService(undefined, { tokenFile: "/tmp/flag-token" });
+      const result = await service.resolveApiConfig();
+
+      expect(result.apiKey).toBe("flag-token");
+    });
+
+    it("prefers a connection string over the flag, env, and profile settings", async () => {
+      mockFiles({ "/tmp/flag-token": "flag-token\n" });
+      process.env.TWENTY_TOKEN = "env-token";
+      process.env.TWENTY_BASE_URL = "https://env.example.com";
+      const workspaceId = "1b4e28ba-2fa1-11d2-883f-0016d3cca427";
+
+      const service = new ConfigService(undefined, {
+        tokenFile: "/tmp/flag-token",
+        workspaceId: "6f9619ff-8b86-d011-b42d-00c04fc964ff",
+        connection: { apiUrl: "https://crm.example.com", apiKey: "dsn-token", workspaceId },
+      });
+      const result = await service.resolveApiConfig();
+
+      expect(result).toMatchObject({
+        apiUrl: "https://crm.example.com",
+        apiKey: "dsn-token",
+        workspaceId,
+        fromConnection: true,
+      });
+    });
+
+    it("prefers --token-file over a TWENTY_CONNECTION token", async () => {
+      mockFiles({ "/tmp/flag-token": "flag-token\n" });
+
+      const service = new ConfigService(undefined, {
+        tokenFile: "/tmp/flag-token",
+        connection: { apiUrl: "https://crm.example.com", apiKey: "dsn-token", fromEnv: true },
+      });
+      const result = await service.resolveApiConfig();
+
+      expect(result.apiUrl).toBe("https://crm.example.com");
+      expect(result.apiKey).toBe("flag-token");
+      expect(result.fromConnection).toBeUndefined();
+    });
+
+    it("fails clearly when the token file cannot be read or is empty", async () => {
+      mockFiles({ "/tmp/empty": " \n" });
+
+      await expect(
+        new ConfigService(undefined, { tokenFile: "/tmp/missing" }).resolveApiConfig(),
+      ).rejects.toMatchObject({
+        message: "Failed to read token file /tmp/missing",
+        code: "AUTH",
+      });
+      await expect(
+        new ConfigService(undefined, { tokenFile: "/tmp/empty" }).resolveApiConfig(),
+      ).rejects.toMatchObject({ message: "Token file /tmp/empty is empty", code: "AUTH" });
+    });
+  });
+
+  describe("hasDefaultWorkspace", () => {
+    it("is true only when the default names an existing workspace", async () => {
+      vi.mocked(fs.pathExists).mockResolvedValue(true as never);
+      const service = new ConfigService();
+
+      for (const [config, expected] of [
+        [{ workspaces: { prod: { apiKey: "key1" } }, defaultWorkspace: "prod" }, true],
+        [{ workspaces: { prod: { apiKey: "key1" } }, defaultWorkspace: "gone" }, false],
+        [{ workspaces: { prod: { apiKey: "key1" } } }, false],
+      ] as const) {
+        vi.mocked(fs.readFile).mockResolvedValueOnce(JSON.stringify(config) as never);
+        await expect(service.hasDefaultWorkspace()).resolves.toBe(expected);
+      }
+    });
+  });
+
   describe("setDefaultWorkspace", () => {
     it("throws if workspace does not exist", async () => {
       const config: TwentyConfigFile = {
@@ -291,6 +504,57 @@ describe("ConfigService", () => {
     });
   });
 
+  describe("token rotation", () => {
+    it("stages a next token without touching the active one", async () => {
+      const config: TwentyConfigFile = {
+        workspaces: { prod: { apiKey: "key1" } },
+        defaultWorkspace: "prod",
+      };
+      vi.mocked(fs.pathExists).mockResolvedValue(true as never);
+      vi.mocked(fs.readFile).mockResolvedValue(JSON.stringify(config) as never);
+      vi.mocked(fs.outputFile).mockResolvedValue(undefined as never);
+
+      const service = new ConfigService();
+      await service.stageWorkspaceToken("prod", "key2");
+
+      const saved = JSON.parse(vi.mocked(fs.outputFile).mock.calls[0][1] as string);
+      expect(saved.workspaces.prod).toEqual({ apiKey: "key1", nextApiKey: "key2" });
+    });
+
+    it("promotes the staged token in a single write", async () => {
+      const config: TwentyConfigFile = {
+        workspaces: { prod: { apiKey: "key1", nextApiKey: "key2" } },
+        defaultWorkspace: "prod",
+      };
+      vi.mocked(fs.pathExists).mockResolvedValue(true as never);
+      vi.mocked(fs.readFile).mockResolvedValue(JSON.stringify(config) as never);
+      vi.mocked(fs.outputFile).mockResolvedValue(undefined as never);
+
+      const service = new ConfigService();
+      await service.promoteWorkspaceToken("prod");
+
+      expect(fs.outputFile).toHaveBeenCalledTimes(1);
+      const saved = JSON.parse(vi.mocked(fs.outputFile).mock.calls[0][1] as string);
+      expect(saved.workspaces.prod).toEqual({ apiKey: "key2" });
+    });
+
+    it("throws when promoting without a staged token", async () => {
+      const config: TwentyConfigFile = {
+        workspaces: { prod: { apiKey: "key1" } },
+        defaultWorkspace: "prod",
+      };
+      vi.mocked(fs.pathExists).mockResolvedValue(true as never);
+      vi.mocked(fs.readFile).mockResolvedValue(JSON.stringify(config) as never);
+
+      const service = new ConfigService();
+
+      await expect(service.promoteWorkspaceToken("prod")).rejects.toThrow(
+        "Workspace 'prod' has no staged token",
+      );
+      expect(fs.outputFile).not.toHaveBeenCalled();
+    });
+  });
+
   describe("removeWorkspace", () => {
     it("removes workspace from config", async () => {
       const config: TwentyConfigFile = {
@@ -0,0 +1,97 @@
+import os from "os";
+import path from "path";
+import fs from "fs-extra";
+import { afterEach, beforeEach, describe, expect, it } from "vitest";
+import {
+  findConfiguredOutput,
+  readConfiguredOutput,
+  readConfiguredQuery,
+  readConfiguredRetries,
+} from "../output-defaults";
+
+describe("findConfiguredOutput", () => {
+  const settings = {
+    default: "json",
+    people: { list: "table", default: "text" },
+    "companies.list": "csv",
+    rest: "jsonl",
+  };
+
+  it("prefers the most specific command entry", () => {
+    expect(findConfiguredOutput(settings, ["people", "list"], "output")).toEqual({
+      format: "table",
+      key: "output.people.list",
+    });
+    expect(findConfiguredOutput(settings, ["companies", "list"], "output")).toEqual({
+      format: "csv",
+      key: "output.companies.list",
+    });
+  });
+
+  it("falls back to the nearest default", () => {
+    expect(findConfiguredOutput(settings, ["people", "get"], "output")?.format).toBe("text");
+    expect(findConfiguredOutput(settings, ["companies", "get"], "output")?.format).toBe("json");
+  });
+
+  it("applies a string entry to every subcommand", () => {
+    expect(findConfiguredOutput(settings, ["rest", "get"], "output")?.format).toBe("jsonl");
+    expect(findConfiguredOutput("table", ["people", "list"], "output")).toEqual({
+      format: "table",
+      key: "output",
+    });
+  });
+
+  it("returns undefined when nothing matches", () => {
+    expect(findConfiguredOutput({ people: { list: "table" } }, ["tasks"], "output")).toBe(
+      undefined,
+    );
+  });
+});
+
+describe("readConfiguredOutput", () => {
+  let dir: string;
+
+  beforeEach(async () => {
+    dir = await fs.mkdtemp(path.join(os.tmpdir(), "twenty-output-defaults-"));
+  });
+
+  afterEach(async () => {
+    await fs.remove(dir);
+  });
+
+  it("reads the output settings from the config file", async () => {
+    const configPath = path.join(dir, "config.json");
+    await fs.writeJson(configPath, { output: { people: { list: "table" } } });
+
+    expect(readConfiguredOutput(["people", "list"], configPath)?.format).toBe("table");
+    expect(readConfiguredOutput(["people", "get"], configPath)).toBe(undefined);
+  });
+
+  it("reads a non-empty defaultQuery", async () => {
+    const configPath = path.join(dir, "config.json");
+    await fs.writeJson(configPath, { defaultQuery: "data[].{id: id, name: name}" });
+    expect(readConfiguredQuery(configPath)).toBe("data[].{id: id, name: name}");
+
+    await fs.writeJson(configPath, { defaultQuery: " " });
+    expect(readConfiguredQuery(configPath)).toBe(undefined);
+  });
+
+  it("reads the retry settings as strings for the flag parser", async () => {
+    const configPath = path.join(dir, "config.json");
+    await fs.writeJson(configPath, { maxRetries: 5, retryBaseDelay: "2000", defaultQuery: "x" });
+    expect(readConfiguredRetries(configPath)).toEqual({ maxRetries: "5", retryBaseDelay: "2000" });
+
+    await fs.writeJson(configPath, { maxRetries: null });
+    expect(readConfiguredRetries(configPath)).toEqual({});
+  });
+
+  it("ignores a missing or unreadable config file", async () => {
+    const configPath = path.join(dir, "config.json");
+    expect(readConfiguredOutput(["people", "list"], configPath)).toBe(undefined);
+
+    await fs.writeFile(configPath, "{not json");
+    expect(readConfiguredOutput(["people", "list"], configPath)).toBe(undefined);
+    expect(readConfiguredQuery(configPath)).toBe(undefined);
+    expect(readConfiguredRetries(configPath)).toBe(undefined);
+  });
+});
@@ -2,10 +2,18 @@ import os from "os";
 import path from "path";
 import fs from "fs-extra";
 import { CliError } from "../../errors/cli-error";
+import { ConnectionSettings } from "../connection-string";
+import { normalizeWorkspaceId } from "../workspace-id";
+import type { OutputDefaultsConfig } from "./output-defaults";
 
 export interface WorkspaceConfig {
   apiUrl?: string;
   apiKey?: string;
+  // Staged replacement for apiKey; never used for requests until promoted.
+  nextApiKey?: string;
+  // Sent as X-Workspace-Id so one token can target a workspace on an instance
+  // that serves several.
+  workspaceId?: string;
   db?: WorkspaceDbConfig;
 }
 
@@ -30,6 +38,13 @@ export interface WorkspaceDbConfig {
 export interface TwentyConfigFile {
   workspaces?: Record<string, WorkspaceConfig>;
   defaultWorkspace?: string;
+  // Output format defaults, per command; see output-defaults.ts.
+  output?: OutputDefaultsConfig;
+  // JMESPath query used when no --query or TWENTY_QUERY is given.
+  defaultQuery?: string;
+  // Retry defaults used when no flag or TWENTY_* variable is given.
+  maxRetries?: number;
+  retryBaseDelay?: number;
 }
 
 export interface WorkspaceInfo {
@@ -42,6 +57,9 @@ export interface ResolvedConfig {
   apiUrl: string;
   apiKey: string;
   workspace?: string;
+  workspaceId?: string;
+  // The token came from --connection/TWENTY_CONNECTION, not from the profile.
+  fromConnection?: boolean;
 }
 
 export interface ConfigOverrides {
@@ -55,11 +73,32 @@ export interface ResolveApiConfigOptions extends ConfigOverrides {
   missingAuthSuggestion?: string;
 }
 
+export interface ConfigServiceOptions {
+  // Token file from --token-file; wins over TWENTY_CONNECTION, TWENTY_TOKEN,
+  // and TWENTY_TOKEN_FILE.
+  tokenFile?: string;
+  // --connection or TWENTY_CONNECTION; its base URL, token, and workspace ID
+  // win over every other source except explicit overrides, and except
+  // --token-file over a TWENTY_CONNECTION token.
+  connection?: ConnectionSettings;
+  // --workspace-id or TWENTY_WORKSPACE_ID; wins over the profile's workspaceId.
+  workspaceId?: string;
+  // Asks which profile to use when none is selected and no default is set.
+  // Returning undefined keeps the fallback to the "default" profile.
+  selectWorkspace?: (names: string[]) => Promise<string | undefined>;
+  // --select-profile: ask even when a default profile is set.
+  alwaysSelectWorkspace?: boolean;
+}
+
 export class ConfigService {
   private configPath: string;
+  private options: ConfigServiceOptions;
+  // The picker runs at most once per process; every request reuses the answer.
+  private selectedWorkspace?: Promise<string | undefined>;
 
-  constructor(configPath?: string) {
+  constructor(configPath?: string, options: ConfigServiceOptions = {}) {
     this.configPath = configPath ?? path.join(os.homedir(), ".twenty", "config.json");
+    this.options = options;
   }
 
   async loadConfigFile(): Promise<TwentyConfigFile | null> {
@@ -88,14 +127,18 @@ export class ConfigService {
       apiUrl: resolved.apiUrl,
       apiKey: resolved.apiKey,
       workspace: resolved.workspace,
+      ...(resolved.workspaceId ? { workspaceId: resolved.workspaceId } : {}),
+      ...(resolved.fromConnection ? { fromConnection: true } : {}),
     };
   }
 
   async resolveApiConfig(overrides?: ResolveApiConfigOptions): Promise<ResolvedConfig> {
     const fileConfig = await this.loadConfigFile();
+    const connection = this.options.connection;
     const workspace =
       overrides?.workspace ??
       process.env.TWENTY_PROFILE ??
+      (connection ? undefined : await this.selectWorkspace(fileConfig)) ??
       fileConfig?.defaultWorkspace ??
       "default";
 
@@ -103,11 +146,28 @@ export class ConfigService {
 
     const apiUrl =
       overrides?.apiUrl ??
+      connection?.apiUrl ??
       process.env.TWENTY_BASE_URL ??
       workspaceConfig.apiUrl ??
       "https://api.twenty.com";
 
-    const apiKey = overrides?.apiKey ?? process.env.TWENTY_TOKEN ?? workspaceConfig.apiKey ?? "";
+    // Token precedence: explicit override, --connection, --token-file,
+    // TWENTY_CONNECTION, TWENTY_TOKEN, TWENTY_TOKEN_FILE, then the workspace's
+    // stored apiKey. A flag beats an environment variable.
+    const tokenFromConnection =
+      !overrides?.apiKey &&
+      connection !== undefined &&
+      (!connection.fromEnv || !this.options.tokenFile);
+    const apiKey =
+      overrides?.apiKey ??
+      (tokenFromConnection ? connection?.apiKey : undefined) ??
+      (this.options.tokenFile ? await readTokenFile(this.options.tokenFile) : undefined) ??
+      process.env.TWENTY_TOKEN ??
+      (process.env.TWENTY_TOKEN_FILE
+        ? await readTokenFile(process.env.TWENTY_TOKEN_FILE)
+        : undefined) ??
+      workspaceConfig.apiKey ??
+      "";
 
     if (overrides?.requireAuth && !apiKey) {
       throw new CliError(
@@ -118,13 +178,50 @@ export class ConfigService {
       );
     }
 
+    // A connection string stands in for the profile, so the profile's stored
+    // workspaceId is not mixed into it; --workspace-id still applies.
+    const profileWorkspaceId = connection ? undefined : workspaceConfig.workspaceId;
+    const workspaceId =
+      connection?.workspaceId ??
+      this.options.workspaceId ??
+      (profileWorkspaceId
+        ? normalizeWorkspaceId(profileWorkspaceId, `profile "${workspace}"`)
+        : undefined);
+
     return {
       apiUrl,
       apiKey,
       workspace,
+      ...(workspaceId ? { workspaceId } : {}),
+      ...(tokenFromConnection ? { fromConnection: true } : {}),
     };
   }
 
+  private async selectWorkspace(fileConfig: TwentyConfigFile | null): Promise<string | undefined> {
+    const names = Object.keys(fileConfig?.workspaces ?? {});
+    const select = this.options.selectWorkspace;
+    if (!select || names.length === 0) {
+      return undefined;
+    }
+    const defaultName = fileConfig?.defaultWorkspace;
+    const hasDefault =
+      (defaultName !== undefined && names.includes(defaultName)) || names.includes("default");
+    if (hasDefault && !this.options.alwaysSelectWorkspace) {
+      return undefined;
+    }
+    this.selectedWorkspace ??= select(names).then((name) => {
+      if (name === undefined && this.options.alwaysSelectWorkspace) {
+        throw new CliError(
+          "--select-profile needs an interactive terminal.",
+          "INVALID_ARGUMENTS",
+          "Pass --profile <name> instead.",
+        );
+      }
+      return name;
+    });
+    return this.selectedWorkspace;
+  }
+
   async listWorkspaces(): Promise<WorkspaceInfo[]> {
     const config = await this.loadConfigFile();
     if (!config?.workspaces) {
@@ -138,6 +235,12 @@ export class ConfigService {
     }));
   }
 
+  async hasDefaultWorkspace(): Promise<boolean> {
+    const config = await this.loadConfigFile();
+    const name = config?.defaultWorkspace;
+    return name !== undefined && config?.workspaces?.[name] !== undefined;
+  }
+
   async setDefaultWorkspace(name: string): Promise<void> {
     const config = await this.loadConfigFile();
     if (!config?.workspaces?.[name]) {
@@ -178,6 +281,45 @@ export class ConfigService {
     await this.saveConfigFile(config);
   }
 
+  async stageWorkspaceToken(name: string, token: string): Promise<void> {
+    const config = await this.loadConfigFile();
+    if (!config) {
+      throw new CliError(
+        `Workspace '${name}' does not exist`,
+        "INVALID_ARGUMENTS",
+        'Use "twenty auth list" to see available workspaces.',
+      );
+    }
+    const workspaceConfig = this.ensureWorkspaceExists(config, name);
+
+    workspaceConfig.nextApiKey = token;
+    await this.saveConfigFile(config);
+  }
+
+  async promoteWorkspaceToken(name: string): Promise<void> {
+    const config = await this.loadConfigFile();
+    if (!config) {
+      throw new CliError(
+        `Workspace '${name}' does not exist`,
+        "INVALID_ARGUMENTS",
+        'Use "twenty auth list" to see available workspaces.',
+      );
+    }
+    const workspaceConfig = this.ensureWorkspaceExists(config, name);
+    if (!workspaceConfig.nextApiKey) {
+      throw new CliError(
+        `Workspace '${name}' has no staged token`,
+        "INVALID_ARGUMENTS",
+        'Use "twenty auth stage-token --token <token>" first.',
+      );
+    }
+
+    // Swap in a single config write so the workspace never lacks a usable token.
+    workspaceConfig.apiKey = workspaceConfig.nextApiKey;
+    delete workspaceConfig.nextApiKey;
+    await this.saveConfigFile(config);
+  }
+
   async removeWorkspace(name: string): Promise<void> {
     const config = await this.loadConfigFile();
     if (!config?.workspaces?.[name]) {
@@ -330,3 +472,23 @@ export class ConfigService {
     return profile;
   }
 }
+
+async function readTokenFile(filePath: string): Promise<string> {
+  let content: string;
+  try {
+    content = await fs.readFile(filePath, "utf-8");
+  } catch {
+    throw new CliError(
+      `Failed to read token file ${filePath}`,
+      "AUTH",
+      "Check that the file exists and is readable, or unset TWENTY_TOKEN_FILE/--token-file.",
+    );
+  }
+
+  const token = content.trim();
+  if (!token) {
+    throw new CliError(`Token file ${filePath} is empty`, "AUTH");
+  }
+
+  return token;
+}
@@ -0,0 +1,100 @@
+import os from "os";
+import path from "path";
+import fs from "fs-extra";
+
+// The "output" key of ~/.twenty/config.json: either one format for every
+// command, or a tree keyed by command path with "default" as the fallback at
+// each level, e.g. {"default": "json", "people": {"list": "table"}}. Dotted
+// keys ("people.list") work too.
+export type OutputDefaultsConfig = string | { [key: string]: OutputDefaultsConfig };
+
+export interface ConfiguredOutput {
+  format: string;
+  // The config key the format came from, for error messages.
+  key: string;
+}
+
+// Reads the config synchronously because global options are resolved before
+// any service exists. An unreadable file is ignored here; ConfigService and
+// `config doctor` report it.
+export function readConfiguredOutput(
+  commandPath: string[],
+  configPath?: string,
+): ConfiguredOutput | undefined {
+  const config = readConfigSync(configPath);
+  if (config?.output === undefined) return undefined;
+
+  return findConfiguredOutput(config.output, commandPath, "output");
+}
+
+// The config's defaultQuery, applied when neither --query nor TWENTY_QUERY is
+// set.
+export function readConfiguredQuery(configPath?: string): string | undefined {
+  const query = readConfigSync(configPath)?.defaultQuery;
+  return typeof query === "string" && query.trim() !== "" ? query : undefined;
+}
+
+export interface ConfiguredRetries {
+  maxRetries?: string;
+  retryBaseDelay?: string;
+}
+
+// The config's maxRetries and retryBaseDelay, applied when neither the flag nor
+// its TWENTY_* variable is set. Values come back as strings so they go through
+// the same parser as the flags.
+export function readConfiguredRetries(configPath?: string): ConfiguredRetries | undefined {
+  const config = readConfigSync(configPath);
+  if (!config) return undefined;
+
+  const settings: ConfiguredRetries = {};
+  for (const key of ["maxRetries", "retryBaseDelay"] as const) {
+    const value = config[key];
+    if (typeof value === "number" || typeof value === "string") {
+      settings[key] = String(value);
+    }
+  }
+  return settings;
+}
+
+// Most specific match wins: "people.list" before "people" before "default".
+export function findConfiguredOutput(
+  node: unknown,
+  commandPath: string[],
+  key: string,
+): ConfiguredOutput | undefined {
+  if (typeof node === "string") {
+    return { format: node, key };
+  }
+  if (!isRecord(node)) return undefined;
+
+  for (let length = commandPath.length; length > 0; length -= 1) {
+    const childKey = commandPath.slice(0, length).join(".");
+    if (node[childKey] === undefined) continue;
+    const found = findConfiguredOutput(
+      node[childKey],
+      commandPath.slice(length),
+      `${key}.${childKey}`,
+    );
+    if (found) return found;
+  }
+
+  return typeof node.default === "string"
+    ? { format: node.default, key: `${key}.default` }
+    : undefined;
+}
+
+function readConfigSync(
+  configPath: string = path.join(os.homedir(), ".twenty", "config.json"),
+): Record<string, unknown> | undefined {
+  try {
+    if (!fs.pathExistsSync(configPath)) return undefined;
+    const config: unknown = JSON.parse(fs.readFileSync(configPath, "utf-8"));
+    return isRecord(config) ? config : undefined;
+  } catch {
+    return undefined;
+  }
+}
+
+function isRecord(value: unknown): value is Record<string, unknown> {
+  return typeof value === "object" && value !== null && !Array.isArray(value);
+}
@@ -0,0 +1,18 @@
+import { CliError } from "../errors/cli-error";
+
+const WORKSPACE_ID_PATTERN = /^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$/;
+
+// Twenty workspace IDs are UUIDs. Checking before any request turns a typo into
+// a clear error instead of a 401 or, worse, a request routed elsewhere.
+export function normalizeWorkspaceId(value: string, source: string): string {
+  const normalized = value.trim().toLowerCase();
+  if (!WORKSPACE_ID_PATTERN.test(normalized)) {
+    throw new CliError(
+      `Invalid workspace ID ${JSON.stringify(value)} from ${source}; expected a UUID.`,
+      "INVALID_ARGUMENTS",
+      'Run "twenty auth workspace" to see the current workspace ID.',
+    );
+  }
+
+  return normalized;
+}
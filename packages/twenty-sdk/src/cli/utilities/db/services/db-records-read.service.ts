@@ -327,6 +327,16 @@ function assertSupportedListOptions(options: ListOptions): void {
   if (options.params && Object.keys(options.params).length > 0) {
     throw new UnsupportedDbReadError("DB list does not support custom query params.");
   }
+
+  if (options.fields?.length) {
+    throw new UnsupportedDbReadError("DB list does not support field selection.");
+  }
+
+  // Streamed pages always come from the API: falling back after some pages
+  // were delivered would deliver them twice.
+  if (options.onPage) {
+    throw new UnsupportedDbReadError("DB list does not support streamed pages.");
+  }
 }
 
 function resolveConnectionOptions(target: ResolvedDbConfig) {
@@ -186,6 +186,36 @@ describe("error-handler", () => {
         expect(formatError(error)).toEqual(["Request failed with status 500.", "{}"]);
       });
 
+      it("names the profile when its token is rejected", () => {
+        const error = {
+          isAxiosError: true,
+          response: { status: 403, data: { message: "Forbidden resource" } },
+          config: { twentyProfile: "prod", headers: { Authorization: "Bearer tok-123" } },
+          message: "Request failed",
+        } as unknown as AxiosError;
+
+        const lines = formatError(error);
+        expect(lines[0]).toBe(
+          'Authentication failed for profile "prod" (HTTP 403); ' +
+            "your token may be expired or lack access to this resource.",
+        );
+        expect(JSON.parse(lines[1])).toEqual({ message: "Forbidden resource" });
+        expect(lines[2]).toBe(
+          "Suggestion: Run twenty auth login --profile prod, or update TWENTY_TOKEN if it is set.",
+        );
+        expect(lines.join("\n")).not.toContain("tok-123");
+      });
+
+      it("keeps the generic message for a 401 on a request sent without a token", () => {
+        const error = {
+          isAxiosError: true,
+          response: { status: 401, data: "Unauthorized" },
+          config: {},
+          message: "Request failed",
+        } as unknown as AxiosError;
+        expect(formatError(error)).toEqual(["Request failed with status 401.", "Unauthorized"]);
+      });
+
       it("formats network error (no response)", () => {
         const error = {
           isAxiosError: true,
@@ -70,6 +70,10 @@ export function formatError(error: unknown): string[] {
 
   if (isAxiosError(error)) {
     const status = error.response?.status;
+    const profile = (error.config as { twentyProfile?: string } | undefined)?.twentyProfile;
+    if ((status === 401 || status === 403) && profile !== undefined) {
+      return formatAuthFailure(status, profile, error.response?.data);
+    }
     if (status) {
       const detail =
         typeof error.response?.data === "string"
@@ -87,6 +91,22 @@ export function formatError(error: unknown): string[] {
   return ["Unknown error"];
 }
 
+// The server rejected the token a profile supplied. Only the profile name is
+// shown; the token itself never appears in error output.
+function formatAuthFailure(status: number, profile: string, data: unknown): string[] {
+  const reason =
+    status === 401
+      ? "your token may be expired or revoked"
+      : "your token may be expired or lack access to this resource";
+  const detail =
+    typeof data === "string" ? data : data == null ? "" : JSON.stringify(data, null, 2);
+  return [
+    `Authentication failed for profile "${profile}" (HTTP ${status}); ${reason}.`,
+    ...(detail && detail !== "{}" ? [detail] : []),
+    `Suggestion: Run twenty auth login --profile ${profile}, or update TWENTY_TOKEN if it is set.`,
+  ];
+}
+
 function isCommanderError(error: unknown): error is { message: string; code?: string } {
   return typeof error === "object" && error !== null && "code" in error && "message" in error;
 }
@@ -0,0 +1,82 @@
+import os from "node:os";
+import path from "node:path";
+import fs from "fs-extra";
+import { afterEach, beforeEach, describe, expect, it } from "vitest";
+import { AppendExportWriter } from "../append-export-writer";
+
+describe("AppendExportWriter", () => {
+  let tempRoot: string;
+
+  beforeEach(async () => {
+    tempRoot = await fs.mkdtemp(path.join(os.tmpdir(), "twenty-append-export-"));
+  });
+
+  afterEach(async () => {
+    await fs.remove(tempRoot);
+  });
+
+  it("writes the CSV header once and appends each page", async () => {
+    const output = path.join(tempRoot, "people.csv");
+    const writer = new AppendExportWriter({ format: "csv", output });
+
+    await writer.write([{ id: "1", city: "Paris" }]);
+    await writer.write([]);
+    await writer.write([{ id: "2", city: null }]);
+    await writer.close();
+
+    const content = await fs.readFile(output, "utf-8");
+    expect(content).toBe("id,city\r\n1,Paris\r\n2,");
+    expect(writer.recordCount).toBe(2);
+    expect(writer.byteCount).toBe(Buffer.byteLength(content));
+    expect(writer.csvColumns).toEqual(["id", "city"]);
+  });
+
+  it("rejects a column that first appears after the header was written", async () => {
+    const output = path.join(tempRoot, "people.csv");
+    const writer = new AppendExportWriter({
+      format: "csv",
+      output,
+      flatten: { arrays: "index" },
+    });
+
+    await writer.write([{ id: "1", tags: ["a"] }]);
+    await expect(writer.write([{ id: "2", tags: ["a", "b"] }])).rejects.toThrow(
+      `CSV column "tags.1" first appeared after the header of ${output} was written.`,
+    );
+    expect(await fs.readFile(output, "utf-8")).toBe("id,tags.0\r\n1,a");
+  });
+
+  it("ignores late columns outside --fields", async () => {
+    const output = path.join(tempRoot, "people.csv");
+    const writer = new AppendExportWriter({ format: "csv", output, columns: ["id"] });
+
+    await writer.write([{ id: "1" }]);
+    await writer.write([{ id: "2", city: "Paris" }]);
+
+    expect(await fs.readFile(output, "utf-8")).toBe("id\r\n1\r\n2");
+  });
+
+  it("trims bytes past the checkpoint before appending on resume", async () => {
+    const output = path.join(tempRoot, "people.ndjson");
+    await fs.writeFile(output, '{"id":"1"}\n{"id":"2"}');
+    const writer = new AppendExportWriter({
+      format: "json",
+      output,
+      resume: { bytes: Buffer.byteLength('{"id":"1"}'), records: 1 },
+    });
+
+    await writer.write([{ id: "2" }, { id: "3" }]);
+
+    expect(await fs.readFile(output, "utf-8")).toBe('{"id":"1"}\n{"id":"2"}\n{"id":"3"}');
+    expect(writer.recordCount).toBe(3);
+  });
+
+  it("creates the file even when no page had records", async () => {
+    const output = path.join(tempRoot, "people.csv");
+    const writer = new AppendExportWriter({ format: "csv", output });
+
+    await writer.close();
+
+    expect(await fs.readFile(output, "utf-8")).toBe("");
+  });
+});
@@ -53,6 +53,28 @@ describe("ExportService", () => {
       expect(output).toContain('"nested"');
     });
 
+    it("quotes every field when quoteAll is set", async () => {
+      const records = [{ id: "1", name: "Test" }];
+
+      await service.export(records, { format: "csv", quoteAll: true });
+      await service.export(records, { format: "csv", quoteAll: true, flatten: {} });
+
+      expect(consoleSpy.mock.calls[0][0]).toBe('"id","name"\r\n"1","Test"');
+      expect(consoleSpy.mock.calls[1][0]).toBe('"id","name"\r\n"1","Test"');
+    });
+
+    it("prepends a UTF-8 byte-order mark to CSV only when bom is set", async () => {
+      const records = [{ id: "1", name: "小酒馆" }];
+
+      await service.export(records, { format: "csv", bom: true });
+      await service.export(records, { format: "csv" });
+      await service.export(records, { format: "json", bom: true });
+
+      expect(consoleSpy.mock.calls[0][0]).toBe("\uFEFFid,name\r\n1,小酒馆");
+      expect(consoleSpy.mock.calls[1][0]).toBe("id,name\r\n1,小酒馆");
+      expect(consoleSpy.mock.calls[2][0]).not.toContain("\uFEFF");
+    });
+
     it("handles empty records array", async () => {
       const records: Record<string, unknown>[] = [];
 
@@ -60,6 +82,14 @@ describe("ExportService", () => {
 
       expect(consoleSpy).toHaveBeenCalledWith("[]");
     });
+
+    it("writes one compact record per line with stream", async () => {
+      const records = [{ id: "1", nested: { key: "value" } }, { id: "2" }];
+
+      await service.export(records, { format: "json", stream: true });
+
+      expect(consoleSpy).toHaveBeenCalledWith('{"id":"1","nested":{"key":"value"}}\n{"id":"2"}');
+    });
   });
 
   describe("CSV export", () => {
@@ -116,6 +146,50 @@ describe("ExportService", () => {
     });
   });
 
+  describe("flattened CSV export", () => {
+    const records = [
+      {
+        id: "1",
+        name: { firstName: "Ada", lastName: "Lovelace" },
+        emails: { primaryEmail: "ada@example.com", additionalEmails: ["a@x.io", "b@x.io"] },
+      },
+      { id: "2", name: { firstName: "Alan" }, city: "London" },
+    ];
+
+    it("expands nested objects into dotted columns across all records", async () => {
+      await service.export(records, { format: "csv", flatten: {} });
+
+      const [header, first, second] = consoleSpy.mock.calls[0][0].split("\r\n");
+      expect(header).toBe(
+        "id,name.firstName,name.lastName,emails.primaryEmail,emails.additionalEmails,city",
+      );
+      expect(first).toBe('1,Ada,Lovelace,ada@example.com,"[""a@x.io"",""b@x.io""]",');
+      expect(second).toBe("2,Alan,,,,London");
+    });
+
+    it("stops expanding at the configured depth", async () => {
+      const nested = [{ id: "3", company: { name: "Acme", address: { city: "Paris" } } }];
+
+      await service.export(nested, { format: "csv", flatten: { depth: 1 } });
+
+      expect(consoleSpy.mock.calls[0][0]).toBe(
+        'id,company.name,company.address\r\n3,Acme,"{""city"":""Paris""}"',
+      );
+    });
+
+    it("joins or indexes arrays when requested", async () => {
+      await service.export(records, { format: "csv", flatten: { arrays: "join" } });
+      await service.export(records, { format: "csv", flatten: { arrays: "index" } });
+
+      const joined = consoleSpy.mock.calls[0][0];
+      const indexed = consoleSpy.mock.calls[1][0];
+      expect(joined).toContain("a@x.io;b@x.io");
+      expect(indexed.split("\r\n")[0]).toContain(
+        "emails.additionalEmails.0,emails.additionalEmails.1",
+      );
+    });
+  });
+
   describe("file output", () => {
     it("reports correct record count for multiple records", async () => {
       const records = [{ id: "1" }, { id: "2" }, { id: "3" }];
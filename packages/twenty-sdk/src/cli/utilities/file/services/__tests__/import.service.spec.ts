@@ -1,6 +1,7 @@
 import { describe, it, expect, vi, beforeEach } from "vitest";
 import { ImportService } from "../import.service";
 import fs from "fs-extra";
+import { configureStrictJson } from "../../../shared/strict-json";
 
 vi.mock("fs-extra");
 
@@ -30,6 +31,19 @@ describe("ImportService", () => {
       expect(result).toHaveLength(1);
       expect(result[0]).toEqual({ id: "1", name: "Test" });
     });
+
+    it("rejects duplicate keys in strict JSON mode", async () => {
+      vi.mocked(fs.readFile).mockResolvedValue('[{"id":"1"},{"id":"2","id":"3"}]');
+      configureStrictJson(true);
+
+      try {
+        await expect(service.import("/path/to/file.json")).rejects.toThrow(
+          "Duplicate JSON key at /1/id.",
+        );
+      } finally {
+        configureStrictJson(false);
+      }
+    });
   });
 
   describe("CSV import", () => {
@@ -0,0 +1,276 @@
+import { execFileSync, spawnSync } from "node:child_process";
+import os from "node:os";
+import path from "node:path";
+import fs from "fs-extra";
+import { afterEach, beforeEach, describe, expect, it } from "vitest";
+import { inferSchema, ParquetExportWriter } from "../parquet-writer";
+import { readParquet } from "../../../../test-utils/parquet-reader";
+
+// Prints a Parquet file's column types and rows as JSON, as read by pyarrow.
+// Timestamps come back as UTC ISO strings.
+const PYARROW_DUMP = `
+import datetime, json, sys
+import pyarrow.parquet as pq
+
+def plain(value):
+    if isinstance(value, datetime.datetime):
+        if value.tzinfo is None:
+            value = value.replace(tzinfo=datetime.timezone.utc)
+        return value.astimezone(datetime.timezone.utc).isoformat(timespec="milliseconds")
+    raise TypeError(type(value))
+
+parquet = pq.ParquetFile(sys.argv[1])
+columns = [
+    {"name": c.name, "physicalType": c.physical_type, "convertedType": c.converted_type}
+    for c in parquet.schema
+]
+rows = parquet.read().to_pylist()
+print(json.dumps({"columns": columns, "rows": rows}, default=plain))
+`;
+
+const hasPyarrow = spawnSync("python3", ["-c", "import pyarrow"]).status === 0;
+// Skipped where pyarrow is not installed (pip install pyarrow).
+const describeWithPyarrow = hasPyarrow ? describe : describe.skip;
+
+describe("ParquetExportWriter", () => {
+  let tempRoot: string;
+
+  beforeEach(async () => {
+    tempRoot = await fs.mkdtemp(path.join(os.tmpdir(), "twenty-parquet-export-"));
+  });
+
+  afterEach(async () => {
+    await fs.remove(tempRoot);
+  });
+
+  it("infers column types, keeping nested values as strings", () => {
+    const schema = inferSchema([
+      {
+        id: "1",
+        name: { firstName: "Ann" },
+        score: 4.5,
+        vip: true,
+        createdAt: "2024-01-01T09:30:00.000Z",
+        birthday: "1990-05-01",
+        city: null,
+      },
+      { id: "2", name: null, score: 3, vip: false, createdAt: null, birthday: null, city: null },
+    ]);
+
+    expect(schema).toEqual([
+      { name: "id", type: "string" },
+      { name: "name", type: "string" },
+      { name: "score", type: "double" },
+      { name: "vip", type: "boolean" },
+      { name: "createdAt", type: "timestamp" },
+      { name: "birthday", type: "string" },
+      { name: "city", type: "string" },
+    ]);
+  });
+
+  it("orders columns by --fields", () => {
+    const schema = inferSchema([{ id: "1", city: "Paris", jobTitle: "CEO" }], ["jobTitle", "id"]);
+
+    expect(schema.map((column) => column.name)).toEqual(["jobTitle", "id"]);
+  });
+
+  it("writes row groups between the Parquet magic bytes with a footer", async () => {
+    const output = path.join(tempRoot, "people.parquet");
+    const writer = new ParquetExportWriter({ output, rowGroupSize: 2 });
+
+    await writer.write([
+      { id: "1", city: "Paris" },
+      { id: "2", city: null },
+    ]);
+    await writer.write([{ id: "3", city: "Zürich" }]);
+    await writer.close();
+
+    const content = await fs.readFile(output);
+    expect(content.subarray(0, 4).toString()).toBe("PAR1");
+    expect(content.subarray(-4).toString()).toBe("PAR1");
+    const footerLength = content.readUInt32LE(content.length - 8);
+    expect(footerLength).toBeGreaterThan(0);
+    expect(footerLength).toBeLessThan(content.length - 12);
+    expect(content.includes(Buffer.from("Zürich"))).toBe(true);
+    expect(writer.recordCount).toBe(3);
+    expect(writer.columns).toEqual([
+      { name: "id", type: "string" },
+      { name: "city", type: "string" },
+    ]);
+  });
+
+  it("leaves a valid file when there are no records", async () => {
+    const output = path.join(tempRoot, "empty.parquet");
+    const writer = new ParquetExportWriter({ output });
+
+    await writer.close();
+
+    const content = await fs.readFile(output);
+    expect(content.subarray(0, 4).toString()).toBe("PAR1");
+    expect(content.subarray(-4).toString()).toBe("PAR1");
+    expect(writer.recordCount).toBe(0);
+  });
+
+  it("round-trips values, nulls, and row groups through a Parquet reader", async () => {
+    const output = path.join(tempRoot, "people.parquet");
+    const writer = new ParquetExportWriter({ output, rowGroupSize: 2 });
+
+    await writer.write([
+      {
+        id: "1",
+        score: 4.5,
+        vip: true,
+        createdAt: "2024-01-01T09:30:00.000Z",
+        name: { firstName: "Ann" },
+        city: "Zürich",
+      },
+      { id: "2", score: null, vip: false, createdAt: null, name: null, city: null },
+    ]);
+    await writer.write([
+      {
+        id: "3",
+        score: -2,
+        vip: null,
+        createdAt: "2024-02-01T00:00:00Z",
+        name: { firstName: "Bo" },
+        city: "Paris",
+      },
+    ]);
+    await writer.close();
+
+    const file = readParquet(await fs.readFile(output));
+    expect(file.numRows).toBe(3);
+    expect(file.rowGroups).toBe(2);
+    expect(file.rows).toEqual([
+      {
+        id: "1",
+        score: 4.5,
+        vip: true,
+        createdAt: "2024-01-01T09:30:00.000Z",
+        name: '{"firstName":"Ann"}',
+        city: "Zürich",
+      },
+      { id: "2", score: null, vip: false, createdAt: null, name: null, city: null },
+      {
+        id: "3",
+        score: -2,
+        vip: null,
+        createdAt: "2024-02-01T00:00:00.000Z",
+        name: '{"firstName":"Bo"}',
+        city: "Paris",
+      },
+    ]);
+  });
+
+  it("types columns from every record, not just the first row group", async () => {
+    const output = path.join(tempRoot, "people.parquet");
+    const writer = new ParquetExportWriter({ output, rowGroupSize: 1 });
+
+    await writer.write([{ id: "1", score: null }]);
+    await writer.write([{ id: "2", score: 3, rank: "high" }]);
+    await writer.write([{ id: "3", score: 4, rank: 1 }]);
+    await writer.close();
+
+    expect(writer.columns).toEqual([
+      { name: "id", type: "string" },
+      { name: "score", type: "double" },
+      { name: "rank", type: "string" },
+    ]);
+    expect(readParquet(await fs.readFile(output)).rows).toEqual([
+      { id: "1", score: null, rank: null },
+      { id: "2", score: 3, rank: "high" },
+      { id: "3", score: 4, rank: "1" },
+    ]);
+  });
+
+  it("keeps a --fields column that only appears on a later page", async () => {
+    const output = path.join(tempRoot, "people.parquet");
+    const writer = new ParquetExportWriter({ output, rowGroupSize: 1, columns: ["id", "city"] });
+
+    await writer.write([{ id: "1" }]);
+    await writer.write([{ id: "2", city: "Paris" }]);
+    await writer.close();
+
+    expect(readParquet(await fs.readFile(output)).rows).toEqual([
+      { id: "1", city: null },
+      { id: "2", city: "Paris" },
+    ]);
+  });
+
+  it("removes its temp files and leaves no partial file when closing fails", async () => {
+    // A directory at the output path makes the final rename fail.
+    const output = path.join(tempRoot, "people.parquet");
+    await fs.mkdir(output);
+    const writer = new ParquetExportWriter({ output });
+
+    await writer.write([{ id: "1" }]);
+    await expect(writer.close()).rejects.toThrow();
+
+    expect(await fs.readdir(tempRoot)).toEqual(["people.parquet"]);
+    expect(await fs.readdir(output)).toEqual([]);
+  });
+
+  it("discards spooled pages on abort", async () => {
+    const output = path.join(tempRoot, "people.parquet");
+    const writer = new ParquetExportWriter({ output });
+
+    await writer.write([{ id: "1" }]);
+    await writer.abort();
+
+    expect(await fs.readdir(tempRoot)).toEqual([]);
+  });
+});
+
+describeWithPyarrow("ParquetExportWriter read by pyarrow", () => {
+  let tempRoot: string;
+
+  beforeEach(async () => {
+    tempRoot = await fs.mkdtemp(path.join(os.tmpdir(), "twenty-parquet-pyarrow-"));
+  });
+
+  afterEach(async () => {
+    await fs.remove(tempRoot);
+  });
+
+  it("reads the column types, values, and nulls the writer produced", async () => {
+    const output = path.join(tempRoot, "people.parquet");
+    const writer = new ParquetExportWriter({ output, rowGroupSize: 2 });
+
+    await writer.write([
+      {
+        id: "1",
+        score: 4.5,
+        vip: true,
+        createdAt: "2024-01-01T09:30:00.000Z",
+        name: { firstName: "Ann" },
+      },
+      { id: "2", score: null, vip: false, createdAt: null, name: null },
+    ]);
+    await writer.write([
+      { id: "3", score: -2, vip: null, createdAt: "2024-02-01T00:00:00Z", name: null },
+    ]);
+    await writer.close();
+
+    const dump = JSON.parse(
+      execFileSync("python3", ["-c", PYARROW_DUMP, output], { encoding: "utf-8" }),
+    );
+    expect(dump.columns).toEqual([
+      { name: "id", physicalType: "BYTE_ARRAY", convertedType: "UTF8" },
+      { name: "score", physicalType: "DOUBLE", convertedType: "NONE" },
+      { name: "vip", physicalType: "BOOLEAN", convertedType: "NONE" },
+      { name: "createdAt", physicalType: "INT64", convertedType: "TIMESTAMP_MILLIS" },
+      { name: "name", physicalType: "BYTE_ARRAY", convertedType: "UTF8" },
+    ]);
+    expect(dump.rows).toEqual([
+      {
+        id: "1",
+        score: 4.5,
+        vip: true,
+        createdAt: "2024-01-01T09:30:00.000+00:00",
+        name: '{"firstName":"Ann"}',
+      },
+      { id: "2", score: null, vip: false, createdAt: null, name: null },
+      { id: "3", score: -2, vip: null, createdAt: "2024-02-01T00:00:00.000+00:00", name: null },
+    ]);
+  });
+});
@@ -0,0 +1,59 @@
+import os from "node:os";
+import path from "node:path";
+import fs from "fs-extra";
+import { afterEach, beforeEach, describe, expect, it } from "vitest";
+import { expandPathPatterns } from "../path-glob";
+
+describe("expandPathPatterns", () => {
+  let tempRoot: string;
+
+  beforeEach(async () => {
+    tempRoot = await fs.mkdtemp(path.join(os.tmpdir(), "twenty-path-glob-"));
+    for (const name of ["2024-02.csv", "2024-01.csv", "notes.txt", "2024-03.json"]) {
+      await fs.writeFile(path.join(tempRoot, name), "");
+    }
+    await fs.mkdir(path.join(tempRoot, "nested.csv"));
+  });
+
+  afterEach(async () => {
+    await fs.remove(tempRoot);
+  });
+
+  it("passes plain paths through unchanged", async () => {
+    await expect(expandPathPatterns(["missing.csv", "b.json"])).resolves.toEqual([
+      "missing.csv",
+      "b.json",
+    ]);
+  });
+
+  it("expands a file-name wildcard to sorted files", async () => {
+    await expect(expandPathPatterns([path.join(tempRoot, "*.csv")])).resolves.toEqual([
+      path.join(tempRoot, "2024-01.csv"),
+      path.join(tempRoot, "2024-02.csv"),
+    ]);
+  });
+
+  it("matches single characters with ?", async () => {
+    await expect(expandPathPatterns([path.join(tempRoot, "2024-0?.json")])).resolves.toEqual([
+      path.join(tempRoot, "2024-03.json"),
+    ]);
+  });
+
+  it("keeps pattern order across several arguments", async () => {
+    await expect(
+      expandPathPatterns([path.join(tempRoot, "*.json"), path.join(tempRoot, "notes.txt")]),
+    ).resolves.toEqual([path.join(tempRoot, "2024-03.json"), path.join(tempRoot, "notes.txt")]);
+  });
+
+  it("rejects a pattern with no matches", async () => {
+    await expect(expandPathPatterns([path.join(tempRoot, "*.xlsx")])).rejects.toThrow(
+      /No files match/,
+    );
+  });
+
+  it("rejects wildcards in the directory part", async () => {
+    await expect(expandPathPatterns([path.join(tempRoot, "*", "a.csv")])).rejects.toThrow(
+      "wildcards are only allowed in the file name",
+    );
+  });
+});
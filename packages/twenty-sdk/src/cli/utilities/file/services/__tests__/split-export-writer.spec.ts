@@ -0,0 +1,147 @@
+import os from "node:os";
+import path from "node:path";
+import fs from "fs-extra";
+import { afterEach, beforeEach, describe, expect, it } from "vitest";
+import { SplitExportWriter, splitPartPath } from "../split-export-writer";
+
+describe("SplitExportWriter", () => {
+  let tempRoot: string;
+
+  beforeEach(async () => {
+    tempRoot = await fs.mkdtemp(path.join(os.tmpdir(), "twenty-split-export-"));
+  });
+
+  afterEach(async () => {
+    await fs.remove(tempRoot);
+  });
+
+  it("names parts with a zero-padded suffix before the extension", () => {
+    expect(splitPartPath("out/people.csv", 1)).toBe("out/people-0001.csv");
+    expect(splitPartPath("people", 12)).toBe("people-0012");
+  });
+
+  it("rotates CSV parts by record count across pages, with a header in each", async () => {
+    const output = path.join(tempRoot, "people.csv");
+    const writer = new SplitExportWriter({ format: "csv", output, maxRecords: 2 });
+
+    await writer.write([
+      { id: "1", name: { firstName: "Ada" } },
+      { id: "2", name: null },
+    ]);
+    await writer.write([{ id: "3", name: { firstName: "Linus" } }]);
+    const files = await writer.close();
+
+    expect(files).toEqual([
+      path.join(tempRoot, "people-0001.csv"),
+      path.join(tempRoot, "people-0002.csv"),
+    ]);
+    expect(await fs.readFile(files[0], "utf-8")).toBe(
+      'id,name\r\n1,"{""firstName"":""Ada""}"\r\n2,',
+    );
+    expect(await fs.readFile(files[1], "utf-8")).toBe('id,name\r\n3,"{""firstName"":""Linus""}"');
+    expect(writer.recordCount).toBe(3);
+  });
+
+  it("starts every CSV part with a byte-order mark when bom is set", async () => {
+    const output = path.join(tempRoot, "people.csv");
+    const writer = new SplitExportWriter({ format: "csv", output, maxRecords: 1, bom: true });
+
+    await writer.write([{ id: "1" }, { id: "2" }]);
+    const files = await writer.close();
+
+    expect(await fs.readFile(files[0], "utf-8")).toBe("\uFEFFid\r\n1");
+    expect(await fs.readFile(files[1], "utf-8")).toBe("\uFEFFid\r\n2");
+  });
+
+  it("rotates JSON parts before the byte limit, each a valid array", async () => {
+    const output = path.join(tempRoot, "people.json");
+    const records = [{ id: "a".repeat(20) }, { id: "b".repeat(20) }, { id: "c".repeat(20) }];
+    const single = JSON.stringify([records[0]], null, 2).length;
+    const writer = new SplitExportWriter({ format: "json", output, maxBytes: single + 10 });
+
+    await writer.write(records);
+    const files = await writer.close();
+
+    expect(files).toHaveLength(3);
+    for (const [index, file] of files.entries()) {
+      const content = await fs.readFile(file, "utf-8");
+      expect(content).toBe(JSON.stringify([records[index]], null, 2));
+      expect(Buffer.byteLength(content)).toBeLessThanOrEqual(single + 10);
+    }
+  });
+
+  it("uses the requested column order in every CSV part", async () => {
+    const output = path.join(tempRoot, "people.csv");
+    const writer = new SplitExportWriter({
+      format: "csv",
+      output,
+      maxRecords: 1,
+      columns: ["city", "id"],
+    });
+
+    await writer.write([
+      { id: "1", city: "London", email: "ada@example.com" },
+      { id: "2", email: "linus@example.com" },
+    ]);
+    const files = await writer.close();
+
+    expect(await fs.readFile(files[0], "utf-8")).toBe("city,id\r\nLondon,1");
+    expect(await fs.readFile(files[1], "utf-8")).toBe("city,id\r\n,2");
+  });
+
+  it("rejects a column that first appears after a part's header was written", async () => {
+    const output = path.join(tempRoot, "people.csv");
+    const writer = new SplitExportWriter({ format: "csv", output, maxRecords: 10 });
+
+    await writer.write([{ id: "1" }]);
+    await expect(writer.write([{ id: "2", city: "Paris" }])).rejects.toMatchObject({
+      message: `CSV column "city" first appeared after the header of ${path.join(
+        tempRoot,
+        "people-0001.csv",
+      )} was written.`,
+      code: "INVALID_ARGUMENTS",
+    });
+  });
+
+  it("ignores later columns outside --fields but rejects later ones inside it", async () => {
+    const output = path.join(tempRoot, "people.csv");
+    const writer = new SplitExportWriter({
+      format: "csv",
+      output,
+      maxRecords: 10,
+      flatten: {},
+      columns: ["id", "name"],
+    });
+
+    await writer.write([{ id: "1", name: { firstName: "Ada" } }]);
+    await writer.write([{ id: "2", name: { firstName: "Bob" }, city: "Paris" }]);
+    await expect(
+      writer.write([{ id: "3", name: { firstName: "Cy", lastName: "Young" } }]),
+    ).rejects.toThrow('CSV column "name.lastName" first appeared');
+  });
+
+  it("writes NDJSON parts with stream", async () => {
+    const output = path.join(tempRoot, "people.ndjson");
+    const writer = new SplitExportWriter({ format: "json", output, maxRecords: 2, stream: true });
+
+    await writer.write([{ id: "1" }, { id: "2" }, { id: "3" }]);
+    const files = await writer.close();
+
+    expect(files).toHaveLength(2);
+    expect(await fs.readFile(files[0], "utf-8")).toBe('{"id":"1"}\n{"id":"2"}');
+    expect(await fs.readFile(files[1], "utf-8")).toBe('{"id":"3"}');
+  });
+
+  it("writes one empty part when there are no records", async () => {
+    const writer = new SplitExportWriter({
+      format: "json",
+      output: path.join(tempRoot, "people.json"),
+      maxRecords: 10,
+    });
+
+    const files = await writer.close();
+
+    expect(files).toEqual([path.join(tempRoot, "people-0001.json")]);
+    expect(await fs.readFile(files[0], "utf-8")).toBe("[]");
+  });
+});
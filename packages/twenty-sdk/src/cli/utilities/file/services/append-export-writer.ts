@@ -0,0 +1,152 @@
+import fs from "fs-extra";
+import { CliError } from "../../errors/cli-error";
+import {
+  collectCsvColumns,
+  CsvFlattenOptions,
+  findUnknownCsvColumn,
+  flattenCsvRecord,
+  orderCsvColumns,
+} from "../../output/services/csv-flatten";
+import {
+  CSV_NEWLINE,
+  csvCell,
+  CsvNumberFormat,
+  unparseCsv,
+} from "../../output/services/csv-writer";
+
+export interface AppendExportOptions {
+  // CSV, or JSON written as NDJSON; a JSON array cannot be appended to.
+  format: "json" | "csv";
+  output: string;
+  flatten?: CsvFlattenOptions;
+  quoteAll?: boolean;
+  bom?: boolean;
+  // CSV only: --float-precision/--plain-numbers.
+  numbers?: CsvNumberFormat;
+  // CSV only: exact column order (from --fields).
+  columns?: string[];
+  // Continue a file from an earlier run instead of starting it over.
+  resume?: AppendExportResume;
+}
+
+export interface AppendExportResume {
+  // File size after the last page the earlier run confirmed; anything after
+  // it was written without being checkpointed and is dropped.
+  bytes: number;
+  records: number;
+  // CSV header columns of the existing file.
+  columns?: string[];
+}
+
+// Writes export pages to one file as they arrive, appending each page before
+// the caller records its checkpoint. CSV columns are fixed by the first page
+// (or by the resumed file's header); a later page with a column the header
+// lacks fails rather than losing it.
+export class AppendExportWriter {
+  private started = false;
+  private bytes = 0;
+  private records = 0;
+  private columns?: string[];
+
+  constructor(private readonly options: AppendExportOptions) {
+    if (options.resume) {
+      this.bytes = options.resume.bytes;
+      this.records = options.resume.records;
+      this.columns = options.resume.columns;
+    }
+  }
+
+  get recordCount(): number {
+    return this.records;
+  }
+
+  get byteCount(): number {
+    return this.bytes;
+  }
+
+  get csvColumns(): string[] | undefined {
+    return this.columns;
+  }
+
+  async write(records: Record<string, unknown>[]): Promise<void> {
+    if (records.length === 0) {
+      return;
+    }
+    if (!this.started) {
+      await this.start(records);
+    }
+
+    const chunk = this.serialize(records);
+    const separated = (this.bytes > 0 ? this.separator() : "") + chunk;
+    await fs.appendFile(this.options.output, separated);
+    this.bytes += Buffer.byteLength(separated);
+    this.records += records.length;
+  }
+
+  // Creates the file when no page had records, so a finished export always
+  // leaves its output behind.
+  async close(): Promise<void> {
+    if (!this.started) {
+      await this.start([]);
+    }
+  }
+
+  private async start(records: Record<string, unknown>[]): Promise<void> {
+    this.started = true;
+    if (this.options.resume) {
+      await fs.truncate(this.options.output, this.options.resume.bytes);
+      return;
+    }
+
+    let header = "";
+    if (this.options.format === "csv") {
+      const rows = this.rows(records);
+      this.columns = collectCsvColumns(rows);
+      if (this.options.columns) {
+        this.columns = orderCsvColumns(this.columns, this.options.columns);
+      }
+      header =
+        this.columns.length > 0
+          ? unparseCsv(
+              { fields: this.columns, data: [] },
+              { quoteAll: this.options.quoteAll, bom: this.options.bom },
+            )
+          : "";
+    }
+    await fs.writeFile(this.options.output, header);
+    this.bytes = Buffer.byteLength(header);
+  }
+
+  private serialize(records: Record<string, unknown>[]): string {
+    if (this.options.format === "json") {
+      return records.map((record) => JSON.stringify(record)).join("\n");
+    }
+    const columns = this.columns ?? [];
+    const rows = this.rows(records);
+    const extra = findUnknownCsvColumn(rows, columns, this.options.columns);
+    if (extra !== undefined) {
+      throw new CliError(
+        `CSV column "${extra}" first appeared after the header of ${this.options.output} was written.`,
+        "INVALID_ARGUMENTS",
+        "List the columns with --fields, or export as JSON.",
+      );
+    }
+    return unparseCsv(
+      {
+        fields: columns,
+        data: rows.map((row) => columns.map((column) => csvCell(row[column]))),
+      },
+      { quoteAll: this.options.quoteAll, header: false, numbers: this.options.numbers },
+    );
+  }
+
+  private rows(records: Record<string, unknown>[]): Record<string, unknown>[] {
+    return this.options.flatten
+      ? records.map((record) => flattenCsvRecord(record, this.options.flatten))
+      : records;
+  }
+
+  private separator(): string {
+    return this.options.format === "csv" ? CSV_NEWLINE : "\n";
+  }
+}
@@ -0,0 +1,13 @@
+import path from "path";
+import fs from "fs-extra";
+
+// Writes to a temp file next to the target and renames it into place, so a
+// reader (or a crash) never leaves a half-written file behind.
+export async function writeFileAtomic(filePath: string, content: string): Promise<void> {
+  const tempPath = path.join(
+    path.dirname(filePath),
+    `.${path.basename(filePath)}.${process.pid}.tmp`,
+  );
+  await fs.writeFile(tempPath, content);
+  await fs.rename(tempPath, filePath);
+}
@@ -0,0 +1,32 @@
+import path from "path";
+
+// Default local file name for a download: the last path segment of the URL
+// or path, without its query string (signed URLs carry tokens there).
+export function inferOutputPath(reference: string): string {
+  let pathname = reference;
+
+  try {
+    pathname = new URL(reference).pathname;
+  } catch {
+    pathname = reference;
+  }
+
+  const cleanPath = pathname.split("?")[0] ?? pathname;
+  const fileName = path.basename(cleanPath);
+
+  return fileName || "download";
+}
+
+// Downloads are requested with responseType "arraybuffer" so bodies are
+// written byte for byte instead of being parsed as JSON.
+export function toOutputBuffer(data: string | ArrayBuffer | Buffer): Buffer {
+  if (Buffer.isBuffer(data)) {
+    return data;
+  }
+
+  if (typeof data === "string") {
+    return Buffer.from(data);
+  }
+
+  return Buffer.from(new Uint8Array(data));
+}
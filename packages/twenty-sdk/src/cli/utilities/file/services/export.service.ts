@@ -1,26 +1,70 @@
-import Papa from "papaparse";
 import fs from "fs-extra";
+import { CsvFlattenOptions, unparseFlattenedCsv } from "../../output/services/csv-flatten";
+import { CsvNumberFormat, unparseCsv } from "../../output/services/csv-writer";
+import { log } from "../../shared/logger";
+import { OutputWriter, stdoutWriter } from "../../output/services/output-writer";
+import { AppendExportOptions, AppendExportWriter } from "./append-export-writer";
+import { ParquetExportOptions, ParquetExportWriter } from "./parquet-writer";
+import { SplitExportOptions, SplitExportWriter } from "./split-export-writer";
 
 export class ExportService {
+  constructor(private writer: OutputWriter = stdoutWriter) {}
+
   async export(
     records: Record<string, unknown>[],
-    options: { format: "json" | "csv"; output?: string },
+    options: {
+      format: "json" | "csv";
+      output?: string;
+      flatten?: CsvFlattenOptions;
+      quoteAll?: boolean;
+      // CSV only: prepend a UTF-8 byte-order mark.
+      bom?: boolean;
+      // CSV only: --float-precision/--plain-numbers.
+      numbers?: CsvNumberFormat;
+      // CSV only: exact column order (from --fields).
+      columns?: string[];
+      // JSON only: one compact record per line (NDJSON) instead of an array.
+      stream?: boolean;
+    },
   ): Promise<void> {
     let content: string;
 
     if (options.format === "csv") {
-      content = Papa.unparse(records as any[]);
+      const writeOptions = {
+        quoteAll: options.quoteAll,
+        columns: options.columns,
+        bom: options.bom,
+        numbers: options.numbers,
+      };
+      content = options.flatten
+        ? unparseFlattenedCsv(records, options.flatten, writeOptions)
+        : unparseCsv(records, writeOptions);
+    } else if (options.stream) {
+      content = records.map((record) => JSON.stringify(record)).join("\n");
     } else {
       content = JSON.stringify(records, null, 2);
     }
 
     if (options.output) {
       await fs.writeFile(options.output, content);
-      // eslint-disable-next-line no-console
-      console.error(`Exported ${records.length} records to ${options.output}`);
+      log("info", `Exported ${records.length} records to ${options.output}`, {
+        records: records.length,
+        path: options.output,
+      });
     } else {
-      // eslint-disable-next-line no-console
-      console.log(content);
+      this.writer.line(content);
     }
   }
+
+  createSplitWriter(options: SplitExportOptions): SplitExportWriter {
+    return new SplitExportWriter(options);
+  }
+
+  createAppendWriter(options: AppendExportOptions): AppendExportWriter {
+    return new AppendExportWriter(options);
+  }
+
+  createParquetWriter(options: ParquetExportOptions): ParquetExportWriter {
+    return new ParquetExportWriter(options);
+  }
 }
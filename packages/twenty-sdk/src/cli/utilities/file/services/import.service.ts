@@ -1,6 +1,7 @@
 import Papa from "papaparse";
 import fs from "fs-extra";
 import path from "path";
+import { assertStrictJson } from "../../shared/strict-json";
 
 export class ImportService {
   async import(
@@ -21,6 +22,7 @@ export class ImportService {
       records = result.data as Record<string, unknown>[];
     } else if (ext === ".json") {
       const parsed = JSON.parse(content) as unknown;
+      assertStrictJson(content);
       records = Array.isArray(parsed)
         ? (parsed as Record<string, unknown>[])
         : [parsed as Record<string, unknown>];
@@ -0,0 +1,397 @@
+import path from "path";
+import readline from "node:readline";
+import fs from "fs-extra";
+import { orderCsvColumns } from "../../output/services/csv-flatten";
+import { ThriftCompactWriter } from "./thrift-compact";
+
+export interface ParquetExportOptions {
+  output: string;
+  // Records per row group.
+  rowGroupSize?: number;
+  // Exact column order (from --fields).
+  columns?: string[];
+}
+
+export type ParquetColumnType = "boolean" | "double" | "timestamp" | "string";
+
+export interface ParquetColumn {
+  name: string;
+  type: ParquetColumnType;
+}
+
+interface ColumnChunkMeta {
+  type: number;
+  offset: number;
+  size: number;
+  values: number;
+}
+
+interface RowGroupMeta {
+  rows: number;
+  bytes: number;
+  columns: ColumnChunkMeta[];
+}
+
+export const DEFAULT_PARQUET_ROW_GROUP_SIZE = 10000;
+
+const MAGIC = Buffer.from("PAR1", "ascii");
+
+// Parquet physical types, converted types, and encodings from parquet.thrift.
+const PHYSICAL_TYPES: Record<ParquetColumnType, number> = {
+  boolean: 0,
+  timestamp: 2,
+  double: 5,
+  string: 6,
+};
+const CONVERTED_UTF8 = 0;
+const CONVERTED_TIMESTAMP_MILLIS = 9;
+const REPETITION_OPTIONAL = 1;
+const ENCODING_PLAIN = 0;
+const ENCODING_RLE = 3;
+const CODEC_UNCOMPRESSED = 0;
+const PAGE_DATA = 0;
+
+const ISO_TIMESTAMP = /^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}(:\d{2}(\.\d+)?)?(Z|[+-]\d{2}:?\d{2})$/;
+
+// Writes export pages to one Parquet file. The schema sits in the footer but
+// column types depend on every record, so pages are spooled as NDJSON to a
+// temp file next to the output while types are tracked, and close() writes
+// the row groups from the spool. Memory stays bounded by the row group size
+// during --all exports, and a column that is empty or missing on early pages
+// still gets its type from later ones. Every column is optional (nulls
+// allowed), PLAIN-encoded, and uncompressed: booleans, numbers (as doubles),
+// ISO 8601 timestamps (as UTC milliseconds), and strings; nested objects and
+// arrays, and columns whose values mix types, are stored as strings. The file
+// is renamed into place only once complete; on failure both temp files are
+// removed and an existing file at the output path is left as it was.
+export class ParquetExportWriter {
+  private readonly rowGroupSize: number;
+  private readonly spoolPath: string;
+  private readonly tempPath: string;
+  private readonly columnTypes = new ColumnTypes();
+  private readonly rowGroups: RowGroupMeta[] = [];
+  private spoolFd?: number;
+  private fd?: number;
+  private schema?: ParquetColumn[];
+  private offset = 0;
+  private total = 0;
+
+  constructor(private readonly options: ParquetExportOptions) {
+    this.rowGroupSize = options.rowGroupSize ?? DEFAULT_PARQUET_ROW_GROUP_SIZE;
+    const base = path.join(
+      path.dirname(options.output),
+      `.${path.basename(options.output)}.${process.pid}`,
+    );
+    this.spoolPath = `${base}.spool`;
+    this.tempPath = `${base}.tmp`;
+  }
+
+  get recordCount(): number {
+    return this.total;
+  }
+
+  // Known once close() has run.
+  get columns(): ParquetColumn[] | undefined {
+    return this.schema;
+  }
+
+  async write(records: Record<string, unknown>[]): Promise<void> {
+    if (records.length === 0) {
+      return;
+    }
+    try {
+      this.spoolFd ??= await fs.open(this.spoolPath, "w");
+      const lines = Buffer.from(
+        records.map((record) => `${JSON.stringify(record)}\n`).join(""),
+        "utf-8",
+      );
+      await fs.write(this.spoolFd, lines, 0, lines.length);
+      this.columnTypes.add(records);
+      this.total += records.length;
+    } catch (error) {
+      await this.abort();
+      throw error;
+    }
+  }
+
+  // Writes the row groups and footer, then moves the file into place. An
+  // export without records still leaves a valid file behind.
+  async close(): Promise<void> {
+    try {
+      this.schema = this.columnTypes.schema(this.options.columns);
+      this.fd = await fs.open(this.tempPath, "w");
+      await this.append(MAGIC);
+      await this.writeRowGroups();
+      const footer = this.footer();
+      const length = Buffer.alloc(4);
+      length.writeUInt32LE(footer.length, 0);
+      await this.append(Buffer.concat([footer, length, MAGIC]));
+      await fs.close(this.fd);
+      this.fd = undefined;
+      await fs.rename(this.tempPath, this.options.output);
+    } catch (error) {
+      await this.abort();
+      throw error;
+    }
+    await this.removeSpool();
+  }
+
+  // Drops everything written so far, e.g. when listing fails midway.
+  async abort(): Promise<void> {
+    if (this.fd !== undefined) {
+      await fs.close(this.fd).catch(() => undefined);
+      this.fd = undefined;
+    }
+    await fs.remove(this.tempPath).catch(() => undefined);
+    await this.removeSpool().catch(() => undefined);
+  }
+
+  private async removeSpool(): Promise<void> {
+    if (this.spoolFd !== undefined) {
+      await fs.close(this.spoolFd);
+      this.spoolFd = undefined;
+    }
+    await fs.remove(this.spoolPath);
+  }
+
+  private async writeRowGroups(): Promise<void> {
+    if (this.spoolFd === undefined) {
+      return;
+    }
+    let group: Record<string, unknown>[] = [];
+    const lines = readline.createInterface({
+      input: fs.createReadStream(this.spoolPath),
+      crlfDelay: Infinity,
+    });
+    for await (const line of lines) {
+      group.push(JSON.parse(line) as Record<string, unknown>);
+      if (group.length === this.rowGroupSize) {
+        await this.writeRowGroup(group);
+        group = [];
+      }
+    }
+    if (group.length > 0) {
+      await this.writeRowGroup(group);
+    }
+  }
+
+  private async writeRowGroup(records: Record<string, unknown>[]): Promise<void> {
+    const group: RowGroupMeta = { rows: records.length, bytes: 0, columns: [] };
+    for (const column of this.schema!) {
+      const page = encodeDataPage(column, records.map((record) => record[column.name]));
+      group.columns.push({
+        type: PHYSICAL_TYPES[column.type],
+        offset: this.offset,
+        size: page.length,
+        values: records.length,
+      });
+      group.bytes += page.length;
+      await this.append(page);
+    }
+    this.rowGroups.push(group);
+  }
+
+  private footer(): Buffer {
+    const schema = this.schema ?? [];
+    const writer = new ThriftCompactWriter();
+    // The schema is flattened depth-first: the root, then one leaf per column.
+    const elements: ((element: ThriftCompactWriter) => void)[] = [
+      (root) => root.string(4, "schema").i32(5, schema.length),
+      ...schema.map((column) => (element: ThriftCompactWriter) => {
+        writeSchemaElement(element, column);
+      }),
+    ];
+    writer.structBody((meta) => {
+      meta.i32(1, 1);
+      meta.list(2, "struct", elements, (element) => meta.structBody(element));
+      meta.i64(3, this.total);
+      meta.list(4, "struct", this.rowGroups, (group) =>
+        meta.structBody(() => {
+          const chunks = group.columns.map((chunk, i) => ({ ...chunk, name: schema[i]!.name }));
+          meta.list(1, "struct", chunks, (chunk) =>
+            meta.structBody(() => {
+              meta.i64(2, chunk.offset).struct(3, () => {
+                meta.i32(1, chunk.type);
+                meta.list(2, "i32", [ENCODING_PLAIN, ENCODING_RLE], (encoding) =>
+                  meta.i32Element(encoding),
+                );
+                meta.list(3, "string", [chunk.name], (part) => meta.stringElement(part));
+                meta.i32(4, CODEC_UNCOMPRESSED);
+                meta.i64(5, chunk.values);
+                meta.i64(6, chunk.size);
+                meta.i64(7, chunk.size);
+                meta.i64(9, chunk.offset);
+              });
+            }),
+          );
+          meta.i64(2, group.bytes).i64(3, group.rows);
+        }),
+      );
+      meta.string(6, "twenty-cli");
+    });
+    return writer.toBuffer();
+  }
+
+  private async append(buffer: Buffer): Promise<void> {
+    await fs.write(this.fd!, buffer, 0, buffer.length);
+    this.offset += buffer.length;
+  }
+}
+
+function writeSchemaElement(meta: ThriftCompactWriter, column: ParquetColumn): void {
+  meta.i32(1, PHYSICAL_TYPES[column.type]).i32(3, REPETITION_OPTIONAL).string(4, column.name);
+  if (column.type === "string") {
+    meta.i32(6, CONVERTED_UTF8).struct(10, (logical) => logical.struct(1, () => {}));
+  } else if (column.type === "timestamp") {
+    meta.i32(6, CONVERTED_TIMESTAMP_MILLIS).struct(10, (logical) =>
+      logical.struct(8, (timestamp) => {
+        timestamp.bool(1, true).struct(2, (unit) => unit.struct(1, () => {}));
+      }),
+    );
+  }
+}
+
+export function inferSchema(
+  records: Record<string, unknown>[],
+  fields?: readonly string[],
+): ParquetColumn[] {
+  const types = new ColumnTypes();
+  types.add(records);
+  return types.schema(fields);
+}
+
+// Tracks which kinds of value each column has held, in first-seen column
+// order, so the schema can be chosen after the last record without keeping
+// the records in memory.
+class ColumnTypes {
+  private readonly kinds = new Map<string, Set<ParquetColumnType>>();
+
+  add(records: Record<string, unknown>[]): void {
+    for (const record of records) {
+      for (const [name, value] of Object.entries(record)) {
+        let kinds = this.kinds.get(name);
+        if (!kinds) {
+          kinds = new Set();
+          this.kinds.set(name, kinds);
+        }
+        if (isPresent(value)) {
+          kinds.add(valueKind(value));
+        }
+      }
+    }
+  }
+
+  // A column that only held one kind of value gets that type; an empty or
+  // mixed column is stored as strings.
+  schema(fields?: readonly string[]): ParquetColumn[] {
+    const names = [...this.kinds.keys()];
+    return (fields ? orderCsvColumns(names, fields) : names).map((name) => {
+      const kinds = [...(this.kinds.get(name) ?? [])];
+      return { name, type: kinds.length === 1 ? kinds[0]! : "string" };
+    });
+  }
+}
+
+function valueKind(value: unknown): ParquetColumnType {
+  if (typeof value === "boolean") {
+    return "boolean";
+  }
+  if (typeof value === "number" && Number.isFinite(value)) {
+    return "double";
+  }
+  if (typeof value === "string" && isTimestamp(value)) {
+    return "timestamp";
+  }
+  return "string";
+}
+
+// One data page (v1) holding the whole column chunk: definition levels
+// (1 = present, 0 = null) followed by the present values.
+function encodeDataPage(column: ParquetColumn, values: unknown[]): Buffer {
+  const present = values.filter(isPresent);
+  const levels = encodeDefinitionLevels(values.map((value) => (isPresent(value) ? 1 : 0)));
+  const body = Buffer.concat([levels, encodeValues(column, present)]);
+  const header = new ThriftCompactWriter();
+  header.structBody((page) => {
+    page.i32(1, PAGE_DATA).i32(2, body.length).i32(3, body.length);
+    page.struct(5, (data) => {
+      data.i32(1, values.length);
+      data.i32(2, ENCODING_PLAIN).i32(3, ENCODING_RLE).i32(4, ENCODING_RLE);
+    });
+  });
+  return Buffer.concat([header.toBuffer(), body]);
+}
+
+// RLE/bit-packed hybrid with bit width 1, written as a single bit-packed run
+// and prefixed with its byte length as data page v1 requires.
+function encodeDefinitionLevels(levels: number[]): Buffer {
+  const groups = Math.ceil(levels.length / 8);
+  const packed = packBits(levels.map((level) => level === 1));
+  const header = varint((groups << 1) | 1);
+  const length = Buffer.alloc(4);
+  length.writeUInt32LE(header.length + packed.length, 0);
+  return Buffer.concat([length, header, packed]);
+}
+
+function encodeValues(column: ParquetColumn, values: unknown[]): Buffer {
+  switch (column.type) {
+    case "boolean":
+      return packBits(values as boolean[]);
+    case "double": {
+      const buffer = Buffer.alloc(values.length * 8);
+      values.forEach((value, i) => {
+        buffer.writeDoubleLE(value as number, i * 8);
+      });
+      return buffer;
+    }
+    case "timestamp": {
+      const buffer = Buffer.alloc(values.length * 8);
+      values.forEach((value, i) => {
+        buffer.writeBigInt64LE(BigInt(Date.parse(value as string)), i * 8);
+      });
+      return buffer;
+    }
+    case "string":
+      return Buffer.concat(
+        values.map((value) => {
+          const text = Buffer.from(
+            typeof value === "object" ? JSON.stringify(value) : String(value),
+            "utf-8",
+          );
+          const length = Buffer.alloc(4);
+          length.writeUInt32LE(text.length, 0);
+          return Buffer.concat([length, text]);
+        }),
+      );
+  }
+}
+
+// Bit-packs booleans least-significant bit first, padded to whole bytes.
+function packBits(bits: boolean[]): Buffer {
+  const buffer = Buffer.alloc(Math.ceil(bits.length / 8));
+  bits.forEach((bit, i) => {
+    if (bit) {
+      buffer[i >> 3] = buffer[i >> 3]! | (1 << (i & 7));
+    }
+  });
+  return buffer;
+}
+
+function varint(value: number): Buffer {
+  const bytes: number[] = [];
+  let remaining = value >>> 0;
+  while (remaining >= 0x80) {
+    bytes.push((remaining & 0x7f) | 0x80);
+    remaining >>>= 7;
+  }
+  bytes.push(remaining);
+  return Buffer.from(bytes);
+}
+
+function isPresent(value: unknown): boolean {
+  return value !== null && value !== undefined;
+}
+
+function isTimestamp(value: string): boolean {
+  return ISO_TIMESTAMP.test(value) && !Number.isNaN(Date.parse(value));
+}
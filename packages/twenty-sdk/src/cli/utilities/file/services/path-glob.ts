@@ -0,0 +1,55 @@
+import path from "path";
+import fs from "fs-extra";
+import { CliError } from "../../errors/cli-error";
+
+const WILDCARD = /[*?]/;
+
+// Expands quoted patterns such as 'exports/*.csv' for shells that pass them
+// through. Wildcards (* and ?) are supported in the file name only; matches
+// are sorted so dated exports import oldest first. Plain paths pass through
+// untouched, even when missing, so the importer reports them.
+export async function expandPathPatterns(patterns: string[]): Promise<string[]> {
+  const files: string[] = [];
+  for (const pattern of patterns) {
+    if (!WILDCARD.test(pattern)) {
+      files.push(pattern);
+      continue;
+    }
+
+    const dir = path.dirname(pattern);
+    if (WILDCARD.test(dir)) {
+      throw new CliError(
+        `Unsupported pattern ${JSON.stringify(pattern)}; wildcards are only allowed in the file name.`,
+        "INVALID_ARGUMENTS",
+      );
+    }
+    const matcher = toRegExp(path.basename(pattern));
+    const entries = (await fs.pathExists(dir))
+      ? await fs.readdir(dir, { withFileTypes: true })
+      : [];
+    // A bare '*.csv' lists names as typed rather than as ./name.
+    const prefix = dir === "." && !pattern.startsWith(".") ? "" : dir;
+    const matches = entries
+      .filter((entry) => entry.isFile() && matcher.test(entry.name))
+      .map((entry) => (prefix ? path.join(prefix, entry.name) : entry.name))
+      .sort();
+    if (matches.length === 0) {
+      throw new CliError(`No files match ${JSON.stringify(pattern)}.`, "INVALID_ARGUMENTS");
+    }
+    files.push(...matches);
+  }
+  return files;
+}
+
+function toRegExp(glob: string): RegExp {
+  const source = glob
+    .split("")
+    .map((char) => {
+      if (char === "*") {
+        return ".*";
+      }
+      return char === "?" ? "." : char.replace(/[\\^$.|+()[\]{}]/g, "\\$&");
+    })
+    .join("");
+  return new RegExp(`^${source}$`);
+}
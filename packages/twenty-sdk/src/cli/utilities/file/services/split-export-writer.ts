@@ -0,0 +1,220 @@
+import path from "path";
+import fs from "fs-extra";
+import { CliError } from "../../errors/cli-error";
+import {
+  collectCsvColumns,
+  CsvFlattenOptions,
+  findUnknownCsvColumn,
+  flattenCsvRecord,
+  orderCsvColumns,
+} from "../../output/services/csv-flatten";
+import {
+  CSV_NEWLINE,
+  csvCell,
+  CsvNumberFormat,
+  unparseCsv,
+} from "../../output/services/csv-writer";
+
+export interface SplitExportOptions {
+  format: "json" | "csv";
+  // Base path such as people.csv; parts are written as people-0001.csv, ...
+  output: string;
+  maxRecords?: number;
+  maxBytes?: number;
+  flatten?: CsvFlattenOptions;
+  quoteAll?: boolean;
+  // CSV only: start every part with a UTF-8 byte-order mark.
+  bom?: boolean;
+  // CSV only: --float-precision/--plain-numbers.
+  numbers?: CsvNumberFormat;
+  // CSV only: exact column order (from --fields).
+  columns?: string[];
+  // JSON only: write NDJSON parts instead of arrays.
+  stream?: boolean;
+}
+
+interface OpenPart {
+  path: string;
+  records: number;
+  bytes: number;
+  // Serialized content not yet flushed to disk.
+  pending: string;
+  // Whether the file has been created; later flushes append.
+  created: boolean;
+  // CSV columns, fixed by the first page written to this part.
+  columns?: string[];
+}
+
+const JSON_CLOSE = "\n]";
+
+// Writes export pages across numbered files, starting a new part before a
+// record would push the current one past --split-size or --split-bytes. Each
+// page is flushed as it arrives, so memory stays bounded by the page size.
+// Every CSV part has its own header, fixed by the page that opens the part; a
+// column that first appears later in the same part is an error rather than
+// silently dropped. Every JSON part is a complete array (or complete NDJSON
+// with stream).
+export class SplitExportWriter {
+  private readonly files: string[] = [];
+  private part?: OpenPart;
+  private total = 0;
+
+  constructor(private readonly options: SplitExportOptions) {}
+
+  get recordCount(): number {
+    return this.total;
+  }
+
+  async write(records: Record<string, unknown>[]): Promise<void> {
+    const rows =
+      this.options.format === "csv" && this.options.flatten
+        ? records.map((record) => flattenCsvRecord(record, this.options.flatten))
+        : records;
+
+    for (let index = 0; index < rows.length; index += 1) {
+      let chunk = this.serialize(rows, index);
+      if (this.part && this.part.records > 0 && this.wouldOverflow(this.part, chunk)) {
+        await this.closePart();
+      }
+      if (!this.part) {
+        // A new CSV part may pick different columns, so serialize again.
+        this.openPart(rows, index);
+        chunk = this.serialize(rows, index);
+      }
+      const part = this.part!;
+      this.assertKnownColumns(part, rows[index]);
+      const separated = this.separator(part) + chunk;
+      part.pending += separated;
+      part.bytes += Buffer.byteLength(separated);
+      part.records += 1;
+      this.total += 1;
+    }
+
+    await this.flush();
+  }
+
+  // Finishes the last part and returns every file written, in order. An
+  // export with no records still produces one (empty) part.
+  async close(): Promise<string[]> {
+    if (!this.part && this.files.length === 0) {
+      this.openPart([], 0);
+    }
+    await this.closePart();
+    return [...this.files];
+  }
+
+  private get jsonArray(): boolean {
+    return this.options.format === "json" && !this.options.stream;
+  }
+
+  private openPart(rows: Record<string, unknown>[], index: number): void {
+    const partPath = splitPartPath(this.options.output, this.files.length + 1);
+    this.files.push(partPath);
+    let header = this.jsonArray ? "[" : "";
+    let columns: string[] | undefined;
+    if (this.options.format === "csv") {
+      columns = collectCsvColumns(rows.slice(index));
+      if (this.options.columns) {
+        columns = orderCsvColumns(columns, this.options.columns);
+      }
+      header =
+        columns.length > 0
+          ? unparseCsv(
+              { fields: columns, data: [] },
+              { quoteAll: this.options.quoteAll, bom: this.options.bom },
+            )
+          : "";
+    }
+    this.part = {
+      path: partPath,
+      records: 0,
+      bytes: Buffer.byteLength(header),
+      pending: header,
+      created: false,
+      columns,
+    };
+  }
+
+  private async closePart(): Promise<void> {
+    const part = this.part;
+    if (!part) {
+      return;
+    }
+    if (this.jsonArray) {
+      part.pending += part.records > 0 ? JSON_CLOSE : "]";
+    }
+    await this.flush();
+    this.part = undefined;
+  }
+
+  private async flush(): Promise<void> {
+    const part = this.part;
+    if (!part || (part.created && part.pending === "")) {
+      return;
+    }
+    if (part.created) {
+      await fs.appendFile(part.path, part.pending);
+    } else {
+      await fs.writeFile(part.path, part.pending);
+      part.created = true;
+    }
+    part.pending = "";
+  }
+
+  private serialize(rows: Record<string, unknown>[], index: number): string {
+    const row = rows[index];
+    if (this.options.format === "json" && this.options.stream) {
+      return JSON.stringify(row);
+    }
+    if (this.options.format === "json") {
+      return JSON.stringify(row, null, 2).replace(/^/gm, "  ");
+    }
+    const columns = this.part?.columns ?? collectCsvColumns(rows.slice(index));
+    return unparseCsv(
+      { fields: columns, data: [columns.map((column) => csvCell(row[column]))] },
+      { quoteAll: this.options.quoteAll, header: false, numbers: this.options.numbers },
+    );
+  }
+
+  private assertKnownColumns(part: OpenPart, row: Record<string, unknown>): void {
+    if (!part.columns) {
+      return;
+    }
+    const extra = findUnknownCsvColumn([row], part.columns, this.options.columns);
+    if (extra !== undefined) {
+      throw new CliError(
+        `CSV column "${extra}" first appeared after the header of ${part.path} was written.`,
+        "INVALID_ARGUMENTS",
+        "List the columns with --fields, or export as JSON.",
+      );
+    }
+  }
+
+  private separator(part: OpenPart): string {
+    if (this.jsonArray) {
+      return part.records > 0 ? ",\n" : "\n";
+    }
+    if (this.options.format === "json") {
+      return part.records > 0 ? "\n" : "";
+    }
+    return part.bytes > 0 ? CSV_NEWLINE : "";
+  }
+
+  private wouldOverflow(part: OpenPart, chunk: string): boolean {
+    const { maxRecords, maxBytes } = this.options;
+    if (maxRecords !== undefined && part.records + 1 > maxRecords) {
+      return true;
+    }
+    const closing = this.jsonArray ? JSON_CLOSE.length : 0;
+    const added = Buffer.byteLength(this.separator(part) + chunk) + closing;
+    return maxBytes !== undefined && part.bytes + added > maxBytes;
+  }
+}
+
+// people.csv -> people-0001.csv; a path without an extension gets the suffix
+// appended directly.
+export function splitPartPath(output: string, partNumber: number): string {
+  const extension = path.extname(output);
+  const base = extension ? output.slice(0, -extension.length) : output;
+  return `${base}-${String(partNumber).padStart(4, "0")}${extension}`;
+}
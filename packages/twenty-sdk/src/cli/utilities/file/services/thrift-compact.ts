@@ -0,0 +1,136 @@
+// Minimal Thrift compact-protocol encoder, enough to write Parquet page
+// headers and file metadata. Only the types Parquet's metadata uses are
+// supported; there is no decoder.
+const TYPE_BOOLEAN_TRUE = 1;
+const TYPE_BOOLEAN_FALSE = 2;
+const TYPE_I32 = 5;
+const TYPE_I64 = 6;
+const TYPE_BINARY = 8;
+const TYPE_LIST = 9;
+const TYPE_STRUCT = 12;
+
+export type ThriftListType = "i32" | "string" | "struct";
+
+const LIST_ELEMENT_TYPES: Record<ThriftListType, number> = {
+  i32: TYPE_I32,
+  string: TYPE_BINARY,
+  struct: TYPE_STRUCT,
+};
+
+export class ThriftCompactWriter {
+  private readonly bytes: number[] = [];
+  // Last field ID of each open struct; compact field headers are deltas.
+  private readonly fieldIds: number[] = [0];
+
+  i32(id: number, value: number): this {
+    this.fieldHeader(id, TYPE_I32);
+    this.varint32(zigzag32(value));
+    return this;
+  }
+
+  i64(id: number, value: number): this {
+    this.fieldHeader(id, TYPE_I64);
+    this.varint64(zigzag64(value));
+    return this;
+  }
+
+  bool(id: number, value: boolean): this {
+    this.fieldHeader(id, value ? TYPE_BOOLEAN_TRUE : TYPE_BOOLEAN_FALSE);
+    return this;
+  }
+
+  string(id: number, value: string): this {
+    this.fieldHeader(id, TYPE_BINARY);
+    this.binary(value);
+    return this;
+  }
+
+  struct(id: number, body: (writer: this) => void): this {
+    this.fieldHeader(id, TYPE_STRUCT);
+    this.structBody(body);
+    return this;
+  }
+
+  list<T>(id: number, type: ThriftListType, items: T[], write: (item: T) => void): this {
+    this.fieldHeader(id, TYPE_LIST);
+    const elementType = LIST_ELEMENT_TYPES[type];
+    if (items.length < 15) {
+      this.bytes.push((items.length << 4) | elementType);
+    } else {
+      this.bytes.push(0xf0 | elementType);
+      this.varint32(items.length);
+    }
+    for (const item of items) {
+      write(item);
+    }
+    return this;
+  }
+
+  // List elements are written without field headers.
+  i32Element(value: number): void {
+    this.varint32(zigzag32(value));
+  }
+
+  stringElement(value: string): void {
+    this.binary(value);
+  }
+
+  structBody(body: (writer: this) => void): void {
+    this.fieldIds.push(0);
+    body(this);
+    this.bytes.push(0);
+    this.fieldIds.pop();
+  }
+
+  toBuffer(): Buffer {
+    return Buffer.from(this.bytes);
+  }
+
+  private fieldHeader(id: number, type: number): void {
+    const delta = id - this.fieldIds[this.fieldIds.length - 1]!;
+    if (delta > 0 && delta <= 15) {
+      this.bytes.push((delta << 4) | type);
+    } else {
+      this.bytes.push(type);
+      this.varint32(zigzag32(id));
+    }
+    this.fieldIds[this.fieldIds.length - 1] = id;
+  }
+
+  private binary(value: string): void {
+    const encoded = Buffer.from(value, "utf-8");
+    this.varint32(encoded.length);
+    for (const byte of encoded) {
+      this.bytes.push(byte);
+    }
+  }
+
+  private varint32(value: number): void {
+    let remaining = value >>> 0;
+    while (remaining >= 0x80) {
+      this.bytes.push((remaining & 0x7f) | 0x80);
+      remaining >>>= 7;
+    }
+    this.bytes.push(remaining);
+  }
+
+  private varint64(value: bigint): void {
+    let remaining = value;
+    const mask = BigInt(0x7f);
+    const seven = BigInt(7);
+    while (remaining >= BigInt(0x80)) {
+      this.bytes.push(Number(remaining & mask) | 0x80);
+      remaining >>= seven;
+    }
+    this.bytes.push(Number(remaining));
+  }
+}
+
+function zigzag32(value: number): number {
+  return ((value << 1) ^ (value >> 31)) >>> 0;
+}
+
+function zigzag64(value: number): bigint {
+  const n = BigInt(value);
+  return n >= BigInt(0) ? n << BigInt(1) : (-n << BigInt(1)) - BigInt(1);
+}
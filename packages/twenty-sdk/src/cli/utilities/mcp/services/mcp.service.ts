@@ -2,6 +2,7 @@ import { ApiService } from "../../api/services/api.service";
 import { ConfigService } from "../../config/services/config.service";
 import { CliError, errorWithCause } from "../../errors/cli-error";
 import { CLI_VERSION } from "../../../version";
+import { log } from "../../shared/logger";
 import { JsonRpcFailure, JsonRpcRequest, JsonRpcSuccess, McpStatusResult } from "../types";
 
 interface McpServiceOptions {
@@ -453,7 +454,6 @@ export class McpService {
       return;
     }
 
-    // eslint-disable-next-line no-console
-    console.error(message);
+    log("debug", message);
   }
 }
@@ -0,0 +1,31 @@
+import { describe, expect, it } from "vitest";
+import { resolveJsonPointer } from "../json-pointer";
+
+describe("resolveJsonPointer", () => {
+  const doc = { data: { items: [{ id: "a" }, { id: "b" }] }, "a/b": 1, "m~n": 2, "": 3 };
+
+  it("returns the whole document for the empty pointer", () => {
+    expect(resolveJsonPointer(doc, "")).toBe(doc);
+  });
+
+  it("walks object members and array indexes", () => {
+    expect(resolveJsonPointer(doc, "/data/items/1/id")).toBe("b");
+  });
+
+  it("unescapes ~1 and ~0 and allows the empty key", () => {
+    expect(resolveJsonPointer(doc, "/a~1b")).toBe(1);
+    expect(resolveJsonPointer(doc, "/m~0n")).toBe(2);
+    expect(resolveJsonPointer(doc, "/")).toBe(3);
+  });
+
+  it("rejects pointers that do not start with a slash", () => {
+    expect(() => resolveJsonPointer(doc, "data/items")).toThrow('must be empty or start with "/"');
+  });
+
+  it("errors on missing members, out-of-range and non-numeric indexes", () => {
+    expect(() => resolveJsonPointer(doc, "/data/missing")).toThrow('no "missing" under /data.');
+    expect(() => resolveJsonPointer(doc, "/data/items/2")).toThrow('no "2" under /data/items.');
+    expect(() => resolveJsonPointer(doc, "/data/items/01")).toThrow('no "01" under /data/items.');
+    expect(() => resolveJsonPointer(doc, "/nope")).toThrow('no "nope" under the root.');
+  });
+});
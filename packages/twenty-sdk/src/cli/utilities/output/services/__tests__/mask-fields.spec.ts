@@ -0,0 +1,48 @@
+import { describe, expect, it } from "vitest";
+import { maskRecordFields, maskValue } from "../mask-fields";
+
+describe("maskValue", () => {
+  it("keeps the first character and the domain of an email", () => {
+    expect(maskValue("john.doe@example.com")).toBe("j***@example.com");
+  });
+
+  it("hides all but the last two digits of a phone number, keeping punctuation", () => {
+    expect(maskValue("+1 (555) 123-4567")).toBe("+* (***) ***-**67");
+    expect(maskValue("5551234")).toBe("*****34");
+  });
+
+  it("keeps only the first character of other text", () => {
+    expect(maskValue("Vancouver")).toBe("V***");
+    expect(maskValue("")).toBe("");
+  });
+});
+
+describe("maskRecordFields", () => {
+  it("masks Twenty's emails and phones composites through the email and phone aliases", () => {
+    const records = [
+      {
+        id: "person-1",
+        emails: { primaryEmail: "ann@acme.io", additionalEmails: ["a.lee@acme.io"] },
+        phones: { primaryPhoneNumber: "6045550199", primaryPhoneCountryCode: "CA" },
+      },
+    ];
+
+    expect(maskRecordFields(records, ["email", "phone"])).toEqual([
+      {
+        id: "person-1",
+        emails: { primaryEmail: "a***@acme.io", additionalEmails: ["a***@acme.io"] },
+        phones: { primaryPhoneNumber: "********99", primaryPhoneCountryCode: "C***" },
+      },
+    ]);
+  });
+
+  it("keeps nulls and leaves unlisted keys and non-records alone", () => {
+    expect(maskRecordFields({ id: "1", email: null, score: 5 }, ["email", "score"])).toEqual({
+      id: "1",
+      email: null,
+      score: "***",
+    });
+    expect(maskRecordFields(["plain"], ["email"])).toEqual(["plain"]);
+    expect(maskRecordFields({ id: "1" }, undefined)).toEqual({ id: "1" });
+  });
+});
@@ -1,7 +1,11 @@
+import os from "node:os";
+import path from "node:path";
+import fs from "fs-extra";
 import { describe, it, expect, vi, beforeEach, afterEach } from "vitest";
 import { OutputService } from "../output.service";
 import { QueryService } from "../query.service";
 import { TableService } from "../table.service";
+import { BufferedOutputWriter } from "../output-writer";
 import { assertCompactAliasesAreValid, toLightPayload } from "../compact-aliases";
 
 describe("OutputService", () => {
@@ -59,6 +63,175 @@ describe("OutputService", () => {
       expect(output).toContain("42");
       expect(output).toContain("true");
     });
+
+    it("flattens nested objects into dotted columns when csvFlatten is set", async () => {
+      const data = [
+        {
+          id: "1",
+          name: { firstName: "John", lastName: "Doe" },
+          emails: { primaryEmail: "john@example.com", additionalEmails: [] },
+        },
+      ];
+
+      await outputService.render(data, { format: "csv", csvFlatten: {} });
+
+      const output = consoleSpy.mock.calls[0][0];
+      expect(output).toBe(
+        "id,name.firstName,name.lastName,emails.primaryEmail,emails.additionalEmails\r\n" +
+          "1,John,Doe,john@example.com,[]",
+      );
+    });
+
+    it("quotes every field when csvQuoteAll is set", async () => {
+      const data = [{ id: "1", name: "Acme", employees: 42, note: null }];
+
+      await outputService.render(data, { format: "csv", csvQuoteAll: true });
+      await outputService.render(data, { format: "csv", csvQuoteAll: true, csvFlatten: {} });
+
+      const expected = '"id","name","employees","note"\r\n"1","Acme","42",""';
+      expect(consoleSpy.mock.calls[0][0]).toBe(expected);
+      expect(consoleSpy.mock.calls[1][0]).toBe(expected);
+    });
+
+    it("prepends a UTF-8 byte-order mark when csvBom is set", async () => {
+      await outputService.render({ id: "1", name: "小酒馆" }, { format: "csv", csvBom: true });
+      await outputService.render({ id: "1" }, { format: "json", csvBom: true });
+
+      expect(consoleSpy.mock.calls[0][0]).toBe("\uFEFFid,name\r\n1,小酒馆");
+      expect(consoleSpy.mock.calls[1][0]).toBe('{"id":"1"}');
+    });
+
+    it("formats csv numbers with csvNumbers and leaves other formats alone", async () => {
+      const data = [{ id: "1", amount: 1234.5, probability: 1.5e-7, count: 3 }];
+
+      await outputService.render(data, { format: "csv" });
+      await outputService.render(data, { format: "csv", csvNumbers: { floatPrecision: 2 } });
+      await outputService.render(data, { format: "csv", csvNumbers: { plain: true } });
+      await outputService.render(data, { format: "json", csvNumbers: { plain: true } });
+
+      expect(consoleSpy.mock.calls[0][0]).toBe(
+        "id,amount,probability,count\r\n1,1234.5,1.5e-7,3",
+      );
+      expect(consoleSpy.mock.calls[1][0]).toBe("id,amount,probability,count\r\n1,1234.50,0.00,3");
+      expect(consoleSpy.mock.calls[2][0]).toBe(
+        "id,amount,probability,count\r\n1,1234.5,0.00000015,3",
+      );
+      expect(JSON.parse(consoleSpy.mock.calls[3][0])).toEqual(data);
+    });
+  });
+
+  describe("prune fields", () => {
+    it("drops pruned keys from every record in any format", async () => {
+      const data = [
+        { id: "1", name: "Acme", createdBy: { source: "API" } },
+        { id: "2", name: "Beta", createdBy: { source: "IMPORT" } },
+      ];
+
+      await outputService.render(data, { format: "csv", pruneFields: ["createdBy"] });
+
+      expect(consoleSpy.mock.calls[0][0]).toBe("id,name\r\n1,Acme\r\n2,Beta");
+    });
+
+    it("prunes before applying the query", async () => {
+      const data = { id: "1", secret: "x", nested: { secret: "kept" } };
+
+      await outputService.render(data, {
+        format: "json",
+        query: "keys(@)",
+        pruneFields: ["secret"],
+      });
+
+      expect(consoleSpy).toHaveBeenCalledWith('["id","nested"]');
+    });
+  });
+
+  describe("mask fields", () => {
+    it("masks values but keeps every column in csv", async () => {
+      const data = [
+        { id: "1", emails: { primaryEmail: "jane@example.com" }, city: "Paris" },
+        { id: "2", emails: { primaryEmail: null }, city: "Oslo" },
+      ];
+
+      await outputService.render(data, { format: "csv", maskFields: ["email", "city"] });
+
+      expect(consoleSpy.mock.calls[0][0]).toBe(
+        'id,emails,city\r\n1,"{""primaryEmail"":""j***@example.com""}",P***\r\n' +
+          '2,"{""primaryEmail"":null}",O***',
+      );
+    });
+  });
+
+  describe("envelope unwrapping", () => {
+    it("renders the inner resource when unwrap is set", async () => {
+      const envelope = {
+        data: { people: [{ id: "1" }] },
+        pageInfo: { hasNextPage: false },
+        totalCount: 1,
+      };
+
+      await outputService.render(envelope, { format: "json", unwrap: true });
+      await outputService.render(envelope, { format: "json" });
+
+      expect(consoleSpy.mock.calls[0][0]).toBe('[{"id":"1"}]');
+      expect(consoleSpy.mock.calls[1][0]).toBe(JSON.stringify(envelope));
+    });
+  });
+
+  describe("raw API payloads", () => {
+    const envelope = {
+      data: { people: [{ id: "1", name: "Ada" }, { id: "2", name: "Grace" }] },
+      pageInfo: { hasNextPage: false },
+      totalCount: 2,
+    };
+
+    it("writes one CSV row per record in a REST list envelope", async () => {
+      await outputService.render(envelope, { format: "csv" });
+
+      expect(consoleSpy.mock.calls[0][0]).toBe("id,name\r\n1,Ada\r\n2,Grace");
+    });
+
+    it("tabulates the records of REST envelopes and GraphQL connections", async () => {
+      await outputService.render(envelope, { format: "table" });
+      await outputService.render(
+        { data: { people: { edges: [{ node: { id: "3", name: "Linus" } }] } } },
+        { format: "table" },
+      );
+
+      const lines = consoleSpy.mock.calls.map((call) => String(call[0]));
+      expect(lines.some((line) => line.includes("Grace"))).toBe(true);
+      expect(lines.some((line) => line.includes("Linus"))).toBe(true);
+      expect(lines.some((line) => line.includes("PAGEINFO"))).toBe(false);
+    });
+  });
+
+  describe("versioned envelope", () => {
+    it("wraps json list output when --envelope is set", async () => {
+      const service = new OutputService(new TableService(), new QueryService(), {
+        envelope: true,
+      });
+
+      await service.render([{ id: "1" }], { format: "json", kind: "PersonList" });
+
+      expect(consoleSpy).toHaveBeenCalledWith(
+        '{"apiVersion":"twenty-cli/v1","kind":"PersonList","items":[{"id":"1"}]}',
+      );
+    });
+
+    it("leaves output unwrapped without a kind or outside json", async () => {
+      const service = new OutputService(new TableService(), new QueryService(), {
+        envelope: true,
+      });
+
+      await service.render({ id: "1" }, { format: "json" });
+      await service.render([{ id: "1" }], { format: "jsonl", kind: "PersonList" });
+      await outputService.render([{ id: "1" }], { format: "json", kind: "PersonList" });
+
+      expect(consoleSpy.mock.calls.map((call) => call[0])).toEqual([
+        '{"id":"1"}',
+        '{"id":"1"}',
+        '[{"id":"1"}]',
+      ]);
+    });
   });
 
   describe("text output with CLI diagnostics", () => {
@@ -74,7 +247,7 @@ describe("OutputService", () => {
               "The MCP server advertised skill names but returned no loaded skills for this workspace. This is likely a workspace configuration issue, not a CLI transport failure.",
           },
         },
-        { format: "text" },
+        { format: "table" },
       );
 
       expect(consoleSpy).toHaveBeenCalledWith(
@@ -86,6 +259,155 @@ describe("OutputService", () => {
     });
   });
 
+  describe("text and table output", () => {
+    it("renders a single record as key/value pairs for text", async () => {
+      await outputService.render({ name: "Ada", id: "1", city: null }, { format: "text" });
+
+      expect(consoleSpy.mock.calls.map((call) => call[0])).toEqual([
+        "id    1",
+        "name  Ada",
+        "city  ",
+      ]);
+    });
+
+    it("renders each record through --text-template", async () => {
+      await outputService.render(
+        { data: { opportunities: [{ name: "Deal", stage: "NEW", owner: { name: "Ada" } }] } },
+        { format: "text", textTemplate: "{{.name}} ({{.stage}}) {{.owner.name}}" },
+      );
+      await outputService.render(
+        { name: "Renewal", stage: "WON" },
+        { format: "text", textTemplate: "{{.name}} ({{.stage}})" },
+      );
+
+      expect(consoleSpy.mock.calls.map((call) => call[0])).toEqual([
+        "Deal (NEW) Ada",
+        "Renewal (WON)",
+      ]);
+    });
+
+    it("renders a single record as a table for table", async () => {
+      await outputService.render({ id: "1", name: "Ada" }, { format: "table" });
+
+      expect(consoleSpy.mock.calls[0]?.[0]).toContain("ID");
+      expect(consoleSpy.mock.calls[0]?.[0]).toContain("NAME");
+    });
+
+    it("keeps rendering lists as a table for text", async () => {
+      await outputService.render([{ id: "1" }, { id: "2" }], { format: "text" });
+
+      expect(consoleSpy.mock.calls[0]?.[0]).toContain("ID");
+      expect(consoleSpy).toHaveBeenCalledTimes(3);
+    });
+  });
+
+  describe("computed columns", () => {
+    const people = [
+      { id: "1", name: { firstName: "Ada", lastName: "Lovelace" }, city: "London" },
+      { id: "2", name: { firstName: "Linus", lastName: null }, city: "Helsinki" },
+    ];
+    const computed = [
+      { name: "fullName", template: "{{.name.firstName}} {{ .name.lastName }}" },
+      { name: "where", template: "in {{city}}" },
+    ];
+
+    it("appends templated CSV columns in order", async () => {
+      await outputService.render(people, { format: "csv", computed });
+
+      const [header, first, second] = String(consoleSpy.mock.calls[0][0]).split("\r\n");
+      expect(header).toBe("id,name,city,fullName,where");
+      expect(first).toContain(",Ada Lovelace,in London");
+      expect(second).toContain(",Linus ,in Helsinki");
+    });
+
+    it("places computed table columns last", async () => {
+      await outputService.render(people, { format: "table", computed });
+
+      expect(consoleSpy.mock.calls[0][0]).toMatch(/CITY\s+FULLNAME\s+WHERE/);
+    });
+
+    it("leaves JSON output untouched", async () => {
+      await outputService.render(people, { format: "json", computed });
+
+      expect(consoleSpy.mock.calls[0][0]).toBe(JSON.stringify(people));
+    });
+  });
+
+  describe("column order from --fields", () => {
+    const people = [
+      { id: "1", name: { firstName: "Ada" }, city: "London", email: "ada@example.com" },
+      { id: "2", city: "Helsinki" },
+    ];
+
+    it("writes CSV headers in the given order, with empty cells for missing fields", async () => {
+      await outputService.render(people, { format: "csv", columns: ["email", "id", "name"] });
+
+      expect(consoleSpy.mock.calls[0][0]).toBe(
+        'email,id,name\r\nada@example.com,1,"{""firstName"":""Ada""}"\r\n,2,',
+      );
+    });
+
+    it("keeps flattened columns grouped under their field", async () => {
+      await outputService.render(people, {
+        format: "csv",
+        columns: ["city", "name", "id"],
+        csvFlatten: {},
+      });
+
+      const [header] = String(consoleSpy.mock.calls[0][0]).split("\r\n");
+      expect(header).toBe("city,name.firstName,id");
+    });
+
+    it("orders table columns and keeps computed columns last", async () => {
+      await outputService.render(people, {
+        format: "table",
+        columns: ["city", "id"],
+        computed: [{ name: "label", template: "{{id}}" }],
+      });
+
+      expect(consoleSpy.mock.calls[0][0]).toMatch(/^CITY\s+ID\s+LABEL\s*$/);
+    });
+
+    it("ignores the order when --query reshapes the result", async () => {
+      await outputService.render(people, {
+        format: "csv",
+        columns: ["email", "id"],
+        query: "[].{city: city}",
+      });
+
+      expect(consoleSpy.mock.calls[0][0]).toBe("city\r\nLondon\r\nHelsinki");
+    });
+  });
+
+  describe("HTML output", () => {
+    it("prints an HTML page from an unwrapped envelope", async () => {
+      await outputService.render(
+        { data: { people: [{ id: "1", city: "Paris" }] } },
+        { format: "html" },
+      );
+
+      const html = String(consoleSpy.mock.calls[0][0]);
+      expect(html).toContain("<th>id</th><th>city</th>");
+      expect(html).toContain("<td>Paris</td>");
+    });
+
+    it("writes the page to outputFile instead of stdout", async () => {
+      const dir = await fs.mkdtemp(path.join(os.tmpdir(), "twenty-html-"));
+      const errorSpy = vi.spyOn(console, "error").mockImplementation(() => {});
+      const outputFile = path.join(dir, "report.html");
+      try {
+        await outputService.render([], { format: "html", outputFile });
+
+        expect(consoleSpy).not.toHaveBeenCalled();
+        expect(await fs.readFile(outputFile, "utf-8")).toContain("No records found.");
+        expect(errorSpy).toHaveBeenCalledWith(`Wrote ${outputFile}`);
+      } finally {
+        errorSpy.mockRestore();
+        await fs.remove(dir);
+      }
+    });
+  });
+
   describe("JSONL output", () => {
     it("writes arrays as newline-delimited JSON objects", async () => {
       await outputService.render(
@@ -109,6 +431,110 @@ describe("OutputService", () => {
     });
   });
 
+  describe("sorted keys", () => {
+    it("sorts object keys at every depth, after --envelope wraps the list", async () => {
+      outputService = new OutputService(new TableService(), new QueryService(), {
+        sortKeys: true,
+        envelope: true,
+      });
+
+      await outputService.render([{ name: { last: "Lovelace", first: "Ada" }, id: "1" }], {
+        format: "json",
+        kind: "PersonList",
+      });
+      await outputService.render([{ b: 1, a: [{ d: 2, c: 3 }] }], { format: "jsonl" });
+
+      expect(consoleSpy.mock.calls[0][0]).toBe(
+        '{"apiVersion":"twenty-cli/v1","items":' +
+          '[{"id":"1","name":{"first":"Ada","last":"Lovelace"}}],"kind":"PersonList"}',
+      );
+      expect(consoleSpy.mock.calls[1][0]).toBe('{"a":[{"c":3,"d":2}],"b":1}');
+    });
+
+    it("keeps key order by default", async () => {
+      await outputService.render({ b: 1, a: 2 }, { format: "json" });
+
+      expect(consoleSpy).toHaveBeenCalledWith('{"b":1,"a":2}');
+    });
+  });
+
+  describe("NUL-delimited output", () => {
+    let writeSpy: ReturnType<typeof vi.spyOn>;
+
+    beforeEach(() => {
+      writeSpy = vi.spyOn(process.stdout, "write").mockImplementation(() => true);
+    });
+
+    afterEach(() => {
+      writeSpy.mockRestore();
+    });
+
+    it("terminates each jsonl value with NUL, keeping embedded newlines", async () => {
+      await outputService.render(["p-1", "two\nlines"], {
+        format: "jsonl",
+        rawOutput: true,
+        print0: true,
+      });
+
+      expect(writeSpy).toHaveBeenCalledWith("p-1\0two\nlines\0");
+      expect(consoleSpy).not.toHaveBeenCalled();
+    });
+
+    it("terminates --text-template lines with NUL", async () => {
+      await outputService.render([{ name: "Ada" }, { name: "Linus" }], {
+        format: "text",
+        textTemplate: "{{.name}}",
+        print0: true,
+      });
+
+      expect(writeSpy).toHaveBeenCalledWith("Ada\0Linus\0");
+    });
+
+    it("rejects formats that are not value-per-line", async () => {
+      await expect(
+        outputService.render([{ id: "1" }], { format: "csv", print0: true }),
+      ).rejects.toThrow("--print0 does not apply to csv output.");
+      expect(writeSpy).not.toHaveBeenCalled();
+    });
+  });
+
+  describe("JSON pointer and raw output", () => {
+    const response = { data: { people: [{ id: "p-1", name: { firstName: "Ada" } }] } };
+
+    it("prints the pointed value as JSON, or unquoted with rawOutput", async () => {
+      await outputService.render(response, { pointer: "/data/people/0/name" });
+      await outputService.render(response, { pointer: "/data/people/0/id" });
+      await outputService.render(response, { pointer: "/data/people/0/id", rawOutput: true });
+
+      expect(consoleSpy.mock.calls.map((call) => call[0])).toEqual([
+        '{"firstName":"Ada"}',
+        '"p-1"',
+        "p-1",
+      ]);
+    });
+
+    it("errors when the pointer does not match", async () => {
+      await expect(outputService.render(response, { pointer: "/data/people/1" })).rejects.toThrow(
+        'JSON pointer /data/people/1 did not match: no "1" under /data/people.',
+      );
+      expect(consoleSpy).not.toHaveBeenCalled();
+    });
+  });
+
+  describe("injected writer", () => {
+    it("sends json, table, and NUL-delimited output to the writer instead of stdout", async () => {
+      const writer = new BufferedOutputWriter();
+      outputService = new OutputService(new TableService(writer), new QueryService(), {}, writer);
+
+      await outputService.render({ id: "1" }, { format: "json" });
+      await outputService.render([{ id: "2", name: "Ada" }], { format: "table" });
+      await outputService.render(["a", "b"], { format: "jsonl", rawOutput: true, print0: true });
+
+      expect(writer.toString()).toBe('{"id":"1"}\nID  NAME\n2   Ada \na\0b\0');
+      expect(consoleSpy).not.toHaveBeenCalled();
+    });
+  });
+
   describe("compact light output", () => {
     it("keeps compact aliases unique", () => {
       expect(() => assertCompactAliasesAreValid()).not.toThrow();
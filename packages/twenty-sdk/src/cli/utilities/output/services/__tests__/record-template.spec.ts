@@ -0,0 +1,31 @@
+import { describe, expect, it } from "vitest";
+import { renderRecordTemplate } from "../record-template";
+
+describe("renderRecordTemplate", () => {
+  const record = { name: { firstName: 'Ann "A"' }, tags: "vip; ; beta", city: null };
+
+  it("substitutes dotted paths and renders missing values as empty strings", () => {
+    expect(renderRecordTemplate("{{.name.firstName}} in {{ city }}{{.missing}}", record)).toBe(
+      'Ann "A" in ',
+    );
+  });
+
+  it("pipes values through json and split", () => {
+    expect(renderRecordTemplate("{{.name.firstName | json}}", record)).toBe('"Ann \\"A\\""');
+    expect(renderRecordTemplate('{{.tags | split ";"}}', record)).toBe('["vip","beta"]');
+    expect(renderRecordTemplate('{{.city | split ";" | json}}', record)).toBe("[]");
+  });
+
+  it("keeps placeholders that are not a path or pipeline as literal text", () => {
+    expect(renderRecordTemplate("{{ not a path }}", record)).toBe("{{ not a path }}");
+  });
+
+  it("rejects unknown functions and split without a separator", () => {
+    expect(() => renderRecordTemplate("{{.tags | upper}}", record)).toThrow(
+      'Unknown template function "upper".',
+    );
+    expect(() => renderRecordTemplate("{{.tags | split}}", record)).toThrow(
+      'Template function "split" needs a separator',
+    );
+  });
+});
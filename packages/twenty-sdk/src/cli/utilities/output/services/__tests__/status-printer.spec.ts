@@ -0,0 +1,47 @@
+import { afterEach, beforeEach, describe, expect, it, vi } from "vitest";
+import { printStatus } from "../status-printer";
+
+describe("printStatus", () => {
+  let consoleSpy: ReturnType<typeof vi.spyOn>;
+
+  beforeEach(() => {
+    consoleSpy = vi.spyOn(console, "log").mockImplementation(() => {});
+  });
+
+  afterEach(() => {
+    consoleSpy.mockRestore();
+  });
+
+  it("prints the human message when output is the implicit default", () => {
+    printStatus(
+      { output: "json" },
+      { action: "deleted", object: "people", id: "1", message: "Deleted people 1" },
+    );
+
+    expect(consoleSpy).toHaveBeenCalledWith("Deleted people 1");
+  });
+
+  it("emits a status object when json output was requested", () => {
+    printStatus(
+      { output: "json", outputExplicit: true },
+      { action: "deleted", object: "people", id: "1", message: "Deleted people 1" },
+    );
+
+    expect(consoleSpy).toHaveBeenCalledTimes(1);
+    expect(JSON.parse(consoleSpy.mock.calls[0][0] as string)).toEqual({
+      status: "ok",
+      action: "deleted",
+      object: "people",
+      id: "1",
+    });
+  });
+
+  it("prints each message line for explicit text output", () => {
+    printStatus(
+      { output: "text", outputExplicit: true },
+      { action: "configured", message: ["Workspace configured.", "API URL: x"] },
+    );
+
+    expect(consoleSpy.mock.calls).toEqual([["Workspace configured."], ["API URL: x"]]);
+  });
+});
@@ -29,6 +29,40 @@ describe("TableService", () => {
     expect(output).toContain("Bob");
   });
 
+  it("appends a totals footer summing numeric and same-currency columns", () => {
+    const usd = (amountMicros: number) => ({ amountMicros, currencyCode: "USD" });
+    const data = [
+      { name: "Deal A", probability: 0.1, amount: usd(1500000000) },
+      { name: "Deal B", probability: 0.2, amount: usd(500000000) },
+      { name: "Deal C", probability: null, amount: null },
+    ];
+    const columns = ["name", "probability", "amount.amountMicros", "amount.currencyCode"];
+
+    service.render(data, [], columns, { totals: true });
+
+    const lines = consoleSpy.mock.calls.map((c) => c[0] as string);
+    expect(lines).toHaveLength(6);
+    expect(lines[4]).toMatch(/^-+  -+  -+  -+$/);
+    expect(lines[5].trim().split(/\s{2,}/)).toEqual(["TOTAL", "0.3", "2000000000"]);
+  });
+
+  it("moves --key-column to the front and keeps the default order otherwise", () => {
+    const data = [{ id: "1", name: "Deal A", stage: "NEW", amount: 5 }];
+
+    service.render(data, [], undefined, { keyColumn: "name" });
+    service.render(data, [], undefined, { keyColumn: "missing" });
+
+    const headers = consoleSpy.mock.calls.map((c) => (c[0] as string).trim().split(/\s+/));
+    expect(headers[0]).toEqual(["NAME", "ID", "AMOUNT", "STAGE"]);
+    expect(headers[2]).toEqual(["ID", "NAME", "AMOUNT", "STAGE"]);
+  });
+
+  it("leaves the footer out unless totals is set", () => {
+    service.render([{ id: "1", n: 2 }]);
+
+    expect(consoleSpy).toHaveBeenCalledTimes(2);
+  });
+
   it("handles single object by wrapping in array", () => {
     const data = { id: "1", name: "Alice" };
 
@@ -205,4 +239,29 @@ describe("TableService", () => {
     expect(titlePos).toBeLessThan(statusPos);
     expect(statusPos).toBeLessThan(createdAtPos);
   });
+
+  describe("formatHtml", () => {
+    it("renders an escaped table with header cells in column order", () => {
+      const html = service.formatHtml([
+        { id: "1", name: "<b>Ada</b> & co", tags: ["a", "b"] },
+        { id: "2", name: 'O"Brien' },
+      ]);
+
+      expect(html).toMatch(/^<!DOCTYPE html>/);
+      expect(html).toContain("<thead><tr><th>id</th><th>name</th><th>tags</th></tr></thead>");
+      expect(html).toContain(
+        "<tr><td>1</td><td>&lt;b&gt;Ada&lt;/b&gt; &amp; co</td><td>[&quot;a&quot;,&quot;b&quot;]</td></tr>",
+      );
+      expect(html).toContain("<tr><td>2</td><td>O&quot;Brien</td><td></td></tr>");
+      expect(html).toContain('<p class="count">2 records</p>');
+      expect(consoleSpy).not.toHaveBeenCalled();
+    });
+
+    it("renders an empty-state message when there are no records", () => {
+      const html = service.formatHtml([]);
+
+      expect(html).toContain('<p class="empty">No records found.</p>');
+      expect(html).not.toContain("<table>");
+    });
+  });
 });
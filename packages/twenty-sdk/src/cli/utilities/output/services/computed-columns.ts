@@ -0,0 +1,34 @@
+import { renderRecordTemplate } from "./record-template";
+
+export interface ComputedColumn {
+  name: string;
+  template: string;
+}
+
+// Appends one template-evaluated key per computed column to each record, in
+// the order given. Non-object entries pass through unchanged.
+export function appendComputedColumns(data: unknown, columns: readonly ComputedColumn[]): unknown {
+  if (columns.length === 0) {
+    return data;
+  }
+
+  if (Array.isArray(data)) {
+    return data.map((record) => appendToRecord(record, columns));
+  }
+
+  return appendToRecord(data, columns);
+}
+
+function appendToRecord(record: unknown, columns: readonly ComputedColumn[]): unknown {
+  if (typeof record !== "object" || record === null || Array.isArray(record)) {
+    return record;
+  }
+
+  const result: Record<string, unknown> = { ...(record as Record<string, unknown>) };
+  for (const column of columns) {
+    delete result[column.name];
+    result[column.name] = renderRecordTemplate(column.template, record);
+  }
+
+  return result;
+}
@@ -0,0 +1,143 @@
+import { CsvWriteOptions, unparseCsv } from "./csv-writer";
+
+export type CsvArrayMode = "json" | "join" | "index";
+
+export const CSV_ARRAY_MODES: readonly CsvArrayMode[] = ["json", "join", "index"];
+
+export interface CsvFlattenOptions {
+  // Maximum nesting levels expanded into dotted columns; unset expands everything.
+  depth?: number;
+  arrays?: CsvArrayMode;
+}
+
+const ARRAY_JOIN_SEPARATOR = ";";
+
+export function flattenCsvRecord(
+  record: Record<string, unknown>,
+  options: CsvFlattenOptions = {},
+): Record<string, unknown> {
+  const result: Record<string, unknown> = {};
+  const depth = options.depth ?? Number.POSITIVE_INFINITY;
+
+  for (const [key, value] of Object.entries(record)) {
+    flattenInto(result, key, value, depth, options.arrays ?? "json");
+  }
+
+  return result;
+}
+
+export function collectCsvColumns(rows: Record<string, unknown>[]): string[] {
+  const columns = new Set<string>();
+  for (const row of rows) {
+    for (const key of Object.keys(row)) {
+      columns.add(key);
+    }
+  }
+
+  return [...columns];
+}
+
+// The first column in rows missing from a CSV header that was fixed to
+// known, and so would be dropped: any new column, or with --fields only one
+// that belongs to a listed field.
+export function findUnknownCsvColumn(
+  rows: Record<string, unknown>[],
+  known: readonly string[],
+  fields?: readonly string[],
+): string | undefined {
+  const header = new Set(known);
+  return collectCsvColumns(rows).find(
+    (column) =>
+      !header.has(column) &&
+      (!fields || fields.some((field) => column === field || column.startsWith(`${field}.`))),
+  );
+}
+
+// Orders flattened columns by --fields: each field's own column, or its
+// dotted columns (name.firstName, ...) in first-seen order, in flag order.
+export function orderCsvColumns(columns: string[], fields: readonly string[]): string[] {
+  return fields.flatMap((field) => {
+    const matches = columns.filter(
+      (column) => column === field || column.startsWith(`${field}.`),
+    );
+    return matches.length > 0 ? matches : [field];
+  });
+}
+
+export function unparseFlattenedCsv(
+  records: unknown[],
+  options: CsvFlattenOptions = {},
+  writeOptions: CsvWriteOptions = {},
+): string {
+  const rows = records.map((record) =>
+    flattenCsvRecord(isRecord(record) ? record : { value: record }, options),
+  );
+  const collected = collectCsvColumns(rows);
+  const fields = writeOptions.columns
+    ? orderCsvColumns(collected, writeOptions.columns)
+    : collected;
+
+  return unparseCsv(
+    { fields, data: rows.map((row) => fields.map((field) => row[field] ?? "")) },
+    writeOptions,
+  );
+}
+
+function flattenInto(
+  target: Record<string, unknown>,
+  path: string,
+  value: unknown,
+  remainingDepth: number,
+  arrays: CsvArrayMode,
+): void {
+  if (value === null || value === undefined) {
+    target[path] = "";
+    return;
+  }
+
+  if (Array.isArray(value)) {
+    if (arrays === "join") {
+      target[path] = value.map(formatScalar).join(ARRAY_JOIN_SEPARATOR);
+      return;
+    }
+    if (arrays === "index" && remainingDepth > 0) {
+      value.forEach((item, index) => {
+        flattenInto(target, `${path}.${index}`, item, remainingDepth - 1, arrays);
+      });
+      return;
+    }
+
+    target[path] = JSON.stringify(value);
+    return;
+  }
+
+  if (isRecord(value)) {
+    const entries = Object.entries(value);
+    if (remainingDepth <= 0 || entries.length === 0) {
+      target[path] = JSON.stringify(value);
+      return;
+    }
+
+    for (const [key, nested] of entries) {
+      flattenInto(target, `${path}.${key}`, nested, remainingDepth - 1, arrays);
+    }
+    return;
+  }
+
+  target[path] = value;
+}
+
+function formatScalar(value: unknown): string {
+  if (value === null || value === undefined) {
+    return "";
+  }
+  if (typeof value === "object") {
+    return JSON.stringify(value);
+  }
+
+  return String(value);
+}
+
+function isRecord(value: unknown): value is Record<string, unknown> {
+  return typeof value === "object" && value !== null && !Array.isArray(value);
+}
@@ -0,0 +1,108 @@
+import Papa from "papaparse";
+
+export interface CsvWriteOptions {
+  // Quote every field, header included; by default only fields that need it.
+  quoteAll?: boolean;
+  // Set to false to write data rows only, e.g. when appending to a file.
+  header?: boolean;
+  // Exact column order for record arrays (from --fields); records missing a
+  // column get an empty cell and unlisted keys are dropped.
+  columns?: readonly string[];
+  // Prepend a UTF-8 byte-order mark so Excel detects the encoding. Only
+  // applies when the header is written, i.e. at the start of a file.
+  bom?: boolean;
+  // --float-precision/--plain-numbers; numbers are written as-is without.
+  numbers?: CsvNumberFormat;
+}
+
+export interface CsvNumberFormat {
+  // Digits after the point for non-integer numbers; integers are unchanged.
+  floatPrecision?: number;
+  // Never use scientific notation, e.g. 1e-7 is written as 0.0000001.
+  plain?: boolean;
+}
+
+export const UTF8_BOM = "\uFEFF";
+
+// Papa.unparse's row separator, for writers that join rows themselves.
+export const CSV_NEWLINE = "\r\n";
+
+export type CsvInput = unknown[] | { fields: string[]; data: unknown[][] };
+
+export function unparseCsv(input: CsvInput, options: CsvWriteOptions = {}): string {
+  if (options.columns && Array.isArray(input)) {
+    const fields = [...options.columns];
+    input = {
+      fields,
+      data: input.map((record) =>
+        fields.map((field) => (isRecord(record) ? (record[field] ?? "") : "")),
+      ),
+    };
+  }
+  if (options.numbers) {
+    input = formatNumberCells(input, options.numbers);
+  }
+  const config = {
+    ...(options.quoteAll ? { quotes: true } : {}),
+    ...(options.header === false ? { header: false } : {}),
+  };
+  const csv = Papa.unparse(input as any, Object.keys(config).length > 0 ? config : undefined);
+  return options.bom && options.header !== false ? UTF8_BOM + csv : csv;
+}
+
+export function formatCsvNumber(value: number, format: CsvNumberFormat): string | number {
+  if (!Number.isFinite(value)) {
+    return value;
+  }
+  if (format.floatPrecision !== undefined && !Number.isInteger(value)) {
+    return value.toFixed(format.floatPrecision);
+  }
+  return format.plain ? toPlainDecimal(value) : value;
+}
+
+// Same cell encoding as CSV output: nested values as JSON, nulls as empty.
+// For writers that build { fields, data } rows themselves.
+export function csvCell(value: unknown): unknown {
+  if (value === null || value === undefined) {
+    return "";
+  }
+  return typeof value === "object" ? JSON.stringify(value) : value;
+}
+
+function formatNumberCells(input: CsvInput, format: CsvNumberFormat): CsvInput {
+  const cell = (value: unknown) =>
+    typeof value === "number" ? formatCsvNumber(value, format) : value;
+  if (!Array.isArray(input)) {
+    return { fields: input.fields, data: input.data.map((row) => row.map(cell)) };
+  }
+  return input.map((record) =>
+    isRecord(record)
+      ? Object.fromEntries(Object.entries(record).map(([key, value]) => [key, cell(value)]))
+      : Array.isArray(record)
+        ? record.map(cell)
+        : record,
+  );
+}
+
+// Expands String()'s exponent form (1.5e-7, 1e+21) into plain digits.
+function toPlainDecimal(value: number): string {
+  const text = String(value);
+  const match = /^(-?)(\d)(?:\.(\d+))?e([+-]\d+)$/.exec(text);
+  if (!match) {
+    return text;
+  }
+  const [, sign, lead, fraction = "", exponent] = match;
+  const digits = lead + fraction;
+  const point = 1 + Number(exponent);
+  if (point <= 0) {
+    return `${sign}0.${"0".repeat(-point)}${digits}`;
+  }
+  if (point >= digits.length) {
+    return `${sign}${digits}${"0".repeat(point - digits.length)}`;
+  }
+  return `${sign}${digits.slice(0, point)}.${digits.slice(point)}`;
+}
+
+function isRecord(value: unknown): value is Record<string, unknown> {
+  return typeof value === "object" && value !== null && !Array.isArray(value);
+}
@@ -0,0 +1,85 @@
+const MICROS_PER_UNIT = 1_000_000;
+const MICROS_DIGITS = 6;
+const DEFAULT_FRACTION_DIGITS = 2;
+
+// major: "1250.00 USD"; minor: integer minor units ("125000 USD");
+// micros: the stored amountMicros unchanged ("1250000000 USD").
+export type CurrencyFormat = "major" | "minor" | "micros";
+
+export const CURRENCY_FORMATS: readonly CurrencyFormat[] = ["major", "minor", "micros"];
+
+export interface CurrencyValue {
+  amountMicros: number | string | null;
+  currencyCode: string | null;
+}
+
+export function isCurrencyValue(value: unknown): value is CurrencyValue {
+  return (
+    typeof value === "object" &&
+    value !== null &&
+    !Array.isArray(value) &&
+    "amountMicros" in value &&
+    "currencyCode" in value
+  );
+}
+
+// Renders Twenty's {amountMicros, currencyCode} composite using the
+// currency's own minor-unit digits (JPY has none, KWD has three).
+export function formatCurrency(value: CurrencyValue, format: CurrencyFormat = "major"): string {
+  if (value.amountMicros === null || value.amountMicros === "") {
+    return "";
+  }
+
+  const code = value.currencyCode ?? "";
+  const digits = currencyFractionDigits(code);
+  let formatted: string;
+  switch (format) {
+    case "micros":
+      formatted = String(value.amountMicros);
+      break;
+    case "minor":
+      formatted = toMinorUnits(value.amountMicros, digits);
+      break;
+    default:
+      formatted = (Number(value.amountMicros) / MICROS_PER_UNIT).toFixed(digits);
+  }
+
+  return code ? `${formatted} ${code}` : formatted;
+}
+
+export function formatCurrencyFields(
+  record: Record<string, unknown>,
+  format: CurrencyFormat = "major",
+): Record<string, unknown> {
+  const result: Record<string, unknown> = {};
+  for (const [key, value] of Object.entries(record)) {
+    result[key] = isCurrencyValue(value) ? formatCurrency(value, format) : value;
+  }
+
+  return result;
+}
+
+// BigInt keeps large integer amounts exact; sub-minor remainders truncate.
+function toMinorUnits(amountMicros: number | string, digits: number): string {
+  const divisor = 10 ** (MICROS_DIGITS - digits);
+  try {
+    return (BigInt(amountMicros) / BigInt(divisor)).toString();
+  } catch {
+    return String(Math.round(Number(amountMicros) / divisor));
+  }
+}
+
+function currencyFractionDigits(code: string): number {
+  if (!code) {
+    return DEFAULT_FRACTION_DIGITS;
+  }
+
+  try {
+    return (
+      new Intl.NumberFormat("en", { style: "currency", currency: code }).resolvedOptions()
+        .maximumFractionDigits ?? DEFAULT_FRACTION_DIGITS
+    );
+  } catch {
+    return DEFAULT_FRACTION_DIGITS;
+  }
+}
@@ -0,0 +1,57 @@
+import { CliError } from "../../errors/cli-error";
+
+// Resolves an RFC 6901 JSON Pointer such as "/data/person/id". The empty
+// pointer selects the whole document; a missing member or index is an error
+// rather than null so scripts notice a wrong path.
+export function resolveJsonPointer(data: unknown, pointer: string): unknown {
+  if (pointer === "") {
+    return data;
+  }
+  if (!pointer.startsWith("/")) {
+    throw new CliError(
+      `Invalid JSON pointer ${JSON.stringify(pointer)}; it must be empty or start with "/".`,
+      "INVALID_ARGUMENTS",
+    );
+  }
+
+  let current = data;
+  let resolved = "";
+  for (const token of pointer.slice(1).split("/").map(unescapeToken)) {
+    if (Array.isArray(current) && /^(0|[1-9][0-9]*)$/.test(token)) {
+      const index = Number(token);
+      if (index >= current.length) {
+        throw missingPointer(pointer, resolved, token);
+      }
+      current = current[index];
+    } else if (
+      current !== null &&
+      typeof current === "object" &&
+      !Array.isArray(current) &&
+      Object.prototype.hasOwnProperty.call(current, token)
+    ) {
+      current = (current as Record<string, unknown>)[token];
+    } else {
+      throw missingPointer(pointer, resolved, token);
+    }
+    resolved += `/${escapeToken(token)}`;
+  }
+
+  return current;
+}
+
+function unescapeToken(token: string): string {
+  return token.replace(/~1/g, "/").replace(/~0/g, "~");
+}
+
+function escapeToken(token: string): string {
+  return token.replace(/~/g, "~0").replace(/\//g, "~1");
+}
+
+function missingPointer(pointer: string, resolved: string, token: string): CliError {
+  const parent = resolved || "the root";
+  return new CliError(
+    `JSON pointer ${pointer} did not match: no ${JSON.stringify(token)} under ${parent}.`,
+    "INVALID_ARGUMENTS",
+    "Check the path against the full output, e.g. --pointer /data/0/id.",
+  );
+}
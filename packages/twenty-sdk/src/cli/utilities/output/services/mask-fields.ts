@@ -0,0 +1,82 @@
+// --mask-fields: replaces the values of named top-level keys with a masked
+// form while keeping the key, so the record shape (and every CSV column)
+// survives. Composite fields are masked leaf by leaf. "email" and "phone"
+// also name Twenty's emails and phones fields.
+const FIELD_ALIASES: Record<string, string[]> = {
+  email: ["emails"],
+  phone: ["phones"],
+};
+
+const MASK = "***";
+
+export function maskRecordFields(data: unknown, fields: readonly string[] | undefined): unknown {
+  if (!fields || fields.length === 0) {
+    return data;
+  }
+
+  const keys = new Set(fields.flatMap((field) => [field, ...(FIELD_ALIASES[field] ?? [])]));
+  if (Array.isArray(data)) {
+    return data.map((record) => maskRecord(record, keys));
+  }
+
+  return maskRecord(data, keys);
+}
+
+// j***@example.com for emails, +1 ***-***-**67 for phone numbers (digits
+// hidden except the last two, punctuation kept), and the first character
+// followed by *** for anything else.
+export function maskValue(value: string): string {
+  if (value === "") {
+    return value;
+  }
+  const at = value.lastIndexOf("@");
+  if (at > 0) {
+    return `${value[0]}${MASK}${value.slice(at)}`;
+  }
+  if (/^\+?[\d\s().-]+$/.test(value)) {
+    const digits = value.replace(/\D/g, "").length;
+    let seen = 0;
+    return value.replace(/\d/g, (digit) => {
+      seen += 1;
+      return seen > digits - 2 ? digit : "*";
+    });
+  }
+
+  return `${value[0]}${MASK}`;
+}
+
+function maskRecord(record: unknown, keys: ReadonlySet<string>): unknown {
+  if (typeof record !== "object" || record === null || Array.isArray(record)) {
+    return record;
+  }
+
+  const result: Record<string, unknown> = {};
+  for (const [key, value] of Object.entries(record as Record<string, unknown>)) {
+    result[key] = keys.has(key) ? maskLeaves(value) : value;
+  }
+
+  return result;
+}
+
+// Nulls stay null so "no value" is still distinguishable from a masked one.
+function maskLeaves(value: unknown): unknown {
+  if (value === null || value === undefined) {
+    return value;
+  }
+  if (typeof value === "string") {
+    return maskValue(value);
+  }
+  if (Array.isArray(value)) {
+    return value.map(maskLeaves);
+  }
+  if (typeof value === "object") {
+    return Object.fromEntries(
+      Object.entries(value as Record<string, unknown>).map(([key, nested]) => [
+        key,
+        maskLeaves(nested),
+      ]),
+    );
+  }
+
+  return MASK;
+}
@@ -0,0 +1,35 @@
+// Where rendered output goes. The CLI writes to stdout; programs embedding
+// the services pass their own writer to capture list, get, and export output.
+export interface OutputWriter {
+  // One line of output; the writer adds the line terminator.
+  line(text: string): void;
+  // Text written exactly as given (NUL-terminated --print0 output).
+  write(text: string): void;
+}
+
+export const stdoutWriter: OutputWriter = {
+  line(text) {
+    // eslint-disable-next-line no-console
+    console.log(text);
+  },
+  write(text) {
+    process.stdout.write(text);
+  },
+};
+
+// Collects output in memory instead of printing it.
+export class BufferedOutputWriter implements OutputWriter {
+  private chunks: string[] = [];
+
+  line(text: string): void {
+    this.chunks.push(`${text}\n`);
+  }
+
+  write(text: string): void {
+    this.chunks.push(text);
+  }
+
+  toString(): string {
+    return this.chunks.join("");
+  }
+}
@@ -1,17 +1,69 @@
-import Papa from "papaparse";
+import fs from "fs-extra";
+import { findRecordArray, unwrapRestEnvelope } from "../../api/rest-response";
 import type { OutputFormat } from "../../shared/global-options";
 import { toLightPayload } from "./compact-aliases";
+import { appendComputedColumns, ComputedColumn } from "./computed-columns";
+import { CsvFlattenOptions, unparseFlattenedCsv } from "./csv-flatten";
+import { CsvNumberFormat, CsvWriteOptions, unparseCsv } from "./csv-writer";
+import { resolveJsonPointer } from "./json-pointer";
+import { maskRecordFields } from "./mask-fields";
+import { pruneRecordFields } from "./prune-fields";
+import { renderRecordTemplate } from "./record-template";
+import { sortJsonKeys } from "./sort-keys";
 import { QueryService } from "./query.service";
 import { TableService } from "./table.service";
+import { OutputWriter, stdoutWriter } from "./output-writer";
+import { log } from "../../shared/logger";
+import { CliError } from "../../errors/cli-error";
 
 export interface OutputOptions {
   format?: OutputFormat;
   query?: string;
+  // RFC 6901 pointer applied where --query would be; a lighter alternative.
+  pointer?: string;
+  // Print string results (or jsonl string lines) without JSON quotes.
+  rawOutput?: boolean;
+  // --print0: end each json/jsonl value or --text-template line with NUL
+  // instead of a newline, for xargs -0.
+  print0?: boolean;
+  pruneFields?: string[];
+  // --mask-fields: keys whose values are masked, applied with pruning.
+  maskFields?: string[];
+  unwrap?: boolean;
   light?: boolean;
   full?: boolean;
   agentMode?: boolean;
+  csvFlatten?: CsvFlattenOptions;
+  csvQuoteAll?: boolean;
+  // --bom: prepend a UTF-8 byte-order mark to csv output for Excel.
+  csvBom?: boolean;
+  // --float-precision/--plain-numbers for csv output.
+  csvNumbers?: CsvNumberFormat;
+  // --totals: table only; a footer row summing numeric columns.
+  totals?: boolean;
+  // --key-column: text, table, and html show this column first.
+  keyColumn?: string;
+  // Templated columns appended to csv, text, and table output.
+  computed?: ComputedColumn[];
+  // Exact csv/text/table column order (from --fields). Ignored when --query
+  // or --pointer reshape the result.
+  columns?: string[];
+  // html only: write the page to this file instead of stdout.
+  outputFile?: string;
+  // --envelope: wrap json output as {"apiVersion","kind","items"}. Only
+  // renders that name a kind (list commands) are wrapped.
+  envelope?: boolean;
+  kind?: string;
+  // --sort-keys: json and jsonl objects list their keys alphabetically.
+  sortKeys?: boolean;
+  // text only: one rendered line per record instead of the key/value view,
+  // using the record-template syntax, e.g. "{{.name}} ({{.stage}})".
+  textTemplate?: string;
 }
 
+// Bumped only when the shape of enveloped output changes incompatibly.
+export const OUTPUT_API_VERSION = "twenty-cli/v1";
+
 interface OutputServiceDefaults extends OutputOptions {}
 
 export class OutputService {
@@ -19,42 +71,123 @@ export class OutputService {
     private table: TableService,
     private queryService: QueryService,
     private defaults: OutputServiceDefaults = {},
+    readonly writer: OutputWriter = stdoutWriter,
   ) {}
 
   async render(data: unknown, options: OutputOptions = {}): Promise<void> {
     const query = options.query ?? this.defaults.query;
+    const pointer = options.pointer ?? this.defaults.pointer;
+    const rawOutput = options.rawOutput ?? this.defaults.rawOutput ?? false;
     const full = options.full ?? this.defaults.full ?? false;
     const light = !full && (options.light ?? this.defaults.light ?? false);
-    let result: unknown = data;
+    const unwrap = options.unwrap ?? this.defaults.unwrap ?? false;
+    let result: unknown = maskRecordFields(
+      pruneRecordFields(
+        unwrap ? unwrapRestEnvelope(data) : data,
+        options.pruneFields ?? this.defaults.pruneFields,
+      ),
+      options.maskFields ?? this.defaults.maskFields,
+    );
     if (query) {
       result = this.queryService.apply(result, query);
     }
+    if (pointer !== undefined) {
+      result = resolveJsonPointer(result, pointer);
+    }
     if (light) {
       result = toLightPayload(result);
     }
 
     const format = options.format ?? this.defaults.format ?? "json";
+    const textTemplate = options.textTemplate ?? this.defaults.textTemplate;
+    const print0 = options.print0 ?? this.defaults.print0 ?? false;
+    const sortKeys = options.sortKeys ?? this.defaults.sortKeys ?? false;
+    const delimited =
+      format === "json" || format === "jsonl" || (format === "text" && textTemplate !== undefined);
+    if (print0 && !delimited) {
+      throw new CliError(
+        `--print0 does not apply to ${format} output.`,
+        "INVALID_ARGUMENTS",
+        "Use -o jsonl (with --raw-output for bare values) or -o text with --text-template.",
+      );
+    }
+    const computed = options.computed ?? [];
+    if (format === "csv" || format === "text" || format === "table" || format === "html") {
+      // Raw payloads (rest, graphql) tabulate their record list, not the envelope.
+      result = appendComputedColumns(findRecordArray(result) ?? result, computed);
+    }
+    const trailingColumns = computed.map((column) => column.name);
+    const keyColumn = options.keyColumn ?? this.defaults.keyColumn;
+    const columns =
+      options.columns && !query && pointer === undefined
+        ? options.columns.filter((column) => !trailingColumns.includes(column))
+        : undefined;
     switch (format) {
       case "json":
-        // eslint-disable-next-line no-console
-        console.log(JSON.stringify(result));
+        if (options.kind && (options.envelope ?? this.defaults.envelope)) {
+          result = toEnvelope(result, options.kind);
+        }
+        if (sortKeys) {
+          result = sortJsonKeys(result);
+        }
+        this.writeLines([formatJsonValue(result, rawOutput)], print0);
         break;
       case "jsonl":
-        // eslint-disable-next-line no-console
-        console.log(this.formatJsonLines(result));
+        if (sortKeys) {
+          result = sortJsonKeys(result);
+        }
+        if (print0) {
+          this.writeLines(this.jsonLines(result, rawOutput), print0);
+        } else {
+          this.writer.line(this.jsonLines(result, rawOutput).join("\n"));
+        }
         break;
       case "csv":
-        // eslint-disable-next-line no-console
-        console.log(this.formatCsv(result));
+        this.writer.line(
+          this.formatCsv(result, options.csvFlatten, {
+            quoteAll: options.csvQuoteAll ?? this.defaults.csvQuoteAll,
+            bom: options.csvBom ?? this.defaults.csvBom,
+            numbers: options.csvNumbers ?? this.defaults.csvNumbers,
+            ...(columns ? { columns: [...columns, ...trailingColumns] } : {}),
+          }),
+        );
         break;
       case "text":
+      case "table":
         {
           const { data: textData, cliMessage } = this.extractTextCliDiagnostic(result);
           if (cliMessage) {
-            // eslint-disable-next-line no-console
-            console.log(`Note: ${cliMessage}`);
+            this.writer.line(`Note: ${cliMessage}`);
+          }
+          // text shows a single record as key/value pairs; lists keep the
+          // table so scripts that used text for list output still work.
+          if (format === "text" && textTemplate !== undefined) {
+            this.renderTemplateLines(textData, textTemplate, print0);
+          } else if (format === "text" && isRecord(textData)) {
+            this.table.renderDetail(textData, trailingColumns, columns, { keyColumn });
+          } else {
+            const totals = format === "table" && (options.totals ?? this.defaults.totals);
+            this.table.render(textData, trailingColumns, columns, {
+              keyColumn,
+              ...(totals ? { totals } : {}),
+            });
+          }
+        }
+        break;
+      case "html":
+        {
+          const html = this.table.formatHtml(
+            unwrapRestEnvelope(result),
+            trailingColumns,
+            columns,
+            { keyColumn },
+          );
+          if (options.outputFile) {
+            await fs.writeFile(options.outputFile, `${html}\n`);
+            log("info", `Wrote ${options.outputFile}`, { path: options.outputFile });
+          } else {
+            this.writer.line(html);
           }
-          this.table.render(textData);
         }
         break;
       default:
@@ -62,6 +195,27 @@ export class OutputService {
     }
   }
 
+  // A shell argument cannot contain NUL, so unlike newlines it is a separator
+  // xargs -0 never confuses with data. Each entry is terminated, as with find -print0.
+  private writeLines(lines: string[], print0: boolean): void {
+    if (print0) {
+      this.writer.write(lines.map((line) => `${line}\0`).join(""));
+      return;
+    }
+    for (const line of lines) {
+      this.writer.line(line);
+    }
+  }
+
+  private renderTemplateLines(data: unknown, template: string, print0: boolean): void {
+    const unwrapped = unwrapRestEnvelope(data);
+    const records = Array.isArray(unwrapped) ? unwrapped : [unwrapped];
+    this.writeLines(
+      records.map((record) => renderRecordTemplate(template, record)),
+      print0,
+    );
+  }
+
   private extractTextCliDiagnostic(data: unknown): { data: unknown; cliMessage?: string } {
     if (!isRecord(data)) {
       return { data };
@@ -79,15 +233,22 @@ export class OutputService {
     };
   }
 
-  private formatCsv(data: unknown): string {
+  private formatCsv(
+    data: unknown,
+    flatten: CsvFlattenOptions | undefined,
+    writeOptions: CsvWriteOptions,
+  ): string {
     const records = Array.isArray(data) ? data : [data];
+    if (flatten) {
+      return unparseFlattenedCsv(records, flatten, writeOptions);
+    }
     const preprocessed = records.map((record) => this.preprocessForCsv(record));
-    return Papa.unparse(preprocessed as any[]);
+    return unparseCsv(preprocessed, writeOptions);
   }
 
-  private formatJsonLines(data: unknown): string {
+  private jsonLines(data: unknown, rawOutput: boolean): string[] {
     const records = Array.isArray(data) ? data : [data];
-    return records.map((record) => JSON.stringify(record)).join("\n");
+    return records.map((record) => formatJsonValue(record, rawOutput));
   }
 
   private preprocessForCsv(record: unknown): unknown {
@@ -117,3 +278,16 @@ export class OutputService {
 function isRecord(value: unknown): value is Record<string, unknown> {
   return typeof value === "object" && value !== null && !Array.isArray(value);
 }
+
+function toEnvelope(data: unknown, kind: string): Record<string, unknown> {
+  const items = unwrapRestEnvelope(data);
+  return {
+    apiVersion: OUTPUT_API_VERSION,
+    kind,
+    items: Array.isArray(items) ? items : [items],
+  };
+}
+
+function formatJsonValue(value: unknown, rawOutput: boolean): string {
+  return rawOutput && typeof value === "string" ? value : JSON.stringify(value);
+}
@@ -0,0 +1,28 @@
+// Drops the named top-level keys from each record. Arrays are pruned per
+// element; a single object is pruned directly; anything else passes through.
+export function pruneRecordFields(data: unknown, fields: readonly string[] | undefined): unknown {
+  if (!fields || fields.length === 0) {
+    return data;
+  }
+
+  if (Array.isArray(data)) {
+    return data.map((record) => pruneRecord(record, fields));
+  }
+
+  return pruneRecord(data, fields);
+}
+
+function pruneRecord(record: unknown, fields: readonly string[]): unknown {
+  if (typeof record !== "object" || record === null || Array.isArray(record)) {
+    return record;
+  }
+
+  const result: Record<string, unknown> = {};
+  for (const [key, value] of Object.entries(record as Record<string, unknown>)) {
+    if (!fields.includes(key)) {
+      result[key] = value;
+    }
+  }
+
+  return result;
+}
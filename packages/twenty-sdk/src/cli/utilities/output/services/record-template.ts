@@ -0,0 +1,103 @@
+// Minimal record templates: `{{.path.to.field}}` (the leading dot is optional)
+// is replaced with the value at that path. Missing values render as "" and
+// objects or arrays render as JSON. Values can be piped through functions,
+// e.g. `{{.tags | split ";"}}` or `{{.name | json}}`.
+const PLACEHOLDER = /\{\{\s*([^{}]*?)\s*\}\}/g;
+
+type TemplateFunction = (value: unknown) => unknown;
+
+const TEMPLATE_FUNCTIONS: Record<string, (arg?: string) => TemplateFunction> = {
+  // JSON-encodes the value so strings are quoted and escaped.
+  json: () => (value) => JSON.stringify(value ?? null),
+  // Splits a delimited cell into a trimmed array without blanks.
+  split: (separator) => {
+    if (separator === undefined) {
+      throw new Error('Template function "split" needs a separator, e.g. split ";".');
+    }
+    return (value) =>
+      value === null || value === undefined
+        ? []
+        : String(value)
+            .split(separator)
+            .map((item) => item.trim())
+            .filter(Boolean);
+  },
+};
+
+export function renderRecordTemplate(template: string, record: unknown): string {
+  return template.replace(PLACEHOLDER, (match, expression: string) => {
+    const pipeline = parsePipeline(expression);
+    if (!pipeline) {
+      return match;
+    }
+
+    const value = pipeline.path === "" ? record : getPathValue(record, pipeline.path);
+    return formatTemplateValue(pipeline.functions.reduce((current, fn) => fn(current), value));
+  });
+}
+
+function parsePipeline(
+  expression: string,
+): { path: string; functions: TemplateFunction[] } | undefined {
+  const [head, ...stages] = splitPipeline(expression);
+  const path = head!.trim();
+  if (/\s/.test(path)) {
+    return undefined;
+  }
+
+  return {
+    path: path.replace(/^\./, ""),
+    functions: stages.map((stage) => parseFunction(stage.trim())),
+  };
+}
+
+function parseFunction(stage: string): TemplateFunction {
+  const match = /^(\w+)(?:\s+("(?:[^"\\]|\\.)*"))?$/.exec(stage);
+  const factory = match ? TEMPLATE_FUNCTIONS[match[1]!] : undefined;
+  if (!match || !factory) {
+    throw new Error(`Unknown template function "${stage}".`);
+  }
+
+  return factory(match[2] === undefined ? undefined : (JSON.parse(match[2]) as string));
+}
+
+// Splits on "|" outside double-quoted arguments.
+function splitPipeline(expression: string): string[] {
+  const stages: string[] = [];
+  let current = "";
+  let quoted = false;
+  for (let i = 0; i < expression.length; i++) {
+    const char = expression[i]!;
+    if (char === "\\" && quoted) {
+      current += char + (expression[i + 1] ?? "");
+      i++;
+      continue;
+    }
+    if (char === '"') {
+      quoted = !quoted;
+    } else if (char === "|" && !quoted) {
+      stages.push(current);
+      current = "";
+      continue;
+    }
+    current += char;
+  }
+  stages.push(current);
+
+  return stages;
+}
+
+function getPathValue(record: unknown, path: string): unknown {
+  return path.split(".").reduce<unknown>((value, key) => {
+    if (typeof value === "object" && value !== null && !Array.isArray(value)) {
+      return (value as Record<string, unknown>)[key];
+    }
+    return undefined;
+  }, record);
+}
+
+function formatTemplateValue(value: unknown): string {
+  if (value === null || value === undefined) return "";
+  if (typeof value === "object") return JSON.stringify(value);
+  return String(value);
+}
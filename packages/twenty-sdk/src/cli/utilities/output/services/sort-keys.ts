@@ -0,0 +1,27 @@
+// --sort-keys: rebuilds objects with their keys in alphabetical order, at
+// every depth, so JSON output from two runs diffs line for line. Array order
+// is data and is kept.
+export function sortJsonKeys<T>(value: T): T {
+  if (Array.isArray(value)) {
+    return value.map((item) => sortJsonKeys(item)) as T;
+  }
+  if (typeof value !== "object" || value === null || !isPlainObject(value)) {
+    return value;
+  }
+
+  const sorted: Record<string, unknown> = {};
+  for (const key of Object.keys(value).sort(compareKeys)) {
+    sorted[key] = sortJsonKeys((value as Record<string, unknown>)[key]);
+  }
+  return sorted as T;
+}
+
+// Code-unit order, as jq -S uses; localeCompare would vary by locale.
+function compareKeys(a: string, b: string): number {
+  return a < b ? -1 : a > b ? 1 : 0;
+}
+
+function isPlainObject(value: object): boolean {
+  const prototype = Object.getPrototypeOf(value);
+  return prototype === Object.prototype || prototype === null;
+}
@@ -0,0 +1,34 @@
+import type { GlobalOptions } from "../../shared/global-options";
+import { OutputWriter, stdoutWriter } from "./output-writer";
+import { sortJsonKeys } from "./sort-keys";
+
+export interface CommandStatus {
+  // Past-tense verb such as "deleted", "revoked", or "configured".
+  action: string;
+  // Human-readable line(s) printed when JSON output was not requested.
+  message: string | string[];
+  [field: string]: unknown;
+}
+
+// Commands that return no record print a confirmation line. When JSON output
+// is requested explicitly (--output, TWENTY_OUTPUT, or agent mode) the same
+// event is emitted as {"status":"ok","action":...} instead, so scripts can
+// parse every command. The implicit json default keeps the human line.
+export function printStatus(
+  globalOptions: Pick<GlobalOptions, "output" | "outputExplicit" | "sortKeys">,
+  status: CommandStatus,
+  writer: OutputWriter = stdoutWriter,
+): void {
+  const { message, ...fields } = status;
+  const json =
+    globalOptions.outputExplicit === true &&
+    (globalOptions.output === "json" || globalOptions.output === "jsonl");
+
+  const event = { status: "ok", ...fields };
+  const lines = json
+    ? [JSON.stringify(globalOptions.sortKeys ? sortJsonKeys(event) : event)]
+    : [message].flat();
+  for (const line of lines) {
+    writer.line(line);
+  }
+}
@@ -1,37 +1,154 @@
+import { OutputWriter, stdoutWriter } from "./output-writer";
+
+export interface TableRenderOptions {
+  // --totals: append a footer that sums the numeric columns.
+  totals?: boolean;
+  // --key-column: move this column to the front; the rest keep their order.
+  keyColumn?: string;
+}
+
+const TOTAL_LABEL = "TOTAL";
+
 export class TableService {
-  render(data: unknown): void {
+  constructor(private writer: OutputWriter = stdoutWriter) {}
+
+  // trailingColumns are moved to the end in the given order instead of being
+  // sorted with the other columns. columns (from --fields) replaces the
+  // default ordering with exactly the listed columns.
+  render(
+    data: unknown,
+    trailingColumns: readonly string[] = [],
+    columns?: readonly string[],
+    options: TableRenderOptions = {},
+  ): void {
     const records = normalizeRecords(data);
     if (records.length === 0) {
-      // eslint-disable-next-line no-console
-      console.log("No records found.");
+      this.writer.line("No records found.");
       return;
     }
 
     if (records.length === 1 && !isRecord(records[0])) {
-      // eslint-disable-next-line no-console
-      console.log(String(records[0]));
+      this.writer.line(String(records[0]));
       return;
     }
 
     const rows = records.map((record) => (isRecord(record) ? record : { value: record }));
-    const columns = extractColumns(rows[0]);
-    const widths = calculateWidths(columns, rows);
+    const headers = orderColumns(rows[0], trailingColumns, columns, options.keyColumn);
+    const footer = options.totals ? totalsRow(headers, rows) : undefined;
+    const widths = calculateWidths(headers, rows).map((width, i) =>
+      footer ? Math.min(Math.max(width, footer[i].length), 60) : width,
+    );
 
-    // eslint-disable-next-line no-console
-    console.log(columns.map((col, i) => col.toUpperCase().padEnd(widths[i])).join("  "));
+    this.writer.line(headers.map((col, i) => col.toUpperCase().padEnd(widths[i])).join("  "));
 
     for (const record of rows) {
-      const row = columns.map((col, i) => {
+      const row = headers.map((col, i) => {
         const value = getValue(record, col);
         const cell = formatValue(value).slice(0, widths[i]);
         return cell.padEnd(widths[i]);
       });
-      // eslint-disable-next-line no-console
-      console.log(row.join("  "));
+      this.writer.line(row.join("  "));
+    }
+
+    if (footer) {
+      this.writer.line(widths.map((width) => "-".repeat(width)).join("  "));
+      this.writer.line(
+        footer.map((cell, i) => cell.slice(0, widths[i]).padEnd(widths[i])).join("  "),
+      );
+    }
+  }
+
+  // A standalone HTML page with one styled table, for opening in a browser.
+  // Cells are escaped; nested values are shown as JSON like the text table.
+  formatHtml(
+    data: unknown,
+    trailingColumns: readonly string[] = [],
+    columns?: readonly string[],
+    options: TableRenderOptions = {},
+  ): string {
+    const records = normalizeRecords(data);
+    if (records.length === 0) {
+      return htmlPage('<p class="empty">No records found.</p>');
+    }
+
+    const rows = records.map((record) => (isRecord(record) ? record : { value: record }));
+    const headers = orderColumns(rows[0], trailingColumns, columns, options.keyColumn);
+    const head = headers.map((column) => `<th>${escapeHtml(column)}</th>`).join("");
+    const body = rows.map(
+      (record) =>
+        `<tr>${headers
+          .map((column) => `<td>${escapeHtml(formatValue(getValue(record, column)))}</td>`)
+          .join("")}</tr>`,
+    );
+    return htmlPage(
+      [
+        "<table>",
+        `<thead><tr>${head}</tr></thead>`,
+        "<tbody>",
+        ...body,
+        "</tbody>",
+        "</table>",
+        `<p class="count">${rows.length} ${rows.length === 1 ? "record" : "records"}</p>`,
+      ].join("\n"),
+    );
+  }
+
+  renderDetail(
+    record: Record<string, unknown>,
+    trailingColumns: readonly string[] = [],
+    columns?: readonly string[],
+    options: TableRenderOptions = {},
+  ): void {
+    const keys = orderColumns(record, trailingColumns, columns, options.keyColumn);
+    if (keys.length === 0) {
+      this.writer.line("No fields.");
+      return;
+    }
+
+    const width = Math.max(...keys.map((key) => key.length));
+    for (const key of keys) {
+      this.writer.line(`${key.padEnd(width)}  ${formatValue(record[key])}`);
     }
   }
 }
 
+const HTML_STYLE = [
+  "body { font-family: system-ui, sans-serif; margin: 1.5rem; color: #222; }",
+  "table { border-collapse: collapse; font-size: 14px; }",
+  "th, td { border: 1px solid #ddd; padding: 4px 8px; text-align: left; vertical-align: top; }",
+  "th { background: #f4f4f5; position: sticky; top: 0; }",
+  "tbody tr:nth-child(even) { background: #fafafa; }",
+  ".empty, .count { color: #666; }",
+].join("\n");
+
+function htmlPage(content: string): string {
+  return [
+    "<!DOCTYPE html>",
+    '<html lang="en">',
+    "<head>",
+    '<meta charset="utf-8">',
+    "<title>Twenty records</title>",
+    `<style>\n${HTML_STYLE}\n</style>`,
+    "</head>",
+    "<body>",
+    content,
+    "</body>",
+    "</html>",
+  ].join("\n");
+}
+
+const HTML_ESCAPES: Record<string, string> = {
+  "&": "&amp;",
+  "<": "&lt;",
+  ">": "&gt;",
+  '"': "&quot;",
+  "'": "&#39;",
+};
+
+function escapeHtml(value: string): string {
+  return value.replace(/[&<>"']/g, (char) => HTML_ESCAPES[char]!);
+}
+
 function normalizeRecords(data: unknown): unknown[] {
   if (Array.isArray(data)) return data;
   if (data == null) return [];
@@ -51,6 +168,74 @@ function extractColumns(record: Record<string, unknown>): string[] {
   ];
 }
 
+function orderColumns(
+  record: Record<string, unknown>,
+  trailingColumns: readonly string[],
+  columns?: readonly string[],
+  keyColumn?: string,
+): string[] {
+  const ordered = [
+    ...(columns ?? extractColumns(record)).filter((column) => !trailingColumns.includes(column)),
+    ...trailingColumns.filter((column) => column in record),
+  ];
+  // A key column that is not shown (absent, or left out of --fields) is ignored.
+  return keyColumn && ordered.includes(keyColumn)
+    ? [keyColumn, ...ordered.filter((column) => column !== keyColumn)]
+    : ordered;
+}
+
+// Sums every column whose values are all numbers (or all currency amounts in
+// one currency), ignoring empty cells. The first non-numeric column is
+// labeled TOTAL; the rest stay blank. Returns the formatted footer cells.
+function totalsRow(headers: readonly string[], rows: Record<string, unknown>[]): string[] {
+  const totals = headers.map((column) =>
+    sumColumn(
+      rows
+        .map((row) => getValue(row, column))
+        .filter((value) => value !== null && value !== undefined && value !== ""),
+    ),
+  );
+  const label = totals.findIndex((total) => total === undefined);
+  return totals.map((total, i) =>
+    i === label && totals.some((other) => other !== undefined) ? TOTAL_LABEL : formatValue(total),
+  );
+}
+
+function sumColumn(values: unknown[]): unknown {
+  if (values.length === 0) {
+    return undefined;
+  }
+  if (values.every((value): value is number => typeof value === "number")) {
+    return addNumbers(values);
+  }
+  if (values.every(isCurrencyAmount)) {
+    const currencies = new Set(values.map((value) => value.currencyCode));
+    if (currencies.size === 1) {
+      return {
+        amountMicros: addNumbers(values.map((value) => value.amountMicros)),
+        currencyCode: values[0].currencyCode,
+      };
+    }
+  }
+  return undefined;
+}
+
+// Rounds to the most decimals among the inputs so 0.1 + 0.2 shows as 0.3.
+function addNumbers(values: number[]): number {
+  const decimals = Math.max(
+    0,
+    ...values.map((value) => (String(value).split(".")[1] ?? "").length),
+  );
+  const sum = values.reduce((total, value) => total + value, 0);
+  return Number(sum.toFixed(Math.min(decimals, 20)));
+}
+
+function isCurrencyAmount(
+  value: unknown,
+): value is { amountMicros: number; currencyCode: unknown } {
+  return isRecord(value) && typeof value.amountMicros === "number" && "currencyCode" in value;
+}
+
 function calculateWidths(columns: string[], records: Record<string, unknown>[]): number[] {
   return columns.map((column) => {
     const maxCell = records.reduce((max, record) => {
@@ -1,5 +1,6 @@
 import { describe, it, expect, vi } from "vitest";
 import { RecordsService } from "../records.service";
+import { configureLogger } from "../../../shared/logger";
 
 describe("RecordsService", () => {
   describe("read backend delegation", () => {
@@ -58,7 +59,9 @@ describe("RecordsService", () => {
       const service = new RecordsService(mockApi as any, { readBackend: mockReadBackend as any });
       const result = await service.create("people", { name: "Alice" });
 
-      expect(mockApi.post).toHaveBeenCalledWith("/rest/people", { name: "Alice" });
+      expect(mockApi.post).toHaveBeenCalledWith("/rest/people", { name: "Alice" }, {
+        headers: { "Idempotency-Key": expect.any(String) },
+      });
       expect(result).toEqual({ id: "1", name: "Alice" });
     });
   });
@@ -89,6 +92,25 @@ describe("RecordsService", () => {
       expect(result.data).toHaveLength(1);
     });
 
+    it("sends --param values as-is but rejects one that repeats a structured option", async () => {
+      const mockApi = {
+        get: vi.fn().mockResolvedValue({ data: { data: { people: [] } } }),
+      };
+      const service = new RecordsService(mockApi as any);
+
+      await service.list("people", { limit: 5, params: { soft_delete: ["true"] } });
+      expect(mockApi.get).toHaveBeenCalledWith("/rest/people", {
+        params: { limit: "5", soft_delete: "true" },
+      });
+
+      await expect(
+        service.list("people", {
+          filter: "city[eq]:Paris",
+          params: { filter: ["city[eq]:Oslo"] },
+        }),
+      ).rejects.toThrow("--param filter conflicts with a value already set by another option.");
+    });
+
     it("returns pageInfo when available", async () => {
       const mockApi = {
         get: vi.fn().mockResolvedValue({
@@ -107,6 +129,89 @@ describe("RecordsService", () => {
     });
   });
 
+  describe("list field selection", () => {
+    it("requests fields server-side and keeps records the server already narrowed", async () => {
+      const consoleErrorSpy = vi.spyOn(console, "error").mockImplementation(() => {});
+      configureLogger({ verbose: true });
+      const mockApi = {
+        get: vi.fn().mockResolvedValue({
+          data: { data: { people: [{ id: "1", city: "Paris" }] } },
+        }),
+      };
+
+      try {
+        const result = await new RecordsService(mockApi as any).list("people", {
+          fields: ["id", "city"],
+        });
+
+        expect(mockApi.get).toHaveBeenCalledWith("/rest/people", {
+          params: { fields: "id,city" },
+        });
+        expect(result.data).toEqual([{ id: "1", city: "Paris" }]);
+        expect(consoleErrorSpy).toHaveBeenCalledWith(
+          "Server-side field selection applied for people",
+        );
+      } finally {
+        configureLogger({});
+        consoleErrorSpy.mockRestore();
+      }
+    });
+
+    it("prunes client-side when the server ignores the fields param", async () => {
+      const consoleErrorSpy = vi.spyOn(console, "error").mockImplementation(() => {});
+      configureLogger({ verbose: true });
+      const mockApi = {
+        get: vi.fn().mockResolvedValue({
+          data: { data: { people: [{ id: "1", city: "Paris", jobTitle: "CEO" }] } },
+        }),
+      };
+
+      try {
+        const result = await new RecordsService(mockApi as any).list("people", {
+          fields: ["id", "city"],
+        });
+
+        expect(result.data).toEqual([{ id: "1", city: "Paris" }]);
+        expect(consoleErrorSpy).toHaveBeenCalledWith(
+          "Server ignored field selection for people; pruning fields client-side",
+        );
+      } finally {
+        configureLogger({});
+        consoleErrorSpy.mockRestore();
+      }
+    });
+
+    it("reports field selection as unverified when no record proves it", async () => {
+      const consoleErrorSpy = vi.spyOn(console, "error").mockImplementation(() => {});
+      configureLogger({ verbose: true });
+      const mockApi = {
+        get: vi
+          .fn()
+          .mockResolvedValueOnce({ data: { data: { people: [] } } })
+          .mockResolvedValueOnce({
+            data: { data: { people: [{ id: "1", createdAt: "x", updatedAt: "y" }] } },
+          }),
+      };
+
+      try {
+        const service = new RecordsService(mockApi as any);
+        await service.list("people", { fields: ["id", "city"] });
+        const result = await service.list("people", {
+          fields: ["id", "createdAt", "updatedAt"],
+        });
+
+        expect(result.data).toEqual([{ id: "1", createdAt: "x", updatedAt: "y" }]);
+        expect(consoleErrorSpy.mock.calls).toEqual([
+          ["Server-side field selection unverified for people; pruning fields client-side"],
+          ["Server-side field selection unverified for people; pruning fields client-side"],
+        ]);
+      } finally {
+        configureLogger({});
+        consoleErrorSpy.mockRestore();
+      }
+    });
+  });
+
   describe("listAll", () => {
     it("fetches all pages until hasNextPage is false", async () => {
       const mockApi = {
@@ -135,6 +240,223 @@ describe("RecordsService", () => {
       expect(result.data).toHaveLength(2);
       expect(result.totalCount).toBe(2);
     });
+
+    it("hands pages to onPage instead of collecting them", async () => {
+      const mockApi = {
+        get: vi
+          .fn()
+          .mockResolvedValueOnce({
+            data: {
+              data: { people: [{ id: "1" }] },
+              pageInfo: { hasNextPage: true, endCursor: "cursor1" },
+            },
+          })
+          .mockResolvedValueOnce({
+            data: { data: { people: [{ id: "2" }] }, pageInfo: { hasNextPage: false } },
+          }),
+      };
+      const onPage = vi.fn().mockResolvedValue(undefined);
+
+      const result = await new RecordsService(mockApi as any).listAll("people", { onPage });
+
+      expect(onPage.mock.calls).toEqual([
+        [[{ id: "1" }], { hasNextPage: true, endCursor: "cursor1" }],
+        [[{ id: "2" }], { hasNextPage: false }],
+      ]);
+      expect(result.data).toEqual([]);
+    });
+
+    it("stops paging when aborted and returns the cursor of the unfetched page", async () => {
+      const controller = new AbortController();
+      const mockApi = {
+        get: vi
+          .fn()
+          .mockResolvedValueOnce({
+            data: {
+              data: { people: [{ id: "1" }] },
+              pageInfo: { hasNextPage: true, endCursor: "cursor1" },
+              totalCount: 3,
+            },
+          })
+          .mockImplementationOnce(async () => {
+            controller.abort();
+            throw new Error("canceled");
+          }),
+      };
+
+      const result = await new RecordsService(mockApi as any).listAll("people", {
+        signal: controller.signal,
+      });
+
+      expect(mockApi.get).toHaveBeenNthCalledWith(2, "/rest/people", {
+        params: { starting_after: "cursor1" },
+        signal: controller.signal,
+      });
+      expect(result).toEqual({
+        data: [{ id: "1" }],
+        totalCount: 3,
+        pageInfo: { hasNextPage: true, endCursor: "cursor1" },
+        interrupted: true,
+      });
+    });
+
+    it("logs page progress when verbose", async () => {
+      const consoleErrorSpy = vi.spyOn(console, "error").mockImplementation(() => {});
+      configureLogger({ verbose: true });
+      const mockApi = {
+        get: vi.fn().mockResolvedValue({
+          data: { data: { people: [{ id: "1" }] }, pageInfo: { hasNextPage: false }, totalCount: 1 },
+        }),
+      };
+
+      try {
+        await new RecordsService(mockApi as any).listAll("people");
+
+        expect(consoleErrorSpy).toHaveBeenCalledWith("Fetched people page 1 (1/1 records)");
+      } finally {
+        configureLogger({});
+        consoleErrorSpy.mockRestore();
+      }
+    });
+  });
+
+  describe("findUniqueBy", () => {
+    function createService(data: unknown[]) {
+      const mockReadBackend = {
+        list: vi.fn().mockResolvedValue({ data }),
+        listAll: vi.fn(),
+        get: vi.fn(),
+        groupBy: vi.fn(),
+      };
+      const service = new RecordsService({} as any, { readBackend: mockReadBackend as any });
+      return { service, mockReadBackend };
+    }
+
+    it("returns the only record matching a quoted equality filter", async () => {
+      const { service, mockReadBackend } = createService([{ id: "1" }]);
+
+      await expect(
+        service.findUniqueBy("people", "emails.primaryEmail", "john@example.com"),
+      ).resolves.toEqual({ id: "1" });
+      expect(mockReadBackend.list).toHaveBeenCalledWith("people", {
+        filter: 'emails.primaryEmail[eq]:"john@example.com"',
+        limit: 2,
+        include: undefined,
+      });
+    });
+
+    it("fails when no record matches", async () => {
+      const { service } = createService([]);
+
+      await expect(service.findUniqueBy("people", "city", "Paris")).rejects.toMatchObject({
+        message: "No people record found with city = Paris.",
+        code: "NOT_FOUND",
+      });
+    });
+
+    it("fails when more than one record matches", async () => {
+      const { service } = createService([{ id: "1" }, { id: "2" }]);
+
+      await expect(service.findUniqueBy("people", "city", "Paris")).rejects.toMatchObject({
+        message: "Multiple people records found with city = Paris.",
+        code: "INVALID_ARGUMENTS",
+      });
+    });
+  });
+
+  describe("ensure", () => {
+    function createService(listResults: unknown[][], post: ReturnType<typeof vi.fn>) {
+      const list = vi.fn();
+      for (const data of listResults) {
+        list.mockResolvedValueOnce({ data });
+      }
+      const mockReadBackend = { list, listAll: vi.fn(), get: vi.fn(), groupBy: vi.fn() };
+      const service = new RecordsService({ post } as any, { readBackend: mockReadBackend as any });
+      return { service, list };
+    }
+
+    it("returns the existing record without creating", async () => {
+      const post = vi.fn();
+      const { service } = createService([[{ id: "1" }]], post);
+
+      await expect(
+        service.ensure("people", "emails.primaryEmail", "a@b.co", { name: "A" }),
+      ).resolves.toEqual({ record: { id: "1" }, created: false });
+      expect(post).not.toHaveBeenCalled();
+    });
+
+    it("creates the record through the server's upsert when none matches", async () => {
+      const post = vi.fn().mockImplementation(async (_url, data: { id: string }) => ({
+        data: { data: { createPerson: { id: data.id } } },
+      }));
+      const { service } = createService([[]], post);
+
+      const result = await service.ensure("people", "emails.primaryEmail", "a@b.co", {
+        name: "A",
+      });
+
+      expect(result.created).toBe(true);
+      expect(post).toHaveBeenCalledWith(
+        "/rest/people",
+        { name: "A", id: expect.any(String) },
+        expect.objectContaining({ params: { upsert: "true" } }),
+      );
+      expect(result.record).toEqual({ id: post.mock.calls[0]![1].id });
+    });
+
+    it("reports a record the upsert matched as updated, not created", async () => {
+      const post = vi.fn().mockResolvedValue({
+        data: { data: { createPerson: { id: "9", name: "A" } } },
+      });
+      const { service } = createService([[]], post);
+
+      await expect(
+        service.ensure("people", "emails.primaryEmail", "a@b.co", { name: "A" }),
+      ).resolves.toEqual({ record: { id: "9", name: "A" }, created: false, updated: true });
+    });
+
+    it("reads the record back by the key when the upsert response has no ID", async () => {
+      const post = vi.fn().mockResolvedValue({ data: { data: { createPerson: {} } } });
+      const { service, list } = createService([[], [{ id: "9" }]], post);
+
+      await expect(
+        service.ensure("people", "emails.primaryEmail", "a@b.co", { name: "A" }),
+      ).resolves.toEqual({ record: { id: "9" }, created: false, updated: true });
+      expect(list).toHaveBeenLastCalledWith(
+        "people",
+        expect.objectContaining({ filter: 'emails.primaryEmail[eq]:"a@b.co"' }),
+      );
+    });
+
+    it("fails when the upserted record cannot be read back", async () => {
+      const post = vi.fn().mockResolvedValue({ data: { data: { createPerson: {} } } });
+      const { service } = createService([[], []], post);
+
+      await expect(
+        service.ensure("people", "emails.primaryEmail", "a@b.co", { name: "A" }),
+      ).rejects.toThrow("Wrote a people record with emails.primaryEmail = a@b.co");
+    });
+
+    it("returns the concurrently created record when the create hits a unique conflict", async () => {
+      const post = vi.fn().mockRejectedValue({
+        isAxiosError: true,
+        response: { status: 400, data: { messages: ["A duplicate entry was detected"] } },
+      });
+      const { service, list } = createService([[], [{ id: "3" }]], post);
+
+      await expect(
+        service.ensure("people", "emails.primaryEmail", "a@b.co", { name: "A" }),
+      ).resolves.toEqual({ record: { id: "3" }, created: false });
+      expect(list).toHaveBeenCalledTimes(2);
+    });
+
+    it("rethrows create failures that are not unique conflicts", async () => {
+      const failure = { isAxiosError: true, response: { status: 400, data: "bad field" } };
+      const post = vi.fn().mockRejectedValue(failure);
+      const { service } = createService([[]], post);
+
+      await expect(service.ensure("people", "city", "Paris", {})).rejects.toBe(failure);
+    });
   });
 
   describe("create", () => {
@@ -148,9 +470,29 @@ describe("RecordsService", () => {
       const service = new RecordsService(mockApi as any);
       const result = await service.create("people", { name: "Test" });
 
-      expect(mockApi.post).toHaveBeenCalledWith("/rest/people", { name: "Test" });
+      expect(mockApi.post).toHaveBeenCalledWith("/rest/people", { name: "Test" }, {
+        headers: { "Idempotency-Key": expect.any(String) },
+      });
       expect((result as any).id).toBe("123");
     });
+
+    it("sends a caller-supplied idempotency key", async () => {
+      const mockApi = {
+        post: vi.fn().mockResolvedValue({ data: { data: { createPerson: { id: "123" } } } }),
+      };
+
+      await new RecordsService(mockApi as any).create(
+        "people",
+        { name: "Test" },
+        { idempotencyKey: "import-42" },
+      );
+
+      expect(mockApi.post).toHaveBeenCalledWith(
+        "/rest/people",
+        { name: "Test" },
+        { headers: { "Idempotency-Key": "import-42" }, twentyIdempotent: true },
+      );
+    });
   });
 
   describe("get", () => {
@@ -249,7 +591,9 @@ describe("RecordsService", () => {
       const records = [{ title: "Task 1" }, { title: "Task 2" }];
       await service.batchCreate("tasks", records);
 
-      expect(mockApi.post).toHaveBeenCalledWith("/rest/batch/tasks", records);
+      expect(mockApi.post).toHaveBeenCalledWith("/rest/batch/tasks", records, {
+        headers: { "Idempotency-Key": expect.any(String) },
+      });
     });
 
     it("batch updates records", async () => {
@@ -1,5 +1,7 @@
 import { extractCollection, extractFirstValue, getDataSection } from "../../api/rest-response";
 import { ApiService } from "../../api/services/api.service";
+import { CliError } from "../../errors/cli-error";
+import { logVerbose } from "../../shared/logger";
 import { singularize } from "../../shared/parse";
 
 type RecordsApiClient = Pick<ApiService, "get">;
@@ -11,7 +13,17 @@ export interface ListOptions {
   sort?: string;
   order?: string;
   include?: string;
+  // Top-level fields to return. Requested from the server and enforced
+  // client-side when the server ignores the selection.
+  fields?: string[];
   params?: Record<string, string[]>;
+  // Aborting stops listAll between pages and cancels the in-flight request.
+  signal?: AbortSignal;
+  // Hands each listAll page to the callback instead of collecting it, so
+  // callers can stream large exports; the returned data is then empty. The
+  // page's pageInfo comes along so callers can checkpoint its cursor, and
+  // the page's totalCount so streams can announce the size up front.
+  onPage?: (records: unknown[], pageInfo?: PageInfo, totalCount?: number) => Promise<void>;
 }
 
 export interface GetOptions {
@@ -27,6 +39,9 @@ export interface ListResponse {
   data: unknown[];
   totalCount?: number;
   pageInfo?: PageInfo;
+  // Set when listAll stopped early because its signal was aborted; pageInfo
+  // then points at the first page that was not fetched.
+  interrupted?: boolean;
 }
 
 export type GroupByParams = Record<string, string[]>;
@@ -41,18 +56,31 @@ export class ApiRecordsReadService {
     if (options.sort) params.order_by = formatOrderBy(options.sort, options.order);
     if (options.include) params.depth = "1";
     if (options.filter) params.filter = options.filter;
+    if (options.fields?.length) params.fields = options.fields.join(",");
     if (options.params) {
       for (const [key, values] of Object.entries(options.params)) {
+        // Raw params are sent as given; one that would silently replace a
+        // structured option (such as --filter) is rejected instead.
+        if (key in params) {
+          throw new CliError(
+            `--param ${key} conflicts with a value already set by another option.`,
+            "INVALID_ARGUMENTS",
+            `Pass ${key} once, either through its option or through --param.`,
+          );
+        }
         params[key] = values.length === 1 ? values[0] : values;
       }
     }
 
-    const response = await this.api.get(`/rest/${object}`, { params });
+    const response = await this.api.get(`/rest/${object}`, {
+      params,
+      ...(options.signal ? { signal: options.signal } : {}),
+    });
     const payload = response.data;
     const dataSection = getDataSection(payload);
     const records = extractCollection({ data: dataSection }, object);
     return {
-      data: records,
+      data: options.fields?.length ? selectFields(object, records, options.fields) : records,
       totalCount: isRecord(payload) ? (payload.totalCount as number | undefined) : undefined,
       pageInfo: isRecord(payload) ? (payload.pageInfo as PageInfo | undefined) : undefined,
     };
@@ -60,21 +88,55 @@ export class ApiRecordsReadService {
 
   async listAll(object: string, options: ListOptions = {}): Promise<ListResponse> {
     const all: unknown[] = [];
+    let fetched = 0;
     let cursor = options.cursor ?? "";
     let pageInfo: PageInfo | undefined;
     let totalCount: number | undefined;
+    let page = 0;
+    let interrupted = false;
 
     while (true) {
-      const response = await this.list(object, { ...options, cursor });
-      all.push(...response.data);
+      if (options.signal?.aborted) {
+        interrupted = true;
+        break;
+      }
+      page += 1;
+      let response: ListResponse;
+      try {
+        response = await this.list(object, { ...options, cursor });
+      } catch (error) {
+        if (!options.signal?.aborted) {
+          throw error;
+        }
+        interrupted = true;
+        break;
+      }
+      if (options.onPage) {
+        await options.onPage(response.data, response.pageInfo, response.totalCount);
+      } else {
+        all.push(...response.data);
+      }
+      fetched += response.data.length;
       pageInfo = response.pageInfo;
       totalCount = response.totalCount ?? totalCount;
+      const progress = totalCount !== undefined ? `${fetched}/${totalCount}` : `${fetched}`;
+      logVerbose(`Fetched ${object} page ${page} (${progress} records)`);
       if (!pageInfo?.hasNextPage || !pageInfo?.endCursor) {
         break;
       }
       cursor = pageInfo.endCursor;
     }
 
+    if (interrupted) {
+      logVerbose(`Stopped ${object} early (${fetched} records)`);
+      return {
+        data: all,
+        totalCount,
+        pageInfo: { hasNextPage: true, endCursor: cursor || undefined },
+        interrupted: true,
+      };
+    }
+
     return { data: all, totalCount, pageInfo };
   }
 
@@ -101,6 +163,47 @@ export class ApiRecordsReadService {
   }
 }
 
+// Every full Twenty record carries these, so a record without an unrequested
+// one shows the server narrowed it.
+const ALWAYS_PRESENT_FIELDS = ["id", "createdAt", "updatedAt"];
+
+// Servers that do not understand the fields param return full records; detect
+// that from the payload and prune locally so output is identical either way.
+// Records holding only requested keys do not prove the server honoured the
+// param (the page may be empty, or the fields may be all a record has), so
+// selection is only reported as applied when a record lacks a field every
+// full record has.
+function selectFields(object: string, records: unknown[], fields: string[]): unknown[] {
+  const narrow = records.every(
+    (record) => !isRecord(record) || Object.keys(record).every((key) => fields.includes(key)),
+  );
+  const missing = ALWAYS_PRESENT_FIELDS.filter((field) => !fields.includes(field));
+  const proven = records.some(
+    (record) => isRecord(record) && missing.some((field) => !(field in record)),
+  );
+  if (narrow && proven) {
+    logVerbose(`Server-side field selection applied for ${object}`);
+    return records;
+  }
+
+  logVerbose(
+    narrow
+      ? `Server-side field selection unverified for ${object}; pruning fields client-side`
+      : `Server ignored field selection for ${object}; pruning fields client-side`,
+  );
+  return records.map((record) => (isRecord(record) ? pickFields(record, fields) : record));
+}
+
+function pickFields(record: Record<string, unknown>, fields: string[]): Record<string, unknown> {
+  const picked: Record<string, unknown> = {};
+  for (const field of fields) {
+    if (field in record) {
+      picked[field] = record[field];
+    }
+  }
+  return picked;
+}
+
 function flattenParams(
   params?: Record<string, string[]>,
 ): Record<string, string | string[]> | undefined {
@@ -1,5 +1,11 @@
+import crypto from "node:crypto";
+import type { AxiosError } from "axios";
 import { extractFirstValue, getDataSection } from "../../api/rest-response";
-import { ApiService } from "../../api/services/api.service";
+import {
+  ApiService,
+  IDEMPOTENCY_KEY_HEADER,
+  type IdempotentRequestConfig,
+} from "../../api/services/api.service";
 import { CliError } from "../../errors/cli-error";
 import type { RecordsReadBackend } from "../../readbackend/types";
 import { capitalize, singularize } from "../../shared/parse";
@@ -24,6 +30,23 @@ interface BulkMutationOptions {
   include?: string;
 }
 
+export interface CreateOptions {
+  // Defaults to a fresh UUID per call; retries of that call reuse it. Only a
+  // supplied key lets the create be resent after a reset or timeout.
+  idempotencyKey?: string;
+  // Ask the server to update the record matching the data's unique fields
+  // instead of failing on a duplicate (?upsert=true).
+  upsert?: boolean;
+}
+
+export interface EnsureResult {
+  record: unknown;
+  created: boolean;
+  // Set when a record another writer created after our lookup was matched by
+  // the upsert and overwritten with our data.
+  updated?: true;
+}
+
 interface RecordsServiceDependencies {
   readBackend?: RecordsReadBackend;
 }
@@ -50,8 +73,78 @@ export class RecordsService {
     return this.readBackend.get(object, id, options);
   }
 
-  async create(object: string, data: Record<string, unknown>): Promise<unknown> {
-    const response = await this.api.post(`/rest/${object}`, data);
+  // Resolves a single record by an exact field match, failing when the value is
+  // missing or ambiguous so callers never act on the wrong record.
+  async findUniqueBy(
+    object: string,
+    field: string,
+    value: string,
+    options: GetOptions = {},
+  ): Promise<unknown> {
+    const record = await this.findOneBy(object, field, value, options);
+    if (record === undefined) {
+      throw new CliError(`No ${object} record found with ${field} = ${value}.`, "NOT_FOUND");
+    }
+
+    return record;
+  }
+
+  // Returns the record matching field = value, creating it when none exists.
+  // The create asks the server to upsert, so a record another writer created
+  // between our lookup and our create is matched on its unique fields rather
+  // than duplicated; the ID we assign up front tells the two outcomes apart.
+  // A matched record has been overwritten with our data, so it is reported as
+  // updated. When the response carries no ID, the record is read back by
+  // field = value to tell. Servers without REST upsert ignore the parameter
+  // and reject the duplicate instead, in which case the winner is looked up
+  // and returned untouched.
+  async ensure(
+    object: string,
+    field: string,
+    value: string,
+    data: Record<string, unknown>,
+  ): Promise<EnsureResult> {
+    const existing = await this.findOneBy(object, field, value);
+    if (existing !== undefined) {
+      return { record: existing, created: false };
+    }
+
+    const id = typeof data.id === "string" ? data.id : crypto.randomUUID();
+    let record: unknown;
+    try {
+      record = await this.create(object, { ...data, id }, { upsert: true });
+    } catch (error) {
+      if (!isUniqueConflict(error)) {
+        throw error;
+      }
+      const winner = await this.findOneBy(object, field, value);
+      if (winner === undefined) {
+        throw error;
+      }
+      return { record: winner, created: false };
+    }
+
+    if (recordIdOf(record) === undefined) {
+      record = await this.findOneBy(object, field, value);
+      if (recordIdOf(record) === undefined) {
+        throw new CliError(
+          `Wrote a ${object} record with ${field} = ${value} but could not read it back.`,
+          "NOT_FOUND",
+          `Look it up with --filter '${field}[eq]:${JSON.stringify(value)}' before retrying.`,
+        );
+      }
+    }
+    return recordIdOf(record) === id
+      ? { record, created: true }
+      : { record, created: false, updated: true };
+  }
+
+  async create(
+    object: string,
+    data: Record<string, unknown>,
+    options: CreateOptions = {},
+  ): Promise<unknown> {
+    const response = await this.api.post(`/rest/${object}`, data, idempotencyConfig(options));
     const dataSection = getDataSection(response.data);
     const key = `create${capitalize(singularize(object))}`;
     return dataSection[key] ?? extractFirstValue(dataSection);
@@ -81,8 +174,16 @@ export class RecordsService {
     return response.data ?? null;
   }
 
-  async batchCreate(object: string, records: Record<string, unknown>[]): Promise<unknown> {
-    const response = await this.api.post(`/rest/batch/${object}`, records);
+  async batchCreate(
+    object: string,
+    records: Record<string, unknown>[],
+    options: CreateOptions = {},
+  ): Promise<unknown> {
+    const response = await this.api.post(
+      `/rest/batch/${object}`,
+      records,
+      idempotencyConfig(options),
+    );
     return response.data ?? null;
   }
 
@@ -147,6 +248,62 @@ export class RecordsService {
     const response = await this.api.patch(`/rest/${object}/merge`, payload);
     return response.data ?? null;
   }
+
+  private async findOneBy(
+    object: string,
+    field: string,
+    value: string,
+    options: GetOptions = {},
+  ): Promise<unknown> {
+    const response = await this.readBackend.list(object, {
+      filter: `${field}[eq]:${JSON.stringify(value)}`,
+      limit: 2,
+      include: options.include,
+    });
+
+    if (response.data.length > 1) {
+      throw new CliError(
+        `Multiple ${object} records found with ${field} = ${value}.`,
+        "INVALID_ARGUMENTS",
+        "Use the record ID or a field whose values are unique.",
+      );
+    }
+
+    return response.data[0];
+  }
+}
+
+// Twenty reports unique-constraint violations as 409 or as a 400 whose message
+// mentions the duplicate.
+function isUniqueConflict(error: unknown): boolean {
+  const response = (error as AxiosError | undefined)?.response;
+  if (!response) {
+    return false;
+  }
+  if (response.status === 409) {
+    return true;
+  }
+  if (response.status !== 400) {
+    return false;
+  }
+
+  const body = typeof response.data === "string" ? response.data : JSON.stringify(response.data);
+  return /duplicate|unique|already exists/i.test(body ?? "");
+}
+
+// The header lives on the request config, which axios-retry re-sends as is, so
+// every retry of one logical write carries the same key.
+function idempotencyConfig(options: CreateOptions): IdempotentRequestConfig {
+  return {
+    headers: { [IDEMPOTENCY_KEY_HEADER]: options.idempotencyKey ?? crypto.randomUUID() },
+    ...(options.idempotencyKey ? { twentyIdempotent: true } : {}),
+    ...(options.upsert ? { params: { upsert: "true" } } : {}),
+  };
+}
+
+function recordIdOf(record: unknown): string | undefined {
+  const id = (record as { id?: unknown } | null | undefined)?.id;
+  return typeof id === "string" && id !== "" ? id : undefined;
 }
 
 function extractRecordId(record: Record<string, unknown>): string {
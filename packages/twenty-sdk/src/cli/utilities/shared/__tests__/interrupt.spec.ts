@@ -0,0 +1,50 @@
+import { afterEach, describe, expect, it, vi } from "vitest";
+import { INTERRUPTED_EXIT_CODE, reportInterrupted, runInterruptible } from "../interrupt";
+
+function currentSigintHandler(): () => void {
+  const listeners = process.listeners("SIGINT");
+  return listeners[listeners.length - 1] as () => void;
+}
+
+describe("runInterruptible", () => {
+  afterEach(() => {
+    process.exitCode = undefined;
+    vi.restoreAllMocks();
+  });
+
+  it("aborts the signal on the first SIGINT and removes its handler afterwards", async () => {
+    vi.spyOn(console, "error").mockImplementation(() => {});
+    const before = process.listenerCount("SIGINT");
+
+    const aborted = await runInterruptible(async (signal) => {
+      expect(process.listenerCount("SIGINT")).toBe(before + 1);
+      currentSigintHandler()();
+      return signal.aborted;
+    });
+
+    expect(aborted).toBe(true);
+    expect(process.listenerCount("SIGINT")).toBe(before);
+  });
+
+  it("exits immediately on a second SIGINT", async () => {
+    vi.spyOn(console, "error").mockImplementation(() => {});
+    const exit = vi.spyOn(process, "exit").mockImplementation((() => undefined) as never);
+
+    await runInterruptible(async () => {
+      const handler = currentSigintHandler();
+      handler();
+      handler();
+    });
+
+    expect(exit).toHaveBeenCalledWith(INTERRUPTED_EXIT_CODE);
+  });
+
+  it("reports the resume hint on stderr and sets exit code 130", () => {
+    const error = vi.spyOn(console, "error").mockImplementation(() => {});
+
+    reportInterrupted("Resume with --cursor abc");
+
+    expect(error).toHaveBeenCalledWith("Resume with --cursor abc");
+    expect(process.exitCode).toBe(130);
+  });
+});
@@ -0,0 +1,103 @@
+import { afterEach, beforeEach, describe, expect, it, vi } from "vitest";
+import { configureLogger, log, logLines, logVerbose, redactSensitive } from "../logger";
+
+describe("logger", () => {
+  let consoleErrorSpy: ReturnType<typeof vi.spyOn>;
+
+  beforeEach(() => {
+    consoleErrorSpy = vi.spyOn(console, "error").mockImplementation(() => {});
+  });
+
+  afterEach(() => {
+    configureLogger({});
+    consoleErrorSpy.mockRestore();
+  });
+
+  function entries(): Record<string, unknown>[] {
+    return consoleErrorSpy.mock.calls.map((call) => JSON.parse(String(call[0])));
+  }
+
+  it("writes plain messages in text format", () => {
+    configureLogger({ verbose: true });
+
+    logVerbose("→ GET https://crm.example.com/rest/people", { method: "GET" });
+    logLines("error", ["Bad input.", "Suggestion: fix it"]);
+
+    expect(consoleErrorSpy.mock.calls).toEqual([
+      ["→ GET https://crm.example.com/rest/people"],
+      ["Bad input."],
+      ["Suggestion: fix it"],
+    ]);
+  });
+
+  it("writes one JSON object per line in json format", () => {
+    configureLogger({ verbose: true, logFormat: "json" });
+
+    logVerbose("→ GET /rest/people", { method: "GET", url: "/rest/people" });
+    logLines("error", ["Bad input.", "Suggestion: fix it"], { exitCode: 2 });
+
+    const [request, error] = entries();
+    expect(request).toMatchObject({
+      level: "verbose",
+      message: "→ GET /rest/people",
+      fields: { method: "GET", url: "/rest/people" },
+    });
+    expect(typeof request?.timestamp).toBe("string");
+    expect(error).toMatchObject({
+      level: "error",
+      message: "Bad input.\nSuggestion: fix it",
+      fields: { exitCode: 2 },
+    });
+  });
+
+  it("redacts tokens and sensitive fields in json format", () => {
+    configureLogger({ logFormat: "json" });
+
+    log("debug", "Authorization: Bearer secret-token", {
+      headers: { Authorization: "Bearer secret-token", accept: "application/json" },
+      apiKey: "abc",
+      note: "sent Bearer other-token",
+    });
+
+    const [entry] = entries();
+    expect(entry?.message).toBe("Authorization: Bearer [REDACTED]");
+    expect(entry?.fields).toEqual({
+      headers: { Authorization: "[REDACTED]", accept: "application/json" },
+      apiKey: "[REDACTED]",
+      note: "sent Bearer [REDACTED]",
+    });
+  });
+
+  it("redacts tokens and sensitive JSON values in text format too", () => {
+    configureLogger({});
+
+    log("debug", "Authorization: Bearer secret-token");
+    log("debug", '  Body: {"email":"a@b.co","password":"hunter2","apiKey":"abc');
+
+    expect(consoleErrorSpy.mock.calls).toEqual([
+      ["Authorization: Bearer [REDACTED]"],
+      ['  Body: {"email":"a@b.co","password":"[REDACTED]","apiKey":"[REDACTED]"'],
+    ]);
+  });
+
+  it("redacts sensitive keys at any depth before a body is previewed", () => {
+    expect(
+      redactSensitive({
+        name: "Ada",
+        credentials: [{ password: "hunter2", user: "ada" }],
+        config: { webhookSecret: "s", url: "https://example.com" },
+      }),
+    ).toEqual({
+      name: "Ada",
+      credentials: [{ password: "[REDACTED]", user: "ada" }],
+      config: { webhookSecret: "[REDACTED]", url: "https://example.com" },
+    });
+  });
+
+  it("skips verbose lines unless verbose or debug is on", () => {
+    configureLogger({ logFormat: "json" });
+    logVerbose("hidden");
+
+    expect(consoleErrorSpy).not.toHaveBeenCalled();
+  });
+});
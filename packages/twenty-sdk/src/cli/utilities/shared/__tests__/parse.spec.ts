@@ -4,9 +4,12 @@ import {
   singularize,
   parsePrimitive,
   parseKeyValuePairs,
+  parseQueryParams,
   splitOnce,
   chunkArray,
   parseBooleanEnv,
+  parsePositiveInteger,
+  parseNonNegativeIntegerOption,
   applySet,
   mergeSets,
 } from "../parse";
@@ -161,6 +164,26 @@ describe("parse utilities", () => {
     });
   });
 
+  describe("parseQueryParams", () => {
+    it("accepts URL-safe keys and passes values through unchanged", () => {
+      expect(parseQueryParams(["filter[city]=São Paulo", "soft_delete=true"])).toEqual({
+        "filter[city]": ["São Paulo"],
+        soft_delete: ["true"],
+      });
+    });
+
+    it("rejects keys outside the URL-safe set", () => {
+      expect(() => parseQueryParams(["bad key=1"])).toThrow('Invalid --param key "bad key".');
+      expect(() => parseQueryParams(["a&b=1"])).toThrow('Invalid --param key "a&b".');
+    });
+
+    it("rejects values with control characters", () => {
+      expect(() => parseQueryParams(["note=line\nbreak"])).toThrow(
+        "Invalid --param value for note: control characters are not allowed.",
+      );
+    });
+  });
+
   describe("splitOnce", () => {
     it("splits on first occurrence", () => {
       expect(splitOnce("a=b=c", "=")).toEqual(["a", "b=c"]);
@@ -221,6 +244,34 @@ describe("parse utilities", () => {
     });
   });
 
+  describe("parsePositiveInteger", () => {
+    it("parses a positive integer", () => {
+      expect(parsePositiveInteger("25", "--limit")).toBe(25);
+    });
+
+    it("rejects zero with the flag name", () => {
+      expect(() => parsePositiveInteger("0", "--limit")).toThrow(
+        "--limit must be a positive integer.",
+      );
+    });
+  });
+
+  describe("parseNonNegativeIntegerOption", () => {
+    it("parses zero and returns undefined when unset", () => {
+      expect(parseNonNegativeIntegerOption("--offset", "0")).toBe(0);
+      expect(parseNonNegativeIntegerOption("--offset", undefined)).toBeUndefined();
+    });
+
+    it("rejects negative and fractional values", () => {
+      expect(() => parseNonNegativeIntegerOption("--offset", "-1")).toThrow(
+        'Invalid --offset value "-1"; expected a non-negative integer.',
+      );
+      expect(() => parseNonNegativeIntegerOption("--offset", "1.5")).toThrow(
+        "expected a non-negative integer",
+      );
+    });
+  });
+
   describe("parseBooleanEnv", () => {
     it("parses truthy values", () => {
       expect(parseBooleanEnv("true")).toBe(true);
@@ -0,0 +1,51 @@
+import { afterEach, describe, expect, it } from "vitest";
+import { CliError } from "../../errors/cli-error";
+import { safeJsonParse } from "../io";
+import { configureStrictJson, findDuplicateJsonKey } from "../strict-json";
+
+describe("findDuplicateJsonKey", () => {
+  it("returns undefined when every object has unique keys", () => {
+    expect(findDuplicateJsonKey('{"a":1,"b":{"a":2},"c":[{"a":3},{"a":4}]}')).toBeUndefined();
+    expect(findDuplicateJsonKey("[]")).toBeUndefined();
+    expect(findDuplicateJsonKey('"text"')).toBeUndefined();
+  });
+
+  it("points at the first repeated key, including inside arrays", () => {
+    expect(findDuplicateJsonKey('{"name":"a","name":"b"}')).toBe("/name");
+    expect(findDuplicateJsonKey('[{"id":1},{"id":2,"email":"x","email":"y"}]')).toBe("/1/email");
+    expect(findDuplicateJsonKey('{"a/b":{"x":1,"x":2}}')).toBe("/a~1b/x");
+  });
+
+  it("treats escaped and literal spellings of a key as the same key", () => {
+    expect(findDuplicateJsonKey('{ "a" : 1 , "\\u0061" : 2 }')).toBe("/a");
+  });
+
+  it("skips braces and quotes inside string values", () => {
+    expect(findDuplicateJsonKey('{"a":"}{\\"a\\":","b":[1, true, null]}')).toBeUndefined();
+  });
+});
+
+describe("strict JSON parsing", () => {
+  afterEach(() => {
+    configureStrictJson(false);
+  });
+
+  it("keeps the last duplicate by default", () => {
+    expect(safeJsonParse('{"name":"a","name":"b"}')).toEqual({ name: "b" });
+  });
+
+  it("rejects duplicate keys with --strict-json", () => {
+    configureStrictJson(true);
+
+    let error: unknown;
+    try {
+      safeJsonParse('{"name":"a","name":"b"}');
+    } catch (caught) {
+      error = caught;
+    }
+
+    expect(error).toBeInstanceOf(CliError);
+    expect((error as CliError).message).toBe("Duplicate JSON key at /name.");
+    expect((error as CliError).code).toBe("INVALID_ARGUMENTS");
+  });
+});
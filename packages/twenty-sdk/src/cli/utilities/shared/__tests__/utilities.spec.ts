@@ -11,6 +11,11 @@ import {
 import { readJsonInput, safeJsonParse, readFileOrStdin } from "../io";
 import { createServices } from "../services";
 import { createCommandContext, createOutputContext } from "../context";
+import {
+  readConfiguredOutput,
+  readConfiguredQuery,
+  readConfiguredRetries,
+} from "../../config/services/output-defaults";
 
 // Mock fs-extra
 vi.mock("fs-extra", () => ({
@@ -46,6 +51,12 @@ vi.mock("../../config/services/config.service", () => ({
   }),
 }));
 
+vi.mock("../../config/services/output-defaults", () => ({
+  readConfiguredOutput: vi.fn(),
+  readConfiguredQuery: vi.fn(),
+  readConfiguredRetries: vi.fn(),
+}));
+
 vi.mock("../../records/services/records.service", () => ({
   RecordsService: vi.fn(function MockRecordsService() {
     return {
@@ -87,6 +98,7 @@ vi.mock("../../output/services/output.service", () => ({
   OutputService: vi.fn(function MockOutputService() {
     return {
       render: vi.fn(),
+      renderDetail: vi.fn(),
     };
   }),
 }));
@@ -231,10 +243,47 @@ describe("global-options utilities", () => {
         new Set([
           "output",
           "query",
+          "pointer",
+          "raw-output",
+          "print0",
+          "prune-fields",
+          "mask-fields",
+          "unwrap",
+          "csv-quote-all",
+          "bom",
+          "float-precision",
+          "plain-numbers",
+          "totals",
+          "key-column",
+          "envelope",
+          "sort-keys",
+          "text-template",
           "workspace",
+          "profile",
+          "workspace-id",
+          "select-profile",
           "env-file",
+          "token-file",
+          "connection",
           "debug",
+          "verbose",
+          "log-format",
           "no-retry",
+          "max-retries",
+          "retry-base-delay",
+          "backoff",
+          "retry-body-match",
+          "retry-status-codes",
+          "retry-on-network-error",
+          "retry-unsafe",
+          "abort-on-rate-limit",
+          "max-body-size",
+          "insecure-allow-http",
+          "strict-json",
+          "show-retry-stats",
+          "etag-cache",
+          "compress-requests",
+          "retries-show-attempt-header",
           "light",
           "li",
           "full",
@@ -246,7 +295,31 @@ describe("global-options utilities", () => {
 
     it("exports the global flags that consume values", () => {
       expect(GLOBAL_OPTION_VALUE_TOKENS).toEqual(
-        new Set(["-o", "--output", "--query", "--workspace", "--env-file"]),
+        new Set([
+          "-o",
+          "--output",
+          "--query",
+          "--pointer",
+          "--prune-fields",
+          "--mask-fields",
+          "--float-precision",
+          "--key-column",
+          "--text-template",
+          "--workspace",
+          "--profile",
+          "--workspace-id",
+          "--env-file",
+          "--token-file",
+          "--connection",
+          "--log-format",
+          "--max-retries",
+          "--retry-base-delay",
+          "--backoff",
+          "--retry-body-match",
+          "--retry-status-codes",
+          "--retry-on-network-error",
+          "--max-body-size",
+        ]),
       );
     });
   });
@@ -321,9 +394,33 @@ describe("global-options utilities", () => {
       // Clear relevant env vars
       delete process.env.TWENTY_OUTPUT;
       delete process.env.TWENTY_QUERY;
+      delete process.env.TWENTY_PRUNE_FIELDS;
+      delete process.env.TWENTY_MASK_FIELDS;
       delete process.env.TWENTY_PROFILE;
+      delete process.env.TWENTY_WORKSPACE_ID;
       delete process.env.TWENTY_DEBUG;
+      delete process.env.TWENTY_VERBOSE;
+      delete process.env.TWENTY_LOG_FORMAT;
       delete process.env.TWENTY_NO_RETRY;
+      delete process.env.TWENTY_MAX_RETRIES;
+      delete process.env.TWENTY_RETRY_BASE_DELAY;
+      delete process.env.TWENTY_BACKOFF;
+      delete process.env.TWENTY_RETRY_BODY_MATCH;
+      delete process.env.TWENTY_RETRY_STATUS_CODES;
+      delete process.env.TWENTY_RETRY_ON_NETWORK_ERROR;
+      delete process.env.TWENTY_RETRY_UNSAFE;
+      delete process.env.TWENTY_ABORT_ON_RATE_LIMIT;
+      delete process.env.TWENTY_MAX_BODY_SIZE;
+      delete process.env.TWENTY_INSECURE_ALLOW_HTTP;
+      delete process.env.TWENTY_STRICT_JSON;
+      delete process.env.TWENTY_ENVELOPE;
+      delete process.env.TWENTY_SORT_KEYS;
+      delete process.env.TWENTY_TEXT_TEMPLATE;
+      delete process.env.TWENTY_SHOW_RETRY_STATS;
+      delete process.env.TWENTY_ETAG_CACHE;
+      delete process.env.TWENTY_COMPRESS_REQUESTS;
+      delete process.env.TWENTY_RETRIES_SHOW_ATTEMPT_HEADER;
+      delete process.env.TWENTY_CONNECTION;
       delete process.env.TWENTY_AGENT;
     });
 
@@ -383,6 +480,91 @@ describe("global-options utilities", () => {
       expect(options.output).toBe("csv");
     });
 
+    it("uses the configured output for the command when no flag or env is set", () => {
+      vi.mocked(readConfiguredOutput).mockReturnValue({
+        format: "table",
+        key: "output.people.list",
+      });
+      const program = new Command("twenty");
+      const people = program.command("people");
+      const list = people.command("list");
+      applyGlobalOptions(list);
+      program.parse(["node", "twenty", "people", "list"]);
+
+      expect(resolveGlobalOptions(list)).toMatchObject({ output: "table", outputExplicit: true });
+      expect(readConfiguredOutput).toHaveBeenCalledWith(["people", "list"]);
+      vi.mocked(readConfiguredOutput).mockReset();
+    });
+
+    it("prefers --output and TWENTY_OUTPUT over the configured output", () => {
+      vi.mocked(readConfiguredOutput).mockClear();
+      vi.mocked(readConfiguredOutput).mockReturnValue({ format: "table", key: "output" });
+      process.env.TWENTY_OUTPUT = "csv";
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test"]);
+      expect(resolveGlobalOptions(command).output).toBe("csv");
+
+      const flagged = new Command("test");
+      applyGlobalOptions(flagged);
+      flagged.parse(["node", "test", "--output", "jsonl"]);
+      expect(resolveGlobalOptions(flagged).output).toBe("jsonl");
+      expect(readConfiguredOutput).not.toHaveBeenCalled();
+      vi.mocked(readConfiguredOutput).mockReset();
+    });
+
+    it("names the config key when the configured output is invalid", () => {
+      vi.mocked(readConfiguredOutput).mockReturnValue({
+        format: "xml",
+        key: "output.people.list",
+      });
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test"]);
+
+      expect(() => resolveGlobalOptions(command)).toThrow(
+        "(from output.people.list in ~/.twenty/config.json).",
+      );
+      vi.mocked(readConfiguredOutput).mockReset();
+    });
+
+    it("applies the config's defaultQuery unless --query or TWENTY_QUERY is given", () => {
+      vi.mocked(readConfiguredQuery).mockReturnValue("data[].{id: id}");
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test"]);
+      expect(resolveGlobalOptions(command).query).toBe("data[].{id: id}");
+
+      const explicit = new Command("test");
+      applyGlobalOptions(explicit);
+      explicit.parse(["node", "test", "--query", "data[0]"]);
+      expect(resolveGlobalOptions(explicit).query).toBe("data[0]");
+
+      process.env.TWENTY_QUERY = "data[1]";
+      expect(resolveGlobalOptions(command).query).toBe("data[1]");
+      vi.mocked(readConfiguredQuery).mockReset();
+    });
+
+    it("disables the config's defaultQuery with an empty --query", () => {
+      vi.mocked(readConfiguredQuery).mockReturnValue("data[].{id: id}");
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test", "--query", ""]);
+
+      expect(resolveGlobalOptions(command).query).toBe("");
+      vi.mocked(readConfiguredQuery).mockReset();
+    });
+
+    it("skips the config's defaultQuery when --pointer is given", () => {
+      vi.mocked(readConfiguredQuery).mockReturnValue("data[].{id: id}");
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test", "--pointer", "/data/0"]);
+
+      expect(resolveGlobalOptions(command)).toMatchObject({ query: undefined, pointer: "/data/0" });
+      vi.mocked(readConfiguredQuery).mockReset();
+    });
+
     it("accepts jsonl output format", () => {
       const command = new Command("test");
       applyGlobalOptions(command);
@@ -415,6 +597,14 @@ describe("global-options utilities", () => {
       expect(options.output).toBe("json");
     });
 
+    it("accepts table as an output format", () => {
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test", "--output", "table"]);
+
+      expect(resolveGlobalOptions(command).output).toBe("table");
+    });
+
     it("rejects invalid output format", () => {
       process.env.TWENTY_OUTPUT = "invalid";
 
@@ -423,7 +613,7 @@ describe("global-options utilities", () => {
       command.parse(["node", "test"]);
 
       expect(() => resolveGlobalOptions(command)).toThrow(
-        'Unsupported output format "invalid". Valid formats: json, jsonl, csv, text.',
+        'Unsupported output format "invalid". Valid formats: json, jsonl, csv, text, table, html.',
       );
     });
 
@@ -507,6 +697,59 @@ describe("global-options utilities", () => {
       });
     });
 
+    it("parses --prune-fields into trimmed top-level keys", () => {
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test", "--prune-fields", "createdBy, updatedAt,,position"]);
+
+      expect(resolveGlobalOptions(command).pruneFields).toEqual([
+        "createdBy",
+        "updatedAt",
+        "position",
+      ]);
+    });
+
+    it("resolves --float-precision and --plain-numbers into a csv number format", () => {
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test"]);
+      expect(resolveGlobalOptions(command).csvNumbers).toBeUndefined();
+
+      const flags = new Command("test");
+      applyGlobalOptions(flags);
+      flags.parse(["node", "test", "--float-precision", "2", "--plain-numbers"]);
+      expect(resolveGlobalOptions(flags).csvNumbers).toEqual({ floatPrecision: 2, plain: true });
+
+      const invalid = new Command("test");
+      applyGlobalOptions(invalid);
+      invalid.parse(["node", "test", "--float-precision", "1.5"]);
+      expect(() => resolveGlobalOptions(invalid)).toThrow("Invalid --float-precision value");
+    });
+
+    it("reads prune fields from TWENTY_PRUNE_FIELDS", () => {
+      process.env.TWENTY_PRUNE_FIELDS = "deletedAt";
+
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test"]);
+
+      expect(resolveGlobalOptions(command).pruneFields).toEqual(["deletedAt"]);
+    });
+
+    it("parses --mask-fields and falls back to TWENTY_MASK_FIELDS", () => {
+      process.env.TWENTY_MASK_FIELDS = "phone";
+
+      const flagged = new Command("test");
+      applyGlobalOptions(flagged);
+      flagged.parse(["node", "test", "--mask-fields", "email, phone"]);
+      const fromEnv = new Command("test");
+      applyGlobalOptions(fromEnv);
+      fromEnv.parse(["node", "test"]);
+
+      expect(resolveGlobalOptions(flagged).maskFields).toEqual(["email", "phone"]);
+      expect(resolveGlobalOptions(fromEnv).maskFields).toEqual(["phone"]);
+    });
+
     it("reads query from command option", () => {
       const command = new Command("test");
       applyGlobalOptions(command);
@@ -558,6 +801,48 @@ describe("global-options utilities", () => {
       expect(options.workspace).toBe("staging");
     });
 
+    it("reads workspace from --profile alias", () => {
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test", "--profile", "staging"]);
+
+      const options = resolveGlobalOptions(command);
+      expect(options.workspace).toBe("staging");
+    });
+
+    it("reads --profile from the root program for chained subcommands", () => {
+      process.env.TWENTY_PROFILE = "from-env";
+
+      const root = new Command("twenty");
+      root.option("--profile <name>");
+      const list = root.command("list");
+      applyGlobalOptions(list);
+      root.parse(["node", "twenty", "--profile", "staging", "list"]);
+
+      const options = resolveGlobalOptions(list);
+      expect(options.workspace).toBe("staging");
+    });
+
+    it("prefers the profile flag over TWENTY_PROFILE", () => {
+      process.env.TWENTY_PROFILE = "from-env";
+
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test", "--profile", "from-flag"]);
+
+      const options = resolveGlobalOptions(command);
+      expect(options.workspace).toBe("from-flag");
+    });
+
+    it("leaves workspace unset so the stored default applies", () => {
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test"]);
+
+      const options = resolveGlobalOptions(command);
+      expect(options.workspace).toBeUndefined();
+    });
+
     it("reads debug from command option", () => {
       const command = new Command("test");
       applyGlobalOptions(command);
@@ -607,6 +892,26 @@ describe("global-options utilities", () => {
       expect(options.noRetry).toBe(true);
     });
 
+    it("lets a command force noRetry through overrides", () => {
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test"]);
+
+      expect(resolveGlobalOptions(command).noRetry).toBe(false);
+      expect(resolveGlobalOptions(command, { noRetry: true }).noRetry).toBe(true);
+    });
+
+    it("reads --no-retry from the root program for chained subcommands", () => {
+      const root = new Command("twenty");
+      root.option("--no-retry");
+      const list = root.command("list");
+      applyGlobalOptions(list);
+      root.parse(["node", "twenty", "--no-retry", "list"]);
+
+      const options = resolveGlobalOptions(list);
+      expect(options.noRetry).toBe(true);
+    });
+
     it("defaults noRetry to false", () => {
       const command = new Command("test");
       applyGlobalOptions(command);
@@ -616,6 +921,360 @@ describe("global-options utilities", () => {
       expect(options.noRetry).toBe(false);
     });
 
+    it("reads retry tuning from flags", () => {
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test", "--max-retries", "5", "--retry-base-delay", "250"]);
+
+      const options = resolveGlobalOptions(command);
+      expect(options.maxRetries).toBe(5);
+      expect(options.retryBaseDelay).toBe(250);
+    });
+
+    it("reads retry tuning from TWENTY_MAX_RETRIES and TWENTY_RETRY_BASE_DELAY", () => {
+      process.env.TWENTY_MAX_RETRIES = "0";
+      process.env.TWENTY_RETRY_BASE_DELAY = "50";
+
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test"]);
+
+      const options = resolveGlobalOptions(command);
+      expect(options.maxRetries).toBe(0);
+      expect(options.retryBaseDelay).toBe(50);
+    });
+
+    it("falls back to the config's retry settings after flags and env", () => {
+      vi.mocked(readConfiguredRetries).mockReturnValue({ maxRetries: "7", retryBaseDelay: "300" });
+      process.env.TWENTY_RETRY_BASE_DELAY = "50";
+
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test"]);
+      expect(resolveGlobalOptions(command)).toMatchObject({ maxRetries: 7, retryBaseDelay: 50 });
+
+      const explicit = new Command("test");
+      applyGlobalOptions(explicit);
+      explicit.parse(["node", "test", "--max-retries", "1"]);
+      expect(resolveGlobalOptions(explicit).maxRetries).toBe(1);
+
+      vi.mocked(readConfiguredRetries).mockReturnValue({ maxRetries: "-1" });
+      expect(() => resolveGlobalOptions(command)).toThrow(
+        'Invalid config maxRetries value "-1"; expected a non-negative integer.',
+      );
+      vi.mocked(readConfiguredRetries).mockReset();
+    });
+
+    it("resolves --backoff from the flag or TWENTY_BACKOFF and rejects unknown values", () => {
+      process.env.TWENTY_BACKOFF = "constant";
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test"]);
+      expect(resolveGlobalOptions(command).backoff).toBe("constant");
+
+      const flag = new Command("test");
+      applyGlobalOptions(flag);
+      flag.parse(["node", "test", "--backoff", "Linear"]);
+      expect(resolveGlobalOptions(flag).backoff).toBe("linear");
+
+      const invalid = new Command("test");
+      applyGlobalOptions(invalid);
+      invalid.parse(["node", "test", "--backoff", "fibonacci"]);
+      expect(() => resolveGlobalOptions(invalid)).toThrow(
+        'Invalid --backoff value "fibonacci"; expected one of: exponential, linear, constant.',
+      );
+    });
+
+    it("rejects negative or non-integer retry tuning", () => {
+      for (const args of [
+        ["--max-retries", "-1"],
+        ["--max-retries", "two"],
+        ["--retry-base-delay", "1.5"],
+      ]) {
+        const command = new Command("test");
+        applyGlobalOptions(command);
+        command.parse(["node", "test", ...args]);
+
+        expect(() => resolveGlobalOptions(command)).toThrow(/expected a non-negative integer/);
+      }
+    });
+
+    it("compiles --retry-body-match and rejects invalid patterns", () => {
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test", "--retry-body-match", "deadlock detected"]);
+
+      expect(resolveGlobalOptions(command).retryBodyMatch).toEqual(/deadlock detected/);
+
+      const invalid = new Command("test");
+      applyGlobalOptions(invalid);
+      invalid.parse(["node", "test", "--retry-body-match", "("]);
+
+      expect(() => resolveGlobalOptions(invalid)).toThrow(/expected a regular expression/);
+    });
+
+    it("parses --retry-status-codes and rejects non-error statuses", () => {
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test", "--retry-status-codes", "409, 500"]);
+
+      expect(resolveGlobalOptions(command).retryStatusCodes).toEqual([409, 500]);
+
+      const invalid = new Command("test");
+      applyGlobalOptions(invalid);
+      invalid.parse(["node", "test", "--retry-status-codes", "200"]);
+
+      expect(() => resolveGlobalOptions(invalid)).toThrow(/expected comma-separated 4xx or 5xx/);
+    });
+
+    it("reads --workspace-id or TWENTY_WORKSPACE_ID and rejects non-UUIDs", () => {
+      const workspaceId = "3b8e6458-5fc1-4e63-8563-008ccddaa6db";
+      process.env.TWENTY_WORKSPACE_ID = "00000000-0000-4000-8000-000000000000";
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test", "--workspace-id", workspaceId.toUpperCase()]);
+
+      expect(resolveGlobalOptions(command).workspaceId).toBe(workspaceId);
+
+      const fromEnv = new Command("test");
+      applyGlobalOptions(fromEnv);
+      fromEnv.parse(["node", "test"]);
+
+      expect(resolveGlobalOptions(fromEnv).workspaceId).toBe(
+        "00000000-0000-4000-8000-000000000000",
+      );
+
+      const invalid = new Command("test");
+      applyGlobalOptions(invalid);
+      invalid.parse(["node", "test", "--workspace-id", "acme"]);
+
+      expect(() => resolveGlobalOptions(invalid)).toThrow(
+        'Invalid workspace ID "acme" from --workspace-id; expected a UUID.',
+      );
+    });
+
+    it("keeps a command's own flag when a global option has the same name", () => {
+      const command = new Command("test").option("--workspace-id <id>", "Webhook workspace");
+      applyGlobalOptions(command);
+
+      expect(command.options.filter((option) => option.long === "--workspace-id")).toHaveLength(1);
+    });
+
+    it("parses --retry-on-network-error=false and rejects non-boolean values", () => {
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test", "--retry-on-network-error=false"]);
+
+      expect(resolveGlobalOptions(command).retryNetworkErrors).toBe(false);
+
+      const invalid = new Command("test");
+      applyGlobalOptions(invalid);
+      invalid.parse(["node", "test", "--retry-on-network-error", "sometimes"]);
+
+      expect(() => resolveGlobalOptions(invalid)).toThrow(/expected true or false/);
+    });
+
+    it("allows insecure http from the flag or TWENTY_INSECURE_ALLOW_HTTP", () => {
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test"]);
+      expect(resolveGlobalOptions(command).allowInsecureHttp).toBe(false);
+
+      process.env.TWENTY_INSECURE_ALLOW_HTTP = "true";
+      expect(resolveGlobalOptions(command).allowInsecureHttp).toBe(true);
+
+      delete process.env.TWENTY_INSECURE_ALLOW_HTTP;
+      const flag = new Command("test");
+      applyGlobalOptions(flag);
+      flag.parse(["node", "test", "--insecure-allow-http"]);
+      expect(resolveGlobalOptions(flag).allowInsecureHttp).toBe(true);
+    });
+
+    it("enables strict JSON from the flag or TWENTY_STRICT_JSON", () => {
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test"]);
+      expect(resolveGlobalOptions(command).strictJson).toBe(false);
+
+      process.env.TWENTY_STRICT_JSON = "1";
+      expect(resolveGlobalOptions(command).strictJson).toBe(true);
+
+      delete process.env.TWENTY_STRICT_JSON;
+      const flag = new Command("test");
+      applyGlobalOptions(flag);
+      flag.parse(["node", "test", "--strict-json"]);
+      expect(resolveGlobalOptions(flag).strictJson).toBe(true);
+    });
+
+    it("parses --connection or TWENTY_CONNECTION without echoing the token", () => {
+      const workspaceId = "1b4e28ba-2fa1-11d2-883f-0016d3cca427";
+      process.env.TWENTY_CONNECTION = "twenty+http://env-token@localhost:3000";
+      const env = new Command("test");
+      applyGlobalOptions(env);
+      env.parse(["node", "test"]);
+      expect(resolveGlobalOptions(env).connection).toEqual({
+        apiUrl: "http://localhost:3000",
+        apiKey: "env-token",
+        fromEnv: true,
+      });
+
+      const flag = new Command("test");
+      applyGlobalOptions(flag);
+      flag.parse(["node", "test", "--connection", `twenty://a%2Fb@crm.example.com/${workspaceId}`]);
+      expect(resolveGlobalOptions(flag).connection).toEqual({
+        apiUrl: "https://crm.example.com",
+        apiKey: "a/b",
+        workspaceId,
+      });
+
+      const invalid = new Command("test");
+      applyGlobalOptions(invalid);
+      invalid.parse(["node", "test", "--connection", "https://secret-token@crm.example.com"]);
+      let message = "";
+      try {
+        resolveGlobalOptions(invalid);
+      } catch (error) {
+        message = (error as Error).message;
+      }
+      expect(message).toBe(
+        "Invalid connection string from --connection: use the twenty:// or twenty+http:// scheme.",
+      );
+    });
+
+    it("enables request compression from the flag or TWENTY_COMPRESS_REQUESTS", () => {
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test"]);
+      expect(resolveGlobalOptions(command).compressRequests).toBe(false);
+
+      process.env.TWENTY_COMPRESS_REQUESTS = "true";
+      expect(resolveGlobalOptions(command).compressRequests).toBe(true);
+
+      delete process.env.TWENTY_COMPRESS_REQUESTS;
+      const flag = new Command("test");
+      applyGlobalOptions(flag);
+      flag.parse(["node", "test", "--compress-requests"]);
+      expect(resolveGlobalOptions(flag).compressRequests).toBe(true);
+    });
+
+    it("resolves --log-format from the flag or env and rejects unknown values", () => {
+      process.env.TWENTY_LOG_FORMAT = "json";
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test"]);
+      expect(resolveGlobalOptions(command).logFormat).toBe("json");
+
+      const flag = new Command("test");
+      applyGlobalOptions(flag);
+      flag.parse(["node", "test", "--log-format", "text"]);
+      expect(resolveGlobalOptions(flag).logFormat).toBe("text");
+
+      const invalid = new Command("test");
+      applyGlobalOptions(invalid);
+      invalid.parse(["node", "test", "--log-format", "xml"]);
+      expect(() => resolveGlobalOptions(invalid)).toThrow(
+        'Invalid --log-format value "xml"; expected one of: text, json.',
+      );
+    });
+
+    it("enables the versioned envelope from the flag or TWENTY_ENVELOPE", () => {
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test"]);
+      expect(resolveGlobalOptions(command).envelope).toBe(false);
+
+      process.env.TWENTY_ENVELOPE = "true";
+      expect(resolveGlobalOptions(command).envelope).toBe(true);
+
+      delete process.env.TWENTY_ENVELOPE;
+      const flag = new Command("test");
+      applyGlobalOptions(flag);
+      flag.parse(["node", "test", "--envelope"]);
+      expect(resolveGlobalOptions(flag).envelope).toBe(true);
+    });
+
+    it("enables sorted JSON keys from the flag or TWENTY_SORT_KEYS", () => {
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test"]);
+      expect(resolveGlobalOptions(command).sortKeys).toBe(false);
+
+      process.env.TWENTY_SORT_KEYS = "true";
+      expect(resolveGlobalOptions(command).sortKeys).toBe(true);
+
+      delete process.env.TWENTY_SORT_KEYS;
+      const flag = new Command("test");
+      applyGlobalOptions(flag);
+      flag.parse(["node", "test", "--sort-keys"]);
+      expect(resolveGlobalOptions(flag).sortKeys).toBe(true);
+    });
+
+    it("resolves --pointer and --raw-output but rejects --pointer with --query", () => {
+      const command = new Command("test");
+      applyGlobalOptions(command);
+      command.parse(["node", "test", "--pointer", "/data/0/id", "--raw-output"]);
+
+      expect(resolveGlobalOptions(command)).toMatchObject({
+        pointer: "/data/0/id",
+        rawOutput: true,
+      });
+
+      const both = new Command("test");
+      applyGlobalOptions(both);
+      both.parse(["node", "test", "--pointer", "/data", "--query", "data"]);
+
+      expect(() => resolveGlobalOptions(both)).toThrow("Use only one of --query or --pointer.");
+    });
+
+    it("marks output as explicit only when a format was requested", () => {
+      const implicit = new Command("test");
+      applyGlobalOptions(implicit);
+      implicit.parse(["node", "test"]);
+      expect(resolveGlobalOptions(implicit)).toMatchObject({ output: "json", outputExplicit: false });
+
+      const flag = new Command("test");
+      applyGlobalOptions(flag);
+      flag.parse(["node", "test", "-o", "json"]);
+      expect(resolveGlobalOptions(flag).outputExplicit).toBe(true);
+
+      process.env.TWENTY_OUTPUT = "jsonl";
+      const env = new Command("test");
+      applyGlobalOptions(env);
+      env.parse(["node", "test"]);
+      expect(resolveGlobalOptions(env)).toMatchObject({ output: "jsonl", outputExplicit: true });
+    });
+
+    it("parses --max-body-size with optional units", () => {
+      for (const [value, expected] of [
+        ["1048576", 1048576],
+        ["500KB", 500 * 1024],
+        ["200mb", 200 * 1024 * 1024],
+        ["1GB", 1024 ** 3],
+      ] as const) {
+        const command = new Command("test");
+        applyGlobalOptions(command);
+        command.parse(["node", "test", "--max-body-size", value]);
+
+        expect(resolveGlobalOptions(command).maxBodySize).toBe(expected);
+      }
+
+      process.env.TWENTY_MAX_BODY_SIZE = "2MB";
+      const fromEnv = new Command("test");
+      applyGlobalOptions(fromEnv);
+      fromEnv.parse(["node", "test"]);
+      expect(resolveGlobalOptions(fromEnv).maxBodySize).toBe(2 * 1024 * 1024);
+    });
+
+    it("rejects zero or unknown --max-body-size units", () => {
+      for (const value of ["0", "10TB", "lots"]) {
+        const command = new Command("test");
+        applyGlobalOptions(command);
+        command.parse(["node", "test", "--max-body-size", value]);
+
+        expect(() => resolveGlobalOptions(command)).toThrow(/expected a positive size/);
+      }
+    });
+
     it("derives an output kind from the command path", () => {
       const root = new Command("twenty");
       const auth = root.command("auth");
@@ -874,6 +1533,17 @@ describe("services factory", () => {
       );
     });
 
+    it("passes noRetry option to PublicHttpService", async () => {
+      const { PublicHttpService } = await import("../../api/services/public-http.service");
+
+      createServices({ noRetry: true });
+
+      expect(PublicHttpService).toHaveBeenCalledWith(
+        expect.anything(),
+        expect.objectContaining({ noRetry: true }),
+      );
+    });
+
     it("creates services with default options", () => {
       const globalOptions: GlobalOptions = {};
 
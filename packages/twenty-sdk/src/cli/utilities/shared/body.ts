@@ -33,3 +33,24 @@ export async function parseArrayPayload(data?: string, filePath?: string): Promi
   }
   return payload;
 }
+
+export const FORM_CONTENT_TYPE = "application/x-www-form-urlencoded";
+
+// Form bodies have no nesting, so only flat objects of scalar values encode.
+export function encodeFormBody(payload: unknown): string {
+  if (payload == null) {
+    return "";
+  }
+  if (typeof payload !== "object" || Array.isArray(payload)) {
+    throw new Error("Form payload must be a flat JSON object");
+  }
+
+  const form = new URLSearchParams();
+  for (const [key, value] of Object.entries(payload)) {
+    if (typeof value !== "string" && typeof value !== "number" && typeof value !== "boolean") {
+      throw new Error(`Form payload field "${key}" must be a string, number, or boolean`);
+    }
+    form.append(key, String(value));
+  }
+  return form.toString();
+}
@@ -0,0 +1,19 @@
+import { spawn } from "node:child_process";
+import { log } from "./logger";
+
+// Best effort: the caller has already written what it wants to show, so a
+// missing opener only prints a hint instead of failing the command.
+export function openInBrowser(target: string): void {
+  const [command, args] =
+    process.platform === "darwin"
+      ? ["open", [target]]
+      : process.platform === "win32"
+        ? ["cmd", ["/c", "start", "", target]]
+        : ["xdg-open", [target]];
+
+  const child = spawn(command, args, { detached: true, stdio: "ignore" });
+  child.on("error", () => {
+    log("warn", `Could not open a browser; open ${target} manually.`, { url: target });
+  });
+  child.unref();
+}
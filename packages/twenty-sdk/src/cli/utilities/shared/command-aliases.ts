@@ -15,10 +15,13 @@ export const ROOT_COMMAND_ALIASES: Readonly<Record<string, readonly string[]>> =
   "event-logs": ["ev"],
   files: ["f"],
   graphql: ["gql"],
+  jobs: ["jb"],
   "marketplace-apps": ["mp"],
   "message-channels": ["mc"],
   metadata: ["md"],
   openapi: ["oa"],
+  opportunities: ["opp"],
+  people: ["ppl"],
   "postgres-proxy": ["pgp"],
   "public-domains": ["pd"],
   raw: ["rw"],
@@ -1,3 +1,4 @@
+import readline from "node:readline/promises";
 import { CliError } from "../errors/cli-error";
 
 export function requireYes(options: { yes?: boolean }, action: string): void {
@@ -9,3 +10,41 @@ export function requireYes(options: { yes?: boolean }, action: string): void {
     );
   }
 }
+
+// Asks a yes/no question on stderr. Without a terminal there is nobody to
+// answer, so the question is skipped and treated as "no".
+export async function confirmPrompt(question: string): Promise<boolean> {
+  if (!process.stdin.isTTY) {
+    return false;
+  }
+
+  const rl = readline.createInterface({ input: process.stdin, output: process.stderr });
+  try {
+    const answer = await rl.question(`${question} [y/N] `);
+    return /^y(es)?$/i.test(answer.trim());
+  } finally {
+    rl.close();
+  }
+}
+
+// Lists choices on stderr and reads a number. Like confirmPrompt, it needs a
+// terminal; without one, or on an answer that is not a listed number, it
+// resolves to undefined so callers keep their non-interactive behavior.
+export async function selectPrompt(
+  question: string,
+  choices: readonly string[],
+): Promise<string | undefined> {
+  if (!process.stdin.isTTY || choices.length === 0) {
+    return undefined;
+  }
+
+  const rl = readline.createInterface({ input: process.stdin, output: process.stderr });
+  try {
+    const lines = choices.map((choice, index) => `  ${index + 1}) ${choice}`);
+    const answer = await rl.question(`${question}\n${lines.join("\n")}\n> `);
+    const index = Number.parseInt(answer.trim(), 10);
+    return Number.isInteger(index) ? choices[index - 1] : undefined;
+  } finally {
+    rl.close();
+  }
+}
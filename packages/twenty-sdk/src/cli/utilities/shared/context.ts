@@ -2,6 +2,7 @@ import { Command } from "commander";
 import { GlobalOptions, resolveGlobalOptions } from "./global-options";
 import { CliServices, createOutputService, createServices } from "./services";
 import { OutputService } from "../output/services/output.service";
+import { OutputWriter } from "../output/services/output-writer";
 
 export interface CommandContext {
   globalOptions: GlobalOptions;
@@ -16,9 +17,10 @@ export interface OutputContext {
 export function createCommandContext(
   command: Command,
   overrides?: Parameters<typeof resolveGlobalOptions>[1],
+  writer?: OutputWriter,
 ): CommandContext {
   const globalOptions = resolveGlobalOptions(command, overrides);
-  const services = createServices(globalOptions);
+  const services = createServices(globalOptions, writer);
 
   return {
     globalOptions,
@@ -29,11 +31,12 @@ export function createCommandContext(
 export function createOutputContext(
   command: Command,
   overrides?: Parameters<typeof resolveGlobalOptions>[1],
+  writer?: OutputWriter,
 ): OutputContext {
   const globalOptions = resolveGlobalOptions(command, overrides);
 
   return {
     globalOptions,
-    output: createOutputService(globalOptions),
+    output: createOutputService(globalOptions, writer),
   };
 }
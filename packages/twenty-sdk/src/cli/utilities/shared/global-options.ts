@@ -1,16 +1,68 @@
 import { Command } from "commander";
+import { BACKOFF_STRATEGIES, BackoffStrategy } from "../api/services/api.service";
 import { loadCliEnvironment } from "../config/services/environment.service";
+import {
+  ConfiguredOutput,
+  readConfiguredOutput,
+  readConfiguredQuery,
+  readConfiguredRetries,
+} from "../config/services/output-defaults";
+import { ConnectionSettings, parseConnectionString } from "../config/connection-string";
+import { normalizeWorkspaceId } from "../config/workspace-id";
+import type { CsvNumberFormat } from "../output/services/csv-writer";
 import { CliError } from "../errors/cli-error";
-import { parseBooleanEnv } from "./parse";
+import { LOG_FORMATS, LogFormat } from "./logger";
+import { parseBooleanEnv, parseFieldList, parseNonNegativeIntegerOption } from "./parse";
 
-export type OutputFormat = "json" | "jsonl" | "csv" | "text";
+export type OutputFormat = "json" | "jsonl" | "csv" | "text" | "table" | "html";
+
+// Number.prototype.toFixed accepts at most 100 digits.
+const MAX_FLOAT_PRECISION = 100;
 
 export interface GlobalOptions {
   output?: OutputFormat;
+  // True when the format came from --output, TWENTY_OUTPUT, or agent mode
+  // rather than the implicit json default.
+  outputExplicit?: boolean;
   query?: string;
+  pointer?: string;
+  rawOutput?: boolean;
+  print0?: boolean;
+  pruneFields?: string[];
+  maskFields?: string[];
+  unwrap?: boolean;
+  csvQuoteAll?: boolean;
+  csvBom?: boolean;
+  csvNumbers?: CsvNumberFormat;
+  totals?: boolean;
+  keyColumn?: string;
+  envelope?: boolean;
+  sortKeys?: boolean;
+  textTemplate?: string;
   workspace?: string;
+  selectProfile?: boolean;
+  workspaceId?: string;
   debug?: boolean;
+  verbose?: boolean;
+  logFormat?: LogFormat;
   noRetry?: boolean;
+  maxRetries?: number;
+  retryBaseDelay?: number;
+  backoff?: BackoffStrategy;
+  retryBodyMatch?: RegExp;
+  retryStatusCodes?: number[];
+  retryNetworkErrors?: boolean;
+  retryUnsafe?: boolean;
+  abortOnRateLimit?: boolean;
+  maxBodySize?: number;
+  allowInsecureHttp?: boolean;
+  strictJson?: boolean;
+  showRetryStats?: boolean;
+  etagCache?: boolean;
+  compressRequests?: boolean;
+  retryAttemptHeader?: boolean;
+  tokenFile?: string;
+  connection?: ConnectionSettings;
   envFile?: string;
   outputKind?: string;
   light?: boolean;
@@ -33,7 +85,7 @@ const GLOBAL_OPTION_DEFINITIONS: GlobalOptionDefinition[] = [
   {
     name: "output",
     flags: "-o, --output <format>",
-    description: "Output format: json, jsonl, csv, text",
+    description: "Output format: json, jsonl, csv, text, table, html",
     takesValue: true,
   },
   {
@@ -42,30 +94,252 @@ const GLOBAL_OPTION_DEFINITIONS: GlobalOptionDefinition[] = [
     description: "JMESPath query filter",
     takesValue: true,
   },
+  {
+    name: "pointer",
+    flags: "--pointer <pointer>",
+    description: "Select one value by RFC 6901 JSON Pointer, e.g. /data/0/id",
+    takesValue: true,
+  },
+  {
+    name: "raw-output",
+    flags: "--raw-output",
+    description: "Print string results without JSON quotes",
+    takesValue: false,
+  },
+  {
+    name: "print0",
+    flags: "--print0",
+    description: "End json/jsonl values and --text-template lines with NUL (for xargs -0)",
+    takesValue: false,
+  },
+  {
+    name: "prune-fields",
+    flags: "--prune-fields <keys>",
+    description: "Comma-separated top-level keys to drop from each record",
+    takesValue: true,
+  },
+  {
+    name: "mask-fields",
+    flags: "--mask-fields <keys>",
+    description: "Comma-separated top-level keys whose values are masked, e.g. email,phone",
+    takesValue: true,
+  },
+  {
+    name: "unwrap",
+    flags: "--unwrap",
+    description: "Strip the {data: ...} response envelope before output",
+    takesValue: false,
+  },
+  {
+    name: "csv-quote-all",
+    flags: "--csv-quote-all",
+    description: "Quote every CSV field, not only those that need it",
+    takesValue: false,
+  },
+  {
+    name: "bom",
+    flags: "--bom",
+    description: "Prepend a UTF-8 byte-order mark to CSV output for Excel",
+    takesValue: false,
+  },
+  {
+    name: "float-precision",
+    flags: "--float-precision <digits>",
+    description: "Write non-integer CSV numbers with this many decimal places",
+    takesValue: true,
+  },
+  {
+    name: "plain-numbers",
+    flags: "--plain-numbers",
+    description: "Write CSV numbers without scientific notation, e.g. 0.0000001",
+    takesValue: false,
+  },
+  {
+    name: "totals",
+    flags: "--totals",
+    description: "Append a footer row summing numeric columns to table output",
+    takesValue: false,
+  },
+  {
+    name: "key-column",
+    flags: "--key-column <field>",
+    description: "Show this column first in table and text output",
+    takesValue: true,
+  },
+  {
+    name: "envelope",
+    flags: "--envelope",
+    description: 'Wrap list JSON as {"apiVersion","kind","items"} for versioned pipelines',
+    takesValue: false,
+  },
+  {
+    name: "sort-keys",
+    flags: "--sort-keys",
+    description: "Sort object keys alphabetically in JSON output and exports",
+    takesValue: false,
+  },
+  {
+    name: "text-template",
+    flags: "--text-template <template>",
+    description: "Render each record in text output through a template, e.g. '{{.name}}'",
+    takesValue: true,
+  },
   {
     name: "workspace",
     flags: "--workspace <name>",
     description: "Workspace profile to use",
     takesValue: true,
   },
+  {
+    name: "profile",
+    flags: "--profile <name>",
+    description: "Alias for --workspace",
+    takesValue: true,
+  },
+  {
+    name: "workspace-id",
+    flags: "--workspace-id <id>",
+    description: "Workspace UUID sent with every request, for instances serving several",
+    takesValue: true,
+  },
+  {
+    name: "select-profile",
+    flags: "--select-profile",
+    description: "Pick the profile from a list of configured profiles (needs a terminal)",
+    takesValue: false,
+  },
   {
     name: "env-file",
     flags: "--env-file <path>",
     description: "Load environment variables from file",
     takesValue: true,
   },
+  {
+    name: "token-file",
+    flags: "--token-file <path>",
+    description: "Read the API token from a file",
+    takesValue: true,
+  },
+  {
+    name: "connection",
+    flags: "--connection <url>",
+    description: "Base URL, token, and workspace ID as twenty://<token>@<host>[/<workspace-id>]",
+    takesValue: true,
+  },
   {
     name: "debug",
     flags: "--debug",
     description: "Show request/response details",
     takesValue: false,
   },
+  {
+    name: "verbose",
+    flags: "-v, --verbose",
+    description: "Log progress (requests, pages, retries) to stderr without bodies",
+    takesValue: false,
+  },
+  {
+    name: "log-format",
+    flags: "--log-format <format>",
+    description: "stderr log format: text (default) or json, one object per line",
+    takesValue: true,
+  },
   {
     name: "no-retry",
     flags: "--no-retry",
     description: "Disable automatic retry",
     takesValue: false,
   },
+  {
+    name: "max-retries",
+    flags: "--max-retries <count>",
+    description: "Retries after the first attempt (total attempts = 1 + count)",
+    takesValue: true,
+  },
+  {
+    name: "retry-base-delay",
+    flags: "--retry-base-delay <ms>",
+    description: "Base delay in milliseconds for retry backoff",
+    takesValue: true,
+  },
+  {
+    name: "backoff",
+    flags: "--backoff <strategy>",
+    description: "Retry delay growth: exponential (default), linear, or constant",
+    takesValue: true,
+  },
+  {
+    name: "retry-body-match",
+    flags: "--retry-body-match <regex>",
+    description: "Also retry error responses whose body matches this regex",
+    takesValue: true,
+  },
+  {
+    name: "retry-status-codes",
+    flags: "--retry-status-codes <codes>",
+    description: "Also retry these HTTP statuses, e.g. 409 (comma-separated)",
+    takesValue: true,
+  },
+  {
+    name: "retry-on-network-error",
+    flags: "--retry-on-network-error <bool>",
+    description: "Retry resets and timeouts of idempotent requests (default true)",
+    takesValue: true,
+  },
+  {
+    name: "retry-unsafe",
+    flags: "--retry-unsafe",
+    description: "Also retry POSTs without --idempotency-key after resets and timeouts",
+    takesValue: false,
+  },
+  {
+    name: "abort-on-rate-limit",
+    flags: "--abort-on-rate-limit",
+    description: "Fail on the first 429 instead of waiting; other retries still apply",
+    takesValue: false,
+  },
+  {
+    name: "max-body-size",
+    flags: "--max-body-size <size>",
+    description: "Largest response body to accept, e.g. 500MB (default 100MB)",
+    takesValue: true,
+  },
+  {
+    name: "insecure-allow-http",
+    flags: "--insecure-allow-http",
+    description: "Allow plaintext http:// base URLs for hosts other than localhost",
+    takesValue: false,
+  },
+  {
+    name: "strict-json",
+    flags: "--strict-json",
+    description: "Reject JSON payloads and import files with duplicate object keys",
+    takesValue: false,
+  },
+  {
+    name: "show-retry-stats",
+    flags: "--show-retry-stats",
+    description: "Print a one-line retry summary (count, time waited) to stderr at exit",
+    takesValue: false,
+  },
+  {
+    name: "etag-cache",
+    flags: "--etag-cache",
+    description: "Revalidate repeated GETs with If-None-Match and reuse cached bodies on 304",
+    takesValue: false,
+  },
+  {
+    name: "compress-requests",
+    flags: "--compress-requests",
+    description: "Gzip JSON request bodies of 1KB or more; resent uncompressed after a 415",
+    takesValue: false,
+  },
+  {
+    name: "retries-show-attempt-header",
+    flags: "--retries-show-attempt-header",
+    description: "Send X-Retry-Attempt: N (1, 2, ...) on each attempt to match server logs",
+    takesValue: false,
+  },
   {
     name: "light",
     flags: "--light",
@@ -126,6 +400,11 @@ export function applyGlobalOptions(command: Command, settings: GlobalOptionSetti
     if (definition.name === "query" && settings.includeQuery === false) {
       continue;
     }
+    // A command's own flag of the same name (e.g. workflows invoke-webhook
+    // --workspace-id) is kept; it is read the same way as the global one.
+    if (command.options.some((option) => option.long === `--${definition.name}`)) {
+      continue;
+    }
 
     command.option(definition.flags, definition.description);
   }
@@ -133,7 +412,7 @@ export function applyGlobalOptions(command: Command, settings: GlobalOptionSetti
 
 export function resolveGlobalOptions(
   command: Command,
-  overrides?: { outputQuery?: string },
+  overrides?: { outputQuery?: string; output?: OutputFormat; noRetry?: boolean },
 ): GlobalOptions {
   const opts = getCommandOptions(command);
   const envFile = typeof opts.envFile === "string" ? opts.envFile : undefined;
@@ -145,12 +424,24 @@ export function resolveGlobalOptions(
   });
 
   const agentMode = Boolean(opts.agentMode || opts.ai || parseBooleanEnv(process.env.TWENTY_AGENT));
-  const rawOutput =
-    typeof opts.output === "string" ? opts.output : (process.env.TWENTY_OUTPUT ?? "json");
-  let output = parseOutputFormat(rawOutput);
+  const requestedOutput =
+    typeof opts.output === "string" ? opts.output : process.env.TWENTY_OUTPUT || undefined;
+  // Output precedence: --output, TWENTY_OUTPUT, the command's entry in the
+  // config file's "output" settings, the config's default, then json.
+  const configuredOutput =
+    requestedOutput === undefined && overrides?.output === undefined && !agentMode
+      ? readConfiguredOutput(deriveCommandKind(command).split(".").slice(1))
+      : undefined;
+  let output =
+    overrides?.output ??
+    (configuredOutput
+      ? parseConfiguredOutputFormat(configuredOutput)
+      : parseOutputFormat(requestedOutput ?? "json"));
   if (agentMode) {
     output = "json";
   }
+  const outputExplicit =
+    agentMode || requestedOutput !== undefined || configuredOutput !== undefined;
   const full = Boolean(opts.full);
   const explicitLight = Boolean(opts.light || opts.li);
   if (explicitLight && full) {
@@ -158,27 +449,175 @@ export function resolveGlobalOptions(
   }
   const defaultsToLight = output === "json" || output === "jsonl";
   const light = full ? false : explicitLight || defaultsToLight;
+  const pointer = typeof opts.pointer === "string" ? opts.pointer : undefined;
+  // --query '' (or TWENTY_QUERY='') turns off the config's defaultQuery.
   const query =
     overrides?.outputQuery ??
     (typeof opts.query === "string" ? opts.query : undefined) ??
     process.env.TWENTY_QUERY ??
-    undefined;
-  const workspace =
-    typeof opts.workspace === "string" ? opts.workspace : process.env.TWENTY_PROFILE;
+    (pointer === undefined ? readConfiguredQuery() : undefined);
+  if (pointer !== undefined && query) {
+    throw new CliError("Use only one of --query or --pointer.", "INVALID_ARGUMENTS");
+  }
+  const rawOutput = Boolean(opts.rawOutput);
+  const print0 = Boolean(opts.print0);
+  const pruneFields = parseFieldList(
+    typeof opts.pruneFields === "string" ? opts.pruneFields : process.env.TWENTY_PRUNE_FIELDS,
+  );
+  const maskFields = parseFieldList(
+    typeof opts.maskFields === "string" ? opts.maskFields : process.env.TWENTY_MASK_FIELDS,
+  );
+  const unwrap = Boolean(opts.unwrap);
+  const csvQuoteAll = Boolean(opts.csvQuoteAll);
+  const csvBom = Boolean(opts.bom);
+  const csvNumbers = resolveCsvNumberFormat(opts);
+  const totals = Boolean(opts.totals);
+  const keyColumn =
+    typeof opts.keyColumn === "string" && opts.keyColumn.trim() !== ""
+      ? opts.keyColumn.trim()
+      : undefined;
+  const envelope =
+    opts.envelope === true || (parseBooleanEnv(process.env.TWENTY_ENVELOPE) ?? false);
+  const sortKeys =
+    opts.sortKeys === true || (parseBooleanEnv(process.env.TWENTY_SORT_KEYS) ?? false);
+  const textTemplate =
+    typeof opts.textTemplate === "string"
+      ? opts.textTemplate
+      : process.env.TWENTY_TEXT_TEMPLATE || undefined;
+  const workspace = resolveWorkspaceOption(opts);
+  const selectProfile = opts.selectProfile === true;
+  const workspaceIdOption =
+    typeof opts.workspaceId === "string"
+      ? opts.workspaceId
+      : process.env.TWENTY_WORKSPACE_ID || undefined;
+  const workspaceId =
+    workspaceIdOption === undefined
+      ? undefined
+      : normalizeWorkspaceId(workspaceIdOption, "--workspace-id");
+  // TWENTY_TOKEN_FILE is read by ConfigService so it ranks below TWENTY_TOKEN.
+  const tokenFile = typeof opts.tokenFile === "string" ? opts.tokenFile : undefined;
+  const connection = resolveConnectionOption(opts);
   const debug =
     typeof opts.debug === "boolean"
       ? opts.debug
       : (parseBooleanEnv(process.env.TWENTY_DEBUG) ?? false);
+  const verbose =
+    typeof opts.verbose === "boolean"
+      ? opts.verbose
+      : (parseBooleanEnv(process.env.TWENTY_VERBOSE) ?? false);
+  const logFormat = parseLogFormat(
+    typeof opts.logFormat === "string" ? opts.logFormat : process.env.TWENTY_LOG_FORMAT,
+  );
   const envNoRetry = parseBooleanEnv(process.env.TWENTY_NO_RETRY) ?? false;
   const retry = typeof opts.retry === "boolean" ? opts.retry : undefined;
-  const noRetry = retry === false ? true : envNoRetry;
+  const noRetry = overrides?.noRetry === true || (retry === false ? true : envNoRetry);
+  // Retry precedence: the flag, its TWENTY_* variable, then the config file.
+  const requestedMaxRetries =
+    typeof opts.maxRetries === "string" ? opts.maxRetries : process.env.TWENTY_MAX_RETRIES;
+  const requestedRetryBaseDelay =
+    typeof opts.retryBaseDelay === "string"
+      ? opts.retryBaseDelay
+      : process.env.TWENTY_RETRY_BASE_DELAY;
+  const configuredRetries =
+    requestedMaxRetries === undefined || requestedRetryBaseDelay === undefined
+      ? readConfiguredRetries()
+      : undefined;
+  const maxRetries =
+    requestedMaxRetries !== undefined
+      ? parseNonNegativeIntegerOption("--max-retries", requestedMaxRetries)
+      : parseNonNegativeIntegerOption("config maxRetries", configuredRetries?.maxRetries);
+  const retryBaseDelay =
+    requestedRetryBaseDelay !== undefined
+      ? parseNonNegativeIntegerOption("--retry-base-delay", requestedRetryBaseDelay)
+      : parseNonNegativeIntegerOption("config retryBaseDelay", configuredRetries?.retryBaseDelay);
+  const backoff = parseBackoffStrategy(
+    typeof opts.backoff === "string" ? opts.backoff : process.env.TWENTY_BACKOFF,
+  );
+  const retryStatusCodes = parseStatusCodesOption(
+    "--retry-status-codes",
+    typeof opts.retryStatusCodes === "string"
+      ? opts.retryStatusCodes
+      : process.env.TWENTY_RETRY_STATUS_CODES,
+  );
+  const retryBodyMatch = parseRegexOption(
+    "--retry-body-match",
+    typeof opts.retryBodyMatch === "string"
+      ? opts.retryBodyMatch
+      : process.env.TWENTY_RETRY_BODY_MATCH,
+  );
+  const retryNetworkErrors = parseBooleanOption(
+    "--retry-on-network-error",
+    typeof opts.retryOnNetworkError === "string"
+      ? opts.retryOnNetworkError
+      : process.env.TWENTY_RETRY_ON_NETWORK_ERROR,
+  );
+  const retryUnsafe =
+    opts.retryUnsafe === true || (parseBooleanEnv(process.env.TWENTY_RETRY_UNSAFE) ?? false);
+  const abortOnRateLimit =
+    opts.abortOnRateLimit === true ||
+    (parseBooleanEnv(process.env.TWENTY_ABORT_ON_RATE_LIMIT) ?? false);
+  const maxBodySize = parseByteSizeOption(
+    "--max-body-size",
+    typeof opts.maxBodySize === "string" ? opts.maxBodySize : process.env.TWENTY_MAX_BODY_SIZE,
+  );
+  const allowInsecureHttp =
+    opts.insecureAllowHttp === true ||
+    (parseBooleanEnv(process.env.TWENTY_INSECURE_ALLOW_HTTP) ?? false);
+  const strictJson =
+    opts.strictJson === true || (parseBooleanEnv(process.env.TWENTY_STRICT_JSON) ?? false);
+  const showRetryStats =
+    opts.showRetryStats === true || (parseBooleanEnv(process.env.TWENTY_SHOW_RETRY_STATS) ?? false);
+  const etagCache =
+    opts.etagCache === true || (parseBooleanEnv(process.env.TWENTY_ETAG_CACHE) ?? false);
+  const compressRequests =
+    opts.compressRequests === true ||
+    (parseBooleanEnv(process.env.TWENTY_COMPRESS_REQUESTS) ?? false);
+  const retryAttemptHeader =
+    opts.retriesShowAttemptHeader === true ||
+    (parseBooleanEnv(process.env.TWENTY_RETRIES_SHOW_ATTEMPT_HEADER) ?? false);
 
   return {
     output,
+    outputExplicit,
     query,
+    pointer,
+    rawOutput,
+    print0,
+    pruneFields,
+    maskFields,
+    unwrap,
+    csvQuoteAll,
+    csvBom,
+    csvNumbers,
+    totals,
+    keyColumn,
+    envelope,
+    sortKeys,
+    textTemplate,
     workspace,
+    selectProfile,
+    workspaceId,
     debug,
+    verbose,
+    logFormat,
     noRetry,
+    maxRetries,
+    retryBaseDelay,
+    backoff,
+    retryBodyMatch,
+    retryStatusCodes,
+    retryNetworkErrors,
+    retryUnsafe,
+    abortOnRateLimit,
+    maxBodySize,
+    allowInsecureHttp,
+    strictJson,
+    showRetryStats,
+    etagCache,
+    compressRequests,
+    retryAttemptHeader,
+    tokenFile,
+    connection,
     envFile,
     outputKind: deriveCommandKind(command),
     light,
@@ -187,6 +626,29 @@ export function resolveGlobalOptions(
   };
 }
 
+function resolveConnectionOption(opts: Record<string, unknown>): ConnectionSettings | undefined {
+  if (typeof opts.connection === "string") {
+    return parseConnectionString(opts.connection, "--connection");
+  }
+  const fromEnv = process.env.TWENTY_CONNECTION;
+  return fromEnv
+    ? { ...parseConnectionString(fromEnv, "TWENTY_CONNECTION"), fromEnv: true }
+    : undefined;
+}
+
+// Profile precedence: --workspace/--profile flag, then TWENTY_PROFILE, then the
+// stored default written by "auth use" (resolved later by ConfigService).
+function resolveWorkspaceOption(opts: Record<string, unknown>): string | undefined {
+  if (typeof opts.workspace === "string") {
+    return opts.workspace;
+  }
+  if (typeof opts.profile === "string") {
+    return opts.profile;
+  }
+
+  return process.env.TWENTY_PROFILE || undefined;
+}
+
 function getCommandOptions(command: Command): Record<string, unknown> {
   const optsFn = (command as any).optsWithGlobals as undefined | (() => Record<string, unknown>);
   if (typeof optsFn === "function") {
@@ -195,6 +657,136 @@ function getCommandOptions(command: Command): Record<string, unknown> {
   return command.opts();
 }
 
+// Undefined when neither flag is set, so CSV numbers are written as before.
+function resolveCsvNumberFormat(opts: Record<string, unknown>): CsvNumberFormat | undefined {
+  const floatPrecision = parseNonNegativeIntegerOption(
+    "--float-precision",
+    typeof opts.floatPrecision === "string" ? opts.floatPrecision : undefined,
+  );
+  if (floatPrecision !== undefined && floatPrecision > MAX_FLOAT_PRECISION) {
+    throw new CliError(
+      `Invalid --float-precision value ${floatPrecision}; expected at most ${MAX_FLOAT_PRECISION}.`,
+      "INVALID_ARGUMENTS",
+    );
+  }
+  const plain = opts.plainNumbers === true;
+  if (floatPrecision === undefined && !plain) {
+    return undefined;
+  }
+  return { ...(floatPrecision !== undefined ? { floatPrecision } : {}), plain };
+}
+
+function parseBooleanOption(flag: string, value: string | undefined): boolean | undefined {
+  if (value === undefined || value.trim() === "") {
+    return undefined;
+  }
+
+  const parsed = parseBooleanEnv(value.trim());
+  if (parsed === undefined) {
+    throw new CliError(
+      `Invalid ${flag} value ${JSON.stringify(value)}; expected true or false.`,
+      "INVALID_ARGUMENTS",
+    );
+  }
+
+  return parsed;
+}
+
+const BYTE_SIZE_UNITS: Record<string, number> = {
+  "": 1,
+  B: 1,
+  KB: 1024,
+  MB: 1024 ** 2,
+  GB: 1024 ** 3,
+};
+
+export function parseByteSizeOption(flag: string, value: string | undefined): number | undefined {
+  if (value === undefined || value.trim() === "") {
+    return undefined;
+  }
+
+  const match = /^(\d+)\s*([a-z]*)$/i.exec(value.trim());
+  const multiplier = match ? BYTE_SIZE_UNITS[match[2]!.toUpperCase()] : undefined;
+  if (!match || multiplier === undefined || Number(match[1]) === 0) {
+    throw new CliError(
+      `Invalid ${flag} value ${JSON.stringify(value)}; expected a positive size such as 1048576, 500KB, 200MB, or 1GB.`,
+      "INVALID_ARGUMENTS",
+    );
+  }
+
+  return Number(match[1]) * multiplier;
+}
+
+function parseRegexOption(flag: string, value: string | undefined): RegExp | undefined {
+  if (value === undefined || value === "") {
+    return undefined;
+  }
+
+  try {
+    return new RegExp(value);
+  } catch {
+    throw new CliError(
+      `Invalid ${flag} value ${JSON.stringify(value)}; expected a regular expression.`,
+      "INVALID_ARGUMENTS",
+    );
+  }
+}
+
+function parseStatusCodesOption(flag: string, value: string | undefined): number[] | undefined {
+  if (value === undefined || value.trim() === "") {
+    return undefined;
+  }
+
+  const codes = value.split(",").map((code) => code.trim());
+  if (codes.some((code) => !/^[45]\d\d$/.test(code))) {
+    throw new CliError(
+      `Invalid ${flag} value ${JSON.stringify(value)}; expected comma-separated 4xx or 5xx statuses.`,
+      "INVALID_ARGUMENTS",
+    );
+  }
+
+  return codes.map(Number);
+}
+
+function parseLogFormat(value: string | undefined): LogFormat | undefined {
+  if (value === undefined || value === "") {
+    return undefined;
+  }
+  if ((LOG_FORMATS as readonly string[]).includes(value)) {
+    return value as LogFormat;
+  }
+  throw new CliError(
+    `Invalid --log-format value ${JSON.stringify(value)}; expected one of: ${LOG_FORMATS.join(", ")}.`,
+    "INVALID_ARGUMENTS",
+  );
+}
+
+function parseBackoffStrategy(value: string | undefined): BackoffStrategy | undefined {
+  if (value === undefined || value.trim() === "") {
+    return undefined;
+  }
+  const strategy = value.trim().toLowerCase();
+  if ((BACKOFF_STRATEGIES as readonly string[]).includes(strategy)) {
+    return strategy as BackoffStrategy;
+  }
+  throw new CliError(
+    `Invalid --backoff value ${JSON.stringify(value)}; expected one of: ${BACKOFF_STRATEGIES.join(", ")}.`,
+    "INVALID_ARGUMENTS",
+  );
+}
+
+function parseConfiguredOutputFormat(configured: ConfiguredOutput): OutputFormat {
+  try {
+    return parseOutputFormat(configured.format);
+  } catch (error) {
+    throw new CliError(
+      `${(error as Error).message.replace(/\.$/, "")} (from ${configured.key} in ~/.twenty/config.json).`,
+      "INVALID_ARGUMENTS",
+      "Fix the output setting in ~/.twenty/config.json or pass --output.",
+    );
+  }
+}
+
 function parseOutputFormat(value: unknown): OutputFormat {
   if (value === "agent") {
     throw new CliError(
@@ -202,12 +794,19 @@ function parseOutputFormat(value: unknown): OutputFormat {
       "INVALID_ARGUMENTS",
     );
   }
-  if (value === "json" || value === "jsonl" || value === "csv" || value === "text") {
+  if (
+    value === "json" ||
+    value === "jsonl" ||
+    value === "csv" ||
+    value === "text" ||
+    value === "table" ||
+    value === "html"
+  ) {
     return value;
   }
 
   throw new CliError(
-    `Unsupported output format ${JSON.stringify(value)}. Valid formats: json, jsonl, csv, text.`,
+    `Unsupported output format ${JSON.stringify(value)}. Valid formats: json, jsonl, csv, text, table, html.`,
     "INVALID_ARGUMENTS",
   );
 }
@@ -0,0 +1,32 @@
+import { log } from "./logger";
+
+// Conventional exit status for a process stopped by SIGINT (128 + 2).
+export const INTERRUPTED_EXIT_CODE = 130;
+
+// Runs a long operation with Ctrl-C turned into a cooperative stop: the first
+// SIGINT aborts the signal so the operation can cancel in-flight reads, keep
+// what it has, and report how to resume. A second SIGINT exits immediately.
+export async function runInterruptible<T>(run: (signal: AbortSignal) => Promise<T>): Promise<T> {
+  const controller = new AbortController();
+  const onSigint = () => {
+    if (controller.signal.aborted) {
+      process.exit(INTERRUPTED_EXIT_CODE);
+    }
+    log("warn", "Interrupted; finishing up. Press Ctrl-C again to exit immediately.");
+    controller.abort();
+  };
+
+  process.on("SIGINT", onSigint);
+  try {
+    return await run(controller.signal);
+  } finally {
+    process.off("SIGINT", onSigint);
+  }
+}
+
+// Prints the resume hint to stderr so it never mixes with exported data, and
+// marks the run as interrupted.
+export function reportInterrupted(message: string): void {
+  log("warn", message);
+  process.exitCode = INTERRUPTED_EXIT_CODE;
+}
@@ -1,4 +1,6 @@
+import readline from "node:readline";
 import fs from "fs-extra";
+import { assertStrictJson } from "./strict-json";
 
 export async function readStdin(): Promise<string> {
   const chunks: Buffer[] = [];
@@ -9,6 +11,12 @@ export async function readStdin(): Promise<string> {
   });
 }
 
+// Stdin one line at a time. readline pauses the stream while the consumer is
+// busy, so large piped input is never held in memory whole.
+export function readStdinLines(): AsyncIterable<string> {
+  return readline.createInterface({ input: process.stdin, crlfDelay: Infinity });
+}
+
 export async function readFileOrStdin(path: string): Promise<string> {
   if (path === "-") {
     return readStdin();
@@ -17,7 +25,9 @@ export async function readFileOrStdin(path: string): Promise<string> {
 }
 
 export function safeJsonParse(input: string): unknown {
-  return JSON.parse(input);
+  const parsed = JSON.parse(input) as unknown;
+  assertStrictJson(input);
+  return parsed;
 }
 
 export async function readJsonInput(
@@ -0,0 +1,100 @@
+// Process-wide stderr logging. --verbose enables progress lines and --debug
+// implies verbose; request/response dumps stay with the HTTP client's own
+// debug switch but are written through here. With --log-format json every
+// line becomes one JSON object so log collectors can parse it; stdout data is
+// unaffected.
+export type LogFormat = "text" | "json";
+export type LogLevel = "debug" | "verbose" | "info" | "warn" | "error";
+
+export const LOG_FORMATS: readonly LogFormat[] = ["text", "json"];
+
+let verboseEnabled = false;
+let logFormat: LogFormat = "text";
+
+// Field names whose values are never logged, matched case-insensitively.
+const SENSITIVE_FIELD = /authorization|token|api[-_]?key|password|secret|cookie/i;
+const BEARER_TOKEN = /\b(Bearer\s+)[^\s"',]+/gi;
+// A sensitive key with a string value inside JSON embedded in a message, such
+// as a request body preview that was cut short and can no longer be parsed.
+const SENSITIVE_JSON_STRING = new RegExp(
+  `("[^"]*(?:${SENSITIVE_FIELD.source})[^"]*"\\s*:\\s*)"(?:[^"\\\\]|\\\\.)*"?`,
+  "gi",
+);
+const REDACTED = "[REDACTED]";
+
+export function configureLogger(options: {
+  verbose?: boolean;
+  debug?: boolean;
+  logFormat?: LogFormat;
+}): void {
+  verboseEnabled = Boolean(options.verbose || options.debug);
+  logFormat = options.logFormat ?? "text";
+}
+
+export function isVerbose(): boolean {
+  return verboseEnabled;
+}
+
+export function logVerbose(message: string, fields?: Record<string, unknown>): void {
+  if (verboseEnabled) {
+    log("verbose", message, fields);
+  }
+}
+
+// Writes one line to stderr. Messages are redacted in both formats; fields
+// are only emitted in JSON, redacted by key.
+export function log(level: LogLevel, message: string, fields?: Record<string, unknown>): void {
+  if (logFormat === "text") {
+    // eslint-disable-next-line no-console
+    console.error(redactText(message));
+    return;
+  }
+
+  const entry: Record<string, unknown> = {
+    timestamp: new Date().toISOString(),
+    level,
+    message: redactText(message),
+  };
+  if (fields && Object.keys(fields).length > 0) {
+    entry.fields = redactSensitive(fields);
+  }
+  // eslint-disable-next-line no-console
+  console.error(JSON.stringify(entry));
+}
+
+// Multi-line reports such as an error and its suggestion: one stderr line
+// each in text, a single entry in JSON.
+export function logLines(level: LogLevel, lines: string[], fields?: Record<string, unknown>): void {
+  if (lines.length === 0) return;
+  if (logFormat === "text") {
+    lines.forEach((line) => log(level, line));
+    return;
+  }
+  log(level, lines.join("\n"), fields);
+}
+
+function redactText(text: string): string {
+  return text
+    .replace(BEARER_TOKEN, `$1${REDACTED}`)
+    .replace(SENSITIVE_JSON_STRING, `$1"${REDACTED}"`);
+}
+
+// Masks the values of sensitive keys at any depth, e.g. a request body before
+// it is previewed in a debug line.
+export function redactSensitive(value: unknown): unknown {
+  if (typeof value === "string") {
+    return redactText(value);
+  }
+  if (Array.isArray(value)) {
+    return value.map(redactSensitive);
+  }
+  if (value && typeof value === "object") {
+    return Object.fromEntries(
+      Object.entries(value).map(([key, entry]) => [
+        key,
+        SENSITIVE_FIELD.test(key) ? REDACTED : redactSensitive(entry),
+      ]),
+    );
+  }
+  return value;
+}
@@ -1,3 +1,4 @@
+import { CliError } from "../errors/cli-error";
 import { safeJsonParse } from "./io";
 
 export function capitalize(value: string): string {
@@ -111,6 +112,45 @@ export function parseKeyValuePairs(pairs: string[] | undefined): Record<string,
   return out;
 }
 
+// RFC 3986 unreserved characters plus the brackets used by keys such as
+// filter[city]. Values may hold anything printable; they are percent-encoded
+// on the way out.
+const QUERY_PARAM_KEY = /^[A-Za-z0-9._~\-[\]]+$/;
+// eslint-disable-next-line no-control-regex
+const CONTROL_CHARACTER = /[\u0000-\u001f\u007f]/;
+
+// --param key=value: raw query parameters sent as given, never merged into
+// the structured filter.
+export function parseQueryParams(pairs: string[] | undefined): Record<string, string[]> {
+  const params = parseKeyValuePairs(pairs);
+  for (const [key, values] of Object.entries(params)) {
+    if (!QUERY_PARAM_KEY.test(key)) {
+      throw new CliError(
+        `Invalid --param key ${JSON.stringify(key)}.`,
+        "INVALID_ARGUMENTS",
+        "Keys may use letters, digits, and . _ ~ - [ ].",
+      );
+    }
+    if (values.some((value) => CONTROL_CHARACTER.test(value))) {
+      throw new CliError(
+        `Invalid --param value for ${key}: control characters are not allowed.`,
+        "INVALID_ARGUMENTS",
+      );
+    }
+  }
+  return params;
+}
+
+// Splits a comma-separated field list, dropping blanks; undefined when empty.
+export function parseFieldList(value: string | undefined): string[] | undefined {
+  const fields = (value ?? "")
+    .split(",")
+    .map((field) => field.trim())
+    .filter(Boolean);
+
+  return fields.length > 0 ? fields : undefined;
+}
+
 export function splitOnce(input: string, delimiter: string): [string, string] {
   const index = input.indexOf(delimiter);
   if (index === -1) {
@@ -138,3 +178,32 @@ export function parseBooleanEnv(value: string | undefined): boolean | undefined
   if (normalized === "false" || normalized === "0" || normalized === "no") return false;
   return undefined;
 }
+
+export function parsePositiveInteger(rawValue: string, label: string): number {
+  const parsed = Number.parseInt(rawValue, 10);
+
+  if (!Number.isFinite(parsed) || parsed <= 0) {
+    throw new CliError(`${label} must be a positive integer.`, "INVALID_ARGUMENTS");
+  }
+
+  return parsed;
+}
+
+export function parseNonNegativeIntegerOption(
+  flag: string,
+  value: string | undefined,
+): number | undefined {
+  if (value === undefined || value.trim() === "") {
+    return undefined;
+  }
+
+  const parsed = Number(value.trim());
+  if (!Number.isInteger(parsed) || parsed < 0) {
+    throw new CliError(
+      `Invalid ${flag} value ${JSON.stringify(value)}; expected a non-negative integer.`,
+      "INVALID_ARGUMENTS",
+    );
+  }
+
+  return parsed;
+}
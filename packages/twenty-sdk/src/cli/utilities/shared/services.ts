@@ -1,4 +1,5 @@
-import { ApiService } from "../api/services/api.service";
+import { ApiService, SharedHttpServiceOptions } from "../api/services/api.service";
+import { EtagCache } from "../api/services/etag-cache";
 import { PublicHttpService } from "../api/services/public-http.service";
 import { ConfigService } from "../config/services/config.service";
 import { MetadataService } from "../metadata/services/metadata.service";
@@ -6,6 +7,7 @@ import { RecordsService } from "../records/services/records.service";
 import { OutputService } from "../output/services/output.service";
 import { QueryService } from "../output/services/query.service";
 import { TableService } from "../output/services/table.service";
+import { OutputWriter, stdoutWriter } from "../output/services/output-writer";
 import { ExportService } from "../file/services/export.service";
 import { ImportService } from "../file/services/import.service";
 import { McpService } from "../mcp/services/mcp.service";
@@ -20,6 +22,10 @@ import { DbStatusService } from "../db/services/db-status.service";
 import { ReadBackendService } from "../readbackend/read-backend.service";
 import { ApiRecordsReadService } from "../records/services/api-records-read.service";
 import { GlobalOptions } from "./global-options";
+import { selectPrompt } from "./confirmation";
+import { configureLogger } from "./logger";
+import { configureStrictJson } from "./strict-json";
+import { configureRetryStats } from "../api/services/retry-stats";
 
 export interface CliServices {
   config: ConfigService;
@@ -35,32 +41,82 @@ export interface CliServices {
   output: OutputService;
   importer: ImportService;
   exporter: ExportService;
+  // Where output and status lines go; stdout unless an embedder passed one.
+  writer: OutputWriter;
 }
 
-export function createOutputService(globalOptions: GlobalOptions): OutputService {
-  return new OutputService(new TableService(), new QueryService(), {
-    format: globalOptions.output,
-    light: globalOptions.light,
-    full: globalOptions.full,
-    agentMode: globalOptions.agentMode,
-  });
+// writer receives everything the output and export services print; embedders
+// pass their own to capture it instead of writing to stdout.
+export function createOutputService(
+  globalOptions: GlobalOptions,
+  writer: OutputWriter = stdoutWriter,
+): OutputService {
+  return new OutputService(
+    new TableService(writer),
+    new QueryService(),
+    {
+      format: globalOptions.output,
+      pointer: globalOptions.pointer,
+      rawOutput: globalOptions.rawOutput,
+      print0: globalOptions.print0,
+      pruneFields: globalOptions.pruneFields,
+      maskFields: globalOptions.maskFields,
+      unwrap: globalOptions.unwrap,
+      csvQuoteAll: globalOptions.csvQuoteAll,
+      csvBom: globalOptions.csvBom,
+      csvNumbers: globalOptions.csvNumbers,
+      totals: globalOptions.totals,
+      keyColumn: globalOptions.keyColumn,
+      light: globalOptions.light,
+      full: globalOptions.full,
+      agentMode: globalOptions.agentMode,
+      envelope: globalOptions.envelope,
+      sortKeys: globalOptions.sortKeys,
+      textTemplate: globalOptions.textTemplate,
+    },
+    writer,
+  );
 }
 
-export function createServices(globalOptions: GlobalOptions): CliServices {
-  const config = new ConfigService();
+export function createServices(
+  globalOptions: GlobalOptions,
+  writer: OutputWriter = stdoutWriter,
+): CliServices {
+  configureLogger(globalOptions);
+  configureStrictJson(globalOptions.strictJson);
+  configureRetryStats(globalOptions.showRetryStats);
+  const config = new ConfigService(undefined, {
+    tokenFile: globalOptions.tokenFile,
+    connection: globalOptions.connection,
+    workspaceId: globalOptions.workspaceId,
+    selectWorkspace: (names) => selectPrompt("Select a profile:", names),
+    alwaysSelectWorkspace: globalOptions.selectProfile,
+  });
   const dbProfiles = new DbProfileService(config);
   const dbConfigResolver = new DbConfigResolverService(dbProfiles);
   const dbStatus = new DbStatusService(dbConfigResolver);
-  const api = new ApiService(config, {
+  const etagCache = globalOptions.etagCache ? new EtagCache() : undefined;
+  // ApiService and PublicHttpService share one HTTP client setup.
+  const httpOptions: SharedHttpServiceOptions = {
     workspace: globalOptions.workspace,
     debug: globalOptions.debug,
     noRetry: globalOptions.noRetry,
-  });
-  const publicHttp = new PublicHttpService(config, {
-    workspace: globalOptions.workspace,
-    debug: globalOptions.debug,
-    noRetry: globalOptions.noRetry,
-  });
+    maxRetries: globalOptions.maxRetries,
+    retryBaseDelay: globalOptions.retryBaseDelay,
+    backoffStrategy: globalOptions.backoff,
+    retryBodyMatch: globalOptions.retryBodyMatch,
+    retryStatusCodes: globalOptions.retryStatusCodes,
+    retryNetworkErrors: globalOptions.retryNetworkErrors,
+    retryUnsafe: globalOptions.retryUnsafe,
+    abortOnRateLimit: globalOptions.abortOnRateLimit,
+    maxResponseSize: globalOptions.maxBodySize,
+    allowInsecureHttp: globalOptions.allowInsecureHttp,
+    etagCache,
+    compressRequests: globalOptions.compressRequests,
+    retryAttemptHeader: globalOptions.retryAttemptHeader,
+  };
+  const api = new ApiService(config, httpOptions);
+  const publicHttp = new PublicHttpService(config, httpOptions);
   const metadata = new MetadataService(api);
   const apiSearch = new ApiSearchService(api);
   const apiRecordsRead = new ApiRecordsReadService(api);
@@ -81,9 +137,9 @@ export function createServices(globalOptions: GlobalOptions): CliServices {
   });
   const schemaCache = new SchemaCacheService(config, api);
   const records = new RecordsService(api, { readBackend });
-  const output = createOutputService(globalOptions);
+  const output = createOutputService(globalOptions, writer);
   const importer = new ImportService();
-  const exporter = new ExportService();
+  const exporter = new ExportService(writer);
 
   return {
     config,
@@ -99,5 +155,6 @@ export function createServices(globalOptions: GlobalOptions): CliServices {
     output,
     importer,
     exporter,
+    writer,
   };
 }
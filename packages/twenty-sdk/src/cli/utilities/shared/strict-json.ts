@@ -0,0 +1,127 @@
+import { CliError } from "../errors/cli-error";
+
+// Process-wide, like the logger: --strict-json applies to every payload and
+// import file the command reads, wherever it is parsed.
+let strictJsonEnabled = false;
+
+export function configureStrictJson(enabled: boolean | undefined): void {
+  strictJsonEnabled = Boolean(enabled);
+}
+
+// Call after JSON.parse succeeded; a no-op unless --strict-json is set.
+export function assertStrictJson(text: string): void {
+  if (!strictJsonEnabled) {
+    return;
+  }
+  const duplicate = findDuplicateJsonKey(text);
+  if (duplicate !== undefined) {
+    throw new CliError(
+      `Duplicate JSON key at ${duplicate}.`,
+      "INVALID_ARGUMENTS",
+      "Remove one of the values; without --strict-json the last one wins.",
+    );
+  }
+}
+
+// JSON.parse keeps the last of duplicated object keys without a word. This
+// scans text that already parsed and returns the JSON Pointer of the first
+// repeated key (e.g. /0/email), or undefined when every object is clean.
+export function findDuplicateJsonKey(text: string): string | undefined {
+  return new DuplicateKeyScanner(text).scan();
+}
+
+class DuplicateKeyScanner {
+  private position = 0;
+
+  constructor(private readonly text: string) {}
+
+  scan(): string | undefined {
+    return this.value("");
+  }
+
+  private value(path: string): string | undefined {
+    this.skipWhitespace();
+    const char = this.text[this.position];
+    if (char === "{") {
+      return this.object(path);
+    }
+    if (char === "[") {
+      return this.array(path);
+    }
+    if (char === '"') {
+      this.string();
+      return undefined;
+    }
+    // Numbers and literals run until the next delimiter.
+    while (this.position < this.text.length && !/[\s,\]}]/.test(this.text[this.position]!)) {
+      this.position += 1;
+    }
+    return undefined;
+  }
+
+  private object(path: string): string | undefined {
+    this.position += 1;
+    const seen = new Set<string>();
+    this.skipWhitespace();
+    if (this.text[this.position] === "}") {
+      this.position += 1;
+      return undefined;
+    }
+    for (;;) {
+      this.skipWhitespace();
+      const key = this.string();
+      const keyPath = `${path}/${key.replace(/~/g, "~0").replace(/\//g, "~1")}`;
+      if (seen.has(key)) {
+        return keyPath;
+      }
+      seen.add(key);
+      this.skipWhitespace();
+      this.position += 1; // ":"
+      const duplicate = this.value(keyPath);
+      if (duplicate !== undefined) {
+        return duplicate;
+      }
+      this.skipWhitespace();
+      if (this.text[this.position++] === "}") {
+        return undefined;
+      }
+    }
+  }
+
+  private array(path: string): string | undefined {
+    this.position += 1;
+    this.skipWhitespace();
+    if (this.text[this.position] === "]") {
+      this.position += 1;
+      return undefined;
+    }
+    for (let index = 0; ; index += 1) {
+      const duplicate = this.value(`${path}/${index}`);
+      if (duplicate !== undefined) {
+        return duplicate;
+      }
+      this.skipWhitespace();
+      if (this.text[this.position++] === "]") {
+        return undefined;
+      }
+    }
+  }
+
+  // Decodes the string starting at the current quote, so escaped and literal
+  // spellings of the same key ("a" and "\u0061") count as duplicates.
+  private string(): string {
+    const start = this.position;
+    this.position += 1;
+    while (this.text[this.position] !== '"') {
+      this.position += this.text[this.position] === "\\" ? 2 : 1;
+    }
+    this.position += 1;
+    return JSON.parse(this.text.slice(start, this.position)) as string;
+  }
+
+  private skipWhitespace(): void {
+    while (/\s/.test(this.text[this.position] ?? "")) {
+      this.position += 1;
+    }
+  }
+}